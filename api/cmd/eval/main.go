@@ -25,6 +25,7 @@ func main() {
 	chain := flag.Int("chain", 0, "generate N consecutive batches for one profile, passing hooks forward")
 	rekan := flag.Bool("rekan", false, "use Rekan-specific generation prompt")
 	message := flag.String("message", "", "generate a single post from a WhatsApp message (requires --profile)")
+	format := flag.String("format", "", "generate reels, stories or carousels instead of feed posts (reel, story, carousel)")
 	flag.Parse()
 
 	if *fast {
@@ -56,6 +57,17 @@ func main() {
 		}
 	}
 
+	if *format != "" {
+		f, ok := content.ParseFormat(*format)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "error: unknown format %q\n", *format)
+			os.Exit(1)
+		}
+		if g, ok := content.FormatGenerators[f]; ok {
+			gen = g
+		}
+	}
+
 	if *diff {
 		args := flag.Args()
 		if len(args) != 2 {
@@ -6,6 +6,8 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strconv"
+	"time"
 
 	"github.com/pocketbase/pocketbase"
 	"github.com/pocketbase/pocketbase/core"
@@ -19,6 +21,7 @@ import (
 	apphttp "github.com/denisraison/rekan/api/internal/http"
 	"github.com/denisraison/rekan/api/internal/http/handlers"
 	"github.com/denisraison/rekan/api/internal/operator"
+	"github.com/denisraison/rekan/api/internal/service"
 	"github.com/denisraison/rekan/api/internal/transcribe"
 	"github.com/denisraison/rekan/api/internal/whatsapp"
 	_ "github.com/denisraison/rekan/api/migrations"
@@ -49,6 +52,7 @@ func run(ctx context.Context, getenv func(string) string) error {
 
 	// WhatsApp client (optional, skipped if no data dir available)
 	var waClient *whatsapp.Client
+	var inbound *whatsapp.Inbound
 	app.OnServe().BindFunc(func(se *core.ServeEvent) error {
 		if isDev {
 			disableRateLimits(app)
@@ -58,6 +62,14 @@ func run(ctx context.Context, getenv func(string) string) error {
 			app.Logger().Warn("failed to configure backups", "error", err)
 		}
 
+		qualityGate := newQualityGate(app, getenv)
+
+		// Rejections, edits and client change requests refresh the client's
+		// style memo, whichever path wrote them.
+		if getenv("GEMINI_API_KEY") != "" {
+			service.WatchStyleFeedback(app, content.DistillStyleMemo)
+		}
+
 		// Start WhatsApp client, store session alongside PocketBase data
 		dbPath := filepath.Join(app.DataDir(), "whatsapp.db")
 		wac, err := whatsapp.New(ctx, dbPath, "Rekan", app.Logger())
@@ -66,31 +78,54 @@ func run(ctx context.Context, getenv func(string) string) error {
 		} else {
 			var whisperClient *transcribe.Client
 			var extractSignal content.ExtractSignalFunc
+			var extractProfile content.ExtractProfileFunc
+			var classifyReply content.ClassifyReplyFunc
 			if key := getenv("GEMINI_API_KEY"); key != "" {
 				whisperClient = transcribe.NewClient(key)
 				extractSignal = content.ExtractProfileSignal
+				extractProfile = content.ExtractBusinessProfile
+				classifyReply = content.ClassifyClientReply
 			}
 
 			// Create group agent if CLAUDE_API_KEY is set
 			var handleGroupMsg whatsapp.GroupMessageHandler
+			var handleClientMsg whatsapp.ClientMessageHandler
 			if key := getenv("CLAUDE_API_KEY"); key != "" {
 				groupAgent := agent.New(app, wac, app.Logger(), whisperClient, content.Generate, key)
+				groupAgent.ExtractProfile = extractProfile
+				groupAgent.QualityGate = qualityGate
+				groupAgent.Rewrite = content.Rewrite
+				groupAgent.Formats = content.FormatGenerators
 				handleGroupMsg = groupAgent.HandleGroupMessage
+
+				// Clients opt in one by one through update_customer.
+				dmAgent := agent.NewDMAgent(app, wac, app.Logger(), key, getenv("REKAN_AGENT_GROUP_JID"))
+				handleClientMsg = dmAgent.HandleClientMessage
 			}
 
+			// Events are handled off the whatsmeow event loop so a slow
+			// download or model call does not stall every other chat.
+			inbound = whatsapp.NewInbound(app.Logger())
 			whatsapp.RegisterMessageHandler(whatsapp.HandlerDeps{
-				Client:         wac,
-				App:            app,
-				Logger:         app.Logger(),
-				Transcribe:     whisperClient,
-				ExtractSignal:  extractSignal,
-				HandleGroupMsg: handleGroupMsg,
-				AgentGroupJID:  getenv("REKAN_AGENT_GROUP_JID"),
+				Client:          wac,
+				App:             app,
+				Logger:          app.Logger(),
+				Transcribe:      whisperClient,
+				ExtractSignal:   extractSignal,
+				ExtractProfile:  extractProfile,
+				ClassifyReply:   classifyReply,
+				HandleGroupMsg:  handleGroupMsg,
+				HandleClientMsg: handleClientMsg,
+				AgentGroupJID:   getenv("REKAN_AGENT_GROUP_JID"),
+				OnboardLeads:    getenv("REKAN_ONBOARD_LEADS") == "true",
+				Inbound:         inbound,
 			})
 			if err := wac.Connect(ctx); err != nil {
 				app.Logger().Warn("whatsapp connect failed", "error", err)
 			} else {
 				waClient = wac
+				outbox := &service.OutboxWorker{App: app, Client: wac, Limits: service.DefaultOutboxLimits}
+				go outbox.Run(ctx, 2*time.Second)
 			}
 		}
 
@@ -102,6 +137,11 @@ func run(ctx context.Context, getenv func(string) string) error {
 			operator.QueueSeasonalMessages(app)
 		})
 
+		// Approved campaigns go to the outbox a few recipients a minute.
+		app.Cron().MustAdd("campaigns", "* * * * *", func() {
+			service.DispatchCampaigns(app, time.Now())
+		})
+
 		var extractFromAudio content.ExtractFromAudioFunc
 		if key := getenv("GEMINI_API_KEY"); key != "" {
 			tc := transcribe.NewClient(key)
@@ -123,11 +163,15 @@ func run(ctx context.Context, getenv func(string) string) error {
 			App:                 app,
 			Asaas:               asaasClient,
 			WhatsApp:            waClient,
+			Inbound:             inbound,
 			Transcribe:          transcribeClient,
 			WebhookToken:        getenv("ASAAS_WEBHOOK_TOKEN"),
 			AppURL:              getenv("APP_URL"),
 			Generate:            content.Generate,
+			Formats:             content.FormatGenerators,
+			QualityGate:         qualityGate,
 			GenerateFromMessage: content.GenerateFromMessage,
+			Rewrite:             content.Rewrite,
 			ExtractFromAudio:    extractFromAudio,
 		})
 		return se.Next()
@@ -138,6 +182,10 @@ func run(ctx context.Context, getenv func(string) string) error {
 		if waClient != nil {
 			waClient.Disconnect()
 		}
+		// Disconnected first so no new events arrive while the queues drain.
+		if inbound != nil {
+			inbound.Close()
+		}
 		return te.Next()
 	})
 
@@ -171,6 +219,30 @@ func run(ctx context.Context, getenv func(string) string) error {
 	return app.Start()
 }
 
+// newQualityGate returns the gate for generated posts. The heuristics and
+// the similarity guard always run; the Gemini judge only with GEMINI_API_KEY.
+// QUALITY_GATE_RETRIES=0 keeps the checks but never regenerates.
+func newQualityGate(app core.App, getenv func(string) string) *service.QualityGate {
+	retries := 2
+	if v := getenv("QUALITY_GATE_RETRIES"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			app.Logger().Warn("invalid QUALITY_GATE_RETRIES, using default", "value", v, "default", retries)
+		} else {
+			retries = n
+		}
+	}
+	gate := &service.QualityGate{
+		MaxRetries:    retries,
+		MaxSimilarity: service.DefaultMaxSimilarity,
+	}
+	if getenv("GEMINI_API_KEY") != "" {
+		gate.Judges = service.DefaultGateJudges
+		gate.Judge = content.RunCheapJudge
+	}
+	return gate
+}
+
 func configureBackups(app core.App, getenv func(string) string) error {
 	bucket := getenv("GCS_BACKUP_BUCKET")
 	if bucket == "" {
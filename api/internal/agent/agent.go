@@ -6,12 +6,15 @@ import (
 	"fmt"
 	"log/slog"
 	"strings"
+	"sync"
 	"time"
 
+	"go.mau.fi/whatsmeow/proto/waE2E"
 	"go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
 
 	content "github.com/denisraison/rekan/api/internal/content"
+	"github.com/denisraison/rekan/api/internal/service"
 	"github.com/denisraison/rekan/api/internal/transcribe"
 	wa "github.com/denisraison/rekan/api/internal/whatsapp"
 	"github.com/pocketbase/pocketbase/core"
@@ -26,6 +29,20 @@ type Agent struct {
 	Transcribe *transcribe.Client   // nil if GEMINI_API_KEY not set
 	Generate   content.GenerateFunc // nil if not wired
 	Claude     *Client
+
+	// Formats holds the generators for reels, stories and carousels. A format
+	// without one can't be asked for.
+	Formats map[content.Format]content.GenerateFunc
+
+	// ExtractProfile turns document text into profile suggestions. nil if not wired.
+	ExtractProfile content.ExtractProfileFunc
+	// Rewrite applies free-form instructions to a pending post. nil if not wired.
+	Rewrite content.RewriteFunc
+	// QualityGate checks and regenerates posts before they are saved. nil skips it.
+	QualityGate *service.QualityGate
+
+	docMu     sync.Mutex
+	documents map[string]string // operator JID -> text of the last document they sent
 }
 
 // New creates a new Agent instance.
@@ -38,9 +55,27 @@ func New(app core.App, waClient WAClient, logger *slog.Logger, tc *transcribe.Cl
 		Transcribe: tc,
 		Generate:   gen,
 		Claude:     NewClient(claudeAPIKey),
+		documents:  make(map[string]string),
 	}
 }
 
+// rememberDocument keeps the last document an operator sent so import_document
+// can read it without the model copying the text back.
+func (a *Agent) rememberDocument(operatorJID, text string) {
+	a.docMu.Lock()
+	defer a.docMu.Unlock()
+	if a.documents == nil {
+		a.documents = make(map[string]string)
+	}
+	a.documents[operatorJID] = text
+}
+
+func (a *Agent) lastDocument(operatorJID string) string {
+	a.docMu.Lock()
+	defer a.docMu.Unlock()
+	return a.documents[operatorJID]
+}
+
 // HandleGroupMessage is called for every incoming group message.
 // Every message in the configured group is processed (group membership is the auth boundary).
 func (a *Agent) HandleGroupMessage(evt *events.Message) {
@@ -64,6 +99,12 @@ func (a *Agent) HandleGroupMessage(evt *events.Message) {
 	}
 	operatorJID := senderJID.User
 
+	// Edits and deletes arrive as protocol messages referencing the original ID.
+	if pm := evt.Message.GetProtocolMessage(); pm != nil {
+		a.handleProtocolMessage(evt.Info.Chat, senderJID, operatorName, operatorJID, pm)
+		return
+	}
+
 	text := extractText(evt)
 
 	// Handle non-text media (images, audio, stickers, contacts, forwarded)
@@ -78,23 +119,31 @@ func (a *Agent) HandleGroupMessage(evt *events.Message) {
 		} else {
 			text = media.Text
 		}
+		if media.Document != "" {
+			a.rememberDocument(operatorJID, media.Document)
+		}
 
 		if text == "" {
 			return
 		}
 	}
 
-	groupJID := evt.Info.Chat
-	messageID := evt.Info.ID
-	sender := senderJID
+	a.submit(evt.Info.Chat, senderJID, evt.Info.ID, text, operatorName, operatorJID)
+}
 
-	a.Debouncer.Submit(operatorJID, text, func(combined string) {
-		a.ProcessMessage(groupJID, messageID, sender, combined, operatorName, operatorJID)
+// submit queues text in the debouncer and processes it once the operator goes quiet.
+func (a *Agent) submit(groupJID, senderJID types.JID, messageID, text, operatorName, operatorJID string) {
+	a.Debouncer.Submit(operatorJID, messageID, text, func(combined string, messageIDs []string) {
+		a.ProcessMessage(groupJID, messageIDs, senderJID, combined, operatorName, operatorJID)
 	})
 }
 
 func extractText(evt *events.Message) string {
-	msg := evt.Message
+	return messageText(evt.Message)
+}
+
+// messageText returns the plain text of a conversation or extended text message.
+func messageText(msg *waE2E.Message) string {
 	switch {
 	case msg.GetConversation() != "":
 		return msg.GetConversation()
@@ -115,30 +164,40 @@ type agentResult struct {
 }
 
 // ProcessMessage is the core message processing pipeline.
-// Stores the message, loads conversation history, uses tool-use loop, and sends the reply.
+// Stores the message, runs command shortcuts or the tool-use loop, and sends the reply.
+// messageIDs are the WhatsApp messages combined into message; the last one gets the reaction.
 // Used by HandleGroupMessage (via debouncer) and directly by tests.
-func (a *Agent) ProcessMessage(groupJID types.JID, messageID string, senderJID types.JID, message, operatorName, operatorJID string) {
+func (a *Agent) ProcessMessage(groupJID types.JID, messageIDs []string, senderJID types.JID, message, operatorName, operatorJID string) {
 	start := time.Now()
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
 	userStructured := marshalMessage(NewUserMessage(NewTextBlock(message)))
-	if err := StoreMessage(a.App, operatorName, operatorJID, "user", message, "", userStructured); err != nil {
+	if err := storeMessage(a.App, operatorName, operatorJID, "user", message, "", userStructured, messageIDs); err != nil {
 		a.Logger.Error("agent: failed to store message", "error", err)
 	}
 
-	if err := ReactThumbsUp(ctx, a.WAClient, groupJID, messageID, senderJID); err != nil {
-		a.Logger.Error("agent: react thumbs up", "error", err)
+	if len(messageIDs) > 0 {
+		if err := ReactThumbsUp(ctx, a.WAClient, groupJID, messageIDs[len(messageIDs)-1], senderJID); err != nil {
+			a.Logger.Error("agent: react thumbs up", "error", err)
+		}
+	}
+
+	// Shortcuts skip the model entirely, so they are fast and keep working
+	// when the provider is down.
+	if cmd, ok := parseCommand(message, a.postIDKnown); ok {
+		a.sendAndLog(ctx, groupJID, operatorName, operatorJID, a.runCommand(ctx, cmd, operatorName, operatorJID), start)
+		return
 	}
 
 	stop := wa.Typing(ctx, a.WAClient, groupJID)
 	defer stop()
 
-	result, err := a.processWithTools(ctx, groupJID, operatorName, message)
+	result, err := a.processWithTools(ctx, groupJID, operatorName, operatorJID, message)
 	if err != nil {
 		a.Logger.Error("agent: tool-use loop failed", "error", err)
 		LogAction(a.App, operatorName, operatorJID, "ERROR", nil, err.Error(), false, start)
-		if sendErr := SendReply(ctx, a.WAClient, groupJID, operatorName+", algo deu errado. Tenta de novo?"); sendErr != nil {
+		if sendErr := SendReply(ctx, a.WAClient, groupJID, operatorName+", algo deu errado. Tenta de novo?\n\n"+commandHelp); sendErr != nil {
 			a.Logger.Error("agent: failed to send error reply", "error", sendErr)
 		}
 		return
@@ -147,8 +206,24 @@ func (a *Agent) ProcessMessage(groupJID types.JID, messageID string, senderJID t
 	a.sendAndLog(ctx, groupJID, operatorName, operatorJID, result, start)
 }
 
+// newToolExecutor builds the executor for one operator message, shared by the
+// tool-use loop and command shortcuts.
+func (a *Agent) newToolExecutor(ctx context.Context, operatorJID string) *ToolExecutor {
+	return &ToolExecutor{
+		Ctx:            ctx,
+		App:            a.App,
+		WAClient:       a.WAClient,
+		Generate:       a.Generate,
+		Formats:        a.Formats,
+		ExtractProfile: a.ExtractProfile,
+		QualityGate:    a.QualityGate,
+		Rewrite:        a.Rewrite,
+		Document:       a.lastDocument(operatorJID),
+	}
+}
+
 // processWithTools runs the Claude tool-use loop for a message.
-func (a *Agent) processWithTools(ctx context.Context, groupJID types.JID, operatorName, message string) (*agentResult, error) {
+func (a *Agent) processWithTools(ctx context.Context, groupJID types.JID, operatorName, operatorJID, message string) (*agentResult, error) {
 	history, err := LoadRecentAndPrune(a.App, 15)
 	if err != nil {
 		a.Logger.Error("agent: failed to load conversation history", "error", err)
@@ -156,14 +231,11 @@ func (a *Agent) processWithTools(ctx context.Context, groupJID types.JID, operat
 
 	messages := buildClaudeMessages(history, message)
 
-	executor := &ToolExecutor{
-		Ctx:      ctx,
-		App:      a.App,
-		WAClient: a.WAClient,
-		Generate: a.Generate,
-	}
+	executor := a.newToolExecutor(ctx, operatorJID)
 	tools := buildTools(executor, operatorName)
 
+	// When the model goes straight to a slow tool without writing anything,
+	// the operator still hears back after a few seconds.
 	slowTimer := time.AfterFunc(5*time.Second, func() {
 		if err := SendReply(ctx, a.WAClient, groupJID, "Um momento..."); err != nil {
 			a.Logger.Error("agent: failed to send slow timer reply", "error", err)
@@ -171,12 +243,32 @@ func (a *Agent) processWithTools(ctx context.Context, groupJID types.JID, operat
 		wa.Typing(ctx, a.WAClient, groupJID)
 	})
 
+	// Text the model writes before a tool call goes out immediately, so the
+	// operator sees what is happening while tools run. The final reply is
+	// sent as a follow-up message by sendAndLog.
+	onText := func(text string) {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return
+		}
+		slowTimer.Stop()
+		if err := SendReply(ctx, a.WAClient, groupJID, text); err != nil {
+			a.Logger.Error("agent: failed to send interim reply", "error", err)
+		}
+		// Sending a message clears the composing indicator.
+		wa.Typing(ctx, a.WAClient, groupJID)
+	}
+
 	systemPrompt := buildSystemPrompt(operatorName)
 	runResult, runErr := a.Claude.Run(ctx, RunConfig{
 		System:   systemPrompt,
 		Messages: messages,
 		Tools:    tools,
 		MaxTurns: maxToolRoundTrips,
+		OnText:   onText,
+		OnTrace: func(t Trace) {
+			a.Logger.Debug("agent: turn", "turn", t.Turn, "model_ms", t.ModelLatency, "first_token_ms", t.FirstTokenLatency, "tools", len(t.ToolCalls))
+		},
 	})
 	slowTimer.Stop()
 
@@ -209,16 +301,23 @@ func (a *Agent) processWithTools(ctx context.Context, groupJID types.JID, operat
 	}
 
 	reply := runResult.Reply
+	if reply == "" && runResult.MaxTurns {
+		reply = maxTurnsReply
+	}
 
-	// Collect loop messages (all except the final) and the final assistant message
+	// Collect loop messages (all except the final) and the final assistant
+	// message. A loop cut short by max turns ends on tool results, so every
+	// new message belongs to the loop.
 	var loopMsgs []Message
 	var finalMsg Message
 	allMsgs := runResult.Messages
 	if len(allMsgs) > len(messages) {
 		newMsgs := allMsgs[len(messages):]
-		if len(newMsgs) > 0 {
-			finalMsg = newMsgs[len(newMsgs)-1]
+		if last := newMsgs[len(newMsgs)-1]; last.Role == RoleAssistant {
+			finalMsg = last
 			loopMsgs = newMsgs[:len(newMsgs)-1]
+		} else {
+			loopMsgs = newMsgs
 		}
 	}
 
@@ -233,6 +332,10 @@ func (a *Agent) processWithTools(ctx context.Context, groupJID types.JID, operat
 
 const maxToolRoundTrips = 5
 
+// maxTurnsReply tells the operator the model ran out of tool round trips
+// before writing an answer.
+const maxTurnsReply = "Não consegui terminar tudo de uma vez. Me diz o que ainda falta que eu continuo."
+
 // buildClaudeMessages converts conversation history + current message into agent messages.
 func buildClaudeMessages(history []ConversationMessage, currentMessage string) []Message {
 	var messages []Message
@@ -369,12 +472,29 @@ func toolNameToActionType(name string) string {
 		return ActionPostApprove
 	case "reject_post":
 		return ActionPostReject
+	case "reschedule_post":
+		return ActionPostReschedule
+	case "import_document":
+		return ActionProfileImport
+	case "pair_photo":
+		return ActionPostPairMedia
+	case "pick_variant":
+		return ActionPostPickVariant
 	default:
 		return ""
 	}
 }
 
 func (a *Agent) sendAndLog(ctx context.Context, groupJID types.JID, operatorName, operatorJID string, result *agentResult, start time.Time) {
+	// Store tool loop messages (assistant tool_use + user tool_result pairs)
+	// first: the tools ran whether or not a reply goes out.
+	for _, msg := range result.LoopMsgs {
+		structured := marshalMessage(msg)
+		if err := StoreMessage(a.App, "Rekan", "", string(msg.Role), "", "", structured); err != nil {
+			a.Logger.Error("agent: failed to store loop message", "error", err)
+		}
+	}
+
 	if result.ReplyText == "" {
 		LogAction(a.App, operatorName, operatorJID, result.ActionType, nil, "empty reply", true, start)
 		return
@@ -386,14 +506,6 @@ func (a *Agent) sendAndLog(ctx context.Context, groupJID types.JID, operatorName
 		return
 	}
 
-	// Store tool loop messages (assistant tool_use + user tool_result pairs)
-	for _, msg := range result.LoopMsgs {
-		structured := marshalMessage(msg)
-		if err := StoreMessage(a.App, "Rekan", "", string(msg.Role), "", "", structured); err != nil {
-			a.Logger.Error("agent: failed to store loop message", "error", err)
-		}
-	}
-
 	// Store final assistant reply with structured data
 	storedContent := result.ReplyText
 	if result.ToolSummary != "" {
@@ -5,6 +5,9 @@ import (
 	"encoding/json"
 	"strings"
 	"testing"
+	"time"
+
+	"go.mau.fi/whatsmeow/types"
 
 	content "github.com/denisraison/rekan/api/internal/content"
 	"github.com/denisraison/rekan/api/internal/domain"
@@ -532,3 +535,120 @@ func TestDoubleCreate_Idempotent(t *testing.T) {
 		t.Errorf("expected exactly 1 business 'Ana', got %d", count)
 	}
 }
+
+func TestImportDocument_SavesSuggestions(t *testing.T) {
+	app := newWave4TestApp(t)
+	biz := wave4SeedBusiness(t, app, "Doceria da Bia", "Confeitaria", "Campinas")
+	te := newExecutor(t, app)
+	te.Document = "Brigadeiro R$ 3,50\nBolo de pote R$ 12"
+	var gotText string
+	te.ExtractProfile = func(_ context.Context, text, _ string) (content.PartialBusinessProfile, error) {
+		gotText = text
+		return content.PartialBusinessProfile{
+			Services: []content.PartialService{
+				{Name: "Brigadeiro", PriceBRL: new(3.5)},
+				{Name: "Bolo de pote", PriceBRL: new(12.0)},
+			},
+		}, nil
+	}
+
+	result, err := callTool(t, te, "import_document", map[string]any{
+		"customer_name": "Doceria",
+	}, "Elenice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotText != te.Document {
+		t.Errorf("extractor got %q, want the document text", gotText)
+	}
+	if !strings.Contains(result, "2 sugestões") {
+		t.Errorf("expected 2 suggestions in result, got: %s", result)
+	}
+
+	suggestions, err := app.FindAllRecords(domain.CollProfileSuggestions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(suggestions) != 2 {
+		t.Fatalf("expected 2 suggestions, got %d", len(suggestions))
+	}
+	for _, s := range suggestions {
+		if s.GetString("business") != biz.Id {
+			t.Errorf("suggestion business: got %q, want %q", s.GetString("business"), biz.Id)
+		}
+	}
+}
+
+func TestImportDocument_NoDocument(t *testing.T) {
+	app := newWave4TestApp(t)
+	wave4SeedBusiness(t, app, "Doceria da Bia", "Confeitaria", "Campinas")
+	te := newExecutor(t, app)
+
+	result, err := callTool(t, te, "import_document", map[string]any{
+		"customer_name": "Doceria",
+	}, "Elenice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(result, "Não tem documento") {
+		t.Errorf("expected missing document message, got: %s", result)
+	}
+}
+
+func TestPostRewrite(t *testing.T) {
+	app := newWave4TestApp(t)
+	biz := wave4SeedBusiness(t, app, "Maria", "Confeitaria", "SP")
+	post := wave4SeedPost(t, app, biz.Id, "Bolo caseiro é sempre a melhor pedida pra tarde de domingo com a família.")
+	te := newExecutor(t, app)
+
+	var gotInstruction string
+	te.Rewrite = func(_ context.Context, profile content.BusinessProfile, p content.Post, instruction string, _ []string) (content.Post, error) {
+		gotInstruction = instruction
+		p.Caption = "Domingo pede bolo caseiro. Bora?"
+		return p, nil
+	}
+
+	result, err := callTool(t, te, "rewrite_post", map[string]any{
+		"post_id":     post.Id,
+		"instruction": "deixa mais curto e divertido",
+	}, "Elenice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotInstruction != "deixa mais curto e divertido" {
+		t.Errorf("instruction: got %q", gotInstruction)
+	}
+	if !strings.Contains(result, "Domingo pede bolo caseiro") {
+		t.Errorf("expected new caption in result, got: %s", result)
+	}
+
+	updated, err := app.FindRecordById(domain.CollPosts, post.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !updated.GetBool("edited") || updated.GetString("original_caption") != post.GetString("caption") {
+		t.Errorf("edited=%v original_caption=%q", updated.GetBool("edited"), updated.GetString("original_caption"))
+	}
+}
+
+func TestSendAndLogStoresLoopWithoutReply(t *testing.T) {
+	app := newWave4TestApp(t)
+	a := &Agent{App: app, WAClient: &fakeWA{}, Logger: app.Logger()}
+	result := &agentResult{
+		ActionType: "INFO",
+		LoopMsgs: []Message{
+			NewAssistantMessage(ContentBlock{Type: "tool_use", ID: "toolu_1", Name: "list_posts", Input: []byte(`{}`)}),
+			NewUserMessage(NewToolResultBlock("toolu_1", "nenhum post", false)),
+		},
+	}
+
+	a.sendAndLog(context.Background(), types.NewJID("120363000000000000", types.GroupServer), "Ana", "5511999990000@s.whatsapp.net", result, time.Now())
+
+	stored, err := app.FindAllRecords(domain.CollAgentConversations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != 2 {
+		t.Errorf("stored %d conversation records, want the 2 loop messages", len(stored))
+	}
+}
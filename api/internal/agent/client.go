@@ -38,6 +38,7 @@ type apiRequest struct {
 	System    []apiTextBlock  `json:"system,omitempty"`
 	Messages  []Message       `json:"messages"`
 	Tools     []apiToolDef    `json:"tools,omitempty"`
+	Stream    bool            `json:"stream,omitempty"`
 }
 
 type apiTextBlock struct {
@@ -63,13 +64,14 @@ type apiUsage struct {
 	OutputTokens int `json:"output_tokens"`
 }
 
-// call sends a single request to the Messages API.
-func (c *Client) call(ctx context.Context, system string, messages []Message, tools []apiToolDef, maxTokens int) (*apiResponse, error) {
+// newRequest builds the HTTP request for /v1/messages.
+func (c *Client) newRequest(ctx context.Context, system string, messages []Message, tools []apiToolDef, maxTokens int, stream bool) (*http.Request, error) {
 	req := apiRequest{
 		Model:     c.Model,
 		MaxTokens: maxTokens,
 		Messages:  messages,
 		Tools:     tools,
+		Stream:    stream,
 	}
 	if system != "" {
 		req.System = []apiTextBlock{{Type: "text", Text: system}}
@@ -92,6 +94,15 @@ func (c *Client) call(ctx context.Context, system string, messages []Message, to
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("X-API-Key", c.APIKey)
 	httpReq.Header.Set("Anthropic-Version", anthropicVersion)
+	return httpReq, nil
+}
+
+// call sends a single request to the Messages API.
+func (c *Client) call(ctx context.Context, system string, messages []Message, tools []apiToolDef, maxTokens int) (*apiResponse, error) {
+	httpReq, err := c.newRequest(ctx, system, messages, tools, maxTokens, false)
+	if err != nil {
+		return nil, err
+	}
 
 	resp, err := http.DefaultClient.Do(httpReq)
 	if err != nil {
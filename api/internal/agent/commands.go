@@ -0,0 +1,129 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/denisraison/rekan/api/internal/service"
+)
+
+// command is a shortcut typed by an operator that maps straight to a tool call,
+// skipping the model. An empty Tool means the help text.
+type command struct {
+	Tool  string
+	Input map[string]any
+}
+
+// commandHelp lists the shortcuts. It is also sent when the model is unavailable.
+const commandHelp = `Comandos rápidos:
+- aprova <id>
+- rejeita <id> <o que mudar>
+- pendentes
+- clientes`
+
+// postIDArg matches a post ID or prefix. Words like "tudo", "primeiro" or
+// "anterior" must go to the model instead of being read as IDs, so an
+// argument without a digit only counts when it is the 8-char short ID from
+// search_posts or a full ID, and known finds a post starting with it.
+var postIDArg = regexp.MustCompile(`^[a-z0-9]{4,15}$`)
+
+func isPostIDArg(s string, known func(prefix string) bool) bool {
+	if !postIDArg.MatchString(s) {
+		return false
+	}
+	if strings.ContainsAny(s, "0123456789") {
+		return true
+	}
+	return (len(s) == 8 || len(s) == 15) && known != nil && known(s)
+}
+
+// parseCommand recognises the shortcut grammar. Anything that doesn't match
+// exactly returns false and goes through the normal tool-use loop. known
+// reports whether a post ID prefix exists; see isPostIDArg.
+func parseCommand(message string, known func(prefix string) bool) (command, bool) {
+	fields := strings.Fields(strings.TrimRight(strings.TrimSpace(message), ".!"))
+	if len(fields) == 0 {
+		return command{}, false
+	}
+	verb := service.NormalizeForMatch(fields[0])
+	rest := fields[1:]
+
+	switch verb {
+	case "aprova", "aprovar", "aprovo":
+		if len(rest) == 1 && isPostIDArg(strings.ToLower(rest[0]), known) {
+			return command{Tool: "approve_post", Input: map[string]any{"post_id": strings.ToLower(rest[0])}}, true
+		}
+	case "rejeita", "rejeitar", "rejeito":
+		if len(rest) >= 2 && isPostIDArg(strings.ToLower(rest[0]), known) {
+			return command{Tool: "reject_post", Input: map[string]any{
+				"post_id":  strings.ToLower(rest[0]),
+				"feedback": strings.Join(rest[1:], " "),
+			}}, true
+		}
+	case "lista", "listar":
+		if len(rest) == 1 {
+			return parseListCommand(service.NormalizeForMatch(rest[0]))
+		}
+	case "pendentes", "clientes":
+		if len(rest) == 0 {
+			return parseListCommand(verb)
+		}
+	case "comandos", "ajuda":
+		if len(rest) == 0 {
+			return command{}, true
+		}
+	}
+	return command{}, false
+}
+
+func parseListCommand(what string) (command, bool) {
+	switch what {
+	case "pendentes":
+		return command{Tool: "search_posts", Input: map[string]any{"status": "pending"}}, true
+	case "clientes":
+		return command{Tool: "search_customers", Input: map[string]any{}}, true
+	}
+	return command{}, false
+}
+
+// postIDKnown reports whether any post ID starts with prefix.
+func (a *Agent) postIDKnown(prefix string) bool {
+	posts, err := service.ListPosts(a.App, service.ListPostsFilter{PostIDPrefix: prefix})
+	return err == nil && len(posts) > 0
+}
+
+// runCommand executes a parsed shortcut through the same tools the model uses,
+// so results and action logging match the tool-use loop.
+func (a *Agent) runCommand(ctx context.Context, cmd command, operatorName, operatorJID string) *agentResult {
+	if cmd.Tool == "" {
+		return &agentResult{ReplyText: commandHelp, ActionType: "INFO"}
+	}
+
+	executor := a.newToolExecutor(ctx, operatorJID)
+	input, _ := json.Marshal(cmd.Input) // string values only, cannot fail
+
+	var reply string
+	for _, tool := range buildTools(executor, operatorName) {
+		if tool.Name == cmd.Tool {
+			var err error
+			reply, err = tool.Execute(ctx, input)
+			if err != nil {
+				a.Logger.Error("agent: command failed", "tool", cmd.Tool, "error", err)
+				reply = "Erro: " + err.Error()
+			}
+			break
+		}
+	}
+
+	actionType := toolNameToActionType(cmd.Tool)
+	if actionType == "" {
+		actionType = "INFO"
+	}
+	return &agentResult{
+		ReplyText:   strings.TrimSpace(reply),
+		ToolSummary: buildToolSummary([]toolCallEntry{{Name: cmd.Tool}}),
+		ActionType:  actionType,
+	}
+}
@@ -0,0 +1,86 @@
+package agent
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/denisraison/rekan/api/internal/domain"
+)
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		msg   string
+		ok    bool
+		tool  string
+		input map[string]any
+	}{
+		{"aprova a1b2c3", true, "approve_post", map[string]any{"post_id": "a1b2c3"}},
+		{"Aprovar A1B2C3!", true, "approve_post", map[string]any{"post_id": "a1b2c3"}},
+		{"rejeita a1b2c3 muito formal", true, "reject_post", map[string]any{"post_id": "a1b2c3", "feedback": "muito formal"}},
+		{"lista pendentes", true, "search_posts", map[string]any{"status": "pending"}},
+		{"pendentes", true, "search_posts", map[string]any{"status": "pending"}},
+		{"Lista clientes", true, "search_customers", map[string]any{}},
+		{"comandos", true, "", nil},
+		{"aprova tudo", false, "", nil},
+		{"rejeita primeiro muito formal", false, "", nil},
+		{"rejeita anterior ficou longo", false, "", nil},
+		{"aprova abcdefgh", true, "approve_post", map[string]any{"post_id": "abcdefgh"}},
+		{"aprova qwertyui", false, "", nil},
+		{"aprova a1b2c3 e manda", false, "", nil},
+		{"rejeita a1b2c3", false, "", nil},
+		{"lista os posts da Ana", false, "", nil},
+		{"aprova o post da Ana", false, "", nil},
+		{"", false, "", nil},
+	}
+	// Only one all-letter ID exists; "primeiro" and "anterior" are words.
+	known := func(prefix string) bool { return prefix == "abcdefgh" }
+	for _, tt := range tests {
+		cmd, ok := parseCommand(tt.msg, known)
+		if ok != tt.ok {
+			t.Errorf("parseCommand(%q) ok = %v, want %v", tt.msg, ok, tt.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if cmd.Tool != tt.tool {
+			t.Errorf("parseCommand(%q) tool = %q, want %q", tt.msg, cmd.Tool, tt.tool)
+		}
+		if tt.input != nil && !reflect.DeepEqual(cmd.Input, tt.input) {
+			t.Errorf("parseCommand(%q) input = %v, want %v", tt.msg, cmd.Input, tt.input)
+		}
+	}
+}
+
+func TestRunCommand_Approve(t *testing.T) {
+	app := newWave4TestApp(t)
+	biz := wave4SeedBusiness(t, app, "Ana", "Manicure", "SP")
+	post := wave4SeedPost(t, app, biz.Id, "Unhas de gel")
+	a := &Agent{App: app}
+
+	cmd, ok := parseCommand("aprova "+shortPostID(post.Id), a.postIDKnown)
+	if !ok {
+		t.Fatal("expected command to parse")
+	}
+	result := a.runCommand(context.Background(), cmd, "Elenice", "5511999990000@s.whatsapp.net")
+
+	if !strings.Contains(result.ReplyText, "aprovado") {
+		t.Errorf("expected approval reply, got: %s", result.ReplyText)
+	}
+	if result.ActionType != ActionPostApprove {
+		t.Errorf("action type: got %q, want %q", result.ActionType, ActionPostApprove)
+	}
+	if result.ToolSummary != "[Ferramentas: approve_post]" {
+		t.Errorf("tool summary: got %q", result.ToolSummary)
+	}
+
+	updated, err := app.FindRecordById(domain.CollPosts, post.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !updated.GetBool("reviewed") {
+		t.Error("post should be reviewed after approve command")
+	}
+}
@@ -2,16 +2,23 @@ package agent
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/denisraison/rekan/api/internal/domain"
+	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase/core"
 )
 
 // StoreMessage saves a message to the agent_conversations collection.
 // The structured parameter holds the JSON-serialized MessageParam for replay.
 func StoreMessage(app core.App, operatorName, operatorJID, role, content, mediaType, structured string) error {
+	return storeMessage(app, operatorName, operatorJID, role, content, mediaType, structured, nil)
+}
+
+// storeMessage is StoreMessage plus the WhatsApp message IDs the record came from.
+func storeMessage(app core.App, operatorName, operatorJID, role, content, mediaType, structured string, waMessageIDs []string) error {
 	col, err := app.FindCachedCollectionByNameOrId(domain.CollAgentConversations)
 	if err != nil {
 		return fmt.Errorf("agent_conversations collection: %w", err)
@@ -24,6 +31,34 @@ func StoreMessage(app core.App, operatorName, operatorJID, role, content, mediaT
 	record.Set("media_type", mediaType)
 	record.Set("structured", structured)
 	record.Set("timestamp", time.Now().UTC().Format(time.RFC3339))
+	if len(waMessageIDs) > 0 {
+		record.Set("wa_message_ids", waMessageIDs)
+	}
+	return app.Save(record)
+}
+
+// findByWAMessageID returns the newest user conversation record produced by
+// the given WhatsApp message, or nil if it was pruned or never stored.
+func findByWAMessageID(app core.App, messageID string) *core.Record {
+	records, err := app.FindRecordsByFilter(domain.CollAgentConversations,
+		"role = 'user' && wa_message_ids ~ {:id}", "-timestamp", 10, 0,
+		dbx.Params{"id": `"` + messageID + `"`})
+	if err != nil {
+		return nil
+	}
+	// The LIKE filter is a coarse match; confirm the exact ID.
+	for _, r := range records {
+		var ids []string
+		if r.UnmarshalJSONField("wa_message_ids", &ids) == nil && slices.Contains(ids, messageID) {
+			return r
+		}
+	}
+	return nil
+}
+
+// markRevoked records that the operator deleted the message behind a conversation record.
+func markRevoked(app core.App, record *core.Record) error {
+	record.Set("revoked_at", time.Now().UTC())
 	return app.Save(record)
 }
 
@@ -1,6 +1,7 @@
 package agent
 
 import (
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -15,8 +16,15 @@ type Debouncer struct {
 }
 
 type pendingOp struct {
-	messages []string
+	messages []pendingMessage
 	timer    *time.Timer
+	process  func(combined string, messageIDs []string)
+}
+
+// pendingMessage keeps the WhatsApp message ID so edits and revokes can find it.
+type pendingMessage struct {
+	id   string
+	text string
 }
 
 func NewDebouncer() *Debouncer {
@@ -26,28 +34,87 @@ func NewDebouncer() *Debouncer {
 }
 
 // Submit adds a message for the given operator JID. After debounceWindow of silence,
-// process is called with all concatenated messages. process is called in a new goroutine.
-func (d *Debouncer) Submit(jid, text string, process func(combined string)) {
+// process is called with all concatenated messages and their IDs. process is called
+// in a new goroutine.
+func (d *Debouncer) Submit(jid, messageID, text string, process func(combined string, messageIDs []string)) {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
 	op, exists := d.pending[jid]
 	if exists {
 		op.timer.Stop()
-		op.messages = append(op.messages, text)
+		op.messages = append(op.messages, pendingMessage{id: messageID, text: text})
 	} else {
 		op = &pendingOp{
-			messages: []string{text},
+			messages: []pendingMessage{{id: messageID, text: text}},
 		}
 		d.pending[jid] = op
 	}
+	op.process = process
+	d.schedule(jid, op)
+}
+
+// Edit replaces the text of a message that is still waiting in the window and
+// restarts the timer. Returns false if the message is not pending.
+func (d *Debouncer) Edit(messageID, text string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for jid, op := range d.pending {
+		i := slices.IndexFunc(op.messages, func(m pendingMessage) bool { return m.id == messageID })
+		if i < 0 {
+			continue
+		}
+		op.timer.Stop()
+		op.messages[i].text = text
+		d.schedule(jid, op)
+		return true
+	}
+	return false
+}
 
+// Revoke drops a message that is still waiting in the window. When it was the
+// only pending message, nothing is processed. Returns false if the message is
+// not pending.
+func (d *Debouncer) Revoke(messageID string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for jid, op := range d.pending {
+		i := slices.IndexFunc(op.messages, func(m pendingMessage) bool { return m.id == messageID })
+		if i < 0 {
+			continue
+		}
+		op.messages = slices.Delete(op.messages, i, i+1)
+		if len(op.messages) == 0 {
+			op.timer.Stop()
+			delete(d.pending, jid)
+		}
+		return true
+	}
+	return false
+}
+
+// schedule (re)arms the op timer. Must be called with d.mu held.
+func (d *Debouncer) schedule(jid string, op *pendingOp) {
 	op.timer = time.AfterFunc(debounceWindow, func() {
 		d.mu.Lock()
-		msgs := op.messages
+		// A stale timer may fire after the op was cancelled or already flushed.
+		if d.pending[jid] != op {
+			d.mu.Unlock()
+			return
+		}
 		delete(d.pending, jid)
+		msgs := op.messages
+		process := op.process
 		d.mu.Unlock()
 
-		process(strings.Join(msgs, " "))
+		texts := make([]string, len(msgs))
+		ids := make([]string, len(msgs))
+		for i, m := range msgs {
+			texts[i] = m.text
+			ids[i] = m.id
+		}
+		process(strings.Join(texts, " "), ids)
 	})
 }
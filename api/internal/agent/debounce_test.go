@@ -0,0 +1,79 @@
+package agent
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+type processed struct {
+	combined string
+	ids      []string
+}
+
+func collect(ch chan processed) func(string, []string) {
+	return func(combined string, ids []string) {
+		ch <- processed{combined, ids}
+	}
+}
+
+func TestDebouncer_CombinesMessages(t *testing.T) {
+	d := NewDebouncer()
+	ch := make(chan processed, 2)
+	d.Submit("op", "m1", "aprova", collect(ch))
+	d.Submit("op", "m2", "o post", collect(ch))
+
+	got := <-ch
+	if got.combined != "aprova o post" {
+		t.Errorf("combined: got %q", got.combined)
+	}
+	if !slices.Equal(got.ids, []string{"m1", "m2"}) {
+		t.Errorf("ids: got %v", got.ids)
+	}
+}
+
+func TestDebouncer_EditReplacesPending(t *testing.T) {
+	d := NewDebouncer()
+	ch := make(chan processed, 2)
+	d.Submit("op", "m1", "busca a Anna", collect(ch))
+
+	if !d.Edit("m1", "busca a Ana") {
+		t.Fatal("expected pending message to be edited")
+	}
+	if d.Edit("other", "x") {
+		t.Error("edit of unknown message should return false")
+	}
+
+	got := <-ch
+	if got.combined != "busca a Ana" {
+		t.Errorf("combined: got %q", got.combined)
+	}
+}
+
+func TestDebouncer_RevokeCancelsPending(t *testing.T) {
+	d := NewDebouncer()
+	ch := make(chan processed, 2)
+	d.Submit("op", "m1", "aprova a1b2c3", collect(ch))
+	d.Submit("op", "m2", "e rejeita d4e5f6", collect(ch))
+
+	if !d.Revoke("m2") {
+		t.Fatal("expected pending message to be revoked")
+	}
+	got := <-ch
+	if got.combined != "aprova a1b2c3" || !slices.Equal(got.ids, []string{"m1"}) {
+		t.Errorf("got %+v", got)
+	}
+
+	d.Submit("op", "m3", "apaga tudo", collect(ch))
+	if !d.Revoke("m3") {
+		t.Fatal("expected pending message to be revoked")
+	}
+	select {
+	case got := <-ch:
+		t.Errorf("revoked op still processed: %+v", got)
+	case <-time.After(debounceWindow + 500*time.Millisecond):
+	}
+	if d.Revoke("m3") {
+		t.Error("second revoke should return false")
+	}
+}
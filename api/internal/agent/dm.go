@@ -0,0 +1,368 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+
+	"github.com/denisraison/rekan/api/internal/domain"
+	"github.com/denisraison/rekan/api/internal/postingtime"
+	"github.com/denisraison/rekan/api/internal/pricing"
+	"github.com/denisraison/rekan/api/internal/service"
+	wa "github.com/denisraison/rekan/api/internal/whatsapp"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+const (
+	// handoffQuiet is how long the assistant stays silent with a client after
+	// passing them to the operators.
+	handoffQuiet = 12 * time.Hour
+	// dmHistory is how many earlier messages with the client the model sees.
+	dmHistory = 10
+	// dmMaxTurns caps tool round trips; the DM tools are cheap lookups.
+	dmMaxTurns = 4
+)
+
+// dmHandoffReply tells the client the team will answer when the assistant
+// hands off without a reply of its own.
+const dmHandoffReply = "Vou pedir pra alguém da equipe te responder, tá? Já já te chamam por aqui."
+
+// Action types logged by the DM assistant.
+const (
+	ActionClientReply   = "CLIENT_REPLY"
+	ActionClientHandoff = "CLIENT_HANDOFF"
+)
+
+// DMAgent answers direct messages from active clients who opted in. It can
+// only read the client's own plan, billing date and posts; anything else goes
+// to the operator group.
+type DMAgent struct {
+	App       core.App
+	WAClient  WAClient
+	Logger    *slog.Logger
+	Claude    *Client
+	Debouncer *Debouncer
+	// OperatorGroup receives handoffs. Empty means handoffs are only logged.
+	OperatorGroup types.JID
+}
+
+// NewDMAgent creates a DMAgent that hands off to the operator group with the
+// given ID (the user part of the group JID).
+func NewDMAgent(app core.App, waClient WAClient, logger *slog.Logger, claudeAPIKey, operatorGroupID string) *DMAgent {
+	var operatorGroup types.JID
+	if operatorGroupID != "" {
+		operatorGroup = types.NewJID(operatorGroupID, types.GroupServer)
+	}
+	return &DMAgent{
+		App:           app,
+		WAClient:      waClient,
+		Logger:        logger,
+		Claude:        NewClient(claudeAPIKey),
+		Debouncer:     NewDebouncer(),
+		OperatorGroup: operatorGroup,
+	}
+}
+
+// HandleClientMessage is called for every stored incoming text from a client.
+// Messages are debounced per phone, so a burst gets a single answer.
+func (d *DMAgent) HandleClientMessage(businessID, phone, messageID, text string) {
+	d.Debouncer.Submit(phone, messageID, text, func(combined string, messageIDs []string) {
+		d.ProcessMessage(businessID, phone, messageIDs, combined)
+	})
+}
+
+// ProcessMessage answers a client if the assistant is on for their business
+// and they were not handed off recently.
+func (d *DMAgent) ProcessMessage(businessID, phone string, messageIDs []string, message string) {
+	start := time.Now()
+	business, err := d.App.FindRecordById(domain.CollBusinesses, businessID)
+	if err != nil || !assistantActive(business, start) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	chat := types.JID{User: phone, Server: types.DefaultUserServer}
+	stop := wa.Typing(ctx, d.WAClient, chat)
+	defer stop()
+
+	history, err := d.loadHistory(phone, messageIDs)
+	if err != nil {
+		d.Logger.Error("dm agent: failed to load history", "error", err)
+	}
+	messages := appendOrMergeUser(mergeConsecutiveRoles(history), message)
+
+	executor := &dmExecutor{agent: d, business: business, phone: phone, message: message}
+	result, err := d.Claude.Run(ctx, RunConfig{
+		System:   buildDMPrompt(business, start),
+		Messages: messages,
+		Tools:    executor.tools(),
+		MaxTurns: dmMaxTurns,
+	})
+	if err != nil {
+		d.Logger.Error("dm agent: tool-use loop failed", "business", businessID, "error", err)
+		// Never leave the client talking to a broken bot.
+		executor.handoff(ctx, "assistente falhou: "+err.Error())
+		LogAction(d.App, business.GetString("name"), phone, ActionClientHandoff, nil, err.Error(), false, start)
+		return
+	}
+
+	reply := strings.TrimSpace(result.Reply)
+	if result.MaxTurns && !executor.handedOff {
+		// The model kept calling tools without settling on an answer; the
+		// operators take over rather than leave the client waiting.
+		executor.handoff(ctx, "assistente não conseguiu concluir a resposta")
+		if reply == "" {
+			reply = dmHandoffReply
+		}
+	}
+	if reply == "" {
+		return
+	}
+	resp, err := d.WAClient.SendMessage(ctx, chat, &waE2E.Message{Conversation: &reply})
+	if err != nil {
+		d.Logger.Error("dm agent: failed to send reply", "error", err)
+		LogAction(d.App, business.GetString("name"), phone, ActionClientReply, nil, err.Error(), false, start)
+		return
+	}
+	service.StoreOutgoingMessage(d.App, service.OutgoingMessage{
+		BusinessID:  businessID,
+		Phone:       phone,
+		Type:        domain.MsgTypeText,
+		Content:     reply,
+		WAMessageID: resp.ID,
+	})
+
+	action := ActionClientReply
+	if executor.handedOff {
+		action = ActionClientHandoff
+	}
+	LogAction(d.App, business.GetString("name"), phone, action, nil, reply, true, start)
+}
+
+// assistantActive reports whether the assistant may answer this business now.
+func assistantActive(business *core.Record, now time.Time) bool {
+	if !business.GetBool("assistant_enabled") || business.GetString("invite_status") != domain.InviteStatusActive {
+		return false
+	}
+	handoff := business.GetDateTime("assistant_handoff_at").Time()
+	return handoff.IsZero() || now.Sub(handoff) >= handoffQuiet
+}
+
+// loadHistory returns the last dmHistory text messages with phone, oldest
+// first, leaving out the ones being answered now.
+func (d *DMAgent) loadHistory(phone string, skipIDs []string) ([]Message, error) {
+	var records []*core.Record
+	err := d.App.RecordQuery(domain.CollMessages).
+		AndWhere(dbx.HashExp{"phone": phone, "type": domain.MsgTypeText}).
+		OrderBy("created DESC").
+		Limit(int64(dmHistory + len(skipIDs))).
+		All(&records)
+	if err != nil {
+		return nil, fmt.Errorf("loading dm history: %w", err)
+	}
+
+	var history []Message
+	for i := len(records) - 1; i >= 0; i-- {
+		r := records[i]
+		text := r.GetString("content")
+		if text == "" || slices.Contains(skipIDs, r.GetString("wa_message_id")) {
+			continue
+		}
+		if r.GetString("direction") == domain.DirectionOutgoing {
+			history = append(history, NewAssistantMessage(NewTextBlock(text)))
+		} else {
+			history = append(history, NewUserMessage(NewTextBlock(text)))
+		}
+	}
+	if len(history) > dmHistory {
+		history = history[len(history)-dmHistory:]
+	}
+	return history, nil
+}
+
+func buildDMPrompt(business *core.Record, now time.Time) string {
+	name := business.GetString("client_name")
+	if name == "" {
+		name = business.GetString("name")
+	}
+	return fmt.Sprintf(`Você é o assistente da Rekan respondendo no WhatsApp uma cliente que assina o serviço de posts pro Instagram.
+
+Cliente: %s, do negócio %s (%s, %s).
+
+Tom: português brasileiro informal, curto e caloroso, como uma pessoa da equipe. Texto puro, sem markdown, sem travessão, no máximo um emoji.
+
+Você só responde sobre: plano contratado (get_plan), data da próxima cobrança (get_next_charge), posts recentes e o próximo post (get_recent_posts) e melhor horário pra postar (get_posting_tip). Use as ferramentas; nunca invente datas, valores ou posts.
+
+Chame handoff_to_operator e avise a cliente que alguém da equipe vai responder em breve quando:
+- a pergunta estiver fora desses assuntos (mudar post, cancelar, trocar plano, pagamento com problema, pedido novo);
+- a cliente parecer chateada, reclamar ou pedir pra falar com uma pessoa;
+- você não tiver certeza da resposta.
+
+Nunca prometa mudanças, descontos ou prazos. Não fale de outras clientes.
+
+Hoje é %s.`, name, business.GetString("name"), business.GetString("type"), business.GetString("city"), today(now))
+}
+
+// dmExecutor runs the DM tools for a single client. Every tool is scoped to
+// that client's business; none take an ID from the model.
+type dmExecutor struct {
+	agent     *DMAgent
+	business  *core.Record
+	phone     string
+	message   string
+	handedOff bool
+}
+
+func (e *dmExecutor) tools() []Tool {
+	empty := marshalSchema(map[string]any{"type": "object", "properties": map[string]any{}})
+	tool := func(name, desc string, fn func() string) Tool {
+		return Tool{
+			Name:        name,
+			Description: desc,
+			InputSchema: empty,
+			Execute: func(context.Context, json.RawMessage) (string, error) {
+				return fn(), nil
+			},
+		}
+	}
+
+	return []Tool{
+		tool("get_plan", "Plano da cliente: nome, posts por mês, período e valor.", e.plan),
+		tool("get_next_charge", "Data e valor da próxima cobrança.", e.nextCharge),
+		tool("get_recent_posts", "Últimos posts enviados e próximos posts planejados da cliente.", e.recentPosts),
+		tool("get_posting_tip", "Melhor horário pra postar no Instagram para o tipo de negócio da cliente.", e.postingTip),
+		{
+			Name:        "handoff_to_operator",
+			Description: "Passa a conversa pra equipe. Use quando o assunto está fora do seu escopo ou a cliente está insatisfeita.",
+			InputSchema: marshalSchema(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"reason": map[string]any{"type": "string", "description": "Motivo em uma frase, para a equipe"},
+				},
+				"required": []string{"reason"},
+			}),
+			Execute: func(ctx context.Context, input json.RawMessage) (string, error) {
+				var args struct {
+					Reason string `json:"reason"`
+				}
+				if err := json.Unmarshal(input, &args); err != nil {
+					return "Erro ao ler parâmetros.", nil
+				}
+				return e.handoff(ctx, args.Reason), nil
+			},
+		},
+	}
+}
+
+func (e *dmExecutor) plan() string {
+	tier := pricing.Tier(e.business.GetString("tier"))
+	commitment := pricing.Commitment(e.business.GetString("commitment"))
+	price, ok := pricing.Price(tier, commitment)
+	if !ok {
+		return "Plano não definido no cadastro."
+	}
+	return fmt.Sprintf("Plano: %s\nPosts por mês: %d\nPeríodo: %s\nValor por período: %s",
+		tier, pricing.Posts(tier), commitment, formatBRL(price))
+}
+
+func (e *dmExecutor) nextCharge() string {
+	next := e.business.GetDateTime("next_charge_date")
+	if next.IsZero() {
+		return "Sem data de cobrança no cadastro."
+	}
+	out := "Próxima cobrança: " + next.Time().In(domain.Location).Format("02/01/2006")
+	price, ok := pricing.Price(pricing.Tier(e.business.GetString("tier")), pricing.Commitment(e.business.GetString("commitment")))
+	if ok {
+		out += "\nValor: " + formatBRL(price)
+	}
+	if e.business.GetBool("charge_pending") {
+		out += "\nA cobrança já foi gerada e aguarda pagamento."
+	}
+	return out
+}
+
+func (e *dmExecutor) recentPosts() string {
+	app := e.agent.App
+	var b strings.Builder
+
+	var sent []*core.Record
+	err := app.RecordQuery(domain.CollMessages).
+		AndWhere(dbx.HashExp{"business": e.business.Id, "direction": domain.DirectionOutgoing}).
+		AndWhere(dbx.NewExp("post != ''")).
+		OrderBy("created DESC").
+		Limit(3).
+		All(&sent)
+	if err != nil {
+		return "Erro ao buscar posts."
+	}
+	if len(sent) == 0 {
+		b.WriteString("Nenhum post enviado ainda.\n")
+	} else {
+		b.WriteString("Últimos posts enviados:\n")
+		for _, m := range sent {
+			fmt.Fprintf(&b, "- %s: \"%s\"\n", m.GetDateTime("sent_at").Time().In(domain.Location).Format("02/01"), truncate(m.GetString("content"), 80))
+		}
+	}
+
+	now := time.Now()
+	upcoming, err := service.ListCalendar(app, service.CalendarFilter{
+		BusinessIDs: []string{e.business.Id},
+		From:        now,
+		To:          now.AddDate(0, 0, 30),
+	})
+	if err != nil {
+		return "Erro ao buscar calendário."
+	}
+	if len(upcoming) == 0 {
+		b.WriteString("Nenhum post planejado nos próximos 30 dias.")
+		return b.String()
+	}
+	b.WriteString("Próximos posts planejados:\n")
+	for _, entry := range upcoming[:min(len(upcoming), 3)] {
+		fmt.Fprintf(&b, "- %s %s\n", weekdays[entry.PlannedFor.In(domain.Location).Weekday()], entry.PlannedFor.In(domain.Location).Format("02/01"))
+	}
+	return b.String()
+}
+
+func (e *dmExecutor) postingTip() string {
+	return postingtime.Tip(e.business.GetString("type"))
+}
+
+// handoff marks the business as handed off and tells the operator group.
+func (e *dmExecutor) handoff(ctx context.Context, reason string) string {
+	e.handedOff = true
+	// Reload so an edit made while the model was running is not overwritten
+	// with the copy loaded at the start of the turn.
+	if business, err := e.agent.App.FindRecordById(domain.CollBusinesses, e.business.Id); err != nil {
+		e.agent.Logger.Error("dm agent: failed to reload business for handoff", "error", err)
+	} else {
+		business.Set("assistant_handoff_at", time.Now().UTC())
+		if err := e.agent.App.Save(business); err != nil {
+			e.agent.Logger.Error("dm agent: failed to save handoff", "error", err)
+		}
+	}
+
+	text := fmt.Sprintf("%s (+%s) precisa de atenção no privado.\nMotivo: %s\nMensagem: \"%s\"",
+		e.business.GetString("name"), e.phone, reason, e.message)
+	if e.agent.OperatorGroup.IsEmpty() {
+		e.agent.Logger.Info("dm agent: handoff not sent, no operator group", "text", text)
+	} else if err := SendReply(ctx, e.agent.WAClient, e.agent.OperatorGroup, text); err != nil {
+		e.agent.Logger.Error("dm agent: failed to send handoff", "error", err)
+	}
+	return "Equipe avisada. Diga à cliente que alguém da equipe vai responder em breve."
+}
+
+// formatBRL formats a price as "R$ 108,90".
+func formatBRL(v float64) string {
+	return "R$ " + strings.Replace(fmt.Sprintf("%.2f", v), ".", ",", 1)
+}
@@ -0,0 +1,228 @@
+package agent
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+
+	"github.com/denisraison/rekan/api/internal/domain"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// fakeWA records text messages sent per chat.
+type fakeWA struct {
+	mu   sync.Mutex
+	sent map[string][]string
+}
+
+func (f *fakeWA) SendMessage(_ context.Context, to types.JID, msg *waE2E.Message) (whatsmeow.SendResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.sent == nil {
+		f.sent = map[string][]string{}
+	}
+	f.sent[to.String()] = append(f.sent[to.String()], msg.GetConversation())
+	return whatsmeow.SendResponse{ID: "wa-reply"}, nil
+}
+
+func (f *fakeWA) SendChatPresence(context.Context, types.JID, types.ChatPresence, types.ChatPresenceMedia) error {
+	return nil
+}
+
+func (f *fakeWA) ResolveLID(_ context.Context, jid types.JID) types.JID { return jid }
+
+func (f *fakeWA) Download(context.Context, whatsmeow.DownloadableMessage) ([]byte, error) {
+	return nil, nil
+}
+
+func (f *fakeWA) Upload(context.Context, []byte, whatsmeow.MediaType) (whatsmeow.UploadResponse, error) {
+	return whatsmeow.UploadResponse{}, nil
+}
+
+func (f *fakeWA) sentTo(jid string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.sent[jid]
+}
+
+// scriptedClaude answers with a tool call on the first request and with
+// reply on the second, keeping every request body.
+func scriptedClaude(t *testing.T, tool string, input string, reply string) (*Client, *atomic.Int32, *[]string) {
+	t.Helper()
+	var calls atomic.Int32
+	var mu sync.Mutex
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(body))
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if calls.Add(1) == 1 {
+			writeResponse(w, []ContentBlock{{Type: "tool_use", ID: "toolu_1", Name: tool, Input: []byte(input)}}, "tool_use")
+			return
+		}
+		writeResponse(w, []ContentBlock{NewTextBlock(reply)}, "end_turn")
+	}))
+	t.Cleanup(server.Close)
+	return testClient(server.URL), &calls, &bodies
+}
+
+func seedDMBusiness(t *testing.T, app core.App, enabled bool) *core.Record {
+	t.Helper()
+	biz := wave4SeedBusiness(t, app, "Padaria da Ana", "Padaria", "Goiania")
+	biz.Set("phone", "5562999990000")
+	biz.Set("tier", "parceiro")
+	biz.Set("commitment", "mensal")
+	biz.Set("next_charge_date", "2026-11-05 12:00:00.000Z")
+	biz.Set("assistant_enabled", enabled)
+	if err := app.Save(biz); err != nil {
+		t.Fatal(err)
+	}
+	return biz
+}
+
+func TestDMAgentAnswersFromTools(t *testing.T) {
+	app := newWave4TestApp(t)
+	biz := seedDMBusiness(t, app, true)
+	claude, _, bodies := scriptedClaude(t, "get_next_charge", `{}`, "Sua próxima cobrança é dia 05/11, de R$ 108,90.")
+	waClient := &fakeWA{}
+	d := &DMAgent{App: app, WAClient: waClient, Logger: app.Logger(), Claude: claude}
+
+	d.ProcessMessage(biz.Id, "5562999990000", []string{"in1"}, "quando vence minha mensalidade?")
+
+	sent := waClient.sentTo("5562999990000@s.whatsapp.net")
+	if len(sent) != 1 || !strings.Contains(sent[0], "05/11") {
+		t.Fatalf("client got %q", sent)
+	}
+	if len(*bodies) != 2 || !strings.Contains((*bodies)[1], "Próxima cobrança: 05/11/2026") || !strings.Contains((*bodies)[1], "R$ 108,90") {
+		t.Errorf("tool result not sent back to the model: %v", *bodies)
+	}
+
+	stored, err := app.FindFirstRecordByFilter(domain.CollMessages, "wa_message_id = 'wa-reply'")
+	if err != nil {
+		t.Fatalf("reply not stored: %v", err)
+	}
+	if stored.GetString("direction") != domain.DirectionOutgoing || stored.GetString("business") != biz.Id {
+		t.Errorf("stored reply: direction %q, business %q", stored.GetString("direction"), stored.GetString("business"))
+	}
+}
+
+func TestDMAgentHandoffSilencesAssistant(t *testing.T) {
+	app := newWave4TestApp(t)
+	biz := seedDMBusiness(t, app, true)
+	claude, calls, _ := scriptedClaude(t, "handoff_to_operator", `{"reason":"quer cancelar"}`, "Vou chamar alguém da equipe pra te ajudar!")
+	waClient := &fakeWA{}
+	group := types.NewJID("120363000000000000", types.GroupServer)
+	d := &DMAgent{App: app, WAClient: waClient, Logger: app.Logger(), Claude: claude, OperatorGroup: group}
+
+	d.ProcessMessage(biz.Id, "5562999990000", []string{"in1"}, "quero cancelar, não gostei")
+
+	tasks := waClient.sentTo(group.String())
+	if len(tasks) != 1 || !strings.Contains(tasks[0], "quer cancelar") || !strings.Contains(tasks[0], "quero cancelar, não gostei") {
+		t.Fatalf("operator group got %q", tasks)
+	}
+	if got := waClient.sentTo("5562999990000@s.whatsapp.net"); len(got) != 1 {
+		t.Errorf("client got %q, want the handoff reply", got)
+	}
+	biz = reloadRecord(t, app, biz)
+	if biz.GetDateTime("assistant_handoff_at").IsZero() {
+		t.Fatal("assistant_handoff_at not set")
+	}
+
+	// The operators own the conversation now.
+	d.ProcessMessage(biz.Id, "5562999990000", []string{"in2"}, "oi?")
+	if calls.Load() != 2 {
+		t.Errorf("assistant answered after handoff: %d model calls", calls.Load())
+	}
+
+	biz.Set("assistant_handoff_at", time.Now().Add(-handoffQuiet-time.Minute).UTC())
+	if !assistantActive(biz, time.Now()) {
+		t.Error("assistant should resume after the quiet period")
+	}
+}
+
+func TestDMHandoffKeepsConcurrentEdits(t *testing.T) {
+	app := newWave4TestApp(t)
+	biz := seedDMBusiness(t, app, true)
+	e := &dmExecutor{agent: &DMAgent{App: app, WAClient: &fakeWA{}, Logger: app.Logger()}, business: biz, phone: "5562999990000", message: "oi"}
+
+	// The operator edits the client while the model is still running.
+	edited := reloadRecord(t, app, biz)
+	edited.Set("city", "Anápolis")
+	if err := app.Save(edited); err != nil {
+		t.Fatal(err)
+	}
+
+	e.handoff(context.Background(), "quer falar com alguém")
+
+	got := reloadRecord(t, app, biz)
+	if got.GetString("city") != "Anápolis" {
+		t.Errorf("city = %q, handoff overwrote the operator's edit", got.GetString("city"))
+	}
+	if got.GetDateTime("assistant_handoff_at").IsZero() {
+		t.Error("assistant_handoff_at not set")
+	}
+}
+
+func TestDMAgentHandsOffAtMaxTurns(t *testing.T) {
+	app := newWave4TestApp(t)
+	biz := seedDMBusiness(t, app, true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		writeResponse(w, []ContentBlock{{Type: "tool_use", ID: "toolu_1", Name: "get_plan", Input: []byte(`{}`)}}, "tool_use")
+	}))
+	t.Cleanup(server.Close)
+	waClient := &fakeWA{}
+	group := types.NewJID("120363000000000000", types.GroupServer)
+	d := &DMAgent{App: app, WAClient: waClient, Logger: app.Logger(), Claude: testClient(server.URL), OperatorGroup: group}
+
+	d.ProcessMessage(biz.Id, "5562999990000", []string{"in1"}, "qual meu plano?")
+
+	if got := waClient.sentTo("5562999990000@s.whatsapp.net"); len(got) != 1 || got[0] != dmHandoffReply {
+		t.Errorf("client got %q, want the handoff reply", got)
+	}
+	if tasks := waClient.sentTo(group.String()); len(tasks) != 1 || !strings.Contains(tasks[0], "qual meu plano?") {
+		t.Errorf("operator group got %q", tasks)
+	}
+	if reloadRecord(t, app, biz).GetDateTime("assistant_handoff_at").IsZero() {
+		t.Error("assistant_handoff_at not set")
+	}
+}
+
+func TestDMAgentRequiresOptIn(t *testing.T) {
+	app := newWave4TestApp(t)
+	biz := seedDMBusiness(t, app, false)
+	claude, calls, _ := scriptedClaude(t, "get_plan", `{}`, "oi")
+	d := &DMAgent{App: app, WAClient: &fakeWA{}, Logger: app.Logger(), Claude: claude}
+
+	d.ProcessMessage(biz.Id, "5562999990000", []string{"in1"}, "qual meu plano?")
+	if calls.Load() != 0 {
+		t.Errorf("assistant answered a client who did not opt in")
+	}
+
+	biz.Set("assistant_enabled", true)
+	biz.Set("invite_status", domain.InviteStatusCancelled)
+	if assistantActive(biz, time.Now()) {
+		t.Error("assistant should not answer a cancelled client")
+	}
+}
+
+func reloadRecord(t *testing.T, app core.App, r *core.Record) *core.Record {
+	t.Helper()
+	fresh, err := app.FindRecordById(r.Collection().Name, r.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fresh
+}
@@ -0,0 +1,57 @@
+package agent
+
+import (
+	"fmt"
+
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// handleProtocolMessage applies operator edits and deletes.
+// Inside the debounce window the pending text is replaced or dropped. After
+// processing, an edit re-runs the request with a note and a delete is
+// recorded on the conversation record (the action itself is not undone).
+func (a *Agent) handleProtocolMessage(groupJID, senderJID types.JID, operatorName, operatorJID string, pm *waE2E.ProtocolMessage) {
+	originalID := pm.GetKey().GetID()
+	if originalID == "" {
+		return
+	}
+
+	switch pm.GetType() {
+	case waE2E.ProtocolMessage_MESSAGE_EDIT:
+		text := messageText(pm.GetEditedMessage())
+		if text == "" {
+			return
+		}
+		if a.Debouncer.Edit(originalID, text) {
+			a.Logger.Info("agent: pending message edited", "operator", operatorName, "message_id", originalID)
+			return
+		}
+
+		record := findByWAMessageID(a.App, originalID)
+		if record == nil {
+			a.Logger.Debug("agent: edit for unknown message ignored", "message_id", originalID)
+			return
+		}
+		if !record.GetDateTime("revoked_at").IsZero() {
+			return
+		}
+		note := fmt.Sprintf("[Mensagem editada. Antes: %q] %s", record.GetString("content"), text)
+		a.submit(groupJID, senderJID, originalID, note, operatorName, operatorJID)
+
+	case waE2E.ProtocolMessage_REVOKE:
+		if a.Debouncer.Revoke(originalID) {
+			a.Logger.Info("agent: pending message revoked", "operator", operatorName, "message_id", originalID)
+			return
+		}
+
+		record := findByWAMessageID(a.App, originalID)
+		if record == nil {
+			a.Logger.Debug("agent: revoke for unknown message ignored", "message_id", originalID)
+			return
+		}
+		if err := markRevoked(a.App, record); err != nil {
+			a.Logger.Error("agent: failed to mark message revoked", "error", err)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package agent
+
+import (
+	"testing"
+)
+
+func TestFindByWAMessageID(t *testing.T) {
+	app := newWave4TestApp(t)
+
+	if err := storeMessage(app, "Elenice", "5511", "user", "busca a Anna", "", "", []string{"MSG1", "MSG2"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := storeMessage(app, "Elenice", "5511", "user", "outra coisa", "", "", []string{"MSG10"}); err != nil {
+		t.Fatal(err)
+	}
+
+	record := findByWAMessageID(app, "MSG2")
+	if record == nil {
+		t.Fatal("expected record for MSG2")
+	}
+	if record.GetString("content") != "busca a Anna" {
+		t.Errorf("content: got %q", record.GetString("content"))
+	}
+	if r := findByWAMessageID(app, "MSG1"); r == nil || r.Id != record.Id {
+		t.Error("MSG1 should match the same record")
+	}
+	if r := findByWAMessageID(app, "MSG"); r != nil {
+		t.Errorf("partial ID should not match, got %q", r.GetString("content"))
+	}
+
+	if err := markRevoked(app, record); err != nil {
+		t.Fatal(err)
+	}
+	reloaded := findByWAMessageID(app, "MSG1")
+	if reloaded.GetDateTime("revoked_at").IsZero() {
+		t.Error("expected revoked_at to be set")
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/denisraison/rekan/api/internal/service"
 	"gopkg.in/yaml.v3"
@@ -269,6 +270,18 @@ func (m *MockExecutor) Execute(name string, input json.RawMessage) string {
 		return m.rejectPost(input)
 	case "revise_post":
 		return m.revisePost(input)
+	case "rewrite_post":
+		return m.rewritePost(input)
+	case "list_calendar":
+		return m.listCalendar(input)
+	case "reschedule_post":
+		return m.reschedulePost(input)
+	case "import_document":
+		return m.importDocument(input)
+	case "search_photos":
+		return "A cliente ainda não mandou fotos."
+	case "pair_photo":
+		return "Foto escolhida pro post."
 	default:
 		return "Ferramenta desconhecida: " + name
 	}
@@ -439,6 +452,49 @@ func (m *MockExecutor) generatePost(input json.RawMessage) string {
 	return b.String()
 }
 
+func (m *MockExecutor) importDocument(input json.RawMessage) string {
+	var args struct {
+		CustomerName string `json:"customer_name"`
+		CustomerID   string `json:"customer_id"`
+	}
+	if err := json.Unmarshal(input, &args); err != nil {
+		return "Erro ao ler parâmetros."
+	}
+
+	customer, errMsg := m.resolveCustomerByNameOrID(args.CustomerID, args.CustomerName)
+	if errMsg != "" {
+		return errMsg
+	}
+	return fmt.Sprintf("2 sugestões salvas pra %s:\n- Serviço: Exemplo|50.0\n- Serviço: Outro exemplo|80.0", customer.Name)
+}
+
+func (m *MockExecutor) listCalendar(_ json.RawMessage) string {
+	if len(m.Fixtures.Posts) == 0 {
+		return "Nenhum post planejado nesse período."
+	}
+	var b strings.Builder
+	for _, p := range m.Fixtures.Posts {
+		fmt.Fprintf(&b, "- 10h-14h %s id:%s status:%s preview:\"%s\"\n", p.Business, shortPostID(p.ID), postStatus(p.Reviewed), truncate(p.Caption, 60))
+	}
+	return b.String()
+}
+
+func (m *MockExecutor) reschedulePost(input json.RawMessage) string {
+	var args struct {
+		PostID string `json:"post_id"`
+		Date   string `json:"date"`
+	}
+	if err := json.Unmarshal(input, &args); err != nil {
+		return "Erro ao ler parâmetros."
+	}
+
+	match, errMsg := m.resolvePostByPrefix(args.PostID)
+	if errMsg != "" {
+		return errMsg
+	}
+	return fmt.Sprintf("Post da %s reagendado pra %s.", match.Business, args.Date)
+}
+
 func (m *MockExecutor) approvePost(input json.RawMessage) string {
 	var args struct {
 		PostID string `json:"post_id"`
@@ -523,6 +579,29 @@ func (m *MockExecutor) revisePost(input json.RawMessage) string {
 	return b.String()
 }
 
+func (m *MockExecutor) rewritePost(input json.RawMessage) string {
+	var args struct {
+		PostID      string `json:"post_id"`
+		Instruction string `json:"instruction"`
+	}
+	if err := json.Unmarshal(input, &args); err != nil {
+		return "Erro ao ler parâmetros."
+	}
+
+	match, errMsg := m.resolvePostByPrefix(args.PostID)
+	if errMsg != "" {
+		return errMsg
+	}
+	if match.Reviewed {
+		return "Post já foi revisado, não pode mais editar."
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Post da %s atualizado. Campos: %s.\n", match.Business, fieldLabel("caption"))
+	fmt.Fprintf(&b, "caption:%s (reescrito: %s)\n", match.Caption, args.Instruction)
+	return b.String()
+}
+
 // resolveCustomerByNameOrID resolves a mock customer by ID or fuzzy name match.
 // Mock customers have no real IDs, so customer_id matches against name (the mock convention).
 func (m *MockExecutor) resolveCustomerByNameOrID(id, name string) (*MockCustomer, string) {
@@ -673,6 +752,7 @@ var writeToolNames = map[string]bool{
 	"reject_post":     true,
 	"generate_post":   true,
 	"revise_post":     true,
+	"rewrite_post":    true,
 }
 
 func assertNoEmptyPromise(reply string, toolsCalled []string) CheckResult {
@@ -736,5 +816,9 @@ func truncate(s string, n int) string {
 	if len(s) <= n {
 		return s
 	}
+	// Back off to a rune boundary so accented text stays valid UTF-8.
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
 	return s[:n] + "..."
 }
@@ -10,6 +10,7 @@ import (
 	"go.mau.fi/whatsmeow/proto/waE2E"
 	"go.mau.fi/whatsmeow/types/events"
 
+	"github.com/denisraison/rekan/api/internal/document"
 	"github.com/denisraison/rekan/api/internal/transcribe"
 )
 
@@ -17,6 +18,7 @@ import (
 type MediaResult struct {
 	Text      string // text to send to BAML (includes media description)
 	MediaType string // "image", "audio", "video", "sticker", "contact", "document"
+	Document  string // full extracted text, documents only
 }
 
 // ExtractMedia processes non-text content from a WhatsApp group message.
@@ -48,6 +50,11 @@ func ExtractMedia(ctx context.Context, wa WAClient, tc *transcribe.Client, evt *
 		return MediaResult{Text: "[Vídeo recebido]", MediaType: "video"}
 	}
 
+	// Document (PDF/DOCX price list or menu): pass the extracted text
+	if doc := msg.GetDocumentMessage(); doc != nil {
+		return processDocument(ctx, wa, doc)
+	}
+
 	// Contact card (vCard)
 	if contact := msg.GetContactMessage(); contact != nil {
 		return processContact(contact.GetDisplayName(), contact.GetVcard())
@@ -61,12 +68,15 @@ func processAudio(ctx context.Context, wa WAClient, tc *transcribe.Client, audio
 		return MediaResult{Text: "Não consegui entender o áudio. Pode mandar por texto?", MediaType: "audio"}
 	}
 
-	data, err := wa.Download(ctx, audio)
-	if err != nil {
-		return MediaResult{Text: "Não consegui entender o áudio. Pode mandar por texto?", MediaType: "audio"}
-	}
-
-	text, err := tc.Transcribe(ctx, data, "audio/ogg")
+	// Shares the transcript cache with the DM handler, so audio forwarded
+	// from a client is neither downloaded nor transcribed twice.
+	text, err := tc.Cache.Do(transcribe.MediaKey("audio", audio.GetFileSHA256(), ""), func() (string, error) {
+		data, err := wa.Download(ctx, audio)
+		if err != nil {
+			return "", err
+		}
+		return tc.Transcribe(ctx, data, "audio/ogg")
+	})
 	if err != nil || strings.TrimSpace(text) == "" {
 		return MediaResult{Text: "Não consegui entender o áudio. Pode mandar por texto?", MediaType: "audio"}
 	}
@@ -89,15 +99,13 @@ func processImageForAgent(ctx context.Context, wa WAClient, tc *transcribe.Clien
 		mimeType = "image/jpeg"
 	}
 
-	data, err := wa.Download(ctx, img)
-	if err != nil {
-		if caption != "" {
-			return MediaResult{Text: fmt.Sprintf("[Imagem com legenda: %s]", caption), MediaType: "image"}
+	desc, err := tc.Cache.Do(transcribe.MediaKey("image", img.GetFileSHA256(), caption), func() (string, error) {
+		data, err := wa.Download(ctx, img)
+		if err != nil {
+			return "", err
 		}
-		return MediaResult{Text: "[Imagem recebida]", MediaType: "image"}
-	}
-
-	desc, err := tc.DescribeImage(ctx, data, mimeType, caption)
+		return tc.DescribeImage(ctx, data, mimeType, caption)
+	})
 	if err != nil || strings.TrimSpace(desc) == "" {
 		if caption != "" {
 			return MediaResult{Text: fmt.Sprintf("[Imagem com legenda: %s]", caption), MediaType: "image"}
@@ -112,6 +120,39 @@ func processImageForAgent(ctx context.Context, wa WAClient, tc *transcribe.Clien
 	return MediaResult{Text: text, MediaType: "image"}
 }
 
+// maxDocumentPrompt caps how much document text goes into the conversation.
+const maxDocumentPrompt = 6000
+
+func processDocument(ctx context.Context, wa WAClient, doc *waE2E.DocumentMessage) MediaResult {
+	name := doc.GetFileName()
+	if name == "" {
+		name = "documento"
+	}
+	caption := doc.GetCaption()
+	fallback := fmt.Sprintf("[Documento %s: não consegui ler o conteúdo]", name)
+	if caption != "" {
+		fallback += " " + caption
+	}
+
+	if !document.Supported(doc.GetMimetype(), name) {
+		return MediaResult{Text: fallback, MediaType: "document"}
+	}
+	data, err := wa.Download(ctx, doc)
+	if err != nil {
+		return MediaResult{Text: fallback, MediaType: "document"}
+	}
+	text, err := document.ExtractText(data, doc.GetMimetype(), name)
+	if err != nil {
+		return MediaResult{Text: fallback, MediaType: "document"}
+	}
+
+	result := fmt.Sprintf("[Documento %s: %s]", name, truncate(text, maxDocumentPrompt))
+	if caption != "" {
+		result += " " + caption
+	}
+	return MediaResult{Text: result, MediaType: "document", Document: text}
+}
+
 func processContact(displayName, vcard string) MediaResult {
 	if vcard == "" && displayName == "" {
 		return MediaResult{}
@@ -1,6 +1,9 @@
 package agent
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // buildSystemPrompt returns the system prompt for the tool-use agent loop.
 func buildSystemPrompt(operatorName string) string {
@@ -18,8 +21,23 @@ Abreviações comuns: "BH" = Belo Horizonte, "SP" = São Paulo, "RJ" = Rio de Ja
 
 "[Imagem: ...]" descreve uma imagem enviada. Cartão de visita: extraia nome, negócio, cidade e telefone. Imagem ilegível: diga que não conseguiu ler.
 "[Mensagem encaminhada de +NÚMERO]": tente identificar o cliente pelo número.
+"[Documento nome: ...]" traz o texto de um PDF ou DOCX. Se for tabela de preços ou cardápio de uma cliente, chame import_document para salvar os serviços como sugestões de perfil. Se não souber de qual cliente é, pergunte.
+"[Mensagem editada. Antes: ...]": a operadora corrigiu um pedido já atendido. Refaça com o texto novo. Se a ação anterior já foi feita (cliente cadastrada, post aprovado), ajuste o que foi feito em vez de repetir e avise o que mudou.
 
-Para ajustes em posts pendentes (trocar hashtags, mudar legenda, tirar trecho), use revise_post com os campos atualizados.
+Hoje é %s. Datas de ferramentas usam YYYY-MM-DD. Para ver o que sai em cada dia use list_calendar; para mudar o dia ou horário de um post use reschedule_post.
 
-NUNCA invente dados. NUNCA diga que vai fazer algo sem chamar a ferramenta. Se não conseguir, diga.`, operatorName)
+As fotos e vídeos que as clientes mandam ficam guardados. Quando pedirem "aquela foto do bolo rosa", use search_photos e depois pair_photo para colocar a foto no post. Fotos com aviso low_res ou screenshot só se não houver outra.
+
+Para ajustes em posts pendentes (trocar hashtags, mudar legenda, tirar trecho), use revise_post com os campos atualizados. Quando o pedido é uma instrução sem o texto novo ("deixa mais curto", "mais divertido", "fala da promoção"), use rewrite_post com a instrução e não reescreva você mesmo.
+
+Antes de chamar ferramentas que demoram (generate_post, buscas grandes), escreva uma frase curta dizendo o que vai fazer, tipo "Vou buscar os posts da Ana". Essa frase é enviada na hora, enquanto a ferramenta roda. Não repita essa frase na resposta final.
+
+NUNCA invente dados. NUNCA diga que vai fazer algo sem chamar a ferramenta. Se não conseguir, diga.`, operatorName, today(time.Now()))
+}
+
+var weekdays = [...]string{"domingo", "segunda", "terça", "quarta", "quinta", "sexta", "sábado"}
+
+// today formats a date for the prompt, e.g. "segunda, 2026-05-04".
+func today(now time.Time) string {
+	return weekdays[now.Weekday()] + ", " + now.Format(time.DateOnly)
 }
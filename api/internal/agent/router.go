@@ -9,11 +9,15 @@ import (
 
 // Action type constants for logging.
 const (
-	ActionCustomerCreate = "CUSTOMER_CREATE"
-	ActionCustomerUpdate = "CUSTOMER_UPDATE"
-	ActionPostGenerate   = "POST_GENERATE"
-	ActionPostApprove    = "POST_APPROVE"
-	ActionPostReject     = "POST_REJECT"
+	ActionCustomerCreate  = "CUSTOMER_CREATE"
+	ActionCustomerUpdate  = "CUSTOMER_UPDATE"
+	ActionPostGenerate    = "POST_GENERATE"
+	ActionPostApprove     = "POST_APPROVE"
+	ActionPostReject      = "POST_REJECT"
+	ActionPostReschedule  = "POST_RESCHEDULE"
+	ActionProfileImport   = "PROFILE_IMPORT"
+	ActionPostPairMedia   = "POST_PAIR_MEDIA"
+	ActionPostPickVariant = "POST_PICK_VARIANT"
 )
 
 // LogAction records an action to the agent_action_log collection.
@@ -39,12 +39,24 @@ func (c *Client) Run(ctx context.Context, cfg RunConfig) (*RunResult, error) {
 	}
 
 	result := &RunResult{}
+	var lastText string
 
 	for turn := range maxTurns {
 		callStart := time.Now()
-		resp, err := c.call(ctx, cfg.System, messages, toolDefs, maxTokens)
-		if err != nil {
-			return nil, err
+		var resp *apiResponse
+		var firstTokenLatency int64
+		if cfg.OnText != nil {
+			sr, err := c.stream(ctx, cfg.System, messages, toolDefs, maxTokens, cfg.OnText)
+			if err != nil {
+				return nil, err
+			}
+			resp, firstTokenLatency = sr.resp, sr.firstTokenLatency
+		} else {
+			var err error
+			resp, err = c.call(ctx, cfg.System, messages, toolDefs, maxTokens)
+			if err != nil {
+				return nil, err
+			}
 		}
 		modelLatency := time.Since(callStart).Milliseconds()
 
@@ -54,24 +66,32 @@ func (c *Client) Run(ctx context.Context, cfg RunConfig) (*RunResult, error) {
 
 		// Collect tool calls and text
 		var toolUseBlocks []ContentBlock
+		var text string
 		for _, block := range resp.Content {
 			if block.Type == "text" {
-				result.Reply = block.Text
+				text = block.Text
 			}
 			if block.Type == "tool_use" {
 				toolUseBlocks = append(toolUseBlocks, block)
 			}
 		}
 
+		if text != "" {
+			lastText = text
+		}
+
 		trace := Trace{
-			Turn:         turn + 1,
-			ModelLatency: modelLatency,
-			InputTokens:  resp.Usage.InputTokens,
-			OutputTokens: resp.Usage.OutputTokens,
+			Turn:              turn + 1,
+			ModelLatency:      modelLatency,
+			FirstTokenLatency: firstTokenLatency,
+			InputTokens:       resp.Usage.InputTokens,
+			OutputTokens:      resp.Usage.OutputTokens,
 		}
 
-		// No tool calls means we're done
+		// No tool calls means we're done. Only this turn's text is the reply:
+		// text written before a tool call was already handed to OnText.
 		if len(toolUseBlocks) == 0 {
+			result.Reply = text
 			result.Traces = append(result.Traces, trace)
 			if cfg.OnTrace != nil {
 				cfg.OnTrace(trace)
@@ -105,6 +125,18 @@ func (c *Client) Run(ctx context.Context, cfg RunConfig) (*RunResult, error) {
 					return
 				}
 
+				// Reject malformed input before it reaches the handler so the
+				// model gets a precise error and can retry in the next turn.
+				if err := validateInput(t.InputSchema, block.Input); err != nil {
+					outputs[i] = toolOutput{
+						block:    NewToolResultBlock(block.ID, err.Error(), true),
+						toolName: block.Name,
+						duration: time.Since(toolStart).Milliseconds(),
+						errMsg:   err.Error(),
+					}
+					return
+				}
+
 				resultText, execErr := t.Execute(ctx, block.Input)
 				dur := time.Since(toolStart).Milliseconds()
 
@@ -145,7 +177,13 @@ func (c *Client) Run(ctx context.Context, cfg RunConfig) (*RunResult, error) {
 		messages = append(messages, NewUserMessage(resultBlocks...))
 	}
 
-	// Max turns reached
+	// Max turns reached. Without OnText nobody has seen the model's last
+	// words yet, so they stand in for the reply; with it they were already
+	// delivered.
+	result.MaxTurns = true
+	if cfg.OnText == nil {
+		result.Reply = lastText
+	}
 	result.Messages = messages
 	return result, nil
 }
@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -245,6 +246,44 @@ func TestRun_MaxTurns(t *testing.T) {
 	}
 }
 
+func TestRun_MaxTurnsFallsBackToLastText(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		blocks := []ContentBlock{{Type: "tool_use", ID: "toolu_loop", Name: "noop", Input: json.RawMessage(`{}`)}}
+		if calls.Add(1) == 1 {
+			blocks = append([]ContentBlock{NewTextBlock("Vou conferir os posts.")}, blocks...)
+		}
+		writeResponse(w, blocks, "tool_use")
+	}))
+	defer server.Close()
+
+	tools := []Tool{{
+		Name:        "noop",
+		Description: "Does nothing",
+		InputSchema: marshalSchema(map[string]any{"type": "object", "properties": map[string]any{}}),
+		Execute: func(_ context.Context, _ json.RawMessage) (string, error) {
+			return "ok", nil
+		},
+	}}
+
+	result, err := testClient(server.URL).Run(context.Background(), RunConfig{
+		Messages: []Message{NewUserMessage(NewTextBlock("loop"))},
+		Tools:    tools,
+		MaxTurns: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.MaxTurns || result.Reply != "Vou conferir os posts." {
+		t.Errorf("MaxTurns = %v, Reply = %q; want the last text written", result.MaxTurns, result.Reply)
+	}
+	// The loop ends on the last tool results: 1 user + 2 × (tool_use, tool_result).
+	if n := len(result.Messages); n != 5 || result.Messages[n-1].Role != RoleUser {
+		t.Errorf("got %d messages, want the whole loop kept", n)
+	}
+}
+
 func TestRun_ConcurrentExecution(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		var req apiRequest
@@ -321,3 +360,290 @@ func TestRun_ConcurrentExecution(t *testing.T) {
 		t.Errorf("expected concurrent execution (max concurrent: %d), tools did not run in parallel", maxConcurrent.Load())
 	}
 }
+
+func TestRun_InvalidInputRejected(t *testing.T) {
+	var gotResult ContentBlock
+	callCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		callCount++
+		w.Header().Set("Content-Type", "application/json")
+
+		if callCount == 1 {
+			writeResponse(w, []ContentBlock{
+				{Type: "tool_use", ID: "toolu_bad", Name: "list_posts", Input: json.RawMessage(`{"status":"aprovado","limit":"5"}`)},
+			}, "tool_use")
+			return
+		}
+
+		var req apiRequest
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("reading request body: %v", err)
+			return
+		}
+		if err := json.Unmarshal(body, &req); err != nil {
+			t.Errorf("unmarshaling request: %v", err)
+			return
+		}
+		lastMsg := req.Messages[len(req.Messages)-1]
+		if len(lastMsg.Content) > 0 {
+			gotResult = lastMsg.Content[0]
+		}
+
+		writeResponse(w, []ContentBlock{NewTextBlock("Vou corrigir.")}, "end_turn")
+	}))
+	defer server.Close()
+
+	client := testClient(server.URL)
+
+	executed := false
+	tools := []Tool{{
+		Name:        "list_posts",
+		Description: "Lists posts",
+		InputSchema: marshalSchema(map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"customer_name": map[string]any{"type": "string"},
+				"status":        map[string]any{"type": "string", "enum": []string{"pending", "reviewed"}},
+				"limit":         map[string]any{"type": "integer"},
+			},
+			"required": []string{"customer_name"},
+		}),
+		Execute: func(_ context.Context, _ json.RawMessage) (string, error) {
+			executed = true
+			return "ok", nil
+		},
+	}}
+
+	result, err := client.Run(context.Background(), RunConfig{
+		Messages: []Message{NewUserMessage(NewTextBlock("posts aprovados"))},
+		Tools:    tools,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if executed {
+		t.Error("tool executed despite invalid input")
+	}
+	if gotResult.Type != "tool_result" || !gotResult.IsError {
+		t.Fatalf("expected is_error tool_result, got %+v", gotResult)
+	}
+	for _, want := range []string{"input.customer_name is required", "input.status must be one of", "input.limit must be integer, got string"} {
+		if !strings.Contains(gotResult.Content, want) {
+			t.Errorf("tool result %q missing %q", gotResult.Content, want)
+		}
+	}
+	if result.Traces[0].ToolCalls[0].Error == "" {
+		t.Error("expected validation error in tool trace")
+	}
+}
+
+func TestValidateInput(t *testing.T) {
+	s := marshalSchema(map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"post_id":  map[string]any{"type": "string"},
+			"hashtags": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+		},
+		"required": []string{"post_id"},
+	})
+
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{"valid", `{"post_id":"abc","hashtags":["#a"]}`, ""},
+		{"empty input", ``, "input.post_id is required"},
+		{"null required", `{"post_id":null}`, "input.post_id is required"},
+		{"bad item", `{"post_id":"abc","hashtags":["#a",3]}`, "input.hashtags[1] must be string, got number"},
+		{"not object", `["abc"]`, "input must be object, got array"},
+		{"not json", `{`, "not valid JSON"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateInput(s, json.RawMessage(tt.input))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// writeSSE writes a streaming Messages API response with the given events.
+func writeSSE(w http.ResponseWriter, events ...string) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	for _, e := range events {
+		var head struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal([]byte(e), &head); err != nil {
+			panic("writeSSE: " + err.Error())
+		}
+		if _, err := io.WriteString(w, "event: "+head.Type+"\ndata: "+e+"\n\n"); err != nil {
+			panic("writeSSE: " + err.Error())
+		}
+	}
+}
+
+func TestRun_StreamingInterimText(t *testing.T) {
+	callCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		callCount++
+		var req apiRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decoding request: %v", err)
+			return
+		}
+		if !req.Stream {
+			t.Error("expected stream=true in request")
+		}
+
+		if callCount == 1 {
+			writeSSE(w,
+				`{"type":"message_start","message":{"usage":{"input_tokens":120,"output_tokens":1}}}`,
+				`{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`,
+				`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Vou buscar "}}`,
+				`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"os posts da Ana."}}`,
+				`{"type":"content_block_stop","index":0}`,
+				`{"type":"content_block_start","index":1,"content_block":{"type":"tool_use","id":"toolu_s","name":"greet","input":{}}}`,
+				`{"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"{\"name\":"}}`,
+				`{"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"\"Ana\"}"}}`,
+				`{"type":"content_block_stop","index":1}`,
+				`{"type":"message_delta","delta":{"stop_reason":"tool_use"},"usage":{"output_tokens":40}}`,
+				`{"type":"message_stop"}`,
+			)
+			return
+		}
+
+		writeSSE(w,
+			`{"type":"message_start","message":{"usage":{"input_tokens":200,"output_tokens":1}}}`,
+			`{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`,
+			`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Pronto!"}}`,
+			`{"type":"content_block_stop","index":0}`,
+			`{"type":"message_delta","delta":{"stop_reason":"end_turn"},"usage":{"output_tokens":5}}`,
+			`{"type":"message_stop"}`,
+		)
+	}))
+	defer server.Close()
+
+	client := testClient(server.URL)
+
+	var interim []string
+	var gotInput string
+	tools := []Tool{{
+		Name:        "greet",
+		Description: "Greet someone",
+		InputSchema: marshalSchema(map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"name": map[string]any{"type": "string"}},
+		}),
+		Execute: func(_ context.Context, input json.RawMessage) (string, error) {
+			if len(interim) != 1 {
+				t.Error("interim text should be delivered before the tool runs")
+			}
+			gotInput = string(input)
+			return "ok", nil
+		},
+	}}
+
+	result, err := client.Run(context.Background(), RunConfig{
+		Messages: []Message{NewUserMessage(NewTextBlock("posts da Ana"))},
+		Tools:    tools,
+		OnText:   func(text string) { interim = append(interim, text) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(interim) != 1 || interim[0] != "Vou buscar os posts da Ana." {
+		t.Errorf("interim: got %q", interim)
+	}
+	if gotInput != `{"name":"Ana"}` {
+		t.Errorf("tool input: got %s", gotInput)
+	}
+	if result.Reply != "Pronto!" {
+		t.Errorf("reply: got %q, want %q", result.Reply, "Pronto!")
+	}
+	if len(result.Traces) != 2 {
+		t.Fatalf("traces: got %d, want 2", len(result.Traces))
+	}
+	if tr := result.Traces[0]; tr.InputTokens != 120 || tr.OutputTokens != 40 {
+		t.Errorf("usage: got in=%d out=%d, want 120/40", tr.InputTokens, tr.OutputTokens)
+	}
+}
+
+func TestRun_StreamingTruncated(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		writeSSE(w,
+			`{"type":"message_start","message":{"usage":{"input_tokens":10,"output_tokens":1}}}`,
+			`{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`,
+		)
+	}))
+	defer server.Close()
+
+	client := testClient(server.URL)
+	_, err := client.Run(context.Background(), RunConfig{
+		Messages: []Message{NewUserMessage(NewTextBlock("oi"))},
+		OnText:   func(string) {},
+	})
+	if err == nil {
+		t.Fatal("expected error for stream without message_stop")
+	}
+}
+
+func TestRun_ReplyIsFinalTurnTextOnly(t *testing.T) {
+	callCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		callCount++
+		if callCount == 1 {
+			writeSSE(w,
+				`{"type":"message_start","message":{"usage":{"input_tokens":10,"output_tokens":1}}}`,
+				`{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`,
+				`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Gerando o post..."}}`,
+				`{"type":"content_block_stop","index":0}`,
+				`{"type":"content_block_start","index":1,"content_block":{"type":"tool_use","id":"toolu_f","name":"noop","input":{}}}`,
+				`{"type":"content_block_stop","index":1}`,
+				`{"type":"message_delta","delta":{"stop_reason":"tool_use"},"usage":{"output_tokens":20}}`,
+				`{"type":"message_stop"}`,
+			)
+			return
+		}
+		// The final turn ends without any text.
+		writeSSE(w,
+			`{"type":"message_start","message":{"usage":{"input_tokens":30,"output_tokens":1}}}`,
+			`{"type":"message_delta","delta":{"stop_reason":"end_turn"},"usage":{"output_tokens":1}}`,
+			`{"type":"message_stop"}`,
+		)
+	}))
+	defer server.Close()
+
+	client := testClient(server.URL)
+	var interim []string
+	result, err := client.Run(context.Background(), RunConfig{
+		Messages: []Message{NewUserMessage(NewTextBlock("gera um post"))},
+		Tools: []Tool{{
+			Name:        "noop",
+			Description: "Does nothing",
+			InputSchema: marshalSchema(map[string]any{"type": "object"}),
+			Execute:     func(context.Context, json.RawMessage) (string, error) { return "ok", nil },
+		}},
+		OnText: func(text string) { interim = append(interim, text) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(interim) != 1 {
+		t.Fatalf("interim: got %q", interim)
+	}
+	if result.Reply != "" {
+		t.Errorf("reply: got %q, want empty so the interim text is not sent twice", result.Reply)
+	}
+}
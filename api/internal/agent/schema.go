@@ -0,0 +1,143 @@
+package agent
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// jsonSchema is the subset of JSON Schema used by tool input definitions.
+type jsonSchema struct {
+	Type       string                `json:"type"`
+	Properties map[string]jsonSchema `json:"properties"`
+	Required   []string              `json:"required"`
+	Enum       []any                 `json:"enum"`
+	Items      *jsonSchema           `json:"items"`
+}
+
+// validateInput checks a tool call input against the tool's declared schema.
+// All violations are reported together so the model can fix them in one turn.
+func validateInput(schemaJSON, input json.RawMessage) error {
+	if len(schemaJSON) == 0 {
+		return nil
+	}
+	var s jsonSchema
+	if err := json.Unmarshal(schemaJSON, &s); err != nil {
+		return fmt.Errorf("invalid tool schema: %w", err)
+	}
+
+	if len(bytes.TrimSpace(input)) == 0 {
+		input = json.RawMessage(`{}`)
+	}
+	var value any
+	if err := json.Unmarshal(input, &value); err != nil {
+		return fmt.Errorf("invalid input: not valid JSON: %v", err)
+	}
+
+	var problems []string
+	validateValue(s, value, "input", &problems)
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid input: %s", strings.Join(problems, "; "))
+}
+
+func validateValue(s jsonSchema, value any, path string, problems *[]string) {
+	if s.Type != "" && !matchesType(s.Type, value) {
+		*problems = append(*problems, fmt.Sprintf("%s must be %s, got %s", path, s.Type, typeName(value)))
+		return
+	}
+
+	if len(s.Enum) > 0 && !slices.ContainsFunc(s.Enum, func(e any) bool { return jsonEqual(e, value) }) {
+		allowed := make([]string, len(s.Enum))
+		for i, e := range s.Enum {
+			b, _ := json.Marshal(e)
+			allowed[i] = string(b)
+		}
+		*problems = append(*problems, fmt.Sprintf("%s must be one of [%s]", path, strings.Join(allowed, ", ")))
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		for _, name := range s.Required {
+			if field, ok := v[name]; !ok || field == nil {
+				*problems = append(*problems, fmt.Sprintf("%s.%s is required", path, name))
+			}
+		}
+		// Sort keys so error messages are deterministic.
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			prop, ok := s.Properties[k]
+			if !ok || v[k] == nil {
+				continue
+			}
+			validateValue(prop, v[k], path+"."+k, problems)
+		}
+	case []any:
+		if s.Items == nil {
+			return
+		}
+		for i, item := range v {
+			validateValue(*s.Items, item, fmt.Sprintf("%s[%d]", path, i), problems)
+		}
+	}
+}
+
+func matchesType(want string, value any) bool {
+	switch want {
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		f, ok := value.(float64)
+		return ok && f == math.Trunc(f)
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	}
+	// Unknown types are not enforced.
+	return true
+}
+
+func typeName(value any) string {
+	switch value.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func jsonEqual(a, b any) bool {
+	ab, errA := json.Marshal(a)
+	bb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(ab, bb)
+}
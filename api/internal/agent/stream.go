@@ -0,0 +1,157 @@
+package agent
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// streamEvent is a server-sent event from the streaming Messages API.
+// Only the fields used to rebuild the final response are decoded.
+type streamEvent struct {
+	Type         string        `json:"type"`
+	Index        int           `json:"index"`
+	Message      *apiResponse  `json:"message"`
+	ContentBlock *ContentBlock `json:"content_block"`
+	Delta        struct {
+		Type        string `json:"type"`
+		Text        string `json:"text"`
+		PartialJSON string `json:"partial_json"`
+		StopReason  string `json:"stop_reason"`
+	} `json:"delta"`
+	Usage *apiUsage `json:"usage"`
+	Error *struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// streamResult is the reassembled response plus streaming-only timing.
+type streamResult struct {
+	resp              *apiResponse
+	firstTokenLatency int64 // ms until the first content delta arrived
+}
+
+// stream sends a streaming request to the Messages API and reassembles the
+// response. When a tool_use block starts after some text, onText receives
+// that text right away, before the tool input finishes streaming.
+func (c *Client) stream(ctx context.Context, system string, messages []Message, tools []apiToolDef, maxTokens int, onText func(string)) (*streamResult, error) {
+	httpReq, err := c.newRequest(ctx, system, messages, tools, maxTokens, true)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Accept", "text/event-stream")
+
+	start := time.Now()
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("http request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("read response: %w", err)
+		}
+		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	result := &streamResult{resp: &apiResponse{}}
+	var (
+		blocks    []ContentBlock
+		inputJSON []strings.Builder
+		text      strings.Builder // text blocks not yet handed to onText
+		done      bool
+	)
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		data, ok := strings.CutPrefix(line, "data:")
+		if !ok {
+			continue
+		}
+		var evt streamEvent
+		if err := json.Unmarshal([]byte(strings.TrimSpace(data)), &evt); err != nil {
+			return nil, fmt.Errorf("unmarshal stream event: %w", err)
+		}
+
+		switch evt.Type {
+		case "message_start":
+			if evt.Message != nil {
+				result.resp.Usage = evt.Message.Usage
+			}
+		case "content_block_start":
+			if evt.ContentBlock == nil || evt.Index != len(blocks) {
+				return nil, fmt.Errorf("unexpected content block %d", evt.Index)
+			}
+			block := *evt.ContentBlock
+			if block.Type == "tool_use" && text.Len() > 0 && onText != nil {
+				onText(text.String())
+				text.Reset()
+			}
+			blocks = append(blocks, block)
+			inputJSON = append(inputJSON, strings.Builder{})
+		case "content_block_delta":
+			if evt.Index >= len(blocks) {
+				return nil, fmt.Errorf("delta for unknown content block %d", evt.Index)
+			}
+			if result.firstTokenLatency == 0 {
+				result.firstTokenLatency = time.Since(start).Milliseconds()
+			}
+			switch evt.Delta.Type {
+			case "text_delta":
+				blocks[evt.Index].Text += evt.Delta.Text
+			case "input_json_delta":
+				inputJSON[evt.Index].WriteString(evt.Delta.PartialJSON)
+			}
+		case "content_block_stop":
+			if evt.Index >= len(blocks) {
+				return nil, fmt.Errorf("stop for unknown content block %d", evt.Index)
+			}
+			block := &blocks[evt.Index]
+			switch block.Type {
+			case "text":
+				text.WriteString(block.Text)
+			case "tool_use":
+				// Tools without arguments stream no deltas at all.
+				if raw := inputJSON[evt.Index].String(); raw != "" {
+					block.Input = json.RawMessage(raw)
+				} else if len(block.Input) == 0 {
+					block.Input = json.RawMessage(`{}`)
+				}
+			}
+		case "message_delta":
+			result.resp.StopReason = evt.Delta.StopReason
+			if evt.Usage != nil {
+				result.resp.Usage.OutputTokens = evt.Usage.OutputTokens
+			}
+		case "message_stop":
+			done = true
+		case "error":
+			if evt.Error != nil {
+				return nil, fmt.Errorf("API stream error %s: %s", evt.Error.Type, evt.Error.Message)
+			}
+			return nil, fmt.Errorf("API stream error: %s", data)
+		}
+		if done {
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read stream: %w", err)
+	}
+	if !done {
+		return nil, fmt.Errorf("stream ended before message_stop")
+	}
+
+	result.resp.Content = blocks
+	return result, nil
+}
@@ -3,23 +3,31 @@ package agent
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	content "github.com/denisraison/rekan/api/internal/content"
 	"github.com/denisraison/rekan/api/internal/domain"
 	"github.com/denisraison/rekan/api/internal/service"
+	wa "github.com/denisraison/rekan/api/internal/whatsapp"
 	"github.com/pocketbase/pocketbase/core"
 )
 
 // ToolExecutor handles tool call execution for the agent loop.
 type ToolExecutor struct {
-	Ctx        context.Context
-	App        core.App
-	WAClient   WAClient
-	Generate   content.GenerateFunc
-	businesses []*core.Record // cached on first access
-	WriteUsed  bool           // whether any write tool was called
+	Ctx            context.Context
+	App            core.App
+	WAClient       WAClient
+	Generate       content.GenerateFunc
+	Formats        map[content.Format]content.GenerateFunc
+	ExtractProfile content.ExtractProfileFunc
+	Rewrite        content.RewriteFunc
+	QualityGate    *service.QualityGate
+	Document       string         // text of the operator's last document, if any
+	businesses     []*core.Record // cached on first access
+	WriteUsed      bool           // whether any write tool was called
 }
 
 // loadBusinesses returns cached businesses, querying once per executor lifetime.
@@ -95,6 +103,24 @@ func buildTools(executor *ToolExecutor, operatorName string) []Tool {
 			}),
 			func(input json.RawMessage) string { return executor.searchPosts(input) },
 		),
+		readTool("list_calendar",
+			"Mostra o calendário de posts planejados por dia, com horário. Sem customer_name: todas as clientes.",
+			schema(map[string]any{
+				"customer_name": map[string]any{"type": "string", "description": "Nome da cliente (opcional)"},
+				"from":          map[string]any{"type": "string", "description": "Data inicial YYYY-MM-DD (padrão: hoje)"},
+				"days":          map[string]any{"type": "integer", "description": "Quantos dias mostrar (padrão: 7)"},
+			}),
+			func(input json.RawMessage) string { return executor.listCalendar(input) },
+		),
+		readTool("search_photos",
+			"Busca fotos e vídeos que a cliente mandou no WhatsApp pela descrição, ex: \"bolo rosa\". Sem query: as mais recentes.",
+			schema(map[string]any{
+				"customer_name": map[string]any{"type": "string", "description": "Nome da cliente"},
+				"customer_id":   map[string]any{"type": "string", "description": "ID da cliente (opcional, pula busca por nome)"},
+				"query":         map[string]any{"type": "string", "description": "O que procurar na foto (opcional)"},
+			}, "customer_name"),
+			func(input json.RawMessage) string { return executor.searchPhotos(input) },
+		),
 		// Write tools
 		writeTool("create_customer",
 			"Cadastra nova cliente. Campos obrigatórios: name, type, city, phone.",
@@ -122,6 +148,7 @@ func buildTools(executor *ToolExecutor, operatorName string) []Tool {
 				"brand_vibe":      map[string]any{"type": "string", "description": "Nova vibe da marca"},
 				"quirks":          map[string]any{"type": "string", "description": "Novas observações"},
 				"status":          map[string]any{"type": "string", "enum": []string{"active", "paused"}, "description": "Status da cliente"},
+				"assistant":       map[string]any{"type": "boolean", "description": "Liga ou desliga o assistente que responde a cliente no privado"},
 			}, "name"),
 			func(input json.RawMessage) string { return executor.updateCustomer(input, operatorName) },
 		),
@@ -130,6 +157,8 @@ func buildTools(executor *ToolExecutor, operatorName string) []Tool {
 			schema(map[string]any{
 				"customer_name": map[string]any{"type": "string", "description": "Nome da cliente"},
 				"customer_id":   map[string]any{"type": "string", "description": "ID da cliente (opcional, pula busca por nome)"},
+				"format":        map[string]any{"type": "string", "enum": []string{"feed", "reel", "story", "carousel"}, "description": "Formato do post (padrão: feed)"},
+				"variants":      map[string]any{"type": "integer", "minimum": 1, "maximum": service.MaxVariants, "description": "Quantas opções de legenda gerar para a operadora escolher com pick_variant (padrão: 1)"},
 			}, "customer_name"),
 			func(input json.RawMessage) string { return executor.generatePost(input, operatorName) },
 		),
@@ -158,6 +187,47 @@ func buildTools(executor *ToolExecutor, operatorName string) []Tool {
 			}, "post_id"),
 			func(input json.RawMessage) string { return executor.revisePost(input) },
 		),
+		writeTool("rewrite_post",
+			"Reescreve um post pendente seguindo uma instrução livre, como \"deixa mais curto e divertido\", usando o perfil da cliente. Use quando o pedido não traz o texto novo pronto. Só funciona com posts de feed; para reel, story ou carrossel, gere outro.",
+			schema(map[string]any{
+				"post_id":     map[string]any{"type": "string", "description": "ID do post"},
+				"instruction": map[string]any{"type": "string", "description": "O que mudar no post, nas palavras da operadora"},
+			}, "post_id", "instruction"),
+			func(input json.RawMessage) string { return executor.rewritePost(input) },
+		),
+		writeTool("reschedule_post",
+			"Muda a data (e opcionalmente o horário) planejada de um post.",
+			schema(map[string]any{
+				"post_id": map[string]any{"type": "string", "description": "ID do post"},
+				"date":    map[string]any{"type": "string", "description": "Nova data YYYY-MM-DD"},
+				"slot":    map[string]any{"type": "string", "description": "Faixa de horário, ex: 10h-14h (opcional, mantém a atual)"},
+			}, "post_id", "date"),
+			func(input json.RawMessage) string { return executor.reschedulePost(input) },
+		),
+		writeTool("pick_variant",
+			"Escolhe uma das opções de legenda geradas para um post (generate_post com variants).",
+			schema(map[string]any{
+				"post_id": map[string]any{"type": "string", "description": "ID do post"},
+				"variant": map[string]any{"type": "integer", "minimum": 1, "description": "Número da opção, como aparece em generate_post"},
+			}, "post_id", "variant"),
+			func(input json.RawMessage) string { return executor.pickVariant(input) },
+		),
+		writeTool("pair_photo",
+			"Escolhe a foto ou vídeo da cliente (de search_photos) que acompanha um post. photo_id vazio tira a foto do post.",
+			schema(map[string]any{
+				"post_id":  map[string]any{"type": "string", "description": "ID do post"},
+				"photo_id": map[string]any{"type": "string", "description": "ID da foto, como aparece em search_photos"},
+			}, "post_id", "photo_id"),
+			func(input json.RawMessage) string { return executor.pairPhoto(input) },
+		),
+		writeTool("import_document",
+			"Lê o último documento (PDF/DOCX) enviado pela operadora, como tabela de preços ou cardápio, e salva os serviços e preços como sugestões de perfil da cliente.",
+			schema(map[string]any{
+				"customer_name": map[string]any{"type": "string", "description": "Nome da cliente"},
+				"customer_id":   map[string]any{"type": "string", "description": "ID da cliente (opcional, pula busca por nome)"},
+			}, "customer_name"),
+			func(input json.RawMessage) string { return executor.importDocument(input) },
+		),
 	}
 }
 
@@ -186,16 +256,18 @@ func shortPostID(id string) string {
 }
 
 var fieldLabels = map[string]string{
-	"name":            "nome",
-	"type":            "tipo",
-	"city":            "cidade",
-	"phone":           "telefone",
-	"target_audience": "público",
-	"brand_vibe":      "vibe",
-	"quirks":          "obs",
-	"caption":         "legenda",
-	"hashtags":        "hashtags",
-	"production_note": "nota de produção",
+	"name":              "nome",
+	"type":              "tipo",
+	"city":              "cidade",
+	"phone":             "telefone",
+	"target_audience":   "público",
+	"brand_vibe":        "vibe",
+	"quirks":            "obs",
+	"caption":           "legenda",
+	"hashtags":          "hashtags",
+	"production_note":   "nota de produção",
+	"services":          "serviço",
+	"assistant_enabled": "assistente",
 }
 
 func fieldLabel(key string) string {
@@ -365,6 +437,17 @@ func (te *ToolExecutor) searchPosts(input json.RawMessage) string {
 		if reviewNote := record.GetString("review_note"); reviewNote != "" {
 			fmt.Fprintf(&b, "review_note:%s\n", reviewNote)
 		}
+		if reply := record.GetString("client_reply"); reply != "" {
+			fmt.Fprintf(&b, "client_reply:%s\n", reply)
+		}
+		if feedback := record.GetString("client_feedback"); feedback != "" {
+			fmt.Fprintf(&b, "client_feedback:%s\n", feedback)
+		}
+		if itemID := record.GetString("media_item"); itemID != "" {
+			if item, err := te.App.FindRecordById(domain.CollMediaItems, itemID); err == nil {
+				fmt.Fprintf(&b, "photo:%s %s\n", item.Id, item.GetString("description"))
+			}
+		}
 		return b.String()
 	}
 
@@ -470,6 +553,7 @@ func (te *ToolExecutor) updateCustomer(input json.RawMessage, _ string) string {
 		BrandVibe      string `json:"brand_vibe"`
 		Quirks         string `json:"quirks"`
 		Status         string `json:"status"`
+		Assistant      *bool  `json:"assistant"`
 	}
 	if err := json.Unmarshal(input, &args); err != nil {
 		return "Erro ao ler parâmetros."
@@ -527,6 +611,7 @@ func (te *ToolExecutor) updateCustomer(input json.RawMessage, _ string) string {
 	if args.Quirks != "" {
 		p.Quirks = &args.Quirks
 	}
+	p.AssistantEnabled = args.Assistant
 
 	updatedKeys, err := service.UpdateBusiness(te.App, record, p)
 	if err != nil {
@@ -554,20 +639,33 @@ func (te *ToolExecutor) generatePost(input json.RawMessage, _ string) string {
 	var args struct {
 		CustomerName string `json:"customer_name"`
 		CustomerID   string `json:"customer_id"`
+		Format       string `json:"format"`
+		Variants     int    `json:"variants"`
 	}
 	if err := json.Unmarshal(input, &args); err != nil {
 		return "Erro ao ler parâmetros."
 	}
+	if args.Variants < 0 || args.Variants > service.MaxVariants {
+		return fmt.Sprintf("Dá pra gerar de 1 a %d opções.", service.MaxVariants)
+	}
 
 	biz, errMsg := te.resolveCustomerByNameOrID(args.CustomerID, args.CustomerName)
 	if errMsg != "" {
 		return errMsg
 	}
-	if te.Generate == nil {
+	format, ok := content.ParseFormat(args.Format)
+	if !ok {
+		return "Formato inválido. Use feed, reel, story ou carousel."
+	}
+	generate := te.Generate
+	if format != content.FormatFeed {
+		generate = te.Formats[format]
+	}
+	if generate == nil {
 		return "Geração de posts não está configurada."
 	}
 
-	result, err := service.GeneratePosts(te.Ctx, te.App, te.Generate, biz.Id)
+	result, err := service.GeneratePosts(te.Ctx, te.App, generate, te.QualityGate, biz.Id, max(args.Variants, 1))
 	if err != nil {
 		return "Erro ao gerar: " + err.Error()
 	}
@@ -579,13 +677,28 @@ func (te *ToolExecutor) generatePost(input json.RawMessage, _ string) string {
 	var b strings.Builder
 	fmt.Fprintf(&b, "Post gerado pra %s.\n", biz.GetString("name"))
 	fmt.Fprintf(&b, "ID: %s\n", post.ID)
-	fmt.Fprintf(&b, "Legenda: %s\n", post.Caption)
+	if len(post.Variants) > 1 {
+		for i, v := range post.Variants {
+			fmt.Fprintf(&b, "Opção %d: %s\n", i+1, v.Caption)
+		}
+		b.WriteString("A opção 1 está salva como legenda; use pick_variant pra trocar.\n")
+	} else {
+		fmt.Fprintf(&b, "Legenda: %s\n", post.Caption)
+	}
 	if len(post.Hashtags) > 0 {
 		fmt.Fprintf(&b, "Hashtags: %s\n", strings.Join(post.Hashtags, " "))
 	}
+	if guide := content.ClientGuide(content.Post{Format: post.Format, Data: post.FormatData}); guide != "" {
+		fmt.Fprintf(&b, "%s\n", guide)
+	}
 	if post.ProductionNote != "" {
 		fmt.Fprintf(&b, "Nota de produção: %s", post.ProductionNote)
 	}
+	if post.MediaItem != "" {
+		if item, err := te.App.FindRecordById(domain.CollMediaItems, post.MediaItem); err == nil {
+			fmt.Fprintf(&b, "\nFoto da cliente escolhida: %s (id:%s)", item.GetString("description"), item.Id)
+		}
+	}
 	return b.String()
 }
 
@@ -617,7 +730,7 @@ func (te *ToolExecutor) approvePost(input json.RawMessage, _ string) string {
 		if sendErr := te.sendPostToClient(post); sendErr != nil {
 			return result + " Não consegui enviar pro cliente: " + sendErr.Error()
 		}
-		result += " Enviado pro cliente."
+		result += " Na fila pra enviar pro cliente."
 	}
 
 	return result
@@ -644,7 +757,6 @@ func (te *ToolExecutor) rejectPost(input json.RawMessage, _ string) string {
 	if _, err := service.RejectPostRecord(te.App, post, args.Feedback); err != nil {
 		return "Erro ao rejeitar: " + err.Error()
 	}
-
 	bizName := te.resolveBizName(post)
 	if args.Feedback != "" {
 		return fmt.Sprintf("Post da %s rejeitado. Feedback: %s.", bizName, args.Feedback)
@@ -694,7 +806,49 @@ func (te *ToolExecutor) revisePost(input json.RawMessage) string {
 	if len(updatedKeys) == 0 {
 		return "Nenhum campo pra atualizar."
 	}
+	return te.revisedReply(post, updatedKeys)
+}
 
+func (te *ToolExecutor) rewritePost(input json.RawMessage) string {
+	var args struct {
+		PostID      string `json:"post_id"`
+		Instruction string `json:"instruction"`
+	}
+	if err := json.Unmarshal(input, &args); err != nil {
+		return "Erro ao ler parâmetros."
+	}
+
+	if args.PostID == "" {
+		return "Qual post quer reescrever?"
+	}
+	if strings.TrimSpace(args.Instruction) == "" {
+		return "O que quer mudar no post?"
+	}
+	if te.Rewrite == nil {
+		return "Reescrita de posts não está configurada."
+	}
+
+	post, errMsg := te.resolvePostByPrefix(args.PostID)
+	if errMsg != "" {
+		return errMsg
+	}
+
+	if post.GetBool("reviewed") {
+		return "Post já foi revisado, não pode mais editar."
+	}
+
+	updatedKeys, err := service.RewritePostRecord(te.Ctx, te.App, te.Rewrite, post, args.Instruction)
+	if err != nil {
+		return "Erro ao reescrever: " + err.Error()
+	}
+	if len(updatedKeys) == 0 {
+		return "A reescrita saiu igual ao post atual."
+	}
+	return te.revisedReply(post, updatedKeys)
+}
+
+// revisedReply lists the updated fields and the post's current content.
+func (te *ToolExecutor) revisedReply(post *core.Record, updatedKeys []string) string {
 	labels := make([]string, len(updatedKeys))
 	for i, key := range updatedKeys {
 		labels[i] = fieldLabel(key)
@@ -713,10 +867,239 @@ func (te *ToolExecutor) revisePost(input json.RawMessage) string {
 	return b.String()
 }
 
-// sendPostToClient sends the post content to the client's WhatsApp.
+// importDocument reads the last PDF or DOCX the operator sent to the group and
+// saves the services and prices it finds as profile suggestions.
+func (te *ToolExecutor) importDocument(input json.RawMessage) string {
+	var args struct {
+		CustomerName string `json:"customer_name"`
+		CustomerID   string `json:"customer_id"`
+	}
+	if err := json.Unmarshal(input, &args); err != nil {
+		return "Erro ao ler parâmetros."
+	}
+
+	biz, errMsg := te.resolveCustomerByNameOrID(args.CustomerID, args.CustomerName)
+	if errMsg != "" {
+		return errMsg
+	}
+	if te.Document == "" {
+		return "Não tem documento recente. Manda o PDF ou DOCX aqui no grupo."
+	}
+	if te.ExtractProfile == nil {
+		return "Leitura de documentos não está configurada."
+	}
+
+	profile, err := te.ExtractProfile(te.Ctx, te.Document, biz.GetString("type"))
+	if err != nil {
+		return "Erro ao ler documento: " + err.Error()
+	}
+	signals := profile.Signals()
+	if len(signals) == 0 {
+		return fmt.Sprintf("Não achei serviços nem preços no documento pra %s.", biz.GetString("name"))
+	}
+
+	saved, err := wa.SaveProfileSuggestions(te.App, biz.Id, signals)
+	if err != nil {
+		return "Erro ao salvar sugestões: " + err.Error()
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d sugestões salvas pra %s:\n", saved, biz.GetString("name"))
+	for _, s := range signals[:saved] {
+		fmt.Fprintf(&b, "- %s: %s\n", fieldLabel(s.Field), s.Value)
+	}
+	return b.String()
+}
+
+func (te *ToolExecutor) listCalendar(input json.RawMessage) string {
+	var args struct {
+		CustomerName string `json:"customer_name"`
+		From         string `json:"from"`
+		Days         int    `json:"days"`
+	}
+	if len(input) > 0 {
+		if err := json.Unmarshal(input, &args); err != nil {
+			return "Erro ao ler parâmetros."
+		}
+	}
+
+	from := time.Now().In(domain.Location)
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, domain.Location)
+	if args.From != "" {
+		parsed, err := time.ParseInLocation(time.DateOnly, args.From, domain.Location)
+		if err != nil {
+			return "Data inválida, use YYYY-MM-DD."
+		}
+		from = parsed
+	}
+	if args.Days <= 0 {
+		args.Days = 7
+	}
+
+	filter := service.CalendarFilter{From: from, To: from.AddDate(0, 0, args.Days)}
+	if args.CustomerName != "" {
+		matches := service.FindBusinessByName(te.loadBusinesses(), args.CustomerName)
+		if len(matches) == 0 {
+			return fmt.Sprintf("Nenhuma cliente encontrada com '%s'.", args.CustomerName)
+		}
+		for _, m := range matches {
+			filter.BusinessIDs = append(filter.BusinessIDs, m.Id)
+		}
+	}
+
+	entries, err := service.ListCalendar(te.App, filter)
+	if err != nil {
+		return "Erro ao buscar calendário."
+	}
+	if len(entries) == 0 {
+		return "Nenhum post planejado nesse período."
+	}
+
+	var b strings.Builder
+	day := ""
+	for _, c := range entries {
+		planned := c.PlannedFor.In(domain.Location)
+		if d := planned.Format("02/01"); d != day {
+			day = d
+			fmt.Fprintf(&b, "%s:\n", d)
+		}
+		fmt.Fprintf(&b, "- %s %s id:%s status:%s", c.Slot, c.BusinessName, shortPostID(c.PostID), postStatus(c.Reviewed))
+		if c.Occasion != "" {
+			fmt.Fprintf(&b, " data:%s", c.Occasion)
+		}
+		fmt.Fprintf(&b, " preview:\"%s\"\n", truncate(c.Caption, 60))
+	}
+	return b.String()
+}
+
+func (te *ToolExecutor) reschedulePost(input json.RawMessage) string {
+	var args struct {
+		PostID string `json:"post_id"`
+		Date   string `json:"date"`
+		Slot   string `json:"slot"`
+	}
+	if err := json.Unmarshal(input, &args); err != nil {
+		return "Erro ao ler parâmetros."
+	}
+
+	day, err := time.ParseInLocation(time.DateOnly, args.Date, domain.Location)
+	if err != nil {
+		return "Data inválida, use YYYY-MM-DD."
+	}
+
+	post, errMsg := te.resolvePostByPrefix(args.PostID)
+	if errMsg != "" {
+		return errMsg
+	}
+
+	if _, err := service.ReschedulePostRecord(te.App, post, day, args.Slot); err != nil {
+		return "Erro ao reagendar: " + err.Error()
+	}
+	return fmt.Sprintf("Post da %s reagendado pra %s, %s.", te.resolveBizName(post), day.Format("02/01"), post.GetString("planned_slot"))
+}
+
+func (te *ToolExecutor) searchPhotos(input json.RawMessage) string {
+	var args struct {
+		CustomerName string `json:"customer_name"`
+		CustomerID   string `json:"customer_id"`
+		Query        string `json:"query"`
+	}
+	if err := json.Unmarshal(input, &args); err != nil {
+		return "Erro ao ler parâmetros."
+	}
+
+	biz, errMsg := te.resolveCustomerByNameOrID(args.CustomerID, args.CustomerName)
+	if errMsg != "" {
+		return errMsg
+	}
+
+	items, err := service.SearchMedia(te.App, biz.Id, args.Query, 5)
+	if err != nil {
+		return "Erro ao buscar fotos."
+	}
+	if len(items) == 0 {
+		if args.Query != "" {
+			return fmt.Sprintf("Nenhuma foto da %s com \"%s\".", biz.GetString("name"), args.Query)
+		}
+		return fmt.Sprintf("A %s ainda não mandou fotos.", biz.GetString("name"))
+	}
+
+	var b strings.Builder
+	for _, item := range items {
+		var flags []string
+		_ = item.UnmarshalJSONField("flags", &flags)
+		fmt.Fprintf(&b, "id:%s tipo:%s usos:%d", item.Id, item.GetString("type"), item.GetInt("used_count"))
+		if len(flags) > 0 {
+			fmt.Fprintf(&b, " avisos:%s", strings.Join(flags, ","))
+		}
+		fmt.Fprintf(&b, " desc:\"%s\"\n", truncate(item.GetString("description"), 120))
+	}
+	return b.String()
+}
+
+func (te *ToolExecutor) pairPhoto(input json.RawMessage) string {
+	var args struct {
+		PostID  string `json:"post_id"`
+		PhotoID string `json:"photo_id"`
+	}
+	if err := json.Unmarshal(input, &args); err != nil {
+		return "Erro ao ler parâmetros."
+	}
+
+	post, errMsg := te.resolvePostByPrefix(args.PostID)
+	if errMsg != "" {
+		return errMsg
+	}
+
+	if _, err := service.PairPostMedia(te.App, post.Id, args.PhotoID); err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			return fmt.Sprintf("Foto %s não encontrada.", args.PhotoID)
+		}
+		if errors.Is(err, service.ErrInvalid) {
+			return "Essa foto é de outra cliente."
+		}
+		return "Erro ao escolher foto: " + err.Error()
+	}
+	if args.PhotoID == "" {
+		return fmt.Sprintf("Tirei a foto do post da %s.", te.resolveBizName(post))
+	}
+	return fmt.Sprintf("Foto escolhida pro post da %s.", te.resolveBizName(post))
+}
+
+// pickVariant makes one of a post's caption options its content. Options are
+// numbered from 1, as listed by generatePost.
+func (te *ToolExecutor) pickVariant(input json.RawMessage) string {
+	var args struct {
+		PostID  string `json:"post_id"`
+		Variant int    `json:"variant"`
+	}
+	if err := json.Unmarshal(input, &args); err != nil {
+		return "Erro ao ler parâmetros."
+	}
+
+	post, errMsg := te.resolvePostByPrefix(args.PostID)
+	if errMsg != "" {
+		return errMsg
+	}
+
+	updated, err := service.PickVariant(te.App, post.Id, args.Variant-1)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalid):
+			return fmt.Sprintf("O post não tem a opção %d.", args.Variant)
+		case errors.Is(err, service.ErrConflict):
+			return "Post já foi revisado, não pode mais trocar a legenda."
+		}
+		return "Erro ao escolher opção: " + err.Error()
+	}
+	return fmt.Sprintf("Opção %d escolhida pro post da %s.\nLegenda: %s", args.Variant, te.resolveBizName(updated), updated.GetString("caption"))
+}
+
+// sendPostToClient queues the post content for the client's WhatsApp.
 func (te *ToolExecutor) sendPostToClient(post *core.Record) error {
-	return service.SendTextMessage(te.Ctx, te.App, te.WAClient, service.SendTextParams{
+	return service.SendTextMessage(te.App, service.SendTextParams{
 		BusinessID:     post.GetString("business"),
+		PostID:         post.Id,
 		Caption:        post.GetString("caption"),
 		Hashtags:       strings.Join(decodeHashtags(post.GetString("hashtags")), " "),
 		ProductionNote: post.GetString("production_note"),
@@ -82,11 +82,12 @@ type Tool struct {
 
 // Trace records one turn of the agent loop.
 type Trace struct {
-	Turn         int         `json:"turn"`
-	ModelLatency int64       `json:"model_latency_ms"`
-	InputTokens  int         `json:"input_tokens"`
-	OutputTokens int         `json:"output_tokens"`
-	ToolCalls    []ToolTrace `json:"tool_calls,omitempty"`
+	Turn              int         `json:"turn"`
+	ModelLatency      int64       `json:"model_latency_ms"`
+	FirstTokenLatency int64       `json:"first_token_latency_ms,omitempty"` // streaming only
+	InputTokens       int         `json:"input_tokens"`
+	OutputTokens      int         `json:"output_tokens"`
+	ToolCalls         []ToolTrace `json:"tool_calls,omitempty"`
 }
 
 // ToolTrace records a single tool execution within a turn.
@@ -104,13 +105,17 @@ type RunConfig struct {
 	MaxTurns  int         // default 10
 	MaxTokens int         // default 2048
 	OnTrace   func(Trace) // optional
+	// OnText receives assistant text written before a tool call, while the
+	// loop is still running. Setting it switches the client to streaming.
+	OnText func(string)
 }
 
 // RunResult is the output of Run().
 type RunResult struct {
-	Reply    string
+	Reply    string    // text of the final turn; on max turns, the last text written unless OnText got it
 	Messages []Message // full conversation including tool turns
 	Traces   []Trace   // one per turn
+	MaxTurns bool      // the loop stopped at MaxTurns with tool calls still pending
 }
 
 // NewTextBlock creates a text content block.
@@ -16,11 +16,15 @@ package baml_client
 var file_map = map[string]string{
 
 	"clients.baml":    "client<llm> JudgeClient {\n  provider google-ai\n  options {\n    model \"gemini-3-flash-preview\"\n    api_key env.GEMINI_API_KEY\n    generationConfig {\n      temperature 0.1\n      maxOutputTokens 2048\n    }\n  }\n}\n\nclient<llm> JudgeClientClaude {\n  provider anthropic\n  options {\n    model \"claude-haiku-4-5-20251001\"\n    api_key env.CLAUDE_API_KEY\n    temperature 0.1\n    max_tokens 1024\n  }\n}\n\nclient<llm> CheapGeneratorClient {\n  provider google-ai\n  options {\n    model \"gemini-3-flash-preview\"\n    api_key env.GEMINI_API_KEY\n    generationConfig {\n      temperature 0.7\n      maxOutputTokens 4096\n    }\n  }\n}\n\nclient<llm> ProfileClient {\n  provider anthropic\n  options {\n    model \"claude-opus-4-6\"\n    api_key env.CLAUDE_API_KEY\n    temperature 0.1\n    max_tokens 2048\n  }\n}\n\nclient<llm> GeneratorClient {\n  provider anthropic\n  options {\n    model \"claude-opus-4-6\"\n    api_key env.CLAUDE_API_KEY\n    temperature 0.7\n    max_tokens 4096\n  }\n}\n",
-	"content.baml":    "function GenerateContent(profile: BusinessProfile, roles: ContentRole[], previousHooks: string[]) -> Post {\n  client GeneratorClient\n  prompt #\"\n    Você é o(a) dono(a) do(a) {{ profile.businessName }}. Você mesmo(a) escreve os posts do Instagram do seu negócio. Sem agência, sem equipe de marketing. Escreve do jeito que fala.\n\n    Escreva 1 post pro seu Instagram.\n\n    Seu negócio:\n    - Nome: {{ profile.businessName }}\n    - Tipo: {{ profile.businessType }}\n    - Cidade: {{ profile.city }}\n    {% if profile.services | length > 0 %}- Serviços: {% for s in profile.services %}{{ s.name }} (R${{ s.priceBRL }}){% if not loop.last %}, {% endif %}{% endfor %}{% endif %}\n    - Público: {{ profile.targetAudience }}\n    - Vibe: {{ profile.brandVibe }}\n    - Diferenciais: {% for q in profile.quirks %}{{ q }}{% if not loop.last %}, {% endif %}{% endfor %}\n\n    O post precisa ter:\n    - Legenda CURTA: MÁXIMO 400 caracteres. Conte os caracteres. 2-3 parágrafos curtos, não mais.\n    - Hashtags do nicho (0 a 3, só se fizer sentido). Não force.\n    - CTA é opcional. A maioria dos posts reais de MEI não tem CTA. Se incluir, varie: \"manda pra uma amiga que precisa ouvir isso\", \"salva pra depois\", \"comenta se já passou por isso\". Evite \"link na bio\", \"chama no zap\". NUNCA use \"salva esse post\" como frase final automática.\n    - Nota de produção: o que fotografar com o celular, enquadramento, uma dica. 2-3 frases. Deve ser algo que a pessoa consiga fazer sozinha, agora, sem planejar.\n\n    Papel do post:\n    {% for r in roles %}  {{ r.name }}: {{ r.description }}\n    {% endfor %}\n\n    Como você escreve:\n    - Do jeito que você falaria com um cliente no balcão. Frases curtas.\n    - NUNCA use travessão (—). Use vírgula ou ponto.\n    - Abra com um micro-momento concreto: uma cena, um número real, um detalhe sensorial. Nada de declarações genéricas.\n    - Inclua pelo menos um detalhe que não está nos dados do negócio acima: um horário, o clima, um som, uma pessoa, algo que aconteceu. Detalhes inventados devem ter vida própria, não apenas decorar o pitch.\n    - Use palavras-chave do nicho em algum lugar da legenda, de forma natural. O Instagram funciona como buscador. Não force na primeira frase se não couber.\n    - Mencione o nome do negócio. A cidade/bairro pode aparecer se couber naturalmente, mas não force \"aqui em [cidade]\" em todo post.\n    - Emojis só quando você usaria de verdade no WhatsApp.\n    - NUNCA termine com pergunta genérica de engajamento (\"qual seu favorito?\", \"comenta aqui\", \"marca um amigo?\").\n    - Evite o formato \"pergunta que eu escuto toda semana + resposta\". Varie as estruturas: bastidor, marco, opinião, cena do dia, reflexão pessoal.\n    - IMPORTANTE: A legenda deve ter no MÁXIMO 400 caracteres. Posts curtos têm mais engajamento. Não desenvolva a história além do necessário. Um parágrafo de abertura + um de contexto é suficiente.\n\n    {% if previousHooks | length > 0 %}\n    IMPORTANTE: Estes ganchos já foram usados em posts anteriores. NÃO repita o mesmo ângulo, tema ou cena. Crie algo completamente diferente:\n    {% for hook in previousHooks %}- {{ hook }}\n    {% endfor %}\n    {% endif %}\n\n    {{ ctx.output_format }}\n  \"#\n}\n\nfunction GenerateFromMessage(profile: BusinessProfile, clientMessage: string, previousHooks: string[]) -> Post {\n  client GeneratorClient\n  prompt #\"\n    Você é o(a) dono(a) do(a) {{ profile.businessName }}. Você mesmo(a) escreve os posts do Instagram do seu negócio. Sem agência, sem equipe de marketing. Escreve do jeito que fala.\n\n    Um(a) cliente mandou essa mensagem no WhatsApp pedindo um post:\n    ---\n    {{ clientMessage }}\n    ---\n\n    Escreva 1 post pro Instagram baseado no que o(a) cliente pediu.\n\n    Seu negócio:\n    - Nome: {{ profile.businessName }}\n    - Tipo: {{ profile.businessType }}\n    - Cidade: {{ profile.city }}\n    {% if profile.services | length > 0 %}- Serviços: {% for s in profile.services %}{{ s.name }} (R${{ s.priceBRL }}){% if not loop.last %}, {% endif %}{% endfor %}{% endif %}\n    - Público: {{ profile.targetAudience }}\n    - Vibe: {{ profile.brandVibe }}\n    - Diferenciais: {% for q in profile.quirks %}{{ q }}{% if not loop.last %}, {% endif %}{% endfor %}\n\n    O post precisa ter:\n    - Legenda CURTA: MÁXIMO 400 caracteres. Conte os caracteres. 2-3 parágrafos curtos, não mais.\n    - Hashtags do nicho (0 a 3, só se fizer sentido). Não force.\n    - CTA é opcional. A maioria dos posts reais de MEI não tem CTA. Se incluir, varie: \"manda pra uma amiga que precisa ouvir isso\", \"salva pra depois\", \"comenta se já passou por isso\". Evite \"link na bio\", \"chama no zap\". NUNCA use \"salva esse post\" como frase final automática.\n    - Nota de produção: o que fotografar com o celular, enquadramento, uma dica. 2-3 frases. Deve ser algo que a pessoa consiga fazer sozinha, agora, sem planejar.\n\n    REGRA PRINCIPAL, use os detalhes concretos da mensagem do cliente:\n    - Extraia nomes, números, datas, detalhes específicos da mensagem e use na legenda.\n    - Se a mensagem mencionar preço, inclua o preço. Se mencionar data, inclua a data.\n\n    Como você escreve:\n    - Do jeito que você falaria com um cliente no balcão. Frases curtas.\n    - NUNCA use travessão (—). Use vírgula ou ponto.\n    - Abra com um micro-momento concreto: uma cena, um número real, um detalhe sensorial.\n    - Inclua pelo menos um detalhe que não está nos dados do negócio acima: um horário, o clima, um som, algo que aconteceu. Detalhes inventados devem ter vida própria, não apenas decorar o pitch.\n    - Use palavras-chave do nicho em algum lugar da legenda, de forma natural. O Instagram funciona como buscador. Não force na primeira frase se não couber.\n    - Mencione o nome do negócio. A cidade/bairro pode aparecer se couber naturalmente, mas não force \"aqui em [cidade]\" em todo post.\n    - Emojis só quando você usaria de verdade no WhatsApp.\n    - NUNCA termine com pergunta genérica de engajamento.\n    - IMPORTANTE: A legenda deve ter no MÁXIMO 400 caracteres. Posts curtos têm mais engajamento. Não desenvolva além do necessário.\n\n    {% if previousHooks | length > 0 %}\n    IMPORTANTE: Estes ganchos já foram usados em posts anteriores. NÃO repita o mesmo ângulo, tema ou cena. Crie algo completamente diferente:\n    {% for hook in previousHooks %}- {{ hook }}\n    {% endfor %}\n    {% endif %}\n\n    {{ ctx.output_format }}\n  \"#\n}\n",
+	"content.baml":    "function GenerateContent(profile: BusinessProfile, roles: ContentRole[], previousHooks: string[], styleMemo: string) -> Post {\n  client GeneratorClient\n  prompt #\"\n    Você é o(a) dono(a) do(a) {{ profile.businessName }}. Você mesmo(a) escreve os posts do Instagram do seu negócio. Sem agência, sem equipe de marketing. Escreve do jeito que fala.\n\n    Escreva 1 post pro seu Instagram.\n\n    Seu negócio:\n    - Nome: {{ profile.businessName }}\n    - Tipo: {{ profile.businessType }}\n    - Cidade: {{ profile.city }}\n    {% if profile.services | length > 0 %}- Serviços: {% for s in profile.services %}{{ s.name }} (R${{ s.priceBRL }}){% if not loop.last %}, {% endif %}{% endfor %}{% endif %}\n    - Público: {{ profile.targetAudience }}\n    - Vibe: {{ profile.brandVibe }}\n    - Diferenciais: {% for q in profile.quirks %}{{ q }}{% if not loop.last %}, {% endif %}{% endfor %}\n\n    O post precisa ter:\n    - Legenda CURTA: MÁXIMO 400 caracteres. Conte os caracteres. 2-3 parágrafos curtos, não mais.\n    - Hashtags do nicho (0 a 3, só se fizer sentido). Não force.\n    - CTA é opcional. A maioria dos posts reais de MEI não tem CTA. Se incluir, varie: \"manda pra uma amiga que precisa ouvir isso\", \"salva pra depois\", \"comenta se já passou por isso\". Evite \"link na bio\", \"chama no zap\". NUNCA use \"salva esse post\" como frase final automática.\n    - Nota de produção: o que fotografar com o celular, enquadramento, uma dica. 2-3 frases. Deve ser algo que a pessoa consiga fazer sozinha, agora, sem planejar.\n\n    Papel do post:\n    {% for r in roles %}  {{ r.name }}: {{ r.description }}\n    {% endfor %}\n\n    Como você escreve:\n    - Do jeito que você falaria com um cliente no balcão. Frases curtas.\n    - NUNCA use travessão (—). Use vírgula ou ponto.\n    - Abra com um micro-momento concreto: uma cena, um número real, um detalhe sensorial. Nada de declarações genéricas.\n    - Inclua pelo menos um detalhe que não está nos dados do negócio acima: um horário, o clima, um som, uma pessoa, algo que aconteceu. Detalhes inventados devem ter vida própria, não apenas decorar o pitch.\n    - Use palavras-chave do nicho em algum lugar da legenda, de forma natural. O Instagram funciona como buscador. Não force na primeira frase se não couber.\n    - Mencione o nome do negócio. A cidade/bairro pode aparecer se couber naturalmente, mas não force \"aqui em [cidade]\" em todo post.\n    - Emojis só quando você usaria de verdade no WhatsApp.\n    - NUNCA termine com pergunta genérica de engajamento (\"qual seu favorito?\", \"comenta aqui\", \"marca um amigo?\").\n    - Evite o formato \"pergunta que eu escuto toda semana + resposta\". Varie as estruturas: bastidor, marco, opinião, cena do dia, reflexão pessoal.\n    - IMPORTANTE: A legenda deve ter no MÁXIMO 400 caracteres. Posts curtos têm mais engajamento. Não desenvolva a história além do necessário. Um parágrafo de abertura + um de contexto é suficiente.\n\n    {% if styleMemo %}\n    Correções que esse cliente já pediu. Siga sempre:\n    {{ styleMemo }}\n    {% endif %}\n\n    {% if previousHooks | length > 0 %}\n    IMPORTANTE: Estes ganchos já foram usados em posts anteriores. NÃO repita o mesmo ângulo, tema ou cena. Crie algo completamente diferente:\n    {% for hook in previousHooks %}- {{ hook }}\n    {% endfor %}\n    {% endif %}\n\n    {{ ctx.output_format }}\n  \"#\n}\n\nfunction GenerateFromMessage(profile: BusinessProfile, clientMessage: string, previousHooks: string[], styleMemo: string) -> Post {\n  client GeneratorClient\n  prompt #\"\n    Você é o(a) dono(a) do(a) {{ profile.businessName }}. Você mesmo(a) escreve os posts do Instagram do seu negócio. Sem agência, sem equipe de marketing. Escreve do jeito que fala.\n\n    Um(a) cliente mandou essa mensagem no WhatsApp pedindo um post:\n    ---\n    {{ clientMessage }}\n    ---\n\n    Escreva 1 post pro Instagram baseado no que o(a) cliente pediu.\n\n    Seu negócio:\n    - Nome: {{ profile.businessName }}\n    - Tipo: {{ profile.businessType }}\n    - Cidade: {{ profile.city }}\n    {% if profile.services | length > 0 %}- Serviços: {% for s in profile.services %}{{ s.name }} (R${{ s.priceBRL }}){% if not loop.last %}, {% endif %}{% endfor %}{% endif %}\n    - Público: {{ profile.targetAudience }}\n    - Vibe: {{ profile.brandVibe }}\n    - Diferenciais: {% for q in profile.quirks %}{{ q }}{% if not loop.last %}, {% endif %}{% endfor %}\n\n    O post precisa ter:\n    - Legenda CURTA: MÁXIMO 400 caracteres. Conte os caracteres. 2-3 parágrafos curtos, não mais.\n    - Hashtags do nicho (0 a 3, só se fizer sentido). Não force.\n    - CTA é opcional. A maioria dos posts reais de MEI não tem CTA. Se incluir, varie: \"manda pra uma amiga que precisa ouvir isso\", \"salva pra depois\", \"comenta se já passou por isso\". Evite \"link na bio\", \"chama no zap\". NUNCA use \"salva esse post\" como frase final automática.\n    - Nota de produção: o que fotografar com o celular, enquadramento, uma dica. 2-3 frases. Deve ser algo que a pessoa consiga fazer sozinha, agora, sem planejar.\n\n    REGRA PRINCIPAL, use os detalhes concretos da mensagem do cliente:\n    - Extraia nomes, números, datas, detalhes específicos da mensagem e use na legenda.\n    - Se a mensagem mencionar preço, inclua o preço. Se mencionar data, inclua a data.\n\n    Como você escreve:\n    - Do jeito que você falaria com um cliente no balcão. Frases curtas.\n    - NUNCA use travessão (—). Use vírgula ou ponto.\n    - Abra com um micro-momento concreto: uma cena, um número real, um detalhe sensorial.\n    - Inclua pelo menos um detalhe que não está nos dados do negócio acima: um horário, o clima, um som, algo que aconteceu. Detalhes inventados devem ter vida própria, não apenas decorar o pitch.\n    - Use palavras-chave do nicho em algum lugar da legenda, de forma natural. O Instagram funciona como buscador. Não force na primeira frase se não couber.\n    - Mencione o nome do negócio. A cidade/bairro pode aparecer se couber naturalmente, mas não force \"aqui em [cidade]\" em todo post.\n    - Emojis só quando você usaria de verdade no WhatsApp.\n    - NUNCA termine com pergunta genérica de engajamento.\n    - IMPORTANTE: A legenda deve ter no MÁXIMO 400 caracteres. Posts curtos têm mais engajamento. Não desenvolva além do necessário.\n\n    {% if styleMemo %}\n    Correções que esse cliente já pediu. Siga sempre:\n    {{ styleMemo }}\n    {% endif %}\n\n    {% if previousHooks | length > 0 %}\n    IMPORTANTE: Estes ganchos já foram usados em posts anteriores. NÃO repita o mesmo ângulo, tema ou cena. Crie algo completamente diferente:\n    {% for hook in previousHooks %}- {{ hook }}\n    {% endfor %}\n    {% endif %}\n\n    {{ ctx.output_format }}\n  \"#\n}\n",
+	"formats.baml":    "class ReelScene {\n  visual string        // what the camera shows\n  onScreenText string  // text overlay, empty if none\n  voiceover string     // what the owner says, empty if none\n}\n\nclass ReelScript {\n  hook string          // first 3 seconds, spoken or on screen\n  scenes ReelScene[]\n  audioHint string     // kind of audio: trending sound, original voice, calm music\n  caption string\n  hashtags string[]\n}\n\nclass StoryFrame {\n  visual string\n  text string\n  sticker string       // \"enquete\", \"caixinha\", \"quiz\", \"link\" or empty\n}\n\nclass StorySequence {\n  frames StoryFrame[]\n}\n\nclass CarouselSlide {\n  title string\n  body string\n}\n\nclass Carousel {\n  slides CarouselSlide[]\n  caption string\n  hashtags string[]\n}\n\nfunction GenerateReelScript(profile: BusinessProfile, roles: ContentRole[], previousHooks: string[], styleMemo: string) -> ReelScript {\n  client GeneratorClient\n  prompt #\"\n    Você é o(a) dono(a) do(a) {{ profile.businessName }}. Você mesmo(a) grava os reels do seu negócio com o celular. Sem agência, sem equipe, sem equipamento.\n\n    Escreva o roteiro de 1 reel pro seu Instagram.\n\n    Seu negócio:\n    - Nome: {{ profile.businessName }}\n    - Tipo: {{ profile.businessType }}\n    - Cidade: {{ profile.city }}\n    {% if profile.services | length > 0 %}- Serviços: {% for s in profile.services %}{{ s.name }} (R${{ s.priceBRL }}){% if not loop.last %}, {% endif %}{% endfor %}{% endif %}\n    - Público: {{ profile.targetAudience }}\n    - Vibe: {{ profile.brandVibe }}\n    - Diferenciais: {% for q in profile.quirks %}{{ q }}{% if not loop.last %}, {% endif %}{% endfor %}\n\n    O roteiro precisa ter:\n    - Gancho: o que aparece ou é dito nos primeiros 3 segundos. Uma frase curta que faça a pessoa parar de rolar.\n    - 3 a 6 cenas. Cada cena: o que filmar (enquadramento, movimento, detalhe), texto na tela (curto, até 8 palavras, ou vazio) e fala (o que você diz, ou vazio).\n    - O reel inteiro deve caber em 15 a 30 segundos.\n    - Sugestão de áudio: áudio em alta, voz original ou música calma. Diga qual combina e por quê em poucas palavras.\n    - Legenda CURTA: MÁXIMO 300 caracteres. O reel conta a história, a legenda só complementa.\n    - Hashtags do nicho (0 a 3, só se fizer sentido).\n\n    Papel do reel:\n    {% for r in roles %}  {{ r.name }}: {{ r.description }}\n    {% endfor %}\n\n    Como você grava:\n    - Tudo precisa ser filmável sozinho(a), hoje, no próprio negócio. Nada de drone, figurante ou cenário montado.\n    - Mostre mãos trabalhando, antes e depois, detalhes de perto. Pessoas param pra ver processo.\n    - Fale do jeito que falaria com um cliente no balcão. Frases curtas.\n    - NUNCA use travessão (—). Use vírgula ou ponto.\n    - Mencione o nome do negócio na legenda ou numa fala.\n\n    {% if styleMemo %}\n    Correções que esse cliente já pediu. Siga sempre:\n    {{ styleMemo }}\n    {% endif %}\n\n    {% if previousHooks | length > 0 %}\n    IMPORTANTE: Estes ganchos já foram usados em posts anteriores. NÃO repita o mesmo ângulo, tema ou cena. Crie algo completamente diferente:\n    {% for hook in previousHooks %}- {{ hook }}\n    {% endfor %}\n    {% endif %}\n\n    {{ ctx.output_format }}\n  \"#\n}\n\nfunction GenerateStorySequence(profile: BusinessProfile, roles: ContentRole[], previousHooks: string[], styleMemo: string) -> StorySequence {\n  client GeneratorClient\n  prompt #\"\n    Você é o(a) dono(a) do(a) {{ profile.businessName }}. Você mesmo(a) posta os stories do seu negócio ao longo do dia, com o celular.\n\n    Escreva uma sequência de stories pro seu Instagram.\n\n    Seu negócio:\n    - Nome: {{ profile.businessName }}\n    - Tipo: {{ profile.businessType }}\n    - Cidade: {{ profile.city }}\n    {% if profile.services | length > 0 %}- Serviços: {% for s in profile.services %}{{ s.name }} (R${{ s.priceBRL }}){% if not loop.last %}, {% endif %}{% endfor %}{% endif %}\n    - Público: {{ profile.targetAudience }}\n    - Vibe: {{ profile.brandVibe }}\n    - Diferenciais: {% for q in profile.quirks %}{{ q }}{% if not loop.last %}, {% endif %}{% endfor %}\n\n    A sequência precisa ter:\n    - 3 a 5 stories que contam uma coisa só, do começo ao fim.\n    - Cada story: o que fotografar ou filmar, o texto que vai por cima (MÁXIMO 120 caracteres, quem vê stories não lê parágrafo) e um sticker quando fizer sentido.\n    - Stickers possíveis: \"enquete\", \"caixinha\", \"quiz\", \"link\". Use no máximo 2 na sequência toda. Deixe vazio nos outros.\n    - O último story dá um motivo pra pessoa responder ou agir.\n\n    Papel da sequência:\n    {% for r in roles %}  {{ r.name }}: {{ r.description }}\n    {% endfor %}\n\n    Como você escreve:\n    - Do jeito que você falaria com um cliente no balcão. Frases curtas.\n    - NUNCA use travessão (—). Use vírgula ou ponto.\n    - Stories são bastidor. Mostre o dia real, não propaganda.\n    - Emojis só quando você usaria de verdade no WhatsApp.\n\n    {% if styleMemo %}\n    Correções que esse cliente já pediu. Siga sempre:\n    {{ styleMemo }}\n    {% endif %}\n\n    {% if previousHooks | length > 0 %}\n    IMPORTANTE: Estes ganchos já foram usados em posts anteriores. NÃO repita o mesmo ângulo, tema ou cena. Crie algo completamente diferente:\n    {% for hook in previousHooks %}- {{ hook }}\n    {% endfor %}\n    {% endif %}\n\n    {{ ctx.output_format }}\n  \"#\n}\n\nfunction GenerateCarousel(profile: BusinessProfile, roles: ContentRole[], previousHooks: string[], styleMemo: string) -> Carousel {\n  client GeneratorClient\n  prompt #\"\n    Você é o(a) dono(a) do(a) {{ profile.businessName }}. Você mesmo(a) monta os carrosséis do Instagram do seu negócio, no Canva ou no próprio app.\n\n    Escreva 1 carrossel pro seu Instagram.\n\n    Seu negócio:\n    - Nome: {{ profile.businessName }}\n    - Tipo: {{ profile.businessType }}\n    - Cidade: {{ profile.city }}\n    {% if profile.services | length > 0 %}- Serviços: {% for s in profile.services %}{{ s.name }} (R${{ s.priceBRL }}){% if not loop.last %}, {% endif %}{% endfor %}{% endif %}\n    - Público: {{ profile.targetAudience }}\n    - Vibe: {{ profile.brandVibe }}\n    - Diferenciais: {% for q in profile.quirks %}{{ q }}{% if not loop.last %}, {% endif %}{% endfor %}\n\n    O carrossel precisa ter:\n    - 4 a 8 slides. O primeiro é a capa: título forte e corpo curto ou vazio.\n    - Cada slide: título (até 6 palavras) e corpo (MÁXIMO 200 caracteres). Uma ideia por slide.\n    - O último slide fecha a ideia. CTA é opcional.\n    - Legenda CURTA: MÁXIMO 300 caracteres. Não repita o que já está nos slides.\n    - Hashtags do nicho (0 a 3, só se fizer sentido).\n\n    Papel do carrossel:\n    {% for r in roles %}  {{ r.name }}: {{ r.description }}\n    {% endfor %}\n\n    Como você escreve:\n    - Do jeito que você falaria com um cliente no balcão. Frases curtas.\n    - NUNCA use travessão (—). Use vírgula ou ponto.\n    - Conteúdo que a pessoa queira salvar: passo a passo, erros comuns, antes e depois, comparação de opções.\n    - Use detalhes concretos do seu negócio: preços, tempos, materiais.\n    - Mencione o nome do negócio em algum slide ou na legenda.\n\n    {% if styleMemo %}\n    Correções que esse cliente já pediu. Siga sempre:\n    {{ styleMemo }}\n    {% endif %}\n\n    {% if previousHooks | length > 0 %}\n    IMPORTANTE: Estes ganchos já foram usados em posts anteriores. NÃO repita o mesmo ângulo, tema ou cena. Crie algo completamente diferente:\n    {% for hook in previousHooks %}- {{ hook }}\n    {% endfor %}\n    {% endif %}\n\n    {{ ctx.output_format }}\n  \"#\n}\n",
 	"generators.baml": "generator go {\n  output_type \"go\"\n  output_dir \"..\"\n  client_package_name \"github.com/denisraison/rekan/api/internal/baml\"\n  version \"0.219.0\"\n  on_generate \"gofmt -w . && goimports -w . && go mod tidy\"\n}\n",
 	"judges.baml":     "class Service {\n  name string\n  priceBRL float\n}\n\nclass BusinessProfile {\n  businessName string\n  businessType string\n  city string\n  services Service[]\n  targetAudience string\n  brandVibe string\n  quirks string[]\n}\n\nclass ContentRole {\n  name string\n  description string\n}\n\nclass Post {\n  caption string\n  hashtags string[]\n  productionNote string\n}\n\nclass JudgeResult {\n  reasoning string\n  verdict bool\n}\n\nclass JudgeVariedadeResult {\n  postMessages string[]\n  reasoning string\n  verdict bool\n}\n\nfunction JudgeNaturalidade(profile: BusinessProfile, content: string) -> JudgeResult {\n  client JudgeClient\n  prompt #\"\n    Você é um avaliador rigoroso de conteúdo para Instagram brasileiro.\n\n    Já foi verificado que o texto usa português brasileiro informal. Sua tarefa é diferente: avaliar se o texto parece escrito por uma PESSOA REAL ou por uma IA imitando o estilo do Instagram.\n\n    Perfil do negócio:\n    - Nome: {{ profile.businessName }}\n    - Tipo: {{ profile.businessType }}\n    - Cidade: {{ profile.city }}\n\n    Conteúdo a avaliar:\n    ---\n    {{ content }}\n    ---\n\n    Sinais de conteúdo gerado por IA (reprove se encontrar 2 ou mais):\n    - Emoji em quase toda frase, como decoração automática\n    - Mesma estrutura nos posts: abertura animada → informação → pergunta → CTA\n    - Informalidade forçada: acumula gente, bora, né, tá no mesmo parágrafo como checklist\n    - Frases genéricas de preenchimento (\"feito com muito carinho\", \"você merece o melhor\", \"a gente ama o que faz\")\n    - Tom uniformemente entusiasmado do início ao fim, sem variação de energia\n    - Uso de travessão (—). Apenas 5% dos posts reais de MEIs usam travessão, mas LLMs usam com frequência. Múltiplos travessões no mesmo texto são sinal forte de IA.\n\n    Sinais de conteúdo autêntico (aprove se predominarem):\n    - Voz com personalidade própria, não \"brasileiro genérico de Instagram\"\n    - Ritmo variado: mistura frases curtas e longas naturalmente\n    - Emojis com intenção, não em toda frase\n    - Pelo menos um momento que soa como opinião pessoal, não fórmula\n\n    Exemplo de reprovação (deve receber verdict: false):\n    \"Gente, vocês não tão prontos! 😍🔥 Nosso smash é feito com muito amor e dedicação pra vocês! A gente ama o que faz e isso faz toda a diferença, né? 💕 Cada detalhe é pensado com carinho pra vocês! Bora experimentar? Chama no WhatsApp! 😘\"\n    Motivo: emoji em toda frase, \"feito com amor e dedicação\" + \"pensado com carinho\" (filler genérico), gente + né + bora empilhados no mesmo parágrafo, tom 100% entusiasmado sem pausa. Parece IA performando informalidade.\n\n    Primeiro explique seu raciocínio em 2-3 frases, depois dê o veredito.\n    Veredito: true se soa autêntico, false se parece gerado por IA.\n\n    {{ ctx.output_format }}\n  \"#\n}\n\nfunction JudgeEspecificidade(profile: BusinessProfile, content: string) -> JudgeResult {\n  client JudgeClient\n  prompt #\"\n    Você é um avaliador rigoroso de conteúdo para Instagram brasileiro.\n\n    Sua tarefa: o conteúdo tem detalhes que existem POR SI SÓS, ou todo detalhe inventado serve apenas para vender o produto/serviço?\n\n    Perfil do negócio (dados que a IA recebeu):\n    - Nome: {{ profile.businessName }}\n    - Tipo: {{ profile.businessType }}\n    - Cidade: {{ profile.city }}\n    - Serviços: {% for s in profile.services %}{{ s.name }} (R${{ s.priceBRL }}){% if not loop.last %}, {% endif %}{% endfor %}\n    - Público: {{ profile.targetAudience }}\n    - Vibe: {{ profile.brandVibe }}\n    - Diferenciais: {% for q in profile.quirks %}{{ q }}{% if not loop.last %}, {% endif %}{% endfor %}\n\n    Conteúdo a avaliar:\n    ---\n    {{ content }}\n    ---\n\n    Teste decisivo: para cada detalhe inventado, tire a menção ao produto/serviço. O detalhe ainda tem valor para o leitor? Se não, é decoração de pitch.\n\n    EXEMPLO 1 — verdict: false (dados do perfil reformatados)\n    \"Aqui no Setor Bueno a gente faz smash burger com nosso blend secreto 🍔 O molho da casa é preparado todo dia! Simples por R$28, duplo por R$38, combo completo por R$52. Bora provar?\"\n    Motivo: Setor Bueno = campo bairro, blend secreto = campo diferenciais, preços = campo serviços. Cada informação veio do perfil. Zero textura.\n\n    EXEMPLO 2 — verdict: false (pitch embrulhado em história)\n    \"Era uma terça à noite e a Maria, dona de uma loja de roupas, tava exausta tentando escrever uma legenda pro Instagram. Ela não sabia o que postar. Foi aí que ela descobriu o AppX. O AppX olha pro conteúdo dela e escreve a legenda perfeita. Maria nunca mais travou.\"\n    Motivo: tire o AppX e a história da Maria não tem razão de existir. A cena foi inventada apenas para montar o pitch. Isso não é especificidade, é narrativa instrumental.\n\n    EXEMPLO 3 — verdict: true (detalhes com vida própria)\n    \"Sexta 18h e o cheiro da chapa já tá chamando a galera aqui no Bueno 🔥 Tem fila? Tem. Mas quem já mordeu o duplo sabe que vale cada minuto. Hoje o Rafa tá no comando da chapa, capricho dobrado 😂\"\n    Motivo: \"sexta 18h\" (cena temporal), \"cheiro da chapa\" (sensorial), \"tem fila\" (observação), \"Rafa no comando\" (personagem). Tire o produto e a cena ainda pinta um momento real. Os detalhes enriquecem por si sós.\n\n    Primeiro explique seu raciocínio em 2-3 frases, depois dê o veredito.\n    Veredito: true se os detalhes inventados valem por si sós, false se servem apenas ao pitch.\n\n    {{ ctx.output_format }}\n  \"#\n}\n\nfunction JudgeAcionavel(profile: BusinessProfile, content: string) -> JudgeResult {\n  client JudgeClient\n  prompt #\"\n    Você é um avaliador rigoroso de conteúdo para Instagram brasileiro.\n\n    Perfil do negócio:\n    - Nome: {{ profile.businessName }}\n    - Tipo: {{ profile.businessType }}\n\n    Conteúdo a avaliar:\n    ---\n    {{ content }}\n    ---\n\n    Avalie estes 3 critérios de qualidade:\n\n    NOTA DE PRODUÇÃO: reprove se for vaga (\"tire uma foto do produto\", \"grave um vídeo mostrando o serviço\"). Aprove se disser o que filmar, de que ângulo, em que momento.\n\n    CTA (só avalie se houver CTA no post, ausência de CTA é perfeitamente aceitável):\n    - Reprove se for genérico e desconectado do conteúdo (\"chama no WhatsApp!\" solto).\n    - Reprove se usar CTA de saída (\"link na bio\", \"chama no zap\", \"acesse o site\") em post que NÃO é explicitamente de venda/promoção. CTAs de saída só fazem sentido em posts de venda direta.\n    - Aprove se for CTA de plataforma (\"salva esse post\", \"manda pra uma amiga\", \"comenta aqui\") com motivo claro ligado ao post.\n    - Se não houver CTA, este critério passa automaticamente.\n\n    FLUIDEZ: reprove se a legenda parecer seções coladas (texto -> bloco de hashtags -> CTA solto -> nota solta). Aprove se a transição entre elementos for natural.\n\n    Reprove se 2 ou mais critérios falharem.\n\n    Exemplo de reprovação (elementos existem mas sem qualidade):\n    \"... Chama no WhatsApp! Nota de produção: tire uma foto bonita do produto.\"\n    Motivo: CTA genérico de saída num post que não é de venda, nota de produção vaga. Elementos sem qualidade.\n\n    Primeiro explique seu raciocínio em 2-3 frases, depois dê o veredito.\n    Veredito: true se os elementos têm qualidade, false se são genéricos/vagos.\n\n    {{ ctx.output_format }}\n  \"#\n}\n\nfunction JudgeVariedade(profile: BusinessProfile, content: string) -> JudgeVariedadeResult {\n  client JudgeClient\n  prompt #\"\n    Você é um avaliador rigoroso de conteúdo para Instagram brasileiro.\n\n    Perfil do negócio:\n    - Nome: {{ profile.businessName }}\n    - Tipo: {{ profile.businessType }}\n\n    Conteúdo a avaliar:\n    ---\n    {{ content }}\n    ---\n\n    TAREFA em 2 passos:\n\n    PASSO 1: Para cada post, escreva em UMA frase curta o que o leitor leva depois de ler. Coloque cada frase no campo postMessages. ATENÇÃO: se todas as frases mencionam o mesmo produto/serviço como solução, elas são a mesma mensagem. Escreva sem mencionar o nome do produto.\n\n    PASSO 2: Compare as frases. Se são essencialmente a mesma (\"use X\", \"experimente X\", \"X resolve\"), reprove.\n\n    Exemplo que REPROVA (verdict: false):\n    Post 1 (história): \"Era terça à noite e eu vi minha amiga Ana travada tentando escrever uma legenda. O AppX nasceu ali. Testa, o link tá na bio.\"\n    Post 2 (números): \"1.500 pessoas já baixaram o AppX. O pequeno negócio quer mostrar o trabalho sem gastar horas num post.\"\n    Post 3 (citação): \"Um dono de oficina me disse que Instagram virou trabalho não remunerado. O AppX resolve isso.\"\n    postMessages: [\"Existe solução pra quem trava na hora de postar\", \"Existe solução pra quem trava na hora de postar\", \"Existe solução pra quem trava na hora de postar\"]\n    Motivo: sem o nome do produto, as três mensagens são idênticas. Três estruturas, um só pitch.\n\n    Exemplo que APROVA (verdict: true):\n    Post 1: \"Sexta 18h e o cheiro da chapa já tá chamando a galera 🔥 Tem fila? Tem. Mas quem já mordeu o duplo sabe que vale cada minuto.\"\n    Post 2: \"3 erros que todo mundo comete na hora de montar o hambúrguer em casa: carne fria na chapa, pão sem tostar, queijo errado.\"\n    Post 3: \"Pergunta honesta: alguém consegue comer smash sem fazer sujeira? Porque aqui a gente já desistiu 😂\"\n    postMessages: [\"Vale esperar na fila\", \"Como fazer melhor em casa\", \"Hambúrguer é pra curtir sem frescura\"]\n    Motivo: cada post dá ao leitor algo diferente para pensar.\n\n    Se houver apenas um post, coloque sua mensagem em postMessages e avalie se demonstra criatividade.\n\n    {{ ctx.output_format }}\n  \"#\n}\n\nfunction JudgeEngajamento(profile: BusinessProfile, content: string) -> JudgeResult {\n  client JudgeClient\n  prompt #\"\n    Você é um avaliador rigoroso de conteúdo para Instagram brasileiro.\n\n    Perfil do negócio:\n    - Nome: {{ profile.businessName }}\n    - Tipo: {{ profile.businessType }}\n    - Público: {{ profile.targetAudience }}\n\n    Conteúdo a avaliar:\n    ---\n    {{ content }}\n    ---\n\n    Reprove se:\n    - O gancho usa fórmulas batidas: \"Você sabia que...?\", \"Gente, prepara o coração!\", \"Vocês não estão prontos!\", \"[Número] coisas que...\"\n    - O engajamento depende de pedir ação genérica (\"comenta aqui 👇\", \"marca um amigo\") sem dar motivo real para fazê-lo\n    - Qualquer negócio do mesmo tipo poderia usar o mesmo gancho, sem nenhum detalhe específico deste negócio\n    - Uso de travessão (—). Apenas 5% dos posts reais de Instagram usam travessão, mas LLMs usam com frequência. Múltiplos travessões no texto são sinal forte de IA.\n\n    Aprove se:\n    - A primeira linha cria curiosidade real (um dado específico, uma cena, uma contradição, uma história que começa no meio)\n    - Há motivo real pra salvar, compartilhar ou comentar (aprendi algo novo, me identifiquei com a situação, quero mandar pra alguém específico)\n    - O post tem voz genuína e personalidade própria, mesmo que seja um anúncio direto ou comunicado simples. Não precisa ser storytelling para passar. Um anúncio com detalhes específicos (preço, data, o que esperar) em tom natural também é válido.\n\n    Exemplo de reprovação (fórmula de engajamento):\n    \"Você sabia que um bom corte pode mudar completamente seu visual? 😱 Pois é! Aqui no nosso espaço a gente transforma! Antes e depois que vai te deixar de queixo caído! Comenta aqui se você também ama! 👇 Marca aquele amigo que tá precisando! 😂\"\n    Motivo: \"Você sabia\" (gancho genérico), \"mudar completamente seu visual\" (óbvio, qualquer salão diria isso), \"comenta + marca\" sem dar motivo real. Fórmula, não engajamento.\n\n    Primeiro explique seu raciocínio em 2-3 frases, depois dê o veredito.\n    Veredito: true se o engajamento é genuíno, false se é fórmula.\n\n    {{ ctx.output_format }}\n  \"#\n}\n",
 	"profile.baml":    "class ProfileSignal {\n  field string    // \"services\", \"quirks\", \"target_audience\", \"brand_vibe\"\n  value string    // for services: \"Name|price_brl\" (e.g. \"Selagem|150.0\"); for others: plain text\n}\n\nclass PartialService {\n  name string\n  priceBRL float?\n}\n\nclass PartialBusinessProfile {\n  services PartialService[]?\n  targetAudience string?\n  brandVibe string?\n  quirks string[]?\n}\n\nfunction ExtractBusinessProfile(transcript: string, businessType: string) -> PartialBusinessProfile {\n  client ProfileClient\n  prompt #\"\n    Você vai extrair informações de um negócio a partir de uma transcrição de áudio em português falado de forma casual.\n\n    Tipo do negócio: {{ businessType }}\n\n    Transcrição:\n    ---\n    {{ transcript }}\n    ---\n\n    Regras de extração:\n    - O áudio é fala informal, com vícios de linguagem, frases incompletas e recomeços. Isso é normal.\n    - Extraia serviços e preços literalmente (\"selagem por R$150\" → name: \"Selagem\", priceBRL: 150). Para faixas de preço, use o menor valor.\n    - Nomes de serviço devem ser curtos e identificáveis, sem fragmentos de fala.\n    - Infira targetAudience a partir de pistas de contexto (\"mulheres da região\", \"jovens que querem emagrecer\").\n    - brandVibe: 1 a 3 adjetivos curtos que descrevem o tom e a atmosfera do lugar (\"premium\", \"acolhedor\", \"despojado e divertido\"). Não inclua adjetivos sobre a personalidade do dono. Não use frases completas.\n    - quirks: diferenciais concretos extraídos diretamente do que foi dito — não resumos nem inferências. Cada quirk deve ter 3 a 7 palavras. Não repita o tipo do negócio como quirk. Prefira fatos específicos e incomuns (\"atende só por encomenda\", \"gelato feito na hora\") a descrições genéricas (\"ambiente agradável\", \"atendimento de qualidade\"). Inclua fatos sobre o dono com o nome se mencionado.\n    - Se um campo não for mencionado, retorne null. NUNCA invente. Um resultado parcial com 2 campos é melhor que um resultado completo com valores inventados.\n\n    {{ ctx.output_format }}\n  \"#\n}\n\nfunction ExtractProfileSignal(message: string, businessType: string) -> ProfileSignal? {\n  client JudgeClient\n  prompt #\"\n    Você está analisando uma mensagem de WhatsApp enviada por um cliente de um negócio brasileiro.\n\n    Tipo do negócio: {{ businessType }}\n\n    Mensagem:\n    ---\n    {{ message }}\n    ---\n\n    Verifique se a mensagem menciona um serviço, preço, diferencial ou característica do negócio que ajudaria a melhorar o perfil.\n\n    Regras:\n    - Se mencionar um serviço específico com ou sem preço: retorne field=\"services\", value=\"Nome do Serviço|preco\" (ex: \"Selagem|150.0\" ou \"Corte|0\")\n    - Se mencionar algo que torna o negócio único ou especial: retorne field=\"quirks\", value=\"o texto relevante\"\n    - Se descrever o público-alvo: retorne field=\"target_audience\", value=\"descrição\"\n    - Se descrever o ambiente ou estilo do negócio: retorne field=\"brand_vibe\", value=\"descrição\"\n    - Se a mensagem for apenas saudação, agendamento, reclamação ou não tiver informação útil sobre o perfil: retorne null\n    - Retorne apenas o sinal mais relevante. Se não houver nada útil, retorne null.\n\n    {{ ctx.output_format }}\n  \"#\n}\n",
 	"rekan.baml":      "function GenerateRekanContent(profile: BusinessProfile, roles: ContentRole[], previousHooks: string[]) -> Post {\n  client GeneratorClient\n  prompt #\"\n    Você é a pessoa que criou o {{ profile.businessName }}. Você viu de perto a dor de microempreendedores que não conseguem postar no Instagram com constância e decidiu resolver isso.\n\n    Você mesmo(a) cuida do Instagram do produto. Sem agência, sem equipe de marketing. Escreve do jeito que fala.\n\n    Escreva 1 post pro Instagram do {{ profile.businessName }}.\n\n    Sobre o produto:\n    - Nome: {{ profile.businessName }}\n    - O que faz: {{ profile.businessType }}\n    - Funcionalidades: {% for s in profile.services %}{{ s.name }}{% if not loop.last %}, {% endif %}{% endfor %}\n    - Público: {{ profile.targetAudience }}\n    - Tom: {{ profile.brandVibe }}\n    - Diferenciais: {% for q in profile.quirks %}{{ q }}{% if not loop.last %}, {% endif %}{% endfor %}\n\n    O post precisa ter:\n    - Legenda CURTA: MÁXIMO 400 caracteres. Conte os caracteres. 2-3 parágrafos curtos, não mais.\n    - Hashtags do nicho (0 a 3, só se fizer sentido). Não force.\n    - CTA é opcional. A maioria dos posts reais não tem CTA. Se incluir, varie: \"manda pra uma amiga que precisa ouvir isso\", \"salva pra depois\", \"comenta se já passou por isso\". Evite \"link na bio\", \"chama no zap\". NUNCA use \"salva esse post\" como frase final automática.\n    - Nota de produção: o que fotografar com o celular, enquadramento, uma dica. 2-3 frases. Deve ser algo que a pessoa consiga fazer sozinha, agora, sem planejar. Ex: screenshot do app, tela do notebook, selfie trabalhando, print de conversa com usuário.\n\n    REGRA PRINCIPAL, valor antes de produto:\n    - O post deve entregar valor MESMO SEM USAR o produto. Dica prática, insight sobre MEI, bastidor que ensina. O produto pode aparecer de passagem.\n\n    REGRA DE TEXTURA, detalhes com vida própria:\n    - Inclua pelo menos um detalhe que não está nos dados do produto acima: um horário, o clima, uma pessoa com nome e detalhe pessoal, algo que aconteceu. O detalhe deve ter vida própria, não apenas decorar o pitch.\n\n    Papel do post:\n    {% for r in roles %}  {{ r.name }}: {{ r.description }}\n    {% endfor %}\n\n    Como você escreve:\n    - Como fundador(a) falando com quem você quer ajudar, não como marca vendendo produto. Frases curtas.\n    - NUNCA use travessão (—). Use vírgula ou ponto.\n    - Abra com um micro-momento concreto: uma cena, um número real, um detalhe do dia a dia.\n    - Use palavras-chave do nicho em algum lugar da legenda, de forma natural. O Instagram funciona como buscador. Não force na primeira frase se não couber.\n    - Mencione o nome do produto. A cidade ({{ profile.city }}) pode aparecer se couber naturalmente, mas não force \"aqui em [cidade]\" em todo post.\n    - Emojis só quando você usaria de verdade no WhatsApp.\n    - NUNCA termine com pergunta genérica de engajamento.\n    - IMPORTANTE: A legenda deve ter no MÁXIMO 400 caracteres. Posts curtos têm mais engajamento. Não desenvolva além do necessário.\n\n    {% if previousHooks | length > 0 %}\n    IMPORTANTE: Estes ganchos já foram usados em posts anteriores. NÃO repita o mesmo ângulo, tema ou cena. Crie algo completamente diferente:\n    {% for hook in previousHooks %}- {{ hook }}\n    {% endfor %}\n    {% endif %}\n\n    {{ ctx.output_format }}\n  \"#\n}\n",
+	"reply.baml":      "class ClientReply {\n  kind string          // \"approved\", \"change_request\", \"question\" or \"other\"\n  summary string       // what the client wants changed or asked, in their words; empty for approvals\n}\n\nfunction ClassifyClientReply(caption: string, reply: string) -> ClientReply {\n  client JudgeClient\n  prompt #\"\n    Você está lendo a resposta de um cliente no WhatsApp a um post que a gente preparou pro Instagram dele.\n\n    Post enviado:\n    ---\n    {{ caption }}\n    ---\n\n    Resposta do cliente:\n    ---\n    {{ reply }}\n    ---\n\n    Classifique a resposta:\n    - \"approved\": o cliente aprovou ou gostou (\"amei\", \"perfeito\", \"pode postar\", \"👍\").\n    - \"change_request\": o cliente pediu para mudar algo no post, na foto, no texto, no preço ou na data (\"troca a foto\", \"tira o preço\", \"posta só sexta\").\n    - \"question\": o cliente fez uma pergunta sobre o post sem pedir mudança (\"pode postar amanhã?\", \"vai ter vídeo?\").\n    - \"other\": a resposta não tem relação com o post (assunto pessoal, outro pedido, só \"ok\" sem contexto).\n\n    Se houver aprovação e pedido de mudança juntos (\"amei, só troca a foto\"), é \"change_request\".\n\n    summary: para change_request e question, repita o pedido ou a pergunta com as palavras do cliente, em uma frase curta. Para approved e other, deixe vazio.\n\n    {{ ctx.output_format }}\n  \"#\n}\n",
+	"rewrite.baml":    "function RewritePost(profile: BusinessProfile, post: Post, instruction: string, previousHooks: string[], styleMemo: string) -> Post {\n  client GeneratorClient\n  prompt #\"\n    Você é o(a) dono(a) do(a) {{ profile.businessName }}. Você mesmo(a) escreve os posts do Instagram do seu negócio. Escreve do jeito que fala.\n\n    Você já escreveu este post, mas pediram um ajuste:\n    ---\n    Legenda:\n    {{ post.caption }}\n\n    Hashtags: {% for h in post.hashtags %}{{ h }}{% if not loop.last %} {% endif %}{% endfor %}\n\n    Nota de produção:\n    {{ post.productionNote }}\n    ---\n\n    O ajuste pedido:\n    ---\n    {{ instruction }}\n    ---\n\n    Seu negócio:\n    - Nome: {{ profile.businessName }}\n    - Tipo: {{ profile.businessType }}\n    - Cidade: {{ profile.city }}\n    {% if profile.services | length > 0 %}- Serviços: {% for s in profile.services %}{{ s.name }} (R${{ s.priceBRL }}){% if not loop.last %}, {% endif %}{% endfor %}{% endif %}\n    - Público: {{ profile.targetAudience }}\n    - Vibe: {{ profile.brandVibe }}\n    - Diferenciais: {% for q in profile.quirks %}{{ q }}{% if not loop.last %}, {% endif %}{% endfor %}\n\n    Como você reescreve:\n    - Faça exatamente o ajuste pedido. Mantenha o resto: o mesmo assunto, os mesmos fatos, preços e datas.\n    - Se o pedido for só sobre a legenda, devolva as hashtags e a nota de produção como estão.\n    - Nunca invente preço, data ou promoção que não estava no post ou no negócio.\n    - Legenda com no MÁXIMO 400 caracteres.\n    - Do jeito que você falaria com um cliente no balcão. Frases curtas.\n    - NUNCA use travessão (—). Use vírgula ou ponto.\n    - Mencione o nome do negócio.\n\n    {% if styleMemo %}\n    Correções que esse cliente já pediu. Siga sempre:\n    {{ styleMemo }}\n    {% endif %}\n\n    {% if previousHooks | length > 0 %}\n    Estes ganchos já foram usados em outros posts. Se mudar a abertura, não use nenhum deles:\n    {% for hook in previousHooks %}- {{ hook }}\n    {% endfor %}\n    {% endif %}\n\n    {{ ctx.output_format }}\n  \"#\n}\n",
+	"style.baml":      "class RejectedCaption {\n  caption string\n  feedback string      // what the operator or client asked to change\n}\n\nclass EditedCaption {\n  before string        // caption as generated\n  after string         // caption after the operator fixed it\n}\n\nclass StyleMemo {\n  rules string[]       // short, actionable instructions for the next posts\n}\n\nfunction DistillStyleMemo(businessName: string, rejections: RejectedCaption[], edits: EditedCaption[]) -> StyleMemo {\n  client JudgeClient\n  prompt #\"\n    Você ajuda a escrever posts de Instagram para o(a) {{ businessName }}. Abaixo estão correções que já foram pedidas para esse cliente. Transforme isso num memorando curto de estilo, pra que os próximos posts não repitam os mesmos erros.\n\n    {% if rejections | length > 0 %}\n    Posts rejeitados e o motivo:\n    {% for r in rejections %}\n    ---\n    Legenda: {{ r.caption }}\n    Motivo: {{ r.feedback }}\n    {% endfor %}\n    ---\n    {% endif %}\n\n    {% if edits | length > 0 %}\n    Legendas editadas antes de publicar (antes → depois):\n    {% for e in edits %}\n    ---\n    Antes: {{ e.before }}\n    Depois: {{ e.after }}\n    {% endfor %}\n    ---\n    {% endif %}\n\n    Regras do memorando:\n    - No máximo 8 regras, cada uma com no máximo 20 palavras.\n    - Cada regra é uma instrução direta (\"Não use emoji de fogo\", \"Chame as clientes de 'meninas'\", \"Não mencione preço de selagem\").\n    - Só inclua o que aparece nas correções. Não invente preferências.\n    - Se duas correções dizem a mesma coisa, junte numa regra só.\n    - Nas edições, compare antes e depois: o que foi tirado, trocado ou acrescentado é o que o cliente quer.\n    - Se uma correção contradiz outra, fique com a mais recente (a primeira da lista).\n\n    {{ ctx.output_format }}\n  \"#\n}\n",
 }
 
 func getBamlFiles() map[string]string {
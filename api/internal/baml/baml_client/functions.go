@@ -21,6 +21,154 @@ import (
 	"github.com/denisraison/rekan/api/internal/baml/baml_client/types"
 )
 
+func ClassifyClientReply(ctx context.Context, caption string, reply string, opts ...CallOptionFunc) (types.ClientReply, error) {
+
+	var callOpts callOption
+	for _, opt := range opts {
+		opt(&callOpts)
+	}
+
+	// Resolve client option to clientRegistry (client takes precedence)
+	if callOpts.client != nil {
+		if callOpts.clientRegistry == nil {
+			callOpts.clientRegistry = baml.NewClientRegistry()
+		}
+		callOpts.clientRegistry.SetPrimaryClient(*callOpts.client)
+	}
+
+	args := baml.BamlFunctionArguments{
+		Kwargs: map[string]any{"caption": caption, "reply": reply},
+		Env:    getEnvVars(callOpts.env),
+	}
+
+	if callOpts.clientRegistry != nil {
+		args.ClientRegistry = callOpts.clientRegistry
+	}
+
+	if callOpts.collectors != nil {
+		args.Collectors = callOpts.collectors
+	}
+
+	if callOpts.typeBuilder != nil {
+		args.TypeBuilder = callOpts.typeBuilder
+	}
+
+	if callOpts.tags != nil {
+		args.Tags = callOpts.tags
+	}
+
+	encoded, err := args.Encode()
+	if err != nil {
+		panic(err)
+	}
+
+	if callOpts.onTick == nil {
+		result, err := bamlRuntime.CallFunction(ctx, "ClassifyClientReply", encoded, callOpts.onTick)
+		if err != nil {
+			return types.ClientReply{}, err
+		}
+
+		if result.Error != nil {
+			return types.ClientReply{}, result.Error
+		}
+
+		casted := (result.Data).(types.ClientReply)
+
+		return casted, nil
+	} else {
+		channel, err := bamlRuntime.CallFunctionStream(ctx, "ClassifyClientReply", encoded, callOpts.onTick)
+		if err != nil {
+			return types.ClientReply{}, err
+		}
+
+		for result := range channel {
+			if result.Error != nil {
+				return types.ClientReply{}, result.Error
+			}
+
+			if result.HasData {
+				return result.Data.(types.ClientReply), nil
+			}
+		}
+
+		return types.ClientReply{}, fmt.Errorf("No data returned from stream")
+	}
+}
+
+func DistillStyleMemo(ctx context.Context, businessName string, rejections []types.RejectedCaption, edits []types.EditedCaption, opts ...CallOptionFunc) (types.StyleMemo, error) {
+
+	var callOpts callOption
+	for _, opt := range opts {
+		opt(&callOpts)
+	}
+
+	// Resolve client option to clientRegistry (client takes precedence)
+	if callOpts.client != nil {
+		if callOpts.clientRegistry == nil {
+			callOpts.clientRegistry = baml.NewClientRegistry()
+		}
+		callOpts.clientRegistry.SetPrimaryClient(*callOpts.client)
+	}
+
+	args := baml.BamlFunctionArguments{
+		Kwargs: map[string]any{"businessName": businessName, "rejections": rejections, "edits": edits},
+		Env:    getEnvVars(callOpts.env),
+	}
+
+	if callOpts.clientRegistry != nil {
+		args.ClientRegistry = callOpts.clientRegistry
+	}
+
+	if callOpts.collectors != nil {
+		args.Collectors = callOpts.collectors
+	}
+
+	if callOpts.typeBuilder != nil {
+		args.TypeBuilder = callOpts.typeBuilder
+	}
+
+	if callOpts.tags != nil {
+		args.Tags = callOpts.tags
+	}
+
+	encoded, err := args.Encode()
+	if err != nil {
+		panic(err)
+	}
+
+	if callOpts.onTick == nil {
+		result, err := bamlRuntime.CallFunction(ctx, "DistillStyleMemo", encoded, callOpts.onTick)
+		if err != nil {
+			return types.StyleMemo{}, err
+		}
+
+		if result.Error != nil {
+			return types.StyleMemo{}, result.Error
+		}
+
+		casted := (result.Data).(types.StyleMemo)
+
+		return casted, nil
+	} else {
+		channel, err := bamlRuntime.CallFunctionStream(ctx, "DistillStyleMemo", encoded, callOpts.onTick)
+		if err != nil {
+			return types.StyleMemo{}, err
+		}
+
+		for result := range channel {
+			if result.Error != nil {
+				return types.StyleMemo{}, result.Error
+			}
+
+			if result.HasData {
+				return result.Data.(types.StyleMemo), nil
+			}
+		}
+
+		return types.StyleMemo{}, fmt.Errorf("No data returned from stream")
+	}
+}
+
 func ExtractBusinessProfile(ctx context.Context, transcript string, businessType string, opts ...CallOptionFunc) (types.PartialBusinessProfile, error) {
 
 	var callOpts callOption
@@ -169,7 +317,7 @@ func ExtractProfileSignal(ctx context.Context, message string, businessType stri
 	}
 }
 
-func GenerateContent(ctx context.Context, profile types.BusinessProfile, roles []types.ContentRole, previousHooks []string, opts ...CallOptionFunc) (types.Post, error) {
+func GenerateCarousel(ctx context.Context, profile types.BusinessProfile, roles []types.ContentRole, previousHooks []string, styleMemo string, opts ...CallOptionFunc) (types.Carousel, error) {
 
 	var callOpts callOption
 	for _, opt := range opts {
@@ -185,7 +333,81 @@ func GenerateContent(ctx context.Context, profile types.BusinessProfile, roles [
 	}
 
 	args := baml.BamlFunctionArguments{
-		Kwargs: map[string]any{"profile": profile, "roles": roles, "previousHooks": previousHooks},
+		Kwargs: map[string]any{"profile": profile, "roles": roles, "previousHooks": previousHooks, "styleMemo": styleMemo},
+		Env:    getEnvVars(callOpts.env),
+	}
+
+	if callOpts.clientRegistry != nil {
+		args.ClientRegistry = callOpts.clientRegistry
+	}
+
+	if callOpts.collectors != nil {
+		args.Collectors = callOpts.collectors
+	}
+
+	if callOpts.typeBuilder != nil {
+		args.TypeBuilder = callOpts.typeBuilder
+	}
+
+	if callOpts.tags != nil {
+		args.Tags = callOpts.tags
+	}
+
+	encoded, err := args.Encode()
+	if err != nil {
+		panic(err)
+	}
+
+	if callOpts.onTick == nil {
+		result, err := bamlRuntime.CallFunction(ctx, "GenerateCarousel", encoded, callOpts.onTick)
+		if err != nil {
+			return types.Carousel{}, err
+		}
+
+		if result.Error != nil {
+			return types.Carousel{}, result.Error
+		}
+
+		casted := (result.Data).(types.Carousel)
+
+		return casted, nil
+	} else {
+		channel, err := bamlRuntime.CallFunctionStream(ctx, "GenerateCarousel", encoded, callOpts.onTick)
+		if err != nil {
+			return types.Carousel{}, err
+		}
+
+		for result := range channel {
+			if result.Error != nil {
+				return types.Carousel{}, result.Error
+			}
+
+			if result.HasData {
+				return result.Data.(types.Carousel), nil
+			}
+		}
+
+		return types.Carousel{}, fmt.Errorf("No data returned from stream")
+	}
+}
+
+func GenerateContent(ctx context.Context, profile types.BusinessProfile, roles []types.ContentRole, previousHooks []string, styleMemo string, opts ...CallOptionFunc) (types.Post, error) {
+
+	var callOpts callOption
+	for _, opt := range opts {
+		opt(&callOpts)
+	}
+
+	// Resolve client option to clientRegistry (client takes precedence)
+	if callOpts.client != nil {
+		if callOpts.clientRegistry == nil {
+			callOpts.clientRegistry = baml.NewClientRegistry()
+		}
+		callOpts.clientRegistry.SetPrimaryClient(*callOpts.client)
+	}
+
+	args := baml.BamlFunctionArguments{
+		Kwargs: map[string]any{"profile": profile, "roles": roles, "previousHooks": previousHooks, "styleMemo": styleMemo},
 		Env:    getEnvVars(callOpts.env),
 	}
 
@@ -243,7 +465,7 @@ func GenerateContent(ctx context.Context, profile types.BusinessProfile, roles [
 	}
 }
 
-func GenerateFromMessage(ctx context.Context, profile types.BusinessProfile, clientMessage string, previousHooks []string, opts ...CallOptionFunc) (types.Post, error) {
+func GenerateFromMessage(ctx context.Context, profile types.BusinessProfile, clientMessage string, previousHooks []string, styleMemo string, opts ...CallOptionFunc) (types.Post, error) {
 
 	var callOpts callOption
 	for _, opt := range opts {
@@ -259,7 +481,7 @@ func GenerateFromMessage(ctx context.Context, profile types.BusinessProfile, cli
 	}
 
 	args := baml.BamlFunctionArguments{
-		Kwargs: map[string]any{"profile": profile, "clientMessage": clientMessage, "previousHooks": previousHooks},
+		Kwargs: map[string]any{"profile": profile, "clientMessage": clientMessage, "previousHooks": previousHooks, "styleMemo": styleMemo},
 		Env:    getEnvVars(callOpts.env),
 	}
 
@@ -317,6 +539,80 @@ func GenerateFromMessage(ctx context.Context, profile types.BusinessProfile, cli
 	}
 }
 
+func GenerateReelScript(ctx context.Context, profile types.BusinessProfile, roles []types.ContentRole, previousHooks []string, styleMemo string, opts ...CallOptionFunc) (types.ReelScript, error) {
+
+	var callOpts callOption
+	for _, opt := range opts {
+		opt(&callOpts)
+	}
+
+	// Resolve client option to clientRegistry (client takes precedence)
+	if callOpts.client != nil {
+		if callOpts.clientRegistry == nil {
+			callOpts.clientRegistry = baml.NewClientRegistry()
+		}
+		callOpts.clientRegistry.SetPrimaryClient(*callOpts.client)
+	}
+
+	args := baml.BamlFunctionArguments{
+		Kwargs: map[string]any{"profile": profile, "roles": roles, "previousHooks": previousHooks, "styleMemo": styleMemo},
+		Env:    getEnvVars(callOpts.env),
+	}
+
+	if callOpts.clientRegistry != nil {
+		args.ClientRegistry = callOpts.clientRegistry
+	}
+
+	if callOpts.collectors != nil {
+		args.Collectors = callOpts.collectors
+	}
+
+	if callOpts.typeBuilder != nil {
+		args.TypeBuilder = callOpts.typeBuilder
+	}
+
+	if callOpts.tags != nil {
+		args.Tags = callOpts.tags
+	}
+
+	encoded, err := args.Encode()
+	if err != nil {
+		panic(err)
+	}
+
+	if callOpts.onTick == nil {
+		result, err := bamlRuntime.CallFunction(ctx, "GenerateReelScript", encoded, callOpts.onTick)
+		if err != nil {
+			return types.ReelScript{}, err
+		}
+
+		if result.Error != nil {
+			return types.ReelScript{}, result.Error
+		}
+
+		casted := (result.Data).(types.ReelScript)
+
+		return casted, nil
+	} else {
+		channel, err := bamlRuntime.CallFunctionStream(ctx, "GenerateReelScript", encoded, callOpts.onTick)
+		if err != nil {
+			return types.ReelScript{}, err
+		}
+
+		for result := range channel {
+			if result.Error != nil {
+				return types.ReelScript{}, result.Error
+			}
+
+			if result.HasData {
+				return result.Data.(types.ReelScript), nil
+			}
+		}
+
+		return types.ReelScript{}, fmt.Errorf("No data returned from stream")
+	}
+}
+
 func GenerateRekanContent(ctx context.Context, profile types.BusinessProfile, roles []types.ContentRole, previousHooks []string, opts ...CallOptionFunc) (types.Post, error) {
 
 	var callOpts callOption
@@ -391,6 +687,80 @@ func GenerateRekanContent(ctx context.Context, profile types.BusinessProfile, ro
 	}
 }
 
+func GenerateStorySequence(ctx context.Context, profile types.BusinessProfile, roles []types.ContentRole, previousHooks []string, styleMemo string, opts ...CallOptionFunc) (types.StorySequence, error) {
+
+	var callOpts callOption
+	for _, opt := range opts {
+		opt(&callOpts)
+	}
+
+	// Resolve client option to clientRegistry (client takes precedence)
+	if callOpts.client != nil {
+		if callOpts.clientRegistry == nil {
+			callOpts.clientRegistry = baml.NewClientRegistry()
+		}
+		callOpts.clientRegistry.SetPrimaryClient(*callOpts.client)
+	}
+
+	args := baml.BamlFunctionArguments{
+		Kwargs: map[string]any{"profile": profile, "roles": roles, "previousHooks": previousHooks, "styleMemo": styleMemo},
+		Env:    getEnvVars(callOpts.env),
+	}
+
+	if callOpts.clientRegistry != nil {
+		args.ClientRegistry = callOpts.clientRegistry
+	}
+
+	if callOpts.collectors != nil {
+		args.Collectors = callOpts.collectors
+	}
+
+	if callOpts.typeBuilder != nil {
+		args.TypeBuilder = callOpts.typeBuilder
+	}
+
+	if callOpts.tags != nil {
+		args.Tags = callOpts.tags
+	}
+
+	encoded, err := args.Encode()
+	if err != nil {
+		panic(err)
+	}
+
+	if callOpts.onTick == nil {
+		result, err := bamlRuntime.CallFunction(ctx, "GenerateStorySequence", encoded, callOpts.onTick)
+		if err != nil {
+			return types.StorySequence{}, err
+		}
+
+		if result.Error != nil {
+			return types.StorySequence{}, result.Error
+		}
+
+		casted := (result.Data).(types.StorySequence)
+
+		return casted, nil
+	} else {
+		channel, err := bamlRuntime.CallFunctionStream(ctx, "GenerateStorySequence", encoded, callOpts.onTick)
+		if err != nil {
+			return types.StorySequence{}, err
+		}
+
+		for result := range channel {
+			if result.Error != nil {
+				return types.StorySequence{}, result.Error
+			}
+
+			if result.HasData {
+				return result.Data.(types.StorySequence), nil
+			}
+		}
+
+		return types.StorySequence{}, fmt.Errorf("No data returned from stream")
+	}
+}
+
 func JudgeAcionavel(ctx context.Context, profile types.BusinessProfile, content string, opts ...CallOptionFunc) (types.JudgeResult, error) {
 
 	var callOpts callOption
@@ -760,3 +1130,76 @@ func JudgeVariedade(ctx context.Context, profile types.BusinessProfile, content
 		return types.JudgeVariedadeResult{}, fmt.Errorf("No data returned from stream")
 	}
 }
+func RewritePost(ctx context.Context, profile types.BusinessProfile, post types.Post, instruction string, previousHooks []string, styleMemo string, opts ...CallOptionFunc) (types.Post, error) {
+
+	var callOpts callOption
+	for _, opt := range opts {
+		opt(&callOpts)
+	}
+
+	// Resolve client option to clientRegistry (client takes precedence)
+	if callOpts.client != nil {
+		if callOpts.clientRegistry == nil {
+			callOpts.clientRegistry = baml.NewClientRegistry()
+		}
+		callOpts.clientRegistry.SetPrimaryClient(*callOpts.client)
+	}
+
+	args := baml.BamlFunctionArguments{
+		Kwargs: map[string]any{"profile": profile, "post": post, "instruction": instruction, "previousHooks": previousHooks, "styleMemo": styleMemo},
+		Env:    getEnvVars(callOpts.env),
+	}
+
+	if callOpts.clientRegistry != nil {
+		args.ClientRegistry = callOpts.clientRegistry
+	}
+
+	if callOpts.collectors != nil {
+		args.Collectors = callOpts.collectors
+	}
+
+	if callOpts.typeBuilder != nil {
+		args.TypeBuilder = callOpts.typeBuilder
+	}
+
+	if callOpts.tags != nil {
+		args.Tags = callOpts.tags
+	}
+
+	encoded, err := args.Encode()
+	if err != nil {
+		panic(err)
+	}
+
+	if callOpts.onTick == nil {
+		result, err := bamlRuntime.CallFunction(ctx, "RewritePost", encoded, callOpts.onTick)
+		if err != nil {
+			return types.Post{}, err
+		}
+
+		if result.Error != nil {
+			return types.Post{}, result.Error
+		}
+
+		casted := (result.Data).(types.Post)
+
+		return casted, nil
+	} else {
+		channel, err := bamlRuntime.CallFunctionStream(ctx, "RewritePost", encoded, callOpts.onTick)
+		if err != nil {
+			return types.Post{}, err
+		}
+
+		for result := range channel {
+			if result.Error != nil {
+				return types.Post{}, result.Error
+			}
+
+			if result.HasData {
+				return result.Data.(types.Post), nil
+			}
+		}
+
+		return types.Post{}, fmt.Errorf("No data returned from stream")
+	}
+}
@@ -25,6 +25,98 @@ type build_request struct{}
 
 var Request = &build_request{}
 
+// Build HTTP request for ClassifyClientReply (returns baml.HTTPRequest)
+func (*build_request) ClassifyClientReply(caption string, reply string, opts ...CallOptionFunc) (baml.HTTPRequest, error) {
+
+	var callOpts callOption
+	for _, opt := range opts {
+		opt(&callOpts)
+	}
+
+	// Resolve client option to clientRegistry (client takes precedence)
+	if callOpts.client != nil {
+		if callOpts.clientRegistry == nil {
+			callOpts.clientRegistry = baml.NewClientRegistry()
+		}
+		callOpts.clientRegistry.SetPrimaryClient(*callOpts.client)
+	}
+
+	args := baml.BamlFunctionArguments{
+		Kwargs: map[string]any{"caption": caption, "reply": reply, "stream": false},
+		Env:    getEnvVars(callOpts.env),
+	}
+
+	if callOpts.clientRegistry != nil {
+		args.ClientRegistry = callOpts.clientRegistry
+	}
+
+	if callOpts.collectors != nil {
+		args.Collectors = callOpts.collectors
+	}
+
+	if callOpts.typeBuilder != nil {
+		args.TypeBuilder = callOpts.typeBuilder
+	}
+
+	if callOpts.tags != nil {
+		args.Tags = callOpts.tags
+	}
+
+	encoded, err := args.Encode()
+	if err != nil {
+		wrapped_err := fmt.Errorf("BAML INTERNAL ERROR: ClassifyClientReply: %w", err)
+		panic(wrapped_err)
+	}
+
+	return bamlRuntime.BuildRequest(context.Background(), "ClassifyClientReply", encoded)
+}
+
+// Build HTTP request for DistillStyleMemo (returns baml.HTTPRequest)
+func (*build_request) DistillStyleMemo(businessName string, rejections []types.RejectedCaption, edits []types.EditedCaption, opts ...CallOptionFunc) (baml.HTTPRequest, error) {
+
+	var callOpts callOption
+	for _, opt := range opts {
+		opt(&callOpts)
+	}
+
+	// Resolve client option to clientRegistry (client takes precedence)
+	if callOpts.client != nil {
+		if callOpts.clientRegistry == nil {
+			callOpts.clientRegistry = baml.NewClientRegistry()
+		}
+		callOpts.clientRegistry.SetPrimaryClient(*callOpts.client)
+	}
+
+	args := baml.BamlFunctionArguments{
+		Kwargs: map[string]any{"businessName": businessName, "rejections": rejections, "edits": edits, "stream": false},
+		Env:    getEnvVars(callOpts.env),
+	}
+
+	if callOpts.clientRegistry != nil {
+		args.ClientRegistry = callOpts.clientRegistry
+	}
+
+	if callOpts.collectors != nil {
+		args.Collectors = callOpts.collectors
+	}
+
+	if callOpts.typeBuilder != nil {
+		args.TypeBuilder = callOpts.typeBuilder
+	}
+
+	if callOpts.tags != nil {
+		args.Tags = callOpts.tags
+	}
+
+	encoded, err := args.Encode()
+	if err != nil {
+		wrapped_err := fmt.Errorf("BAML INTERNAL ERROR: DistillStyleMemo: %w", err)
+		panic(wrapped_err)
+	}
+
+	return bamlRuntime.BuildRequest(context.Background(), "DistillStyleMemo", encoded)
+}
+
 // Build HTTP request for ExtractBusinessProfile (returns baml.HTTPRequest)
 func (*build_request) ExtractBusinessProfile(transcript string, businessType string, opts ...CallOptionFunc) (baml.HTTPRequest, error) {
 
@@ -117,8 +209,54 @@ func (*build_request) ExtractProfileSignal(message string, businessType string,
 	return bamlRuntime.BuildRequest(context.Background(), "ExtractProfileSignal", encoded)
 }
 
+// Build HTTP request for GenerateCarousel (returns baml.HTTPRequest)
+func (*build_request) GenerateCarousel(profile types.BusinessProfile, roles []types.ContentRole, previousHooks []string, styleMemo string, opts ...CallOptionFunc) (baml.HTTPRequest, error) {
+
+	var callOpts callOption
+	for _, opt := range opts {
+		opt(&callOpts)
+	}
+
+	// Resolve client option to clientRegistry (client takes precedence)
+	if callOpts.client != nil {
+		if callOpts.clientRegistry == nil {
+			callOpts.clientRegistry = baml.NewClientRegistry()
+		}
+		callOpts.clientRegistry.SetPrimaryClient(*callOpts.client)
+	}
+
+	args := baml.BamlFunctionArguments{
+		Kwargs: map[string]any{"profile": profile, "roles": roles, "previousHooks": previousHooks, "styleMemo": styleMemo, "stream": false},
+		Env:    getEnvVars(callOpts.env),
+	}
+
+	if callOpts.clientRegistry != nil {
+		args.ClientRegistry = callOpts.clientRegistry
+	}
+
+	if callOpts.collectors != nil {
+		args.Collectors = callOpts.collectors
+	}
+
+	if callOpts.typeBuilder != nil {
+		args.TypeBuilder = callOpts.typeBuilder
+	}
+
+	if callOpts.tags != nil {
+		args.Tags = callOpts.tags
+	}
+
+	encoded, err := args.Encode()
+	if err != nil {
+		wrapped_err := fmt.Errorf("BAML INTERNAL ERROR: GenerateCarousel: %w", err)
+		panic(wrapped_err)
+	}
+
+	return bamlRuntime.BuildRequest(context.Background(), "GenerateCarousel", encoded)
+}
+
 // Build HTTP request for GenerateContent (returns baml.HTTPRequest)
-func (*build_request) GenerateContent(profile types.BusinessProfile, roles []types.ContentRole, previousHooks []string, opts ...CallOptionFunc) (baml.HTTPRequest, error) {
+func (*build_request) GenerateContent(profile types.BusinessProfile, roles []types.ContentRole, previousHooks []string, styleMemo string, opts ...CallOptionFunc) (baml.HTTPRequest, error) {
 
 	var callOpts callOption
 	for _, opt := range opts {
@@ -134,7 +272,7 @@ func (*build_request) GenerateContent(profile types.BusinessProfile, roles []typ
 	}
 
 	args := baml.BamlFunctionArguments{
-		Kwargs: map[string]any{"profile": profile, "roles": roles, "previousHooks": previousHooks, "stream": false},
+		Kwargs: map[string]any{"profile": profile, "roles": roles, "previousHooks": previousHooks, "styleMemo": styleMemo, "stream": false},
 		Env:    getEnvVars(callOpts.env),
 	}
 
@@ -164,7 +302,7 @@ func (*build_request) GenerateContent(profile types.BusinessProfile, roles []typ
 }
 
 // Build HTTP request for GenerateFromMessage (returns baml.HTTPRequest)
-func (*build_request) GenerateFromMessage(profile types.BusinessProfile, clientMessage string, previousHooks []string, opts ...CallOptionFunc) (baml.HTTPRequest, error) {
+func (*build_request) GenerateFromMessage(profile types.BusinessProfile, clientMessage string, previousHooks []string, styleMemo string, opts ...CallOptionFunc) (baml.HTTPRequest, error) {
 
 	var callOpts callOption
 	for _, opt := range opts {
@@ -180,7 +318,7 @@ func (*build_request) GenerateFromMessage(profile types.BusinessProfile, clientM
 	}
 
 	args := baml.BamlFunctionArguments{
-		Kwargs: map[string]any{"profile": profile, "clientMessage": clientMessage, "previousHooks": previousHooks, "stream": false},
+		Kwargs: map[string]any{"profile": profile, "clientMessage": clientMessage, "previousHooks": previousHooks, "styleMemo": styleMemo, "stream": false},
 		Env:    getEnvVars(callOpts.env),
 	}
 
@@ -209,6 +347,52 @@ func (*build_request) GenerateFromMessage(profile types.BusinessProfile, clientM
 	return bamlRuntime.BuildRequest(context.Background(), "GenerateFromMessage", encoded)
 }
 
+// Build HTTP request for GenerateReelScript (returns baml.HTTPRequest)
+func (*build_request) GenerateReelScript(profile types.BusinessProfile, roles []types.ContentRole, previousHooks []string, styleMemo string, opts ...CallOptionFunc) (baml.HTTPRequest, error) {
+
+	var callOpts callOption
+	for _, opt := range opts {
+		opt(&callOpts)
+	}
+
+	// Resolve client option to clientRegistry (client takes precedence)
+	if callOpts.client != nil {
+		if callOpts.clientRegistry == nil {
+			callOpts.clientRegistry = baml.NewClientRegistry()
+		}
+		callOpts.clientRegistry.SetPrimaryClient(*callOpts.client)
+	}
+
+	args := baml.BamlFunctionArguments{
+		Kwargs: map[string]any{"profile": profile, "roles": roles, "previousHooks": previousHooks, "styleMemo": styleMemo, "stream": false},
+		Env:    getEnvVars(callOpts.env),
+	}
+
+	if callOpts.clientRegistry != nil {
+		args.ClientRegistry = callOpts.clientRegistry
+	}
+
+	if callOpts.collectors != nil {
+		args.Collectors = callOpts.collectors
+	}
+
+	if callOpts.typeBuilder != nil {
+		args.TypeBuilder = callOpts.typeBuilder
+	}
+
+	if callOpts.tags != nil {
+		args.Tags = callOpts.tags
+	}
+
+	encoded, err := args.Encode()
+	if err != nil {
+		wrapped_err := fmt.Errorf("BAML INTERNAL ERROR: GenerateReelScript: %w", err)
+		panic(wrapped_err)
+	}
+
+	return bamlRuntime.BuildRequest(context.Background(), "GenerateReelScript", encoded)
+}
+
 // Build HTTP request for GenerateRekanContent (returns baml.HTTPRequest)
 func (*build_request) GenerateRekanContent(profile types.BusinessProfile, roles []types.ContentRole, previousHooks []string, opts ...CallOptionFunc) (baml.HTTPRequest, error) {
 
@@ -255,6 +439,52 @@ func (*build_request) GenerateRekanContent(profile types.BusinessProfile, roles
 	return bamlRuntime.BuildRequest(context.Background(), "GenerateRekanContent", encoded)
 }
 
+// Build HTTP request for GenerateStorySequence (returns baml.HTTPRequest)
+func (*build_request) GenerateStorySequence(profile types.BusinessProfile, roles []types.ContentRole, previousHooks []string, styleMemo string, opts ...CallOptionFunc) (baml.HTTPRequest, error) {
+
+	var callOpts callOption
+	for _, opt := range opts {
+		opt(&callOpts)
+	}
+
+	// Resolve client option to clientRegistry (client takes precedence)
+	if callOpts.client != nil {
+		if callOpts.clientRegistry == nil {
+			callOpts.clientRegistry = baml.NewClientRegistry()
+		}
+		callOpts.clientRegistry.SetPrimaryClient(*callOpts.client)
+	}
+
+	args := baml.BamlFunctionArguments{
+		Kwargs: map[string]any{"profile": profile, "roles": roles, "previousHooks": previousHooks, "styleMemo": styleMemo, "stream": false},
+		Env:    getEnvVars(callOpts.env),
+	}
+
+	if callOpts.clientRegistry != nil {
+		args.ClientRegistry = callOpts.clientRegistry
+	}
+
+	if callOpts.collectors != nil {
+		args.Collectors = callOpts.collectors
+	}
+
+	if callOpts.typeBuilder != nil {
+		args.TypeBuilder = callOpts.typeBuilder
+	}
+
+	if callOpts.tags != nil {
+		args.Tags = callOpts.tags
+	}
+
+	encoded, err := args.Encode()
+	if err != nil {
+		wrapped_err := fmt.Errorf("BAML INTERNAL ERROR: GenerateStorySequence: %w", err)
+		panic(wrapped_err)
+	}
+
+	return bamlRuntime.BuildRequest(context.Background(), "GenerateStorySequence", encoded)
+}
+
 // Build HTTP request for JudgeAcionavel (returns baml.HTTPRequest)
 func (*build_request) JudgeAcionavel(profile types.BusinessProfile, content string, opts ...CallOptionFunc) (baml.HTTPRequest, error) {
 
@@ -484,3 +714,49 @@ func (*build_request) JudgeVariedade(profile types.BusinessProfile, content stri
 
 	return bamlRuntime.BuildRequest(context.Background(), "JudgeVariedade", encoded)
 }
+
+// Build HTTP request for RewritePost (returns baml.HTTPRequest)
+func (*build_request) RewritePost(profile types.BusinessProfile, post types.Post, instruction string, previousHooks []string, styleMemo string, opts ...CallOptionFunc) (baml.HTTPRequest, error) {
+
+	var callOpts callOption
+	for _, opt := range opts {
+		opt(&callOpts)
+	}
+
+	// Resolve client option to clientRegistry (client takes precedence)
+	if callOpts.client != nil {
+		if callOpts.clientRegistry == nil {
+			callOpts.clientRegistry = baml.NewClientRegistry()
+		}
+		callOpts.clientRegistry.SetPrimaryClient(*callOpts.client)
+	}
+
+	args := baml.BamlFunctionArguments{
+		Kwargs: map[string]any{"profile": profile, "post": post, "instruction": instruction, "previousHooks": previousHooks, "styleMemo": styleMemo, "stream": false},
+		Env:    getEnvVars(callOpts.env),
+	}
+
+	if callOpts.clientRegistry != nil {
+		args.ClientRegistry = callOpts.clientRegistry
+	}
+
+	if callOpts.collectors != nil {
+		args.Collectors = callOpts.collectors
+	}
+
+	if callOpts.typeBuilder != nil {
+		args.TypeBuilder = callOpts.typeBuilder
+	}
+
+	if callOpts.tags != nil {
+		args.Tags = callOpts.tags
+	}
+
+	encoded, err := args.Encode()
+	if err != nil {
+		wrapped_err := fmt.Errorf("BAML INTERNAL ERROR: RewritePost: %w", err)
+		panic(wrapped_err)
+	}
+
+	return bamlRuntime.BuildRequest(context.Background(), "RewritePost", encoded)
+}
@@ -25,6 +25,100 @@ type parse struct{}
 
 var Parse = &parse{}
 
+// / Parse version of ClassifyClientReply (Takes in string and returns types.ClientReply)
+func (*parse) ClassifyClientReply(text string, opts ...CallOptionFunc) (types.ClientReply, error) {
+
+	var callOpts callOption
+	for _, opt := range opts {
+		opt(&callOpts)
+	}
+
+	args := baml.BamlFunctionArguments{
+		Kwargs: map[string]any{"text": text, "stream": false},
+		Env:    getEnvVars(callOpts.env),
+	}
+
+	if callOpts.clientRegistry != nil {
+		args.ClientRegistry = callOpts.clientRegistry
+	}
+
+	if callOpts.collectors != nil {
+		args.Collectors = callOpts.collectors
+	}
+
+	if callOpts.typeBuilder != nil {
+		args.TypeBuilder = callOpts.typeBuilder
+	}
+
+	if callOpts.tags != nil {
+		args.Tags = callOpts.tags
+	}
+
+	encoded, err := args.Encode()
+	if err != nil {
+		// This should never happen. if it does, please file an issue at https://github.com/boundaryml/baml/issues
+		// and include the type of the args you're passing in.
+		wrapped_err := fmt.Errorf("BAML INTERNAL ERROR: ClassifyClientReply: %w", err)
+		panic(wrapped_err)
+	}
+
+	result, err := bamlRuntime.CallFunctionParse(context.Background(), "ClassifyClientReply", encoded)
+	if err != nil {
+		return types.ClientReply{}, err
+	}
+
+	casted := (result).(types.ClientReply)
+
+	return casted, nil
+}
+
+// / Parse version of DistillStyleMemo (Takes in string and returns types.StyleMemo)
+func (*parse) DistillStyleMemo(text string, opts ...CallOptionFunc) (types.StyleMemo, error) {
+
+	var callOpts callOption
+	for _, opt := range opts {
+		opt(&callOpts)
+	}
+
+	args := baml.BamlFunctionArguments{
+		Kwargs: map[string]any{"text": text, "stream": false},
+		Env:    getEnvVars(callOpts.env),
+	}
+
+	if callOpts.clientRegistry != nil {
+		args.ClientRegistry = callOpts.clientRegistry
+	}
+
+	if callOpts.collectors != nil {
+		args.Collectors = callOpts.collectors
+	}
+
+	if callOpts.typeBuilder != nil {
+		args.TypeBuilder = callOpts.typeBuilder
+	}
+
+	if callOpts.tags != nil {
+		args.Tags = callOpts.tags
+	}
+
+	encoded, err := args.Encode()
+	if err != nil {
+		// This should never happen. if it does, please file an issue at https://github.com/boundaryml/baml/issues
+		// and include the type of the args you're passing in.
+		wrapped_err := fmt.Errorf("BAML INTERNAL ERROR: DistillStyleMemo: %w", err)
+		panic(wrapped_err)
+	}
+
+	result, err := bamlRuntime.CallFunctionParse(context.Background(), "DistillStyleMemo", encoded)
+	if err != nil {
+		return types.StyleMemo{}, err
+	}
+
+	casted := (result).(types.StyleMemo)
+
+	return casted, nil
+}
+
 // / Parse version of ExtractBusinessProfile (Takes in string and returns types.PartialBusinessProfile)
 func (*parse) ExtractBusinessProfile(text string, opts ...CallOptionFunc) (types.PartialBusinessProfile, error) {
 
@@ -119,6 +213,53 @@ func (*parse) ExtractProfileSignal(text string, opts ...CallOptionFunc) (*types.
 	return casted, nil
 }
 
+// / Parse version of GenerateCarousel (Takes in string and returns types.Carousel)
+func (*parse) GenerateCarousel(text string, opts ...CallOptionFunc) (types.Carousel, error) {
+
+	var callOpts callOption
+	for _, opt := range opts {
+		opt(&callOpts)
+	}
+
+	args := baml.BamlFunctionArguments{
+		Kwargs: map[string]any{"text": text, "stream": false},
+		Env:    getEnvVars(callOpts.env),
+	}
+
+	if callOpts.clientRegistry != nil {
+		args.ClientRegistry = callOpts.clientRegistry
+	}
+
+	if callOpts.collectors != nil {
+		args.Collectors = callOpts.collectors
+	}
+
+	if callOpts.typeBuilder != nil {
+		args.TypeBuilder = callOpts.typeBuilder
+	}
+
+	if callOpts.tags != nil {
+		args.Tags = callOpts.tags
+	}
+
+	encoded, err := args.Encode()
+	if err != nil {
+		// This should never happen. if it does, please file an issue at https://github.com/boundaryml/baml/issues
+		// and include the type of the args you're passing in.
+		wrapped_err := fmt.Errorf("BAML INTERNAL ERROR: GenerateCarousel: %w", err)
+		panic(wrapped_err)
+	}
+
+	result, err := bamlRuntime.CallFunctionParse(context.Background(), "GenerateCarousel", encoded)
+	if err != nil {
+		return types.Carousel{}, err
+	}
+
+	casted := (result).(types.Carousel)
+
+	return casted, nil
+}
+
 // / Parse version of GenerateContent (Takes in string and returns types.Post)
 func (*parse) GenerateContent(text string, opts ...CallOptionFunc) (types.Post, error) {
 
@@ -213,6 +354,53 @@ func (*parse) GenerateFromMessage(text string, opts ...CallOptionFunc) (types.Po
 	return casted, nil
 }
 
+// / Parse version of GenerateReelScript (Takes in string and returns types.ReelScript)
+func (*parse) GenerateReelScript(text string, opts ...CallOptionFunc) (types.ReelScript, error) {
+
+	var callOpts callOption
+	for _, opt := range opts {
+		opt(&callOpts)
+	}
+
+	args := baml.BamlFunctionArguments{
+		Kwargs: map[string]any{"text": text, "stream": false},
+		Env:    getEnvVars(callOpts.env),
+	}
+
+	if callOpts.clientRegistry != nil {
+		args.ClientRegistry = callOpts.clientRegistry
+	}
+
+	if callOpts.collectors != nil {
+		args.Collectors = callOpts.collectors
+	}
+
+	if callOpts.typeBuilder != nil {
+		args.TypeBuilder = callOpts.typeBuilder
+	}
+
+	if callOpts.tags != nil {
+		args.Tags = callOpts.tags
+	}
+
+	encoded, err := args.Encode()
+	if err != nil {
+		// This should never happen. if it does, please file an issue at https://github.com/boundaryml/baml/issues
+		// and include the type of the args you're passing in.
+		wrapped_err := fmt.Errorf("BAML INTERNAL ERROR: GenerateReelScript: %w", err)
+		panic(wrapped_err)
+	}
+
+	result, err := bamlRuntime.CallFunctionParse(context.Background(), "GenerateReelScript", encoded)
+	if err != nil {
+		return types.ReelScript{}, err
+	}
+
+	casted := (result).(types.ReelScript)
+
+	return casted, nil
+}
+
 // / Parse version of GenerateRekanContent (Takes in string and returns types.Post)
 func (*parse) GenerateRekanContent(text string, opts ...CallOptionFunc) (types.Post, error) {
 
@@ -260,6 +448,53 @@ func (*parse) GenerateRekanContent(text string, opts ...CallOptionFunc) (types.P
 	return casted, nil
 }
 
+// / Parse version of GenerateStorySequence (Takes in string and returns types.StorySequence)
+func (*parse) GenerateStorySequence(text string, opts ...CallOptionFunc) (types.StorySequence, error) {
+
+	var callOpts callOption
+	for _, opt := range opts {
+		opt(&callOpts)
+	}
+
+	args := baml.BamlFunctionArguments{
+		Kwargs: map[string]any{"text": text, "stream": false},
+		Env:    getEnvVars(callOpts.env),
+	}
+
+	if callOpts.clientRegistry != nil {
+		args.ClientRegistry = callOpts.clientRegistry
+	}
+
+	if callOpts.collectors != nil {
+		args.Collectors = callOpts.collectors
+	}
+
+	if callOpts.typeBuilder != nil {
+		args.TypeBuilder = callOpts.typeBuilder
+	}
+
+	if callOpts.tags != nil {
+		args.Tags = callOpts.tags
+	}
+
+	encoded, err := args.Encode()
+	if err != nil {
+		// This should never happen. if it does, please file an issue at https://github.com/boundaryml/baml/issues
+		// and include the type of the args you're passing in.
+		wrapped_err := fmt.Errorf("BAML INTERNAL ERROR: GenerateStorySequence: %w", err)
+		panic(wrapped_err)
+	}
+
+	result, err := bamlRuntime.CallFunctionParse(context.Background(), "GenerateStorySequence", encoded)
+	if err != nil {
+		return types.StorySequence{}, err
+	}
+
+	casted := (result).(types.StorySequence)
+
+	return casted, nil
+}
+
 // / Parse version of JudgeAcionavel (Takes in string and returns types.JudgeResult)
 func (*parse) JudgeAcionavel(text string, opts ...CallOptionFunc) (types.JudgeResult, error) {
 
@@ -494,3 +729,50 @@ func (*parse) JudgeVariedade(text string, opts ...CallOptionFunc) (types.JudgeVa
 
 	return casted, nil
 }
+
+// / Parse version of RewritePost (Takes in string and returns types.Post)
+func (*parse) RewritePost(text string, opts ...CallOptionFunc) (types.Post, error) {
+
+	var callOpts callOption
+	for _, opt := range opts {
+		opt(&callOpts)
+	}
+
+	args := baml.BamlFunctionArguments{
+		Kwargs: map[string]any{"text": text, "stream": false},
+		Env:    getEnvVars(callOpts.env),
+	}
+
+	if callOpts.clientRegistry != nil {
+		args.ClientRegistry = callOpts.clientRegistry
+	}
+
+	if callOpts.collectors != nil {
+		args.Collectors = callOpts.collectors
+	}
+
+	if callOpts.typeBuilder != nil {
+		args.TypeBuilder = callOpts.typeBuilder
+	}
+
+	if callOpts.tags != nil {
+		args.Tags = callOpts.tags
+	}
+
+	encoded, err := args.Encode()
+	if err != nil {
+		// This should never happen. if it does, please file an issue at https://github.com/boundaryml/baml/issues
+		// and include the type of the args you're passing in.
+		wrapped_err := fmt.Errorf("BAML INTERNAL ERROR: RewritePost: %w", err)
+		panic(wrapped_err)
+	}
+
+	result, err := bamlRuntime.CallFunctionParse(context.Background(), "RewritePost", encoded)
+	if err != nil {
+		return types.Post{}, err
+	}
+
+	casted := (result).(types.Post)
+
+	return casted, nil
+}
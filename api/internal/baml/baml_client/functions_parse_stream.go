@@ -25,6 +25,100 @@ type parse_stream struct{}
 
 var ParseStream = &parse_stream{}
 
+// / Parse version of ClassifyClientReply (Takes in string and returns stream_types.ClientReply)
+func (*parse_stream) ClassifyClientReply(text string, opts ...CallOptionFunc) (stream_types.ClientReply, error) {
+
+	var callOpts callOption
+	for _, opt := range opts {
+		opt(&callOpts)
+	}
+
+	args := baml.BamlFunctionArguments{
+		Kwargs: map[string]any{"text": text, "stream": true},
+		Env:    getEnvVars(callOpts.env),
+	}
+
+	if callOpts.clientRegistry != nil {
+		args.ClientRegistry = callOpts.clientRegistry
+	}
+
+	if callOpts.collectors != nil {
+		args.Collectors = callOpts.collectors
+	}
+
+	if callOpts.typeBuilder != nil {
+		args.TypeBuilder = callOpts.typeBuilder
+	}
+
+	if callOpts.tags != nil {
+		args.Tags = callOpts.tags
+	}
+
+	encoded, err := args.Encode()
+	if err != nil {
+		// This should never happen. if it does, please file an issue at https://github.com/boundaryml/baml/issues
+		// and include the type of the args you're passing in.
+		wrapped_err := fmt.Errorf("BAML INTERNAL ERROR: ClassifyClientReply: %w", err)
+		panic(wrapped_err)
+	}
+
+	result, err := bamlRuntime.CallFunctionParse(context.Background(), "ClassifyClientReply", encoded)
+	if err != nil {
+		return stream_types.ClientReply{}, err
+	}
+
+	casted := (result).(stream_types.ClientReply)
+
+	return casted, nil
+}
+
+// / Parse version of DistillStyleMemo (Takes in string and returns stream_types.StyleMemo)
+func (*parse_stream) DistillStyleMemo(text string, opts ...CallOptionFunc) (stream_types.StyleMemo, error) {
+
+	var callOpts callOption
+	for _, opt := range opts {
+		opt(&callOpts)
+	}
+
+	args := baml.BamlFunctionArguments{
+		Kwargs: map[string]any{"text": text, "stream": true},
+		Env:    getEnvVars(callOpts.env),
+	}
+
+	if callOpts.clientRegistry != nil {
+		args.ClientRegistry = callOpts.clientRegistry
+	}
+
+	if callOpts.collectors != nil {
+		args.Collectors = callOpts.collectors
+	}
+
+	if callOpts.typeBuilder != nil {
+		args.TypeBuilder = callOpts.typeBuilder
+	}
+
+	if callOpts.tags != nil {
+		args.Tags = callOpts.tags
+	}
+
+	encoded, err := args.Encode()
+	if err != nil {
+		// This should never happen. if it does, please file an issue at https://github.com/boundaryml/baml/issues
+		// and include the type of the args you're passing in.
+		wrapped_err := fmt.Errorf("BAML INTERNAL ERROR: DistillStyleMemo: %w", err)
+		panic(wrapped_err)
+	}
+
+	result, err := bamlRuntime.CallFunctionParse(context.Background(), "DistillStyleMemo", encoded)
+	if err != nil {
+		return stream_types.StyleMemo{}, err
+	}
+
+	casted := (result).(stream_types.StyleMemo)
+
+	return casted, nil
+}
+
 // / Parse version of ExtractBusinessProfile (Takes in string and returns stream_types.PartialBusinessProfile)
 func (*parse_stream) ExtractBusinessProfile(text string, opts ...CallOptionFunc) (stream_types.PartialBusinessProfile, error) {
 
@@ -119,6 +213,53 @@ func (*parse_stream) ExtractProfileSignal(text string, opts ...CallOptionFunc) (
 	return casted, nil
 }
 
+// / Parse version of GenerateCarousel (Takes in string and returns stream_types.Carousel)
+func (*parse_stream) GenerateCarousel(text string, opts ...CallOptionFunc) (stream_types.Carousel, error) {
+
+	var callOpts callOption
+	for _, opt := range opts {
+		opt(&callOpts)
+	}
+
+	args := baml.BamlFunctionArguments{
+		Kwargs: map[string]any{"text": text, "stream": true},
+		Env:    getEnvVars(callOpts.env),
+	}
+
+	if callOpts.clientRegistry != nil {
+		args.ClientRegistry = callOpts.clientRegistry
+	}
+
+	if callOpts.collectors != nil {
+		args.Collectors = callOpts.collectors
+	}
+
+	if callOpts.typeBuilder != nil {
+		args.TypeBuilder = callOpts.typeBuilder
+	}
+
+	if callOpts.tags != nil {
+		args.Tags = callOpts.tags
+	}
+
+	encoded, err := args.Encode()
+	if err != nil {
+		// This should never happen. if it does, please file an issue at https://github.com/boundaryml/baml/issues
+		// and include the type of the args you're passing in.
+		wrapped_err := fmt.Errorf("BAML INTERNAL ERROR: GenerateCarousel: %w", err)
+		panic(wrapped_err)
+	}
+
+	result, err := bamlRuntime.CallFunctionParse(context.Background(), "GenerateCarousel", encoded)
+	if err != nil {
+		return stream_types.Carousel{}, err
+	}
+
+	casted := (result).(stream_types.Carousel)
+
+	return casted, nil
+}
+
 // / Parse version of GenerateContent (Takes in string and returns stream_types.Post)
 func (*parse_stream) GenerateContent(text string, opts ...CallOptionFunc) (stream_types.Post, error) {
 
@@ -213,6 +354,53 @@ func (*parse_stream) GenerateFromMessage(text string, opts ...CallOptionFunc) (s
 	return casted, nil
 }
 
+// / Parse version of GenerateReelScript (Takes in string and returns stream_types.ReelScript)
+func (*parse_stream) GenerateReelScript(text string, opts ...CallOptionFunc) (stream_types.ReelScript, error) {
+
+	var callOpts callOption
+	for _, opt := range opts {
+		opt(&callOpts)
+	}
+
+	args := baml.BamlFunctionArguments{
+		Kwargs: map[string]any{"text": text, "stream": true},
+		Env:    getEnvVars(callOpts.env),
+	}
+
+	if callOpts.clientRegistry != nil {
+		args.ClientRegistry = callOpts.clientRegistry
+	}
+
+	if callOpts.collectors != nil {
+		args.Collectors = callOpts.collectors
+	}
+
+	if callOpts.typeBuilder != nil {
+		args.TypeBuilder = callOpts.typeBuilder
+	}
+
+	if callOpts.tags != nil {
+		args.Tags = callOpts.tags
+	}
+
+	encoded, err := args.Encode()
+	if err != nil {
+		// This should never happen. if it does, please file an issue at https://github.com/boundaryml/baml/issues
+		// and include the type of the args you're passing in.
+		wrapped_err := fmt.Errorf("BAML INTERNAL ERROR: GenerateReelScript: %w", err)
+		panic(wrapped_err)
+	}
+
+	result, err := bamlRuntime.CallFunctionParse(context.Background(), "GenerateReelScript", encoded)
+	if err != nil {
+		return stream_types.ReelScript{}, err
+	}
+
+	casted := (result).(stream_types.ReelScript)
+
+	return casted, nil
+}
+
 // / Parse version of GenerateRekanContent (Takes in string and returns stream_types.Post)
 func (*parse_stream) GenerateRekanContent(text string, opts ...CallOptionFunc) (stream_types.Post, error) {
 
@@ -260,6 +448,53 @@ func (*parse_stream) GenerateRekanContent(text string, opts ...CallOptionFunc) (
 	return casted, nil
 }
 
+// / Parse version of GenerateStorySequence (Takes in string and returns stream_types.StorySequence)
+func (*parse_stream) GenerateStorySequence(text string, opts ...CallOptionFunc) (stream_types.StorySequence, error) {
+
+	var callOpts callOption
+	for _, opt := range opts {
+		opt(&callOpts)
+	}
+
+	args := baml.BamlFunctionArguments{
+		Kwargs: map[string]any{"text": text, "stream": true},
+		Env:    getEnvVars(callOpts.env),
+	}
+
+	if callOpts.clientRegistry != nil {
+		args.ClientRegistry = callOpts.clientRegistry
+	}
+
+	if callOpts.collectors != nil {
+		args.Collectors = callOpts.collectors
+	}
+
+	if callOpts.typeBuilder != nil {
+		args.TypeBuilder = callOpts.typeBuilder
+	}
+
+	if callOpts.tags != nil {
+		args.Tags = callOpts.tags
+	}
+
+	encoded, err := args.Encode()
+	if err != nil {
+		// This should never happen. if it does, please file an issue at https://github.com/boundaryml/baml/issues
+		// and include the type of the args you're passing in.
+		wrapped_err := fmt.Errorf("BAML INTERNAL ERROR: GenerateStorySequence: %w", err)
+		panic(wrapped_err)
+	}
+
+	result, err := bamlRuntime.CallFunctionParse(context.Background(), "GenerateStorySequence", encoded)
+	if err != nil {
+		return stream_types.StorySequence{}, err
+	}
+
+	casted := (result).(stream_types.StorySequence)
+
+	return casted, nil
+}
+
 // / Parse version of JudgeAcionavel (Takes in string and returns stream_types.JudgeResult)
 func (*parse_stream) JudgeAcionavel(text string, opts ...CallOptionFunc) (stream_types.JudgeResult, error) {
 
@@ -494,3 +729,50 @@ func (*parse_stream) JudgeVariedade(text string, opts ...CallOptionFunc) (stream
 
 	return casted, nil
 }
+
+// / Parse version of RewritePost (Takes in string and returns stream_types.Post)
+func (*parse_stream) RewritePost(text string, opts ...CallOptionFunc) (stream_types.Post, error) {
+
+	var callOpts callOption
+	for _, opt := range opts {
+		opt(&callOpts)
+	}
+
+	args := baml.BamlFunctionArguments{
+		Kwargs: map[string]any{"text": text, "stream": true},
+		Env:    getEnvVars(callOpts.env),
+	}
+
+	if callOpts.clientRegistry != nil {
+		args.ClientRegistry = callOpts.clientRegistry
+	}
+
+	if callOpts.collectors != nil {
+		args.Collectors = callOpts.collectors
+	}
+
+	if callOpts.typeBuilder != nil {
+		args.TypeBuilder = callOpts.typeBuilder
+	}
+
+	if callOpts.tags != nil {
+		args.Tags = callOpts.tags
+	}
+
+	encoded, err := args.Encode()
+	if err != nil {
+		// This should never happen. if it does, please file an issue at https://github.com/boundaryml/baml/issues
+		// and include the type of the args you're passing in.
+		wrapped_err := fmt.Errorf("BAML INTERNAL ERROR: RewritePost: %w", err)
+		panic(wrapped_err)
+	}
+
+	result, err := bamlRuntime.CallFunctionParse(context.Background(), "RewritePost", encoded)
+	if err != nil {
+		return stream_types.Post{}, err
+	}
+
+	casted := (result).(stream_types.Post)
+
+	return casted, nil
+}
@@ -42,6 +42,154 @@ func (s *StreamValue[TStream, TFinal]) Stream() *TStream {
 	return s.as_stream
 }
 
+// / Streaming version of ClassifyClientReply
+func (*stream) ClassifyClientReply(ctx context.Context, caption string, reply string, opts ...CallOptionFunc) (<-chan StreamValue[stream_types.ClientReply, types.ClientReply], error) {
+
+	var callOpts callOption
+	for _, opt := range opts {
+		opt(&callOpts)
+	}
+
+	args := baml.BamlFunctionArguments{
+		Kwargs: map[string]any{"caption": caption, "reply": reply},
+		Env:    getEnvVars(callOpts.env),
+	}
+
+	if callOpts.clientRegistry != nil {
+		args.ClientRegistry = callOpts.clientRegistry
+	}
+
+	if callOpts.collectors != nil {
+		args.Collectors = callOpts.collectors
+	}
+
+	if callOpts.typeBuilder != nil {
+		args.TypeBuilder = callOpts.typeBuilder
+	}
+
+	if callOpts.tags != nil {
+		args.Tags = callOpts.tags
+	}
+
+	encoded, err := args.Encode()
+	if err != nil {
+		// This should never happen. if it does, please file an issue at https://github.com/boundaryml/baml/issues
+		// and include the type of the args you're passing in.
+		wrapped_err := fmt.Errorf("BAML INTERNAL ERROR: ClassifyClientReply: %w", err)
+		panic(wrapped_err)
+	}
+
+	internal_channel, err := bamlRuntime.CallFunctionStream(ctx, "ClassifyClientReply", encoded, callOpts.onTick)
+	if err != nil {
+		return nil, err
+	}
+
+	channel := make(chan StreamValue[stream_types.ClientReply, types.ClientReply])
+	go func() {
+		for result := range internal_channel {
+			if result.Error != nil {
+				channel <- StreamValue[stream_types.ClientReply, types.ClientReply]{
+					IsError: true,
+					Error:   result.Error,
+				}
+				close(channel)
+				return
+			}
+			if result.HasData {
+				data := (result.Data).(types.ClientReply)
+				channel <- StreamValue[stream_types.ClientReply, types.ClientReply]{
+					IsFinal:  true,
+					as_final: &data,
+				}
+			} else {
+				data := (result.StreamData).(stream_types.ClientReply)
+				channel <- StreamValue[stream_types.ClientReply, types.ClientReply]{
+					IsFinal:   false,
+					as_stream: &data,
+				}
+			}
+		}
+
+		// when internal_channel is closed, close the output too
+		close(channel)
+	}()
+	return channel, nil
+}
+
+// / Streaming version of DistillStyleMemo
+func (*stream) DistillStyleMemo(ctx context.Context, businessName string, rejections []types.RejectedCaption, edits []types.EditedCaption, opts ...CallOptionFunc) (<-chan StreamValue[stream_types.StyleMemo, types.StyleMemo], error) {
+
+	var callOpts callOption
+	for _, opt := range opts {
+		opt(&callOpts)
+	}
+
+	args := baml.BamlFunctionArguments{
+		Kwargs: map[string]any{"businessName": businessName, "rejections": rejections, "edits": edits},
+		Env:    getEnvVars(callOpts.env),
+	}
+
+	if callOpts.clientRegistry != nil {
+		args.ClientRegistry = callOpts.clientRegistry
+	}
+
+	if callOpts.collectors != nil {
+		args.Collectors = callOpts.collectors
+	}
+
+	if callOpts.typeBuilder != nil {
+		args.TypeBuilder = callOpts.typeBuilder
+	}
+
+	if callOpts.tags != nil {
+		args.Tags = callOpts.tags
+	}
+
+	encoded, err := args.Encode()
+	if err != nil {
+		// This should never happen. if it does, please file an issue at https://github.com/boundaryml/baml/issues
+		// and include the type of the args you're passing in.
+		wrapped_err := fmt.Errorf("BAML INTERNAL ERROR: DistillStyleMemo: %w", err)
+		panic(wrapped_err)
+	}
+
+	internal_channel, err := bamlRuntime.CallFunctionStream(ctx, "DistillStyleMemo", encoded, callOpts.onTick)
+	if err != nil {
+		return nil, err
+	}
+
+	channel := make(chan StreamValue[stream_types.StyleMemo, types.StyleMemo])
+	go func() {
+		for result := range internal_channel {
+			if result.Error != nil {
+				channel <- StreamValue[stream_types.StyleMemo, types.StyleMemo]{
+					IsError: true,
+					Error:   result.Error,
+				}
+				close(channel)
+				return
+			}
+			if result.HasData {
+				data := (result.Data).(types.StyleMemo)
+				channel <- StreamValue[stream_types.StyleMemo, types.StyleMemo]{
+					IsFinal:  true,
+					as_final: &data,
+				}
+			} else {
+				data := (result.StreamData).(stream_types.StyleMemo)
+				channel <- StreamValue[stream_types.StyleMemo, types.StyleMemo]{
+					IsFinal:   false,
+					as_stream: &data,
+				}
+			}
+		}
+
+		// when internal_channel is closed, close the output too
+		close(channel)
+	}()
+	return channel, nil
+}
+
 // / Streaming version of ExtractBusinessProfile
 func (*stream) ExtractBusinessProfile(ctx context.Context, transcript string, businessType string, opts ...CallOptionFunc) (<-chan StreamValue[stream_types.PartialBusinessProfile, types.PartialBusinessProfile], error) {
 
@@ -190,8 +338,82 @@ func (*stream) ExtractProfileSignal(ctx context.Context, message string, busines
 	return channel, nil
 }
 
+// / Streaming version of GenerateCarousel
+func (*stream) GenerateCarousel(ctx context.Context, profile types.BusinessProfile, roles []types.ContentRole, previousHooks []string, styleMemo string, opts ...CallOptionFunc) (<-chan StreamValue[stream_types.Carousel, types.Carousel], error) {
+
+	var callOpts callOption
+	for _, opt := range opts {
+		opt(&callOpts)
+	}
+
+	args := baml.BamlFunctionArguments{
+		Kwargs: map[string]any{"profile": profile, "roles": roles, "previousHooks": previousHooks, "styleMemo": styleMemo},
+		Env:    getEnvVars(callOpts.env),
+	}
+
+	if callOpts.clientRegistry != nil {
+		args.ClientRegistry = callOpts.clientRegistry
+	}
+
+	if callOpts.collectors != nil {
+		args.Collectors = callOpts.collectors
+	}
+
+	if callOpts.typeBuilder != nil {
+		args.TypeBuilder = callOpts.typeBuilder
+	}
+
+	if callOpts.tags != nil {
+		args.Tags = callOpts.tags
+	}
+
+	encoded, err := args.Encode()
+	if err != nil {
+		// This should never happen. if it does, please file an issue at https://github.com/boundaryml/baml/issues
+		// and include the type of the args you're passing in.
+		wrapped_err := fmt.Errorf("BAML INTERNAL ERROR: GenerateCarousel: %w", err)
+		panic(wrapped_err)
+	}
+
+	internal_channel, err := bamlRuntime.CallFunctionStream(ctx, "GenerateCarousel", encoded, callOpts.onTick)
+	if err != nil {
+		return nil, err
+	}
+
+	channel := make(chan StreamValue[stream_types.Carousel, types.Carousel])
+	go func() {
+		for result := range internal_channel {
+			if result.Error != nil {
+				channel <- StreamValue[stream_types.Carousel, types.Carousel]{
+					IsError: true,
+					Error:   result.Error,
+				}
+				close(channel)
+				return
+			}
+			if result.HasData {
+				data := (result.Data).(types.Carousel)
+				channel <- StreamValue[stream_types.Carousel, types.Carousel]{
+					IsFinal:  true,
+					as_final: &data,
+				}
+			} else {
+				data := (result.StreamData).(stream_types.Carousel)
+				channel <- StreamValue[stream_types.Carousel, types.Carousel]{
+					IsFinal:   false,
+					as_stream: &data,
+				}
+			}
+		}
+
+		// when internal_channel is closed, close the output too
+		close(channel)
+	}()
+	return channel, nil
+}
+
 // / Streaming version of GenerateContent
-func (*stream) GenerateContent(ctx context.Context, profile types.BusinessProfile, roles []types.ContentRole, previousHooks []string, opts ...CallOptionFunc) (<-chan StreamValue[stream_types.Post, types.Post], error) {
+func (*stream) GenerateContent(ctx context.Context, profile types.BusinessProfile, roles []types.ContentRole, previousHooks []string, styleMemo string, opts ...CallOptionFunc) (<-chan StreamValue[stream_types.Post, types.Post], error) {
 
 	var callOpts callOption
 	for _, opt := range opts {
@@ -199,7 +421,7 @@ func (*stream) GenerateContent(ctx context.Context, profile types.BusinessProfil
 	}
 
 	args := baml.BamlFunctionArguments{
-		Kwargs: map[string]any{"profile": profile, "roles": roles, "previousHooks": previousHooks},
+		Kwargs: map[string]any{"profile": profile, "roles": roles, "previousHooks": previousHooks, "styleMemo": styleMemo},
 		Env:    getEnvVars(callOpts.env),
 	}
 
@@ -265,7 +487,7 @@ func (*stream) GenerateContent(ctx context.Context, profile types.BusinessProfil
 }
 
 // / Streaming version of GenerateFromMessage
-func (*stream) GenerateFromMessage(ctx context.Context, profile types.BusinessProfile, clientMessage string, previousHooks []string, opts ...CallOptionFunc) (<-chan StreamValue[stream_types.Post, types.Post], error) {
+func (*stream) GenerateFromMessage(ctx context.Context, profile types.BusinessProfile, clientMessage string, previousHooks []string, styleMemo string, opts ...CallOptionFunc) (<-chan StreamValue[stream_types.Post, types.Post], error) {
 
 	var callOpts callOption
 	for _, opt := range opts {
@@ -273,7 +495,7 @@ func (*stream) GenerateFromMessage(ctx context.Context, profile types.BusinessPr
 	}
 
 	args := baml.BamlFunctionArguments{
-		Kwargs: map[string]any{"profile": profile, "clientMessage": clientMessage, "previousHooks": previousHooks},
+		Kwargs: map[string]any{"profile": profile, "clientMessage": clientMessage, "previousHooks": previousHooks, "styleMemo": styleMemo},
 		Env:    getEnvVars(callOpts.env),
 	}
 
@@ -338,6 +560,80 @@ func (*stream) GenerateFromMessage(ctx context.Context, profile types.BusinessPr
 	return channel, nil
 }
 
+// / Streaming version of GenerateReelScript
+func (*stream) GenerateReelScript(ctx context.Context, profile types.BusinessProfile, roles []types.ContentRole, previousHooks []string, styleMemo string, opts ...CallOptionFunc) (<-chan StreamValue[stream_types.ReelScript, types.ReelScript], error) {
+
+	var callOpts callOption
+	for _, opt := range opts {
+		opt(&callOpts)
+	}
+
+	args := baml.BamlFunctionArguments{
+		Kwargs: map[string]any{"profile": profile, "roles": roles, "previousHooks": previousHooks, "styleMemo": styleMemo},
+		Env:    getEnvVars(callOpts.env),
+	}
+
+	if callOpts.clientRegistry != nil {
+		args.ClientRegistry = callOpts.clientRegistry
+	}
+
+	if callOpts.collectors != nil {
+		args.Collectors = callOpts.collectors
+	}
+
+	if callOpts.typeBuilder != nil {
+		args.TypeBuilder = callOpts.typeBuilder
+	}
+
+	if callOpts.tags != nil {
+		args.Tags = callOpts.tags
+	}
+
+	encoded, err := args.Encode()
+	if err != nil {
+		// This should never happen. if it does, please file an issue at https://github.com/boundaryml/baml/issues
+		// and include the type of the args you're passing in.
+		wrapped_err := fmt.Errorf("BAML INTERNAL ERROR: GenerateReelScript: %w", err)
+		panic(wrapped_err)
+	}
+
+	internal_channel, err := bamlRuntime.CallFunctionStream(ctx, "GenerateReelScript", encoded, callOpts.onTick)
+	if err != nil {
+		return nil, err
+	}
+
+	channel := make(chan StreamValue[stream_types.ReelScript, types.ReelScript])
+	go func() {
+		for result := range internal_channel {
+			if result.Error != nil {
+				channel <- StreamValue[stream_types.ReelScript, types.ReelScript]{
+					IsError: true,
+					Error:   result.Error,
+				}
+				close(channel)
+				return
+			}
+			if result.HasData {
+				data := (result.Data).(types.ReelScript)
+				channel <- StreamValue[stream_types.ReelScript, types.ReelScript]{
+					IsFinal:  true,
+					as_final: &data,
+				}
+			} else {
+				data := (result.StreamData).(stream_types.ReelScript)
+				channel <- StreamValue[stream_types.ReelScript, types.ReelScript]{
+					IsFinal:   false,
+					as_stream: &data,
+				}
+			}
+		}
+
+		// when internal_channel is closed, close the output too
+		close(channel)
+	}()
+	return channel, nil
+}
+
 // / Streaming version of GenerateRekanContent
 func (*stream) GenerateRekanContent(ctx context.Context, profile types.BusinessProfile, roles []types.ContentRole, previousHooks []string, opts ...CallOptionFunc) (<-chan StreamValue[stream_types.Post, types.Post], error) {
 
@@ -412,6 +708,80 @@ func (*stream) GenerateRekanContent(ctx context.Context, profile types.BusinessP
 	return channel, nil
 }
 
+// / Streaming version of GenerateStorySequence
+func (*stream) GenerateStorySequence(ctx context.Context, profile types.BusinessProfile, roles []types.ContentRole, previousHooks []string, styleMemo string, opts ...CallOptionFunc) (<-chan StreamValue[stream_types.StorySequence, types.StorySequence], error) {
+
+	var callOpts callOption
+	for _, opt := range opts {
+		opt(&callOpts)
+	}
+
+	args := baml.BamlFunctionArguments{
+		Kwargs: map[string]any{"profile": profile, "roles": roles, "previousHooks": previousHooks, "styleMemo": styleMemo},
+		Env:    getEnvVars(callOpts.env),
+	}
+
+	if callOpts.clientRegistry != nil {
+		args.ClientRegistry = callOpts.clientRegistry
+	}
+
+	if callOpts.collectors != nil {
+		args.Collectors = callOpts.collectors
+	}
+
+	if callOpts.typeBuilder != nil {
+		args.TypeBuilder = callOpts.typeBuilder
+	}
+
+	if callOpts.tags != nil {
+		args.Tags = callOpts.tags
+	}
+
+	encoded, err := args.Encode()
+	if err != nil {
+		// This should never happen. if it does, please file an issue at https://github.com/boundaryml/baml/issues
+		// and include the type of the args you're passing in.
+		wrapped_err := fmt.Errorf("BAML INTERNAL ERROR: GenerateStorySequence: %w", err)
+		panic(wrapped_err)
+	}
+
+	internal_channel, err := bamlRuntime.CallFunctionStream(ctx, "GenerateStorySequence", encoded, callOpts.onTick)
+	if err != nil {
+		return nil, err
+	}
+
+	channel := make(chan StreamValue[stream_types.StorySequence, types.StorySequence])
+	go func() {
+		for result := range internal_channel {
+			if result.Error != nil {
+				channel <- StreamValue[stream_types.StorySequence, types.StorySequence]{
+					IsError: true,
+					Error:   result.Error,
+				}
+				close(channel)
+				return
+			}
+			if result.HasData {
+				data := (result.Data).(types.StorySequence)
+				channel <- StreamValue[stream_types.StorySequence, types.StorySequence]{
+					IsFinal:  true,
+					as_final: &data,
+				}
+			} else {
+				data := (result.StreamData).(stream_types.StorySequence)
+				channel <- StreamValue[stream_types.StorySequence, types.StorySequence]{
+					IsFinal:   false,
+					as_stream: &data,
+				}
+			}
+		}
+
+		// when internal_channel is closed, close the output too
+		close(channel)
+	}()
+	return channel, nil
+}
+
 // / Streaming version of JudgeAcionavel
 func (*stream) JudgeAcionavel(ctx context.Context, profile types.BusinessProfile, content string, opts ...CallOptionFunc) (<-chan StreamValue[stream_types.JudgeResult, types.JudgeResult], error) {
 
@@ -781,3 +1151,77 @@ func (*stream) JudgeVariedade(ctx context.Context, profile types.BusinessProfile
 	}()
 	return channel, nil
 }
+
+// / Streaming version of RewritePost
+func (*stream) RewritePost(ctx context.Context, profile types.BusinessProfile, post types.Post, instruction string, previousHooks []string, styleMemo string, opts ...CallOptionFunc) (<-chan StreamValue[stream_types.Post, types.Post], error) {
+
+	var callOpts callOption
+	for _, opt := range opts {
+		opt(&callOpts)
+	}
+
+	args := baml.BamlFunctionArguments{
+		Kwargs: map[string]any{"profile": profile, "post": post, "instruction": instruction, "previousHooks": previousHooks, "styleMemo": styleMemo},
+		Env:    getEnvVars(callOpts.env),
+	}
+
+	if callOpts.clientRegistry != nil {
+		args.ClientRegistry = callOpts.clientRegistry
+	}
+
+	if callOpts.collectors != nil {
+		args.Collectors = callOpts.collectors
+	}
+
+	if callOpts.typeBuilder != nil {
+		args.TypeBuilder = callOpts.typeBuilder
+	}
+
+	if callOpts.tags != nil {
+		args.Tags = callOpts.tags
+	}
+
+	encoded, err := args.Encode()
+	if err != nil {
+		// This should never happen. if it does, please file an issue at https://github.com/boundaryml/baml/issues
+		// and include the type of the args you're passing in.
+		wrapped_err := fmt.Errorf("BAML INTERNAL ERROR: RewritePost: %w", err)
+		panic(wrapped_err)
+	}
+
+	internal_channel, err := bamlRuntime.CallFunctionStream(ctx, "RewritePost", encoded, callOpts.onTick)
+	if err != nil {
+		return nil, err
+	}
+
+	channel := make(chan StreamValue[stream_types.Post, types.Post])
+	go func() {
+		for result := range internal_channel {
+			if result.Error != nil {
+				channel <- StreamValue[stream_types.Post, types.Post]{
+					IsError: true,
+					Error:   result.Error,
+				}
+				close(channel)
+				return
+			}
+			if result.HasData {
+				data := (result.Data).(types.Post)
+				channel <- StreamValue[stream_types.Post, types.Post]{
+					IsFinal:  true,
+					as_final: &data,
+				}
+			} else {
+				data := (result.StreamData).(stream_types.Post)
+				channel <- StreamValue[stream_types.Post, types.Post]{
+					IsFinal:   false,
+					as_stream: &data,
+				}
+			}
+		}
+
+		// when internal_channel is closed, close the output too
+		close(channel)
+	}()
+	return channel, nil
+}
@@ -98,6 +98,156 @@ func (c BusinessProfile) BamlTypeName() string {
 	return "BusinessProfile"
 }
 
+type Carousel struct {
+	Slides   []CarouselSlide `json:"slides"`
+	Caption  *string         `json:"caption"`
+	Hashtags []string        `json:"hashtags"`
+}
+
+func (c *Carousel) Decode(holder *cffi.CFFIValueClass, typeMap baml.TypeMap) {
+	typeName := holder.Name
+	if typeName.Namespace != cffi.CFFITypeNamespace_STREAM_TYPES {
+		panic(fmt.Sprintf("expected cffi.CFFITypeNamespace_STREAM_TYPES, got %s", string(typeName.Namespace.String())))
+	}
+	if typeName.Name != "Carousel" {
+		panic(fmt.Sprintf("expected Carousel, got %s", typeName.Name))
+	}
+
+	for _, field := range holder.Fields {
+		key := field.Key
+		valueHolder := field.Value
+		switch key {
+
+		case "slides":
+			c.Slides = baml.Decode(valueHolder).Interface().([]CarouselSlide)
+
+		case "caption":
+			c.Caption = baml.Decode(valueHolder).Interface().(*string)
+
+		case "hashtags":
+			c.Hashtags = baml.Decode(valueHolder).Interface().([]string)
+
+		default:
+
+			panic(fmt.Sprintf("unexpected field: %s in class Carousel", key))
+
+		}
+	}
+
+}
+
+func (c Carousel) Encode() (*cffi.HostValue, error) {
+	fields := map[string]any{}
+
+	fields["slides"] = c.Slides
+
+	fields["caption"] = c.Caption
+
+	fields["hashtags"] = c.Hashtags
+
+	return baml.EncodeClass("Carousel", fields, nil)
+}
+
+func (c Carousel) BamlTypeName() string {
+	return "Carousel"
+}
+
+type CarouselSlide struct {
+	Title *string `json:"title"`
+	Body  *string `json:"body"`
+}
+
+func (c *CarouselSlide) Decode(holder *cffi.CFFIValueClass, typeMap baml.TypeMap) {
+	typeName := holder.Name
+	if typeName.Namespace != cffi.CFFITypeNamespace_STREAM_TYPES {
+		panic(fmt.Sprintf("expected cffi.CFFITypeNamespace_STREAM_TYPES, got %s", string(typeName.Namespace.String())))
+	}
+	if typeName.Name != "CarouselSlide" {
+		panic(fmt.Sprintf("expected CarouselSlide, got %s", typeName.Name))
+	}
+
+	for _, field := range holder.Fields {
+		key := field.Key
+		valueHolder := field.Value
+		switch key {
+
+		case "title":
+			c.Title = baml.Decode(valueHolder).Interface().(*string)
+
+		case "body":
+			c.Body = baml.Decode(valueHolder).Interface().(*string)
+
+		default:
+
+			panic(fmt.Sprintf("unexpected field: %s in class CarouselSlide", key))
+
+		}
+	}
+
+}
+
+func (c CarouselSlide) Encode() (*cffi.HostValue, error) {
+	fields := map[string]any{}
+
+	fields["title"] = c.Title
+
+	fields["body"] = c.Body
+
+	return baml.EncodeClass("CarouselSlide", fields, nil)
+}
+
+func (c CarouselSlide) BamlTypeName() string {
+	return "CarouselSlide"
+}
+
+type ClientReply struct {
+	Kind    *string `json:"kind"`
+	Summary *string `json:"summary"`
+}
+
+func (c *ClientReply) Decode(holder *cffi.CFFIValueClass, typeMap baml.TypeMap) {
+	typeName := holder.Name
+	if typeName.Namespace != cffi.CFFITypeNamespace_STREAM_TYPES {
+		panic(fmt.Sprintf("expected cffi.CFFITypeNamespace_STREAM_TYPES, got %s", string(typeName.Namespace.String())))
+	}
+	if typeName.Name != "ClientReply" {
+		panic(fmt.Sprintf("expected ClientReply, got %s", typeName.Name))
+	}
+
+	for _, field := range holder.Fields {
+		key := field.Key
+		valueHolder := field.Value
+		switch key {
+
+		case "kind":
+			c.Kind = baml.Decode(valueHolder).Interface().(*string)
+
+		case "summary":
+			c.Summary = baml.Decode(valueHolder).Interface().(*string)
+
+		default:
+
+			panic(fmt.Sprintf("unexpected field: %s in class ClientReply", key))
+
+		}
+	}
+
+}
+
+func (c ClientReply) Encode() (*cffi.HostValue, error) {
+	fields := map[string]any{}
+
+	fields["kind"] = c.Kind
+
+	fields["summary"] = c.Summary
+
+	return baml.EncodeClass("ClientReply", fields, nil)
+}
+
+func (c ClientReply) BamlTypeName() string {
+	return "ClientReply"
+}
+
 type ContentRole struct {
 	Name        *string `json:"name"`
 	Description *string `json:"description"`
@@ -146,6 +296,54 @@ func (c ContentRole) BamlTypeName() string {
 	return "ContentRole"
 }
 
+type EditedCaption struct {
+	Before *string `json:"before"`
+	After  *string `json:"after"`
+}
+
+func (c *EditedCaption) Decode(holder *cffi.CFFIValueClass, typeMap baml.TypeMap) {
+	typeName := holder.Name
+	if typeName.Namespace != cffi.CFFITypeNamespace_STREAM_TYPES {
+		panic(fmt.Sprintf("expected cffi.CFFITypeNamespace_STREAM_TYPES, got %s", string(typeName.Namespace.String())))
+	}
+	if typeName.Name != "EditedCaption" {
+		panic(fmt.Sprintf("expected EditedCaption, got %s", typeName.Name))
+	}
+
+	for _, field := range holder.Fields {
+		key := field.Key
+		valueHolder := field.Value
+		switch key {
+
+		case "before":
+			c.Before = baml.Decode(valueHolder).Interface().(*string)
+
+		case "after":
+			c.After = baml.Decode(valueHolder).Interface().(*string)
+
+		default:
+
+			panic(fmt.Sprintf("unexpected field: %s in class EditedCaption", key))
+
+		}
+	}
+
+}
+
+func (c EditedCaption) Encode() (*cffi.HostValue, error) {
+	fields := map[string]any{}
+
+	fields["before"] = c.Before
+
+	fields["after"] = c.After
+
+	return baml.EncodeClass("EditedCaption", fields, nil)
+}
+
+func (c EditedCaption) BamlTypeName() string {
+	return "EditedCaption"
+}
+
 type JudgeResult struct {
 	Reasoning *string `json:"reasoning"`
 	Verdict   *bool   `json:"verdict"`
@@ -458,6 +656,174 @@ func (c ProfileSignal) BamlTypeName() string {
 	return "ProfileSignal"
 }
 
+type ReelScene struct {
+	Visual       *string `json:"visual"`
+	OnScreenText *string `json:"onScreenText"`
+	Voiceover    *string `json:"voiceover"`
+}
+
+func (c *ReelScene) Decode(holder *cffi.CFFIValueClass, typeMap baml.TypeMap) {
+	typeName := holder.Name
+	if typeName.Namespace != cffi.CFFITypeNamespace_STREAM_TYPES {
+		panic(fmt.Sprintf("expected cffi.CFFITypeNamespace_STREAM_TYPES, got %s", string(typeName.Namespace.String())))
+	}
+	if typeName.Name != "ReelScene" {
+		panic(fmt.Sprintf("expected ReelScene, got %s", typeName.Name))
+	}
+
+	for _, field := range holder.Fields {
+		key := field.Key
+		valueHolder := field.Value
+		switch key {
+
+		case "visual":
+			c.Visual = baml.Decode(valueHolder).Interface().(*string)
+
+		case "onScreenText":
+			c.OnScreenText = baml.Decode(valueHolder).Interface().(*string)
+
+		case "voiceover":
+			c.Voiceover = baml.Decode(valueHolder).Interface().(*string)
+
+		default:
+
+			panic(fmt.Sprintf("unexpected field: %s in class ReelScene", key))
+
+		}
+	}
+
+}
+
+func (c ReelScene) Encode() (*cffi.HostValue, error) {
+	fields := map[string]any{}
+
+	fields["visual"] = c.Visual
+
+	fields["onScreenText"] = c.OnScreenText
+
+	fields["voiceover"] = c.Voiceover
+
+	return baml.EncodeClass("ReelScene", fields, nil)
+}
+
+func (c ReelScene) BamlTypeName() string {
+	return "ReelScene"
+}
+
+type ReelScript struct {
+	Hook      *string     `json:"hook"`
+	Scenes    []ReelScene `json:"scenes"`
+	AudioHint *string     `json:"audioHint"`
+	Caption   *string     `json:"caption"`
+	Hashtags  []string    `json:"hashtags"`
+}
+
+func (c *ReelScript) Decode(holder *cffi.CFFIValueClass, typeMap baml.TypeMap) {
+	typeName := holder.Name
+	if typeName.Namespace != cffi.CFFITypeNamespace_STREAM_TYPES {
+		panic(fmt.Sprintf("expected cffi.CFFITypeNamespace_STREAM_TYPES, got %s", string(typeName.Namespace.String())))
+	}
+	if typeName.Name != "ReelScript" {
+		panic(fmt.Sprintf("expected ReelScript, got %s", typeName.Name))
+	}
+
+	for _, field := range holder.Fields {
+		key := field.Key
+		valueHolder := field.Value
+		switch key {
+
+		case "hook":
+			c.Hook = baml.Decode(valueHolder).Interface().(*string)
+
+		case "scenes":
+			c.Scenes = baml.Decode(valueHolder).Interface().([]ReelScene)
+
+		case "audioHint":
+			c.AudioHint = baml.Decode(valueHolder).Interface().(*string)
+
+		case "caption":
+			c.Caption = baml.Decode(valueHolder).Interface().(*string)
+
+		case "hashtags":
+			c.Hashtags = baml.Decode(valueHolder).Interface().([]string)
+
+		default:
+
+			panic(fmt.Sprintf("unexpected field: %s in class ReelScript", key))
+
+		}
+	}
+
+}
+
+func (c ReelScript) Encode() (*cffi.HostValue, error) {
+	fields := map[string]any{}
+
+	fields["hook"] = c.Hook
+
+	fields["scenes"] = c.Scenes
+
+	fields["audioHint"] = c.AudioHint
+
+	fields["caption"] = c.Caption
+
+	fields["hashtags"] = c.Hashtags
+
+	return baml.EncodeClass("ReelScript", fields, nil)
+}
+
+func (c ReelScript) BamlTypeName() string {
+	return "ReelScript"
+}
+
+type RejectedCaption struct {
+	Caption  *string `json:"caption"`
+	Feedback *string `json:"feedback"`
+}
+
+func (c *RejectedCaption) Decode(holder *cffi.CFFIValueClass, typeMap baml.TypeMap) {
+	typeName := holder.Name
+	if typeName.Namespace != cffi.CFFITypeNamespace_STREAM_TYPES {
+		panic(fmt.Sprintf("expected cffi.CFFITypeNamespace_STREAM_TYPES, got %s", string(typeName.Namespace.String())))
+	}
+	if typeName.Name != "RejectedCaption" {
+		panic(fmt.Sprintf("expected RejectedCaption, got %s", typeName.Name))
+	}
+
+	for _, field := range holder.Fields {
+		key := field.Key
+		valueHolder := field.Value
+		switch key {
+
+		case "caption":
+			c.Caption = baml.Decode(valueHolder).Interface().(*string)
+
+		case "feedback":
+			c.Feedback = baml.Decode(valueHolder).Interface().(*string)
+
+		default:
+
+			panic(fmt.Sprintf("unexpected field: %s in class RejectedCaption", key))
+
+		}
+	}
+
+}
+
+func (c RejectedCaption) Encode() (*cffi.HostValue, error) {
+	fields := map[string]any{}
+
+	fields["caption"] = c.Caption
+
+	fields["feedback"] = c.Feedback
+
+	return baml.EncodeClass("RejectedCaption", fields, nil)
+}
+
+func (c RejectedCaption) BamlTypeName() string {
+	return "RejectedCaption"
+}
+
 type Service struct {
 	Name     *string  `json:"name"`
 	PriceBRL *float64 `json:"priceBRL"`
@@ -505,3 +871,141 @@ func (c Service) Encode() (*cffi.HostValue, error) {
 func (c Service) BamlTypeName() string {
 	return "Service"
 }
+
+type StoryFrame struct {
+	Visual  *string `json:"visual"`
+	Text    *string `json:"text"`
+	Sticker *string `json:"sticker"`
+}
+
+func (c *StoryFrame) Decode(holder *cffi.CFFIValueClass, typeMap baml.TypeMap) {
+	typeName := holder.Name
+	if typeName.Namespace != cffi.CFFITypeNamespace_STREAM_TYPES {
+		panic(fmt.Sprintf("expected cffi.CFFITypeNamespace_STREAM_TYPES, got %s", string(typeName.Namespace.String())))
+	}
+	if typeName.Name != "StoryFrame" {
+		panic(fmt.Sprintf("expected StoryFrame, got %s", typeName.Name))
+	}
+
+	for _, field := range holder.Fields {
+		key := field.Key
+		valueHolder := field.Value
+		switch key {
+
+		case "visual":
+			c.Visual = baml.Decode(valueHolder).Interface().(*string)
+
+		case "text":
+			c.Text = baml.Decode(valueHolder).Interface().(*string)
+
+		case "sticker":
+			c.Sticker = baml.Decode(valueHolder).Interface().(*string)
+
+		default:
+
+			panic(fmt.Sprintf("unexpected field: %s in class StoryFrame", key))
+
+		}
+	}
+
+}
+
+func (c StoryFrame) Encode() (*cffi.HostValue, error) {
+	fields := map[string]any{}
+
+	fields["visual"] = c.Visual
+
+	fields["text"] = c.Text
+
+	fields["sticker"] = c.Sticker
+
+	return baml.EncodeClass("StoryFrame", fields, nil)
+}
+
+func (c StoryFrame) BamlTypeName() string {
+	return "StoryFrame"
+}
+
+type StorySequence struct {
+	Frames []StoryFrame `json:"frames"`
+}
+
+func (c *StorySequence) Decode(holder *cffi.CFFIValueClass, typeMap baml.TypeMap) {
+	typeName := holder.Name
+	if typeName.Namespace != cffi.CFFITypeNamespace_STREAM_TYPES {
+		panic(fmt.Sprintf("expected cffi.CFFITypeNamespace_STREAM_TYPES, got %s", string(typeName.Namespace.String())))
+	}
+	if typeName.Name != "StorySequence" {
+		panic(fmt.Sprintf("expected StorySequence, got %s", typeName.Name))
+	}
+
+	for _, field := range holder.Fields {
+		key := field.Key
+		valueHolder := field.Value
+		switch key {
+
+		case "frames":
+			c.Frames = baml.Decode(valueHolder).Interface().([]StoryFrame)
+
+		default:
+
+			panic(fmt.Sprintf("unexpected field: %s in class StorySequence", key))
+
+		}
+	}
+
+}
+
+func (c StorySequence) Encode() (*cffi.HostValue, error) {
+	fields := map[string]any{}
+
+	fields["frames"] = c.Frames
+
+	return baml.EncodeClass("StorySequence", fields, nil)
+}
+
+func (c StorySequence) BamlTypeName() string {
+	return "StorySequence"
+}
+
+type StyleMemo struct {
+	Rules []string `json:"rules"`
+}
+
+func (c *StyleMemo) Decode(holder *cffi.CFFIValueClass, typeMap baml.TypeMap) {
+	typeName := holder.Name
+	if typeName.Namespace != cffi.CFFITypeNamespace_STREAM_TYPES {
+		panic(fmt.Sprintf("expected cffi.CFFITypeNamespace_STREAM_TYPES, got %s", string(typeName.Namespace.String())))
+	}
+	if typeName.Name != "StyleMemo" {
+		panic(fmt.Sprintf("expected StyleMemo, got %s", typeName.Name))
+	}
+
+	for _, field := range holder.Fields {
+		key := field.Key
+		valueHolder := field.Value
+		switch key {
+
+		case "rules":
+			c.Rules = baml.Decode(valueHolder).Interface().([]string)
+
+		default:
+
+			panic(fmt.Sprintf("unexpected field: %s in class StyleMemo", key))
+
+		}
+	}
+
+}
+
+func (c StyleMemo) Encode() (*cffi.HostValue, error) {
+	fields := map[string]any{}
+
+	fields["rules"] = c.Rules
+
+	return baml.EncodeClass("StyleMemo", fields, nil)
+}
+
+func (c StyleMemo) BamlTypeName() string {
+	return "StyleMemo"
+}
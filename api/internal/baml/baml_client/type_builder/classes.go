@@ -71,6 +71,118 @@ func (t *BusinessProfileClassView) Type() (baml.Type, error) {
 	return t.inner.Type()
 }
 
+type CarouselClassView struct {
+	inner baml.ClassBuilder
+}
+
+func (t *CarouselClassView) ListProperties() ([]ClassPropertyView, error) {
+	result, err := t.inner.ListProperties()
+	if err != nil {
+		return nil, err
+	}
+	builders := make([]ClassPropertyView, len(result))
+	for i, p := range result {
+		builders[i] = p
+	}
+	return builders, nil
+}
+
+func (t *CarouselClassView) PropertySlides() (ClassPropertyView, error) {
+	return t.inner.Property("slides")
+}
+
+func (t *CarouselClassView) PropertyCaption() (ClassPropertyView, error) {
+	return t.inner.Property("caption")
+}
+
+func (t *CarouselClassView) PropertyHashtags() (ClassPropertyView, error) {
+	return t.inner.Property("hashtags")
+}
+
+func (t *TypeBuilder) Carousel() (*CarouselClassView, error) {
+	bld, err := t.inner.Class("Carousel")
+	if err != nil {
+		return nil, err
+	}
+	return &CarouselClassView{inner: bld}, nil
+}
+
+func (t *CarouselClassView) Type() (baml.Type, error) {
+	return t.inner.Type()
+}
+
+type CarouselSlideClassView struct {
+	inner baml.ClassBuilder
+}
+
+func (t *CarouselSlideClassView) ListProperties() ([]ClassPropertyView, error) {
+	result, err := t.inner.ListProperties()
+	if err != nil {
+		return nil, err
+	}
+	builders := make([]ClassPropertyView, len(result))
+	for i, p := range result {
+		builders[i] = p
+	}
+	return builders, nil
+}
+
+func (t *CarouselSlideClassView) PropertyTitle() (ClassPropertyView, error) {
+	return t.inner.Property("title")
+}
+
+func (t *CarouselSlideClassView) PropertyBody() (ClassPropertyView, error) {
+	return t.inner.Property("body")
+}
+
+func (t *TypeBuilder) CarouselSlide() (*CarouselSlideClassView, error) {
+	bld, err := t.inner.Class("CarouselSlide")
+	if err != nil {
+		return nil, err
+	}
+	return &CarouselSlideClassView{inner: bld}, nil
+}
+
+func (t *CarouselSlideClassView) Type() (baml.Type, error) {
+	return t.inner.Type()
+}
+
+type ClientReplyClassView struct {
+	inner baml.ClassBuilder
+}
+
+func (t *ClientReplyClassView) ListProperties() ([]ClassPropertyView, error) {
+	result, err := t.inner.ListProperties()
+	if err != nil {
+		return nil, err
+	}
+	builders := make([]ClassPropertyView, len(result))
+	for i, p := range result {
+		builders[i] = p
+	}
+	return builders, nil
+}
+
+func (t *ClientReplyClassView) PropertyKind() (ClassPropertyView, error) {
+	return t.inner.Property("kind")
+}
+
+func (t *ClientReplyClassView) PropertySummary() (ClassPropertyView, error) {
+	return t.inner.Property("summary")
+}
+
+func (t *TypeBuilder) ClientReply() (*ClientReplyClassView, error) {
+	bld, err := t.inner.Class("ClientReply")
+	if err != nil {
+		return nil, err
+	}
+	return &ClientReplyClassView{inner: bld}, nil
+}
+
+func (t *ClientReplyClassView) Type() (baml.Type, error) {
+	return t.inner.Type()
+}
+
 type ContentRoleClassView struct {
 	inner baml.ClassBuilder
 }
@@ -107,6 +219,42 @@ func (t *ContentRoleClassView) Type() (baml.Type, error) {
 	return t.inner.Type()
 }
 
+type EditedCaptionClassView struct {
+	inner baml.ClassBuilder
+}
+
+func (t *EditedCaptionClassView) ListProperties() ([]ClassPropertyView, error) {
+	result, err := t.inner.ListProperties()
+	if err != nil {
+		return nil, err
+	}
+	builders := make([]ClassPropertyView, len(result))
+	for i, p := range result {
+		builders[i] = p
+	}
+	return builders, nil
+}
+
+func (t *EditedCaptionClassView) PropertyBefore() (ClassPropertyView, error) {
+	return t.inner.Property("before")
+}
+
+func (t *EditedCaptionClassView) PropertyAfter() (ClassPropertyView, error) {
+	return t.inner.Property("after")
+}
+
+func (t *TypeBuilder) EditedCaption() (*EditedCaptionClassView, error) {
+	bld, err := t.inner.Class("EditedCaption")
+	if err != nil {
+		return nil, err
+	}
+	return &EditedCaptionClassView{inner: bld}, nil
+}
+
+func (t *EditedCaptionClassView) Type() (baml.Type, error) {
+	return t.inner.Type()
+}
+
 type JudgeResultClassView struct {
 	inner baml.ClassBuilder
 }
@@ -339,6 +487,130 @@ func (t *ProfileSignalClassView) Type() (baml.Type, error) {
 	return t.inner.Type()
 }
 
+type ReelSceneClassView struct {
+	inner baml.ClassBuilder
+}
+
+func (t *ReelSceneClassView) ListProperties() ([]ClassPropertyView, error) {
+	result, err := t.inner.ListProperties()
+	if err != nil {
+		return nil, err
+	}
+	builders := make([]ClassPropertyView, len(result))
+	for i, p := range result {
+		builders[i] = p
+	}
+	return builders, nil
+}
+
+func (t *ReelSceneClassView) PropertyVisual() (ClassPropertyView, error) {
+	return t.inner.Property("visual")
+}
+
+func (t *ReelSceneClassView) PropertyOnScreenText() (ClassPropertyView, error) {
+	return t.inner.Property("onScreenText")
+}
+
+func (t *ReelSceneClassView) PropertyVoiceover() (ClassPropertyView, error) {
+	return t.inner.Property("voiceover")
+}
+
+func (t *TypeBuilder) ReelScene() (*ReelSceneClassView, error) {
+	bld, err := t.inner.Class("ReelScene")
+	if err != nil {
+		return nil, err
+	}
+	return &ReelSceneClassView{inner: bld}, nil
+}
+
+func (t *ReelSceneClassView) Type() (baml.Type, error) {
+	return t.inner.Type()
+}
+
+type ReelScriptClassView struct {
+	inner baml.ClassBuilder
+}
+
+func (t *ReelScriptClassView) ListProperties() ([]ClassPropertyView, error) {
+	result, err := t.inner.ListProperties()
+	if err != nil {
+		return nil, err
+	}
+	builders := make([]ClassPropertyView, len(result))
+	for i, p := range result {
+		builders[i] = p
+	}
+	return builders, nil
+}
+
+func (t *ReelScriptClassView) PropertyHook() (ClassPropertyView, error) {
+	return t.inner.Property("hook")
+}
+
+func (t *ReelScriptClassView) PropertyScenes() (ClassPropertyView, error) {
+	return t.inner.Property("scenes")
+}
+
+func (t *ReelScriptClassView) PropertyAudioHint() (ClassPropertyView, error) {
+	return t.inner.Property("audioHint")
+}
+
+func (t *ReelScriptClassView) PropertyCaption() (ClassPropertyView, error) {
+	return t.inner.Property("caption")
+}
+
+func (t *ReelScriptClassView) PropertyHashtags() (ClassPropertyView, error) {
+	return t.inner.Property("hashtags")
+}
+
+func (t *TypeBuilder) ReelScript() (*ReelScriptClassView, error) {
+	bld, err := t.inner.Class("ReelScript")
+	if err != nil {
+		return nil, err
+	}
+	return &ReelScriptClassView{inner: bld}, nil
+}
+
+func (t *ReelScriptClassView) Type() (baml.Type, error) {
+	return t.inner.Type()
+}
+
+type RejectedCaptionClassView struct {
+	inner baml.ClassBuilder
+}
+
+func (t *RejectedCaptionClassView) ListProperties() ([]ClassPropertyView, error) {
+	result, err := t.inner.ListProperties()
+	if err != nil {
+		return nil, err
+	}
+	builders := make([]ClassPropertyView, len(result))
+	for i, p := range result {
+		builders[i] = p
+	}
+	return builders, nil
+}
+
+func (t *RejectedCaptionClassView) PropertyCaption() (ClassPropertyView, error) {
+	return t.inner.Property("caption")
+}
+
+func (t *RejectedCaptionClassView) PropertyFeedback() (ClassPropertyView, error) {
+	return t.inner.Property("feedback")
+}
+
+func (t *TypeBuilder) RejectedCaption() (*RejectedCaptionClassView, error) {
+	bld, err := t.inner.Class("RejectedCaption")
+	if err != nil {
+		return nil, err
+	}
+	return &RejectedCaptionClassView{inner: bld}, nil
+}
+
+func (t *RejectedCaptionClassView) Type() (baml.Type, error) {
+	return t.inner.Type()
+}
+
 type ServiceClassView struct {
 	inner baml.ClassBuilder
 }
@@ -374,3 +646,107 @@ func (t *TypeBuilder) Service() (*ServiceClassView, error) {
 func (t *ServiceClassView) Type() (baml.Type, error) {
 	return t.inner.Type()
 }
+
+type StoryFrameClassView struct {
+	inner baml.ClassBuilder
+}
+
+func (t *StoryFrameClassView) ListProperties() ([]ClassPropertyView, error) {
+	result, err := t.inner.ListProperties()
+	if err != nil {
+		return nil, err
+	}
+	builders := make([]ClassPropertyView, len(result))
+	for i, p := range result {
+		builders[i] = p
+	}
+	return builders, nil
+}
+
+func (t *StoryFrameClassView) PropertyVisual() (ClassPropertyView, error) {
+	return t.inner.Property("visual")
+}
+
+func (t *StoryFrameClassView) PropertyText() (ClassPropertyView, error) {
+	return t.inner.Property("text")
+}
+
+func (t *StoryFrameClassView) PropertySticker() (ClassPropertyView, error) {
+	return t.inner.Property("sticker")
+}
+
+func (t *TypeBuilder) StoryFrame() (*StoryFrameClassView, error) {
+	bld, err := t.inner.Class("StoryFrame")
+	if err != nil {
+		return nil, err
+	}
+	return &StoryFrameClassView{inner: bld}, nil
+}
+
+func (t *StoryFrameClassView) Type() (baml.Type, error) {
+	return t.inner.Type()
+}
+
+type StorySequenceClassView struct {
+	inner baml.ClassBuilder
+}
+
+func (t *StorySequenceClassView) ListProperties() ([]ClassPropertyView, error) {
+	result, err := t.inner.ListProperties()
+	if err != nil {
+		return nil, err
+	}
+	builders := make([]ClassPropertyView, len(result))
+	for i, p := range result {
+		builders[i] = p
+	}
+	return builders, nil
+}
+
+func (t *StorySequenceClassView) PropertyFrames() (ClassPropertyView, error) {
+	return t.inner.Property("frames")
+}
+
+func (t *TypeBuilder) StorySequence() (*StorySequenceClassView, error) {
+	bld, err := t.inner.Class("StorySequence")
+	if err != nil {
+		return nil, err
+	}
+	return &StorySequenceClassView{inner: bld}, nil
+}
+
+func (t *StorySequenceClassView) Type() (baml.Type, error) {
+	return t.inner.Type()
+}
+
+type StyleMemoClassView struct {
+	inner baml.ClassBuilder
+}
+
+func (t *StyleMemoClassView) ListProperties() ([]ClassPropertyView, error) {
+	result, err := t.inner.ListProperties()
+	if err != nil {
+		return nil, err
+	}
+	builders := make([]ClassPropertyView, len(result))
+	for i, p := range result {
+		builders[i] = p
+	}
+	return builders, nil
+}
+
+func (t *StyleMemoClassView) PropertyRules() (ClassPropertyView, error) {
+	return t.inner.Property("rules")
+}
+
+func (t *TypeBuilder) StyleMemo() (*StyleMemoClassView, error) {
+	bld, err := t.inner.Class("StyleMemo")
+	if err != nil {
+		return nil, err
+	}
+	return &StyleMemoClassView{inner: bld}, nil
+}
+
+func (t *StyleMemoClassView) Type() (baml.Type, error) {
+	return t.inner.Type()
+}
@@ -23,8 +23,16 @@ import (
 var typeMap = map[string]reflect.Type{
 	"TYPES.BusinessProfile":               reflect.TypeOf(types.BusinessProfile{}),
 	"STREAM_TYPES.BusinessProfile":        reflect.TypeOf(stream_types.BusinessProfile{}),
+	"TYPES.Carousel":                      reflect.TypeOf(types.Carousel{}),
+	"STREAM_TYPES.Carousel":               reflect.TypeOf(stream_types.Carousel{}),
+	"TYPES.CarouselSlide":                 reflect.TypeOf(types.CarouselSlide{}),
+	"STREAM_TYPES.CarouselSlide":          reflect.TypeOf(stream_types.CarouselSlide{}),
+	"TYPES.ClientReply":                   reflect.TypeOf(types.ClientReply{}),
+	"STREAM_TYPES.ClientReply":            reflect.TypeOf(stream_types.ClientReply{}),
 	"TYPES.ContentRole":                   reflect.TypeOf(types.ContentRole{}),
 	"STREAM_TYPES.ContentRole":            reflect.TypeOf(stream_types.ContentRole{}),
+	"TYPES.EditedCaption":                 reflect.TypeOf(types.EditedCaption{}),
+	"STREAM_TYPES.EditedCaption":          reflect.TypeOf(stream_types.EditedCaption{}),
 	"TYPES.JudgeResult":                   reflect.TypeOf(types.JudgeResult{}),
 	"STREAM_TYPES.JudgeResult":            reflect.TypeOf(stream_types.JudgeResult{}),
 	"TYPES.JudgeVariedadeResult":          reflect.TypeOf(types.JudgeVariedadeResult{}),
@@ -37,6 +45,18 @@ var typeMap = map[string]reflect.Type{
 	"STREAM_TYPES.Post":                   reflect.TypeOf(stream_types.Post{}),
 	"TYPES.ProfileSignal":                 reflect.TypeOf(types.ProfileSignal{}),
 	"STREAM_TYPES.ProfileSignal":          reflect.TypeOf(stream_types.ProfileSignal{}),
+	"TYPES.ReelScene":                     reflect.TypeOf(types.ReelScene{}),
+	"STREAM_TYPES.ReelScene":              reflect.TypeOf(stream_types.ReelScene{}),
+	"TYPES.ReelScript":                    reflect.TypeOf(types.ReelScript{}),
+	"STREAM_TYPES.ReelScript":             reflect.TypeOf(stream_types.ReelScript{}),
+	"TYPES.RejectedCaption":               reflect.TypeOf(types.RejectedCaption{}),
+	"STREAM_TYPES.RejectedCaption":        reflect.TypeOf(stream_types.RejectedCaption{}),
 	"TYPES.Service":                       reflect.TypeOf(types.Service{}),
 	"STREAM_TYPES.Service":                reflect.TypeOf(stream_types.Service{}),
+	"TYPES.StoryFrame":                    reflect.TypeOf(types.StoryFrame{}),
+	"STREAM_TYPES.StoryFrame":             reflect.TypeOf(stream_types.StoryFrame{}),
+	"TYPES.StorySequence":                 reflect.TypeOf(types.StorySequence{}),
+	"STREAM_TYPES.StorySequence":          reflect.TypeOf(stream_types.StorySequence{}),
+	"TYPES.StyleMemo":                     reflect.TypeOf(types.StyleMemo{}),
+	"STREAM_TYPES.StyleMemo":              reflect.TypeOf(stream_types.StyleMemo{}),
 }
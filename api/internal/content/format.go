@@ -0,0 +1,236 @@
+package content
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	baml "github.com/denisraison/rekan/api/internal/baml/baml_client"
+	"github.com/denisraison/rekan/api/internal/baml/baml_client/types"
+)
+
+// Format is the kind of Instagram content a post holds.
+type Format string
+
+const (
+	FormatFeed     Format = "feed"
+	FormatReel     Format = "reel"
+	FormatStory    Format = "story"
+	FormatCarousel Format = "carousel"
+)
+
+// ParseFormat maps a stored or user-supplied format to a Format. Empty means
+// feed, which is what every post was before formats existed.
+func ParseFormat(s string) (Format, bool) {
+	switch f := Format(strings.ToLower(strings.TrimSpace(s))); f {
+	case "":
+		return FormatFeed, true
+	case FormatFeed, FormatReel, FormatStory, FormatCarousel:
+		return f, true
+	}
+	return "", false
+}
+
+// FormatGenerators holds the generator for each non-feed format. Feed posts
+// use whichever GenerateFunc the caller already has (Generate, GenerateRekan).
+var FormatGenerators = map[Format]GenerateFunc{
+	FormatReel:     GenerateReel,
+	FormatStory:    GenerateStory,
+	FormatCarousel: GenerateCarousel,
+}
+
+type ReelScene struct {
+	Visual       string `json:"visual"`
+	OnScreenText string `json:"onScreenText"`
+	Voiceover    string `json:"voiceover"`
+}
+
+type Reel struct {
+	Hook      string      `json:"hook"`
+	Scenes    []ReelScene `json:"scenes"`
+	AudioHint string      `json:"audioHint"`
+}
+
+type StoryFrame struct {
+	Visual  string `json:"visual"`
+	Text    string `json:"text"`
+	Sticker string `json:"sticker"`
+}
+
+type CarouselSlide struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+// FormatData is the format-specific part of a post, stored as JSON next to
+// the caption. Only the field matching the post's format is set.
+type FormatData struct {
+	Reel     *Reel           `json:"reel,omitempty"`
+	Story    []StoryFrame    `json:"story,omitempty"`
+	Carousel []CarouselSlide `json:"carousel,omitempty"`
+}
+
+func GenerateReel(ctx context.Context, profile BusinessProfile, roles []Role, previousHooks []string) ([]Post, error) {
+	r, err := baml.GenerateReelScript(ctx, toBamlProfile(profile), toBamlRoles(roles), previousHooks, profile.StyleMemo, generatorOpts()...)
+	if err != nil {
+		return nil, fmt.Errorf("generate reel: %w", err)
+	}
+	return []Post{withHashtags(fromBamlReel(r), profile)}, nil
+}
+
+func GenerateStory(ctx context.Context, profile BusinessProfile, roles []Role, previousHooks []string) ([]Post, error) {
+	s, err := baml.GenerateStorySequence(ctx, toBamlProfile(profile), toBamlRoles(roles), previousHooks, profile.StyleMemo, generatorOpts()...)
+	if err != nil {
+		return nil, fmt.Errorf("generate story: %w", err)
+	}
+	return []Post{fromBamlStory(s)}, nil
+}
+
+func GenerateCarousel(ctx context.Context, profile BusinessProfile, roles []Role, previousHooks []string) ([]Post, error) {
+	c, err := baml.GenerateCarousel(ctx, toBamlProfile(profile), toBamlRoles(roles), previousHooks, profile.StyleMemo, generatorOpts()...)
+	if err != nil {
+		return nil, fmt.Errorf("generate carousel: %w", err)
+	}
+	return []Post{withHashtags(fromBamlCarousel(c), profile)}, nil
+}
+
+func fromBamlReel(r types.ReelScript) Post {
+	scenes := make([]ReelScene, len(r.Scenes))
+	for i, s := range r.Scenes {
+		scenes[i] = ReelScene{Visual: s.Visual, OnScreenText: s.OnScreenText, Voiceover: s.Voiceover}
+	}
+	return Post{
+		Caption:  r.Caption,
+		Hashtags: r.Hashtags,
+		Format:   FormatReel,
+		Data:     FormatData{Reel: &Reel{Hook: r.Hook, Scenes: scenes, AudioHint: r.AudioHint}},
+	}
+}
+
+// fromBamlStory uses the frame texts as the caption: stories have no caption
+// of their own, but the text is what gets previewed and sent for approval.
+func fromBamlStory(s types.StorySequence) Post {
+	frames := make([]StoryFrame, len(s.Frames))
+	texts := make([]string, 0, len(s.Frames))
+	for i, f := range s.Frames {
+		frames[i] = StoryFrame{Visual: f.Visual, Text: f.Text, Sticker: f.Sticker}
+		if t := strings.TrimSpace(f.Text); t != "" {
+			texts = append(texts, t)
+		}
+	}
+	return Post{
+		Caption: strings.Join(texts, "\n\n"),
+		Format:  FormatStory,
+		Data:    FormatData{Story: frames},
+	}
+}
+
+func fromBamlCarousel(c types.Carousel) Post {
+	slides := make([]CarouselSlide, len(c.Slides))
+	for i, s := range c.Slides {
+		slides[i] = CarouselSlide{Title: s.Title, Body: s.Body}
+	}
+	return Post{
+		Caption:  c.Caption,
+		Hashtags: c.Hashtags,
+		Format:   FormatCarousel,
+		Data:     FormatData{Carousel: slides},
+	}
+}
+
+func renderFormat(b *strings.Builder, p Post) {
+	switch p.Format {
+	case FormatReel:
+		r := p.Data.Reel
+		if r == nil {
+			return
+		}
+		fmt.Fprintf(b, "[Reel] Gancho: %s\n", r.Hook)
+		for i, s := range r.Scenes {
+			fmt.Fprintf(b, "\nCena %d: %s", i+1, s.Visual)
+			if s.OnScreenText != "" {
+				fmt.Fprintf(b, "\n  Texto na tela: %s", s.OnScreenText)
+			}
+			if s.Voiceover != "" {
+				fmt.Fprintf(b, "\n  Fala: %s", s.Voiceover)
+			}
+		}
+		if r.AudioHint != "" {
+			fmt.Fprintf(b, "\n\nÁudio: %s", r.AudioHint)
+		}
+		b.WriteString("\n\n")
+	case FormatStory:
+		b.WriteString("[Stories]")
+		for i, f := range p.Data.Story {
+			fmt.Fprintf(b, "\n\nStory %d: %s\n  Texto: %s", i+1, f.Visual, f.Text)
+			if f.Sticker != "" {
+				fmt.Fprintf(b, "\n  Sticker: %s", f.Sticker)
+			}
+		}
+	case FormatCarousel:
+		b.WriteString("[Carrossel]")
+		for i, s := range p.Data.Carousel {
+			fmt.Fprintf(b, "\n\nSlide %d: %s", i+1, s.Title)
+			if s.Body != "" {
+				fmt.Fprintf(b, "\n%s", s.Body)
+			}
+		}
+		b.WriteString("\n\n")
+	}
+}
+
+// ClientGuide renders a post's reel script, story frames or carousel slides
+// for the client's WhatsApp, so they know what to film or design. Feed posts
+// return "".
+func ClientGuide(p Post) string {
+	var b strings.Builder
+	switch p.Format {
+	case FormatReel:
+		r := p.Data.Reel
+		if r == nil {
+			return ""
+		}
+		b.WriteString("*Roteiro do reel*")
+		if r.Hook != "" {
+			fmt.Fprintf(&b, "\n\n*Gancho:* %s", r.Hook)
+		}
+		for i, s := range r.Scenes {
+			fmt.Fprintf(&b, "\n\n*Cena %d:* %s", i+1, s.Visual)
+			if s.OnScreenText != "" {
+				fmt.Fprintf(&b, "\nTexto na tela: %s", s.OnScreenText)
+			}
+			if s.Voiceover != "" {
+				fmt.Fprintf(&b, "\nFala: %s", s.Voiceover)
+			}
+		}
+		if r.AudioHint != "" {
+			fmt.Fprintf(&b, "\n\n*Áudio:* %s", r.AudioHint)
+		}
+	case FormatStory:
+		if len(p.Data.Story) == 0 {
+			return ""
+		}
+		b.WriteString("*Sequência de stories*")
+		for i, f := range p.Data.Story {
+			fmt.Fprintf(&b, "\n\n*Story %d:* %s", i+1, f.Visual)
+			if f.Text != "" {
+				fmt.Fprintf(&b, "\nTexto: %s", f.Text)
+			}
+			if f.Sticker != "" {
+				fmt.Fprintf(&b, "\nSticker: %s", f.Sticker)
+			}
+		}
+	case FormatCarousel:
+		if len(p.Data.Carousel) == 0 {
+			return ""
+		}
+		b.WriteString("*Slides do carrossel*")
+		for i, s := range p.Data.Carousel {
+			fmt.Fprintf(&b, "\n\n*Slide %d:* %s", i+1, s.Title)
+			if s.Body != "" {
+				fmt.Fprintf(&b, "\n%s", s.Body)
+			}
+		}
+	}
+	return b.String()
+}
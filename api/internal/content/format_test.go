@@ -0,0 +1,96 @@
+package content
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Format
+		ok   bool
+	}{
+		{"", FormatFeed, true},
+		{"feed", FormatFeed, true},
+		{" Reel ", FormatReel, true},
+		{"story", FormatStory, true},
+		{"carousel", FormatCarousel, true},
+		{"live", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseFormat(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseFormat(%q) = %q, %v; want %q, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestRenderPostsFormats(t *testing.T) {
+	reel := Post{
+		Caption:  "Sábado tem horário.",
+		Hashtags: []string{"nails"},
+		Format:   FormatReel,
+		Data: FormatData{Reel: &Reel{
+			Hook:      "Você lixa errado",
+			Scenes:    []ReelScene{{Visual: "Lixa na mão", OnScreenText: "Errado", Voiceover: "Olha só"}},
+			AudioHint: "voz original",
+		}},
+	}
+	story := Post{
+		Caption: "Chegou esmalte novo",
+		Format:  FormatStory,
+		Data:    FormatData{Story: []StoryFrame{{Visual: "Bancada", Text: "Chegou esmalte novo", Sticker: "enquete"}}},
+	}
+	carousel := Post{
+		Caption: "Salva pra depois.",
+		Format:  FormatCarousel,
+		Data:    FormatData{Carousel: []CarouselSlide{{Title: "3 erros", Body: "Que estragam a unha"}}},
+	}
+
+	tests := []struct {
+		name string
+		post Post
+		want []string
+	}{
+		{"reel", reel, []string{"Gancho: Você lixa errado", "Cena 1: Lixa na mão", "Texto na tela: Errado", "Fala: Olha só", "Áudio: voz original", "Sábado tem horário.", "#nails"}},
+		{"story", story, []string{"Story 1: Bancada", "Texto: Chegou esmalte novo", "Sticker: enquete"}},
+		{"carousel", carousel, []string{"Slide 1: 3 erros", "Que estragam a unha", "Salva pra depois."}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RenderPosts([]Post{tt.post})
+			for _, w := range tt.want {
+				if !strings.Contains(got, w) {
+					t.Errorf("rendered %s missing %q:\n%s", tt.name, w, got)
+				}
+			}
+		})
+	}
+
+	if got := RenderPosts([]Post{story}); strings.Count(got, "Chegou esmalte novo") != 1 {
+		t.Errorf("story caption should not be rendered next to its frames:\n%s", got)
+	}
+}
+
+func TestClientGuide(t *testing.T) {
+	reel := Post{Format: FormatReel, Data: FormatData{Reel: &Reel{
+		Hook:   "Você lixa errado",
+		Scenes: []ReelScene{{Visual: "Lixa na mão", OnScreenText: "Errado", Voiceover: "Olha só"}},
+	}}}
+	got := ClientGuide(reel)
+	for _, want := range []string{"*Roteiro do reel*", "*Gancho:* Você lixa errado", "*Cena 1:* Lixa na mão", "Texto na tela: Errado", "Fala: Olha só"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("reel guide missing %q:\n%s", want, got)
+		}
+	}
+
+	carousel := Post{Format: FormatCarousel, Data: FormatData{Carousel: []CarouselSlide{{Title: "3 erros", Body: "Lixar de um lado só"}}}}
+	if got := ClientGuide(carousel); !strings.Contains(got, "*Slide 1:* 3 erros\nLixar de um lado só") {
+		t.Errorf("carousel guide: %q", got)
+	}
+
+	if got := ClientGuide(Post{Caption: "Só legenda"}); got != "" {
+		t.Errorf("feed guide should be empty, got %q", got)
+	}
+}
@@ -6,12 +6,17 @@ import (
 	"strings"
 
 	baml "github.com/denisraison/rekan/api/internal/baml/baml_client"
+	"github.com/denisraison/rekan/api/internal/hashtag"
 )
 
 type Post struct {
 	Caption        string   `json:"caption"`
 	Hashtags       []string `json:"hashtags"`
 	ProductionNote string   `json:"productionNote"`
+
+	// Format is empty or FormatFeed for regular feed posts.
+	Format Format     `json:"format,omitempty"`
+	Data   FormatData `json:"data,omitzero"`
 }
 
 // CheapMode uses Gemini Flash instead of Opus for generation.
@@ -32,15 +37,15 @@ func generatorOpts() []baml.CallOptionFunc {
 }
 
 func Generate(ctx context.Context, profile BusinessProfile, roles []Role, previousHooks []string) ([]Post, error) {
-	p, err := baml.GenerateContent(ctx, toBamlProfile(profile), toBamlRoles(roles), previousHooks, generatorOpts()...)
+	p, err := baml.GenerateContent(ctx, toBamlProfile(profile), toBamlRoles(roles), previousHooks, profile.StyleMemo, generatorOpts()...)
 	if err != nil {
 		return nil, fmt.Errorf("generate content: %w", err)
 	}
-	return []Post{{
+	return []Post{withHashtags(Post{
 		Caption:        p.Caption,
 		Hashtags:       p.Hashtags,
 		ProductionNote: p.ProductionNote,
-	}}, nil
+	}, profile)}, nil
 }
 
 func GenerateRekan(ctx context.Context, profile BusinessProfile, roles []Role, previousHooks []string) ([]Post, error) {
@@ -48,32 +53,47 @@ func GenerateRekan(ctx context.Context, profile BusinessProfile, roles []Role, p
 	if err != nil {
 		return nil, fmt.Errorf("generate rekan content: %w", err)
 	}
-	return []Post{{
+	return []Post{withHashtags(Post{
 		Caption:        p.Caption,
 		Hashtags:       p.Hashtags,
 		ProductionNote: p.ProductionNote,
-	}}, nil
+	}, profile)}, nil
 }
 
 func GenerateFromMessage(ctx context.Context, profile BusinessProfile, message string, previousHooks []string) (Post, error) {
-	bamlPost, err := baml.GenerateFromMessage(ctx, toBamlProfile(profile), message, previousHooks, generatorOpts()...)
+	bamlPost, err := baml.GenerateFromMessage(ctx, toBamlProfile(profile), message, previousHooks, profile.StyleMemo, generatorOpts()...)
 	if err != nil {
 		return Post{}, fmt.Errorf("generate from message: %w", err)
 	}
-	return Post{
+	return withHashtags(Post{
 		Caption:        bamlPost.Caption,
 		Hashtags:       bamlPost.Hashtags,
 		ProductionNote: bamlPost.ProductionNote,
-	}, nil
+	}, profile), nil
+}
+
+// withHashtags replaces the model's hashtags with the cleaned and curated
+// set for the business. Stories carry no hashtags.
+func withHashtags(p Post, profile BusinessProfile) Post {
+	if p.Format == FormatStory {
+		return p
+	}
+	p.Hashtags = hashtag.Process(p.Hashtags, profile.BusinessType, profile.City)
+	return p
 }
 
 // RenderPosts reconstructs a human-readable text format from structured posts.
-// Used for judge input and verbose display.
+// Used for judge input and verbose display. Reels and carousels render their
+// script or slides before the caption; stories render their frames instead of
+// the caption, which only repeats the frame texts.
 func RenderPosts(posts []Post) string {
 	var parts []string
 	for _, p := range posts {
 		var b strings.Builder
-		b.WriteString(p.Caption)
+		renderFormat(&b, p)
+		if p.Format != FormatStory {
+			b.WriteString(p.Caption)
+		}
 		if len(p.Hashtags) > 0 {
 			b.WriteString("\n\n")
 			for i, h := range p.Hashtags {
@@ -20,6 +20,10 @@ type BusinessProfile struct {
 	TargetAudience string    `json:"targetAudience"`
 	BrandVibe      string    `json:"brandVibe"`
 	Quirks         []string  `json:"quirks"`
+
+	// StyleMemo holds what this client has asked to change in past posts.
+	// It is passed to generation on its own, not as part of the BAML profile.
+	StyleMemo string `json:"styleMemo,omitempty"`
 }
 
 type CheckResult struct {
@@ -35,6 +39,7 @@ func RunChecks(posts []Post) []CheckResult {
 		checkBrazilianPortuguese(rendered),
 		checkCaptionLength(posts),
 		checkProductionNote(posts),
+		checkFormat(posts),
 	}
 }
 
@@ -130,6 +135,10 @@ const maxCaptionLength = 500
 func checkCaptionLength(posts []Post) CheckResult {
 	var reason string
 	for _, p := range posts {
+		// A story's caption is its frame texts, checked by checkFormat.
+		if p.Format == FormatStory {
+			continue
+		}
 		n := len([]rune(p.Caption))
 		if n > maxCaptionLength {
 			return CheckResult{
@@ -146,9 +155,11 @@ func checkCaptionLength(posts []Post) CheckResult {
 	return CheckResult{Name: "caption_length", Pass: true, Reason: reason}
 }
 
+// checkProductionNote passes if any post says how to shoot it. Reels, stories
+// and carousels carry that in their scenes, frames and slides.
 func checkProductionNote(posts []Post) CheckResult {
 	for _, p := range posts {
-		if p.ProductionNote != "" {
+		if p.ProductionNote != "" || (p.Format != "" && p.Format != FormatFeed) {
 			return CheckResult{Name: "production_note", Pass: true}
 		}
 	}
@@ -157,3 +168,85 @@ func checkProductionNote(posts []Post) CheckResult {
 		Reason: "no production notes found",
 	}
 }
+
+const (
+	minReelScenes     = 3
+	maxReelScenes     = 6
+	minStoryFrames    = 3
+	maxStoryFrames    = 5
+	maxStoryText      = 120
+	maxStoryStickers  = 2
+	minCarouselSlides = 4
+	maxCarouselSlides = 8
+	maxSlideBody      = 200
+)
+
+// checkFormat validates the structure of reels, stories and carousels against
+// the limits in their prompts. Feed posts have nothing to check here.
+func checkFormat(posts []Post) CheckResult {
+	for _, p := range posts {
+		if reason := formatProblem(p); reason != "" {
+			return CheckResult{Name: "format", Reason: reason}
+		}
+	}
+	return CheckResult{Name: "format", Pass: true}
+}
+
+func formatProblem(p Post) string {
+	switch p.Format {
+	case "", FormatFeed:
+		return ""
+	case FormatReel:
+		r := p.Data.Reel
+		if r == nil {
+			return "reel has no script"
+		}
+		if strings.TrimSpace(r.Hook) == "" {
+			return "reel has no hook"
+		}
+		if n := len(r.Scenes); n < minReelScenes || n > maxReelScenes {
+			return fmt.Sprintf("reel has %d scenes, want %d-%d", n, minReelScenes, maxReelScenes)
+		}
+		for i, s := range r.Scenes {
+			if strings.TrimSpace(s.Visual) == "" {
+				return fmt.Sprintf("reel scene %d has no visual", i+1)
+			}
+		}
+	case FormatStory:
+		frames := p.Data.Story
+		if n := len(frames); n < minStoryFrames || n > maxStoryFrames {
+			return fmt.Sprintf("story has %d frames, want %d-%d", n, minStoryFrames, maxStoryFrames)
+		}
+		stickers := 0
+		for i, f := range frames {
+			if strings.TrimSpace(f.Visual) == "" {
+				return fmt.Sprintf("story frame %d has no visual", i+1)
+			}
+			if n := len([]rune(f.Text)); n > maxStoryText {
+				return fmt.Sprintf("story frame %d text is %d chars, max %d", i+1, n, maxStoryText)
+			}
+			if f.Sticker != "" {
+				stickers++
+			}
+		}
+		if stickers > maxStoryStickers {
+			return fmt.Sprintf("story has %d stickers, max %d", stickers, maxStoryStickers)
+		}
+	case FormatCarousel:
+		slides := p.Data.Carousel
+		if n := len(slides); n < minCarouselSlides || n > maxCarouselSlides {
+			return fmt.Sprintf("carousel has %d slides, want %d-%d", n, minCarouselSlides, maxCarouselSlides)
+		}
+		for i, s := range slides {
+			if strings.TrimSpace(s.Title) == "" {
+				return fmt.Sprintf("carousel slide %d has no title", i+1)
+			}
+			if n := len([]rune(s.Body)); n > maxSlideBody {
+				return fmt.Sprintf("carousel slide %d body is %d chars, max %d", i+1, n, maxSlideBody)
+			}
+		}
+	default:
+		return fmt.Sprintf("unknown format %q", p.Format)
+	}
+	return ""
+}
@@ -149,3 +149,38 @@ func TestCheckProductionNote(t *testing.T) {
 		}
 	})
 }
+
+func TestCheckFormat(t *testing.T) {
+	scenes := []ReelScene{{Visual: "Mãos passando a base"}, {Visual: "Close no esmalte"}, {Visual: "Unha pronta na luz da janela"}}
+	frames := []StoryFrame{{Visual: "Bancada", Text: "Chegou esmalte novo"}, {Visual: "Vidros", Text: "Qual cor?", Sticker: "enquete"}, {Visual: "Agenda", Text: "Sábado tem horário"}}
+	slides := []CarouselSlide{{Title: "3 erros"}, {Title: "Lixar demais"}, {Title: "Tirar cutícula"}, {Title: "Pular a base"}}
+
+	tests := []struct {
+		name string
+		post Post
+		pass bool
+	}{
+		{"feed", Post{Caption: "Legenda"}, true},
+		{"reel", Post{Format: FormatReel, Data: FormatData{Reel: &Reel{Hook: "Olha isso", Scenes: scenes}}}, true},
+		{"reel without script", Post{Format: FormatReel}, false},
+		{"reel without hook", Post{Format: FormatReel, Data: FormatData{Reel: &Reel{Scenes: scenes}}}, false},
+		{"reel too short", Post{Format: FormatReel, Data: FormatData{Reel: &Reel{Hook: "Olha isso", Scenes: scenes[:2]}}}, false},
+		{"story", Post{Format: FormatStory, Data: FormatData{Story: frames}}, true},
+		{"story text too long", Post{Format: FormatStory, Data: FormatData{Story: append(frames, StoryFrame{Visual: "Vitrine", Text: strings.Repeat("a", 121)})}}, false},
+		{"story too many stickers", Post{Format: FormatStory, Data: FormatData{Story: []StoryFrame{
+			{Visual: "a", Text: "a", Sticker: "enquete"}, {Visual: "b", Text: "b", Sticker: "quiz"}, {Visual: "c", Text: "c", Sticker: "caixinha"},
+		}}}, false},
+		{"carousel", Post{Format: FormatCarousel, Data: FormatData{Carousel: slides}}, true},
+		{"carousel too short", Post{Format: FormatCarousel, Data: FormatData{Carousel: slides[:3]}}, false},
+		{"carousel body too long", Post{Format: FormatCarousel, Data: FormatData{Carousel: append(slides, CarouselSlide{Title: "Fim", Body: strings.Repeat("a", 201)})}}, false},
+		{"unknown", Post{Format: "live"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := checkFormat([]Post{tt.post})
+			if r.Pass != tt.pass {
+				t.Errorf("pass = %v, want %v (reason %q)", r.Pass, tt.pass, r.Reason)
+			}
+		})
+	}
+}
@@ -2,11 +2,16 @@ package content
 
 import "strings"
 
-// ExtractHooks pulls the first sentence of each post's caption.
+// ExtractHooks pulls the first sentence of each post's caption, or the
+// scripted hook for reels.
 func ExtractHooks(posts []Post) []string {
 	hooks := make([]string, 0, len(posts))
 	for _, p := range posts {
-		if h := firstSentence(p.Caption); h != "" {
+		h := firstSentence(p.Caption)
+		if p.Data.Reel != nil && p.Data.Reel.Hook != "" {
+			h = strings.TrimSpace(p.Data.Reel.Hook)
+		}
+		if h != "" {
 			hooks = append(hooks, h)
 		}
 	}
@@ -28,6 +28,19 @@ func TestExtractHooks(t *testing.T) {
 	}
 }
 
+func TestExtractHooksReel(t *testing.T) {
+	posts := []Post{{
+		Caption: "Sábado de agenda cheia. Vem ver o processo.",
+		Format:  FormatReel,
+		Data:    FormatData{Reel: &Reel{Hook: "Você lixa a unha errado e nem sabe"}},
+	}}
+
+	hooks := ExtractHooks(posts)
+	if len(hooks) != 1 || hooks[0] != "Você lixa a unha errado e nem sabe" {
+		t.Errorf("expected the reel hook, got %v", hooks)
+	}
+}
+
 func TestExtractHooksEmpty(t *testing.T) {
 	hooks := ExtractHooks(nil)
 	if len(hooks) != 0 {
@@ -101,6 +101,16 @@ func runJudgeSingle(ctx context.Context, name string, bp types.BusinessProfile,
 
 // RunJudge runs a single judge criterion across all panel models and requires unanimity to pass.
 func RunJudge(ctx context.Context, name string, profile BusinessProfile, content string) (JudgeResult, error) {
+	return runJudgePanel(ctx, name, profile, content, JudgeClients)
+}
+
+// RunCheapJudge runs a judge criterion on the first panel model only. Used by
+// the production quality gate, where a full panel per post costs too much.
+func RunCheapJudge(ctx context.Context, name string, profile BusinessProfile, content string) (JudgeResult, error) {
+	return runJudgePanel(ctx, name, profile, content, JudgeClients[:1])
+}
+
+func runJudgePanel(ctx context.Context, name string, profile BusinessProfile, content string, clients []string) (JudgeResult, error) {
 	bp := toBamlProfile(profile)
 
 	type voteOut struct {
@@ -109,20 +119,20 @@ func RunJudge(ctx context.Context, name string, profile BusinessProfile, content
 		err  error
 	}
 
-	ch := make(chan voteOut, len(JudgeClients))
-	for i, client := range JudgeClients {
+	ch := make(chan voteOut, len(clients))
+	for i, client := range clients {
 		go func(i int, client string) {
 			v, err := runJudgeSingle(ctx, name, bp, content, client)
 			ch <- voteOut{idx: i, vote: v, err: err}
 		}(i, client)
 	}
 
-	votes := make([]Vote, 0, len(JudgeClients))
-	for range JudgeClients {
+	votes := make([]Vote, 0, len(clients))
+	for range clients {
 		out := <-ch
 		if out.err != nil {
 			votes = append(votes, Vote{
-				Client: JudgeClients[out.idx],
+				Client: clients[out.idx],
 				Error:  out.err.Error(),
 			})
 			continue
@@ -0,0 +1,138 @@
+package content
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/denisraison/rekan/api/internal/seasonal"
+)
+
+// PlanSlot is one post in a monthly content plan.
+type PlanSlot struct {
+	Date     time.Time
+	Role     Role
+	Occasion string // seasonal date label, empty for regular posts
+}
+
+// seasonalLead is how many days before a seasonal date its post is planned,
+// so followers see it while there is still time to book or order.
+const seasonalLead = 3
+
+const seasonalRole = "Temporada"
+
+// PlanMonth lays out count posts over the month starting at start for a
+// business of the given type. Posts are spread evenly across the month.
+// Upcoming seasonal dates relevant to the niche take the nearest slot (at
+// most half the plan); the other slots cycle through RolePool so no role
+// repeats before every role has been used. The client's role history orders
+// each round the way PickRolesFor would.
+func PlanMonth(count int, start time.Time, niche string, history RoleHistory) []PlanSlot {
+	if count <= 0 {
+		return nil
+	}
+	start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+	end := start.AddDate(0, 1, 0)
+	days := int(math.Round(end.Sub(start).Hours() / 24))
+
+	slots := make([]PlanSlot, count)
+	for i := range slots {
+		slots[i].Date = start.AddDate(0, 0, i*days/count)
+	}
+
+	type occasion struct {
+		label string
+		date  time.Time
+	}
+	var occasions []occasion
+	for _, sd := range seasonal.Dates {
+		if !sd.ForNiche(niche) {
+			continue
+		}
+		date, ok := sd.Next(start)
+		if !ok || !date.Before(end) {
+			continue
+		}
+		occasions = append(occasions, occasion{label: sd.Label, date: date})
+	}
+	slices.SortFunc(occasions, func(a, b occasion) int { return a.date.Compare(b.date) })
+	if len(occasions) > count/2 {
+		occasions = occasions[:count/2]
+	}
+
+	taken := make([]bool, count)
+	for _, o := range occasions {
+		postDate := o.date.AddDate(0, 0, -seasonalLead)
+		if postDate.Before(start) {
+			postDate = start
+		}
+		best := -1
+		for i, s := range slots {
+			if taken[i] {
+				continue
+			}
+			if best < 0 || absDuration(s.Date.Sub(postDate)) < absDuration(slots[best].Date.Sub(postDate)) {
+				best = i
+			}
+		}
+		taken[best] = true
+		slots[best] = PlanSlot{
+			Date: postDate,
+			Role: Role{
+				Name:        seasonalRole,
+				Description: fmt.Sprintf("%s (%s): um post ligado a essa data, com motivo pro cliente agir antes dela.", o.label, o.date.Format("02/01")),
+			},
+			Occasion: o.label,
+		}
+	}
+
+	var exclude []string
+	if len(occasions) > 0 {
+		exclude = []string{seasonalRole}
+	}
+	roles := cycleRoles(count-len(occasions), exclude, history)
+	for i := range slots {
+		if !taken[i] {
+			slots[i].Role, roles = roles[0], roles[1:]
+		}
+	}
+
+	slices.SortStableFunc(slots, func(a, b PlanSlot) int { return a.Date.Compare(b.Date) })
+	return slots
+}
+
+// cycleRoles returns n roles from the pool in rounds: every role is used once
+// before any repeats. Each round is ordered by pickRolesFrom, with the roles
+// already placed counted as the newest posts, so the client's recent roles
+// come late and a round never starts with the role that ended the previous one.
+func cycleRoles(n int, exclude []string, h RoleHistory) []Role {
+	var pool []Role
+	for _, r := range RolePool {
+		if !slices.Contains(exclude, r.Name) {
+			pool = append(pool, r)
+		}
+	}
+
+	out := make([]Role, 0, n+len(pool))
+	for len(out) < n {
+		round := pickRolesFrom(pool, len(pool), h)
+		if len(out) > 0 && len(round) > 1 && round[0].Name == out[len(out)-1].Name {
+			round[0], round[1] = round[1], round[0]
+		}
+		out = append(out, round...)
+		recent := make([]string, 0, len(round)+len(h.Recent))
+		for i := len(round) - 1; i >= 0; i-- {
+			recent = append(recent, round[i].Name)
+		}
+		h.Recent = append(recent, h.Recent...)
+	}
+	return out[:n]
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
@@ -0,0 +1,96 @@
+package content
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPlanMonth_SizeAndDates(t *testing.T) {
+	start := time.Date(2026, 4, 20, 15, 30, 0, 0, time.UTC)
+	end := time.Date(2026, 5, 20, 0, 0, 0, 0, time.UTC)
+
+	slots := PlanMonth(16, start, "Confeitaria", RoleHistory{})
+	if len(slots) != 16 {
+		t.Fatalf("expected 16 slots, got %d", len(slots))
+	}
+
+	for i, s := range slots {
+		if s.Date.Before(start.Truncate(24*time.Hour)) || !s.Date.Before(end) {
+			t.Errorf("slot %d date %s outside the month", i, s.Date.Format(time.DateOnly))
+		}
+		if i > 0 && s.Date.Before(slots[i-1].Date) {
+			t.Errorf("slot %d out of order: %s before %s", i, s.Date.Format(time.DateOnly), slots[i-1].Date.Format(time.DateOnly))
+		}
+		if s.Role.Name == "" {
+			t.Errorf("slot %d has no role", i)
+		}
+	}
+}
+
+func TestPlanMonth_Seasonal(t *testing.T) {
+	start := time.Date(2026, 4, 20, 0, 0, 0, 0, time.UTC)
+
+	slots := PlanMonth(8, start, "Confeitaria", RoleHistory{})
+
+	var found *PlanSlot
+	for i, s := range slots {
+		if s.Occasion != "" {
+			if found != nil {
+				t.Errorf("unexpected second occasion %q", s.Occasion)
+			}
+			found = &slots[i]
+		} else if s.Role.Name == seasonalRole {
+			t.Errorf("regular slot %d should not use %s when a seasonal date is planned", i, seasonalRole)
+		}
+	}
+	if found == nil {
+		t.Fatal("expected a Dia das Mães slot")
+	}
+	if found.Occasion != "Dia das Mães" {
+		t.Errorf("occasion: got %q, want %q", found.Occasion, "Dia das Mães")
+	}
+	if want := time.Date(2026, 5, 7, 0, 0, 0, 0, time.UTC); !found.Date.Equal(want) {
+		t.Errorf("seasonal date: got %s, want %s", found.Date.Format(time.DateOnly), want.Format(time.DateOnly))
+	}
+	if found.Role.Name != seasonalRole {
+		t.Errorf("seasonal role: got %q, want %q", found.Role.Name, seasonalRole)
+	}
+}
+
+func TestPlanMonth_NoRepeatsUntilPoolUsed(t *testing.T) {
+	// No seasonal date for Barbearia between mid-January and mid-February.
+	start := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+
+	slots := PlanMonth(16, start, "Barbearia", RoleHistory{})
+
+	seen := map[string]bool{}
+	for i, s := range slots {
+		if s.Occasion != "" {
+			t.Fatalf("unexpected occasion %q", s.Occasion)
+		}
+		if i < len(RolePool) {
+			if seen[s.Role.Name] {
+				t.Errorf("role %q repeated before the pool was used", s.Role.Name)
+			}
+			seen[s.Role.Name] = true
+		}
+		if i > 0 && s.Role.Name == slots[i-1].Role.Name {
+			t.Errorf("role %q used twice in a row at slot %d", s.Role.Name, i)
+		}
+	}
+}
+
+func TestPlanMonth_RecentRolesComeLast(t *testing.T) {
+	start := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	recent := []string{"Bastidor", "Útil", "Pessoal"}
+
+	for range 20 {
+		slots := PlanMonth(8, start, "Barbearia", RoleHistory{Recent: recent})
+		for i, s := range slots {
+			if slices.Contains(recent, s.Role.Name) {
+				t.Fatalf("slot %d uses %q, one of the client's last roles", i, s.Role.Name)
+			}
+		}
+	}
+}
@@ -3,6 +3,7 @@ package content
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	baml "github.com/denisraison/rekan/api/internal/baml/baml_client"
 )
@@ -34,6 +35,33 @@ type ProfileSignal struct {
 	Value string
 }
 
+// ExtractProfileFunc extracts profile fields from free text (a transcript or a document).
+type ExtractProfileFunc func(ctx context.Context, text string, businessType string) (PartialBusinessProfile, error)
+
+// Signals flattens an extracted profile into one ProfileSignal per field value,
+// in the same format ExtractProfileSignal produces. Services without a price
+// use "Name|0".
+func (p PartialBusinessProfile) Signals() []ProfileSignal {
+	var signals []ProfileSignal
+	for _, s := range p.Services {
+		price := "0"
+		if s.PriceBRL != nil {
+			price = strconv.FormatFloat(*s.PriceBRL, 'f', 1, 64)
+		}
+		signals = append(signals, ProfileSignal{Field: "services", Value: s.Name + "|" + price})
+	}
+	for _, q := range p.Quirks {
+		signals = append(signals, ProfileSignal{Field: "quirks", Value: q})
+	}
+	if p.TargetAudience != nil && *p.TargetAudience != "" {
+		signals = append(signals, ProfileSignal{Field: "target_audience", Value: *p.TargetAudience})
+	}
+	if p.BrandVibe != nil && *p.BrandVibe != "" {
+		signals = append(signals, ProfileSignal{Field: "brand_vibe", Value: *p.BrandVibe})
+	}
+	return signals
+}
+
 // ExtractSignalFunc checks whether a WhatsApp message contains profile-relevant information.
 type ExtractSignalFunc func(ctx context.Context, message, businessType string) (*ProfileSignal, error)
 
@@ -0,0 +1,44 @@
+package content
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	baml "github.com/denisraison/rekan/api/internal/baml/baml_client"
+)
+
+// Kinds of client reply to a post sent over WhatsApp.
+const (
+	ReplyApproved      = "approved"
+	ReplyChangeRequest = "change_request"
+	ReplyQuestion      = "question"
+	ReplyOther         = "other"
+)
+
+// ClientReply is how a client answered a post. Summary holds the requested
+// change or the question in the client's words; empty otherwise.
+type ClientReply struct {
+	Kind    string
+	Summary string
+}
+
+// ClassifyReplyFunc classifies a client's WhatsApp reply to a post caption.
+type ClassifyReplyFunc func(ctx context.Context, caption, reply string) (ClientReply, error)
+
+// ClassifyClientReply calls Gemini to decide whether reply approves the post,
+// asks for a change or asks a question. Unknown kinds come back as ReplyOther.
+func ClassifyClientReply(ctx context.Context, caption, reply string) (ClientReply, error) {
+	result, err := baml.ClassifyClientReply(ctx, caption, reply)
+	if err != nil {
+		return ClientReply{}, fmt.Errorf("classify client reply: %w", err)
+	}
+
+	kind := strings.ToLower(strings.TrimSpace(result.Kind))
+	switch kind {
+	case ReplyApproved, ReplyChangeRequest, ReplyQuestion:
+	default:
+		kind = ReplyOther
+	}
+	return ClientReply{Kind: kind, Summary: strings.TrimSpace(result.Summary)}, nil
+}
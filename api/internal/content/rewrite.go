@@ -0,0 +1,33 @@
+package content
+
+import (
+	"context"
+	"fmt"
+
+	baml "github.com/denisraison/rekan/api/internal/baml/baml_client"
+	"github.com/denisraison/rekan/api/internal/baml/baml_client/types"
+)
+
+// RewriteFunc rewrites an existing post following a free-form instruction
+// such as "deixa mais curto e divertido".
+type RewriteFunc func(ctx context.Context, profile BusinessProfile, post Post, instruction string, previousHooks []string) (Post, error)
+
+// Rewrite asks the generator to apply instruction to a feed post. Only the
+// caption, hashtags and production note change.
+func Rewrite(ctx context.Context, profile BusinessProfile, post Post, instruction string, previousHooks []string) (Post, error) {
+	in := types.Post{Caption: post.Caption, Hashtags: post.Hashtags, ProductionNote: post.ProductionNote}
+	if in.Hashtags == nil {
+		in.Hashtags = []string{}
+	}
+	p, err := baml.RewritePost(ctx, toBamlProfile(profile), in, instruction, previousHooks, profile.StyleMemo, generatorOpts()...)
+	if err != nil {
+		return Post{}, fmt.Errorf("rewrite post: %w", err)
+	}
+	return withHashtags(Post{
+		Caption:        p.Caption,
+		Hashtags:       p.Hashtags,
+		ProductionNote: p.ProductionNote,
+		Format:         post.Format,
+		Data:           post.Data,
+	}, profile), nil
+}
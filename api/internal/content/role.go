@@ -1,6 +1,9 @@
 package content
 
-import "math/rand/v2"
+import (
+	"math/rand/v2"
+	"slices"
+)
 
 type Role struct {
 	Name        string
@@ -45,3 +48,85 @@ func PickRoles(n int, exclude []string) []Role {
 	})
 	return candidates[:n]
 }
+
+// RoleHistory is what a client's past posts say about content roles.
+type RoleHistory struct {
+	Recent   []string       // roles of the latest posts, newest first
+	Approved map[string]int // approved posts per role
+	Rejected map[string]int // posts sent back with feedback, per role
+}
+
+// recentRoleWindow is how many of the latest posts block their role outright.
+// Roles used further back are down-weighted instead.
+const recentRoleWindow = 3
+
+// PickRolesFor selects n roles for a client. Roles of the last few posts are
+// skipped unless nothing else is left, older repeats are less likely, and
+// roles the client approved more often than rejected are more likely.
+func PickRolesFor(n int, h RoleHistory) []Role {
+	return pickRolesFrom(RolePool, n, h)
+}
+
+// pickRolesFrom is PickRolesFor over a subset of the pool.
+func pickRolesFrom(pool []Role, n int, h RoleHistory) []Role {
+	blocked := h.Recent[:min(len(h.Recent), recentRoleWindow)]
+
+	var fresh, stale []Role
+	for _, r := range pool {
+		if slices.Contains(blocked, r.Name) {
+			stale = append(stale, r)
+		} else {
+			fresh = append(fresh, r)
+		}
+	}
+
+	picked := pickWeighted(n, fresh, h)
+	if len(picked) < n {
+		picked = append(picked, pickWeighted(n-len(picked), stale, h)...)
+	}
+	return picked
+}
+
+// roleWeight is the smoothed approval rate of a role, scaled so a role with
+// no history weighs 1, and divided by how often it shows up in Recent.
+func roleWeight(name string, h RoleHistory) float64 {
+	approved, rejected := h.Approved[name], h.Rejected[name]
+	w := 2 * float64(approved+1) / float64(approved+rejected+2)
+	uses := 0
+	for _, r := range h.Recent {
+		if r == name {
+			uses++
+		}
+	}
+	return w / float64(1+uses)
+}
+
+// pickWeighted draws up to n roles without replacement, each draw
+// proportional to roleWeight.
+func pickWeighted(n int, candidates []Role, h RoleHistory) []Role {
+	candidates = slices.Clone(candidates)
+	weights := make([]float64, len(candidates))
+	for i, r := range candidates {
+		weights[i] = roleWeight(r.Name, h)
+	}
+
+	var picked []Role
+	for len(picked) < n && len(candidates) > 0 {
+		var total float64
+		for _, w := range weights {
+			total += w
+		}
+		x := rand.Float64() * total
+		i := 0
+		for ; i < len(weights)-1; i++ {
+			x -= weights[i]
+			if x < 0 {
+				break
+			}
+		}
+		picked = append(picked, candidates[i])
+		candidates = slices.Delete(candidates, i, i+1)
+		weights = slices.Delete(weights, i, i+1)
+	}
+	return picked
+}
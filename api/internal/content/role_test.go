@@ -0,0 +1,61 @@
+package content
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPickRolesForSkipsRecent(t *testing.T) {
+	h := RoleHistory{Recent: []string{"Bastidor", "Útil", "Pessoal", "Cliente"}}
+
+	for range 50 {
+		roles := PickRolesFor(2, h)
+		if len(roles) != 2 {
+			t.Fatalf("expected 2 roles, got %d", len(roles))
+		}
+		for _, r := range roles {
+			if slices.Contains(h.Recent[:recentRoleWindow], r.Name) {
+				t.Fatalf("picked recently used role %q", r.Name)
+			}
+		}
+		if roles[0].Name == roles[1].Name {
+			t.Fatalf("picked %q twice", roles[0].Name)
+		}
+	}
+}
+
+func TestPickRolesForFallsBackToRecent(t *testing.T) {
+	h := RoleHistory{Recent: []string{"Bastidor", "Útil", "Pessoal"}}
+
+	roles := PickRolesFor(len(RolePool), h)
+	if len(roles) != len(RolePool) {
+		t.Fatalf("expected all %d roles, got %d", len(RolePool), len(roles))
+	}
+	last := roles[len(roles)-recentRoleWindow:]
+	for _, r := range last {
+		if !slices.Contains(h.Recent, r.Name) {
+			t.Errorf("recent roles should only fill the end, got %q there", r.Name)
+		}
+	}
+}
+
+func TestRoleWeight(t *testing.T) {
+	h := RoleHistory{
+		Recent:   []string{"Bastidor", "Opinião", "Opinião"},
+		Approved: map[string]int{"Cliente": 4},
+		Rejected: map[string]int{"Tendência": 4},
+	}
+
+	if w := roleWeight("Marco", h); w != 1 {
+		t.Errorf("role without history: got %v, want 1", w)
+	}
+	if roleWeight("Cliente", h) <= 1 {
+		t.Error("approved role should weigh more than one without history")
+	}
+	if roleWeight("Tendência", h) >= 1 {
+		t.Error("rejected role should weigh less than one without history")
+	}
+	if roleWeight("Opinião", h) >= roleWeight("Bastidor", h) {
+		t.Error("a role used twice recently should weigh less than one used once")
+	}
+}
@@ -0,0 +1,69 @@
+package content
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	baml "github.com/denisraison/rekan/api/internal/baml/baml_client"
+	"github.com/denisraison/rekan/api/internal/baml/baml_client/types"
+)
+
+// RejectedCaption is a post caption that was sent back, with the reason.
+type RejectedCaption struct {
+	Caption  string
+	Feedback string
+}
+
+// EditedCaption is a caption as generated and as the operator left it.
+type EditedCaption struct {
+	Before string
+	After  string
+}
+
+// StyleFeedback is a client's correction history, newest first.
+type StyleFeedback struct {
+	Rejections []RejectedCaption
+	Edits      []EditedCaption
+}
+
+func (f StyleFeedback) Empty() bool {
+	return len(f.Rejections) == 0 && len(f.Edits) == 0
+}
+
+// DistillStyleFunc turns a client's correction history into a style memo:
+// a short list of rules to follow in the next posts.
+type DistillStyleFunc func(ctx context.Context, businessName string, feedback StyleFeedback) (string, error)
+
+func DistillStyleMemo(ctx context.Context, businessName string, feedback StyleFeedback) (string, error) {
+	rejections := make([]types.RejectedCaption, len(feedback.Rejections))
+	for i, r := range feedback.Rejections {
+		rejections[i] = types.RejectedCaption{Caption: r.Caption, Feedback: r.Feedback}
+	}
+	edits := make([]types.EditedCaption, len(feedback.Edits))
+	for i, e := range feedback.Edits {
+		edits[i] = types.EditedCaption{Before: e.Before, After: e.After}
+	}
+
+	memo, err := baml.DistillStyleMemo(ctx, businessName, rejections, edits)
+	if err != nil {
+		return "", fmt.Errorf("distill style memo: %w", err)
+	}
+	return FormatStyleMemo(memo.Rules), nil
+}
+
+// FormatStyleMemo renders memo rules as the bullet list the generators expect.
+func FormatStyleMemo(rules []string) string {
+	var b strings.Builder
+	for _, r := range rules {
+		if r = strings.TrimSpace(r); r == "" {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString("- ")
+		b.WriteString(r)
+	}
+	return b.String()
+}
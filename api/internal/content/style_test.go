@@ -0,0 +1,14 @@
+package content
+
+import "testing"
+
+func TestFormatStyleMemo(t *testing.T) {
+	got := FormatStyleMemo([]string{"Não use emoji de fogo", "  ", " Chame as clientes de meninas "})
+	want := "- Não use emoji de fogo\n- Chame as clientes de meninas"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := FormatStyleMemo(nil); got != "" {
+		t.Errorf("empty rules: got %q", got)
+	}
+}
@@ -0,0 +1,106 @@
+// Package document extracts plain text from documents clients send over
+// WhatsApp (price lists, menus). Everything runs locally in pure Go.
+package document
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxTextLen caps the extracted text (in runes) so a long catalogue doesn't
+// blow up the prompts it is fed into.
+const MaxTextLen = 12000
+
+var (
+	ErrUnsupported = errors.New("document: unsupported format")
+	ErrEncrypted   = errors.New("document: encrypted")
+	ErrNoText      = errors.New("document: no extractable text")
+)
+
+const (
+	mimePDF  = "application/pdf"
+	mimeDOCX = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+)
+
+// Supported reports whether ExtractText can handle the given document.
+func Supported(mimeType, filename string) bool {
+	return kind(nil, mimeType, filename) != ""
+}
+
+// ExtractText returns the plain text of a PDF or DOCX document. The format is
+// detected from the MIME type, the file extension or the content itself.
+// Scanned PDFs (images only) return ErrNoText.
+func ExtractText(data []byte, mimeType, filename string) (string, error) {
+	var (
+		text string
+		err  error
+	)
+	switch kind(data, mimeType, filename) {
+	case "pdf":
+		text, err = extractPDF(data)
+	case "docx":
+		text, err = extractDOCX(data)
+	default:
+		return "", ErrUnsupported
+	}
+	if err != nil {
+		return "", err
+	}
+
+	text = clean(text)
+	if text == "" {
+		return "", ErrNoText
+	}
+	return truncate(text, MaxTextLen), nil
+}
+
+func kind(data []byte, mimeType, filename string) string {
+	mimeType = strings.ToLower(strings.TrimSpace(strings.Split(mimeType, ";")[0]))
+	ext := strings.ToLower(filepath.Ext(filename))
+	switch {
+	case mimeType == mimePDF || ext == ".pdf" || bytes.HasPrefix(data, []byte("%PDF-")):
+		return "pdf"
+	case mimeType == mimeDOCX || ext == ".docx":
+		return "docx"
+	}
+	return ""
+}
+
+var (
+	spaceRun   = regexp.MustCompile(`[ \t\x{00a0}]+`)
+	newlineRun = regexp.MustCompile(`\n{3,}`)
+)
+
+// clean drops control characters, collapses whitespace and trims every line.
+func clean(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.ReplaceAll(s, "\r", "\n")
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r == '\n' || r == '\t':
+			return r
+		case r == utf8.RuneError || unicode.IsControl(r):
+			return -1
+		}
+		return r
+	}, s)
+
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimSpace(spaceRun.ReplaceAllString(l, " "))
+	}
+	s = strings.Join(lines, "\n")
+	return strings.TrimSpace(newlineRun.ReplaceAllString(s, "\n\n"))
+}
+
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
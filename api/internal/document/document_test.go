@@ -0,0 +1,150 @@
+package document
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// buildPDF assembles a minimal PDF from object bodies (1-indexed). A body
+// with a stream uses the "%STREAM%" placeholder where the data goes.
+func buildPDF(objects []string, streams map[int][]byte) []byte {
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.7\n")
+	for i, body := range objects {
+		num := i + 1
+		fmt.Fprintf(&b, "%d 0 obj\n", num)
+		if data, ok := streams[num]; ok {
+			body = strings.Replace(body, "%LEN%", fmt.Sprint(len(data)), 1)
+			b.WriteString(body)
+			b.WriteString("\nstream\n")
+			b.Write(data)
+			b.WriteString("\nendstream")
+		} else {
+			b.WriteString(body)
+		}
+		b.WriteString("\nendobj\n")
+	}
+	b.WriteString("trailer\n<< /Root 1 0 R >>\n%%EOF\n")
+	return b.Bytes()
+}
+
+func deflate(s string) []byte {
+	var b bytes.Buffer
+	w := zlib.NewWriter(&b)
+	w.Write([]byte(s)) //nolint:errcheck
+	w.Close()          //nolint:errcheck
+	return b.Bytes()
+}
+
+func TestExtractText_PDFSimpleFont(t *testing.T) {
+	content := "BT /F1 12 Tf 72 720 Td (Tabela de pre\\347os) Tj 0 -20 Td (Corte feminino) Tj 200 0 Td (R$ 80) Tj " +
+		"0 -20 Td [(Escova)-300(progressiva)] TJ ET"
+	pdf := buildPDF([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 /Resources << /Font << /F1 5 0 R >> >> >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>",
+		"<< /Length %LEN% /Filter /FlateDecode >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+	}, map[int][]byte{4: deflate(content)})
+
+	text, err := ExtractText(pdf, "application/pdf", "precos.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Tabela de preços\nCorte feminino R$ 80\nEscova progressiva"
+	if text != want {
+		t.Errorf("got %q, want %q", text, want)
+	}
+}
+
+func TestExtractText_PDFToUnicode(t *testing.T) {
+	cmap := `/CIDInit /ProcSet findresource begin
+begincmap
+1 begincodespacerange <0000> <FFFF> endcodespacerange
+2 beginbfchar
+<0001> <0043>
+<0002> <00E1>
+endbfchar
+1 beginbfrange
+<0010> <0012> <0061>
+endbfrange
+endcmap`
+	// Glyphs: C á a b c
+	content := "BT /F1 10 Tf 1 0 0 1 50 700 Tm <00010002> Tj <001000110012> Tj ET"
+	pdf := buildPDF([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Resources << /Font << /F1 5 0 R >> >> /Contents [4 0 R] >>",
+		"<< /Length %LEN% >>",
+		"<< /Type /Font /Subtype /Type0 /BaseFont /ABC /Encoding /Identity-H /ToUnicode 6 0 R >>",
+		"<< /Length %LEN% >>",
+	}, map[int][]byte{4: []byte(content), 6: []byte(cmap)})
+
+	text, err := ExtractText(pdf, "", "cardapio.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text != "Cáabc" {
+		t.Errorf("got %q, want %q", text, "Cáabc")
+	}
+}
+
+func TestExtractText_PDFWithoutText(t *testing.T) {
+	pdf := buildPDF([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>",
+		"<< /Length %LEN% >>",
+	}, map[int][]byte{4: []byte("q 100 0 0 100 0 0 cm /Im1 Do Q")})
+
+	if _, err := ExtractText(pdf, "application/pdf", ""); !errors.Is(err, ErrNoText) {
+		t.Errorf("got %v, want ErrNoText", err)
+	}
+}
+
+func TestExtractText_DOCX(t *testing.T) {
+	doc := `<?xml version="1.0" encoding="UTF-8"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>
+<w:p><w:r><w:t>Cardápio</w:t></w:r></w:p>
+<w:tbl><w:tr><w:tc><w:p><w:r><w:t>Brigadeiro</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>R$ 3,50</w:t></w:r></w:p></w:tc></w:tr></w:tbl>
+<w:p><w:r><w:t xml:space="preserve">Encomendas com </w:t></w:r><w:r><w:t>2 dias</w:t></w:r></w:p>
+</w:body></w:document>`
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("word/document.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte(doc)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	text, err := ExtractText(buf.Bytes(), "application/vnd.openxmlformats-officedocument.wordprocessingml.document", "menu.docx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Cardápio\nBrigadeiro R$ 3,50\nEncomendas com 2 dias"
+	if text != want {
+		t.Errorf("got %q, want %q", text, want)
+	}
+}
+
+func TestExtractText_Unsupported(t *testing.T) {
+	if _, err := ExtractText([]byte("hello"), "application/msword", "menu.doc"); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("got %v, want ErrUnsupported", err)
+	}
+	if Supported("image/png", "foto.png") {
+		t.Error("png should not be supported")
+	}
+	if !Supported("", "Lista.PDF") {
+		t.Error("pdf extension should be supported")
+	}
+}
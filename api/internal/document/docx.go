@@ -0,0 +1,89 @@
+package document
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// maxDOCXPart bounds the decompressed size of word/document.xml.
+const maxDOCXPart = 20 << 20
+
+// extractDOCX reads the main document part of a Word file. Paragraphs and
+// table rows become lines, table cells are separated by tabs.
+func extractDOCX(data []byte) (string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("document: open docx: %w", err)
+	}
+
+	var part *zip.File
+	for _, f := range zr.File {
+		if f.Name == "word/document.xml" {
+			part = f
+			break
+		}
+	}
+	if part == nil {
+		return "", ErrUnsupported
+	}
+
+	rc, err := part.Open()
+	if err != nil {
+		return "", fmt.Errorf("document: open docx part: %w", err)
+	}
+	defer rc.Close()
+
+	var b strings.Builder
+	dec := xml.NewDecoder(io.LimitReader(rc, maxDOCXPart))
+	inText := false
+	cellDepth := 0 // paragraphs inside table cells stay on the row's line
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("document: parse docx: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tc":
+				cellDepth++
+			case "tab":
+				b.WriteByte('\t')
+			case "br", "cr":
+				b.WriteByte('\n')
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				if cellDepth > 0 {
+					b.WriteByte(' ')
+				} else {
+					b.WriteByte('\n')
+				}
+			case "tr":
+				b.WriteByte('\n')
+			case "tc":
+				cellDepth--
+				b.WriteByte('\t')
+			}
+		case xml.CharData:
+			if inText {
+				b.Write(t)
+			}
+		}
+	}
+	return b.String(), nil
+}
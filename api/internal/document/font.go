@@ -0,0 +1,185 @@
+package document
+
+import (
+	"unicode/utf16"
+)
+
+// pdfFont decodes the bytes of a text-showing operator into Unicode.
+// A ToUnicode CMap wins; simple fonts fall back to their /Differences and
+// then to WinAnsi or MacRoman, which covers Portuguese accents.
+type pdfFont struct {
+	codeBytes   int // 2 for composite (Type0) fonts
+	toUnicode   map[uint32]string
+	differences map[byte]rune
+	macRoman    bool
+}
+
+// defaultFont is used when text is shown before any Tf operator.
+var defaultFont = &pdfFont{codeBytes: 1}
+
+func (f *pdfFont) decode(s []byte) string {
+	var out []rune
+	step := f.codeBytes
+	if step < 1 {
+		step = 1
+	}
+	for i := 0; i+step <= len(s); i += step {
+		var code uint32
+		for _, c := range s[i : i+step] {
+			code = code<<8 | uint32(c)
+		}
+		if u, ok := f.toUnicode[code]; ok {
+			out = append(out, []rune(u)...)
+			continue
+		}
+		if step > 1 {
+			// Composite font without a usable CMap: the codes are glyph IDs.
+			continue
+		}
+		out = append(out, f.simpleRune(byte(code)))
+	}
+	return string(out)
+}
+
+func (f *pdfFont) simpleRune(c byte) rune {
+	if r, ok := f.differences[c]; ok {
+		return r
+	}
+	switch {
+	case c < 0x80:
+		return rune(c)
+	case f.macRoman:
+		return macRomanHigh[c-0x80]
+	case c < 0xa0:
+		return winAnsiC1[c-0x80]
+	}
+	return rune(c) // WinAnsi matches Latin-1 from 0xA0 up
+}
+
+// parseToUnicode reads bfchar and bfrange mappings from a ToUnicode CMap.
+func (f *pdfFont) parseToUnicode(cmap []byte) {
+	f.toUnicode = map[uint32]string{}
+	p := newPDFParser(cmap)
+	width := 0
+
+	code := func(b []byte) uint32 {
+		var c uint32
+		for _, x := range b {
+			c = c<<8 | uint32(x)
+		}
+		return c
+	}
+
+	for {
+		v, ok := p.value()
+		if !ok {
+			break
+		}
+		switch v {
+		case pdfOp("begincodespacerange"):
+			for {
+				lo, ok := p.value()
+				if !ok || lo == pdfOp("endcodespacerange") {
+					break
+				}
+				p.value() // hi
+				if b, ok := lo.([]byte); ok && width == 0 {
+					width = len(b)
+				}
+			}
+		case pdfOp("beginbfchar"):
+			for {
+				src, ok := p.value()
+				if !ok || src == pdfOp("endbfchar") {
+					break
+				}
+				dst, _ := p.value()
+				s, ok1 := src.([]byte)
+				d, ok2 := dst.([]byte)
+				if ok1 && ok2 {
+					f.toUnicode[code(s)] = utf16BE(d)
+					width = max(width, len(s))
+				}
+			}
+		case pdfOp("beginbfrange"):
+			for {
+				lo, ok := p.value()
+				if !ok || lo == pdfOp("endbfrange") {
+					break
+				}
+				hi, _ := p.value()
+				dst, _ := p.value()
+				l, ok1 := lo.([]byte)
+				h, ok2 := hi.([]byte)
+				if !ok1 || !ok2 || code(h) < code(l) || code(h)-code(l) > 0xffff {
+					continue
+				}
+				width = max(width, len(l))
+				switch d := dst.(type) {
+				case []byte:
+					base := []rune(utf16BE(d))
+					if len(base) == 0 {
+						continue
+					}
+					for c := code(l); c <= code(h); c++ {
+						r := append([]rune{}, base...)
+						r[len(r)-1] += rune(c - code(l))
+						f.toUnicode[c] = string(r)
+					}
+				case []any:
+					for i, item := range d {
+						if b, ok := item.([]byte); ok {
+							f.toUnicode[code(l)+uint32(i)] = utf16BE(b)
+						}
+					}
+				}
+			}
+		}
+	}
+	if width > 0 {
+		f.codeBytes = width
+	}
+}
+
+func utf16BE(b []byte) string {
+	u := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		u = append(u, uint16(b[i])<<8|uint16(b[i+1]))
+	}
+	return string(utf16.Decode(u))
+}
+
+// winAnsiC1 maps WinAnsiEncoding 0x80-0x9F, where it differs from Latin-1.
+var winAnsiC1 = []rune("€\ufffd‚ƒ„…†‡ˆ‰Š‹Œ\ufffdŽ\ufffd\ufffd‘’“”•–—˜™š›œ\ufffdžŸ")
+
+// macRomanHigh maps MacRomanEncoding 0x80-0xFF.
+var macRomanHigh = []rune("ÄÅÇÉÑÖÜáàâäãåçéèêëíìîïñóòôöõúùûü†°¢£§•¶ß®©™´¨≠ÆØ∞±≤≥¥µ∂∑∏π∫ªºΩæø¿¡¬√ƒ≈∆«»…\u00a0ÀÃÕŒœ–—“”‘’÷◊ÿŸ⁄€‹›ﬁﬂ‡·‚„‰ÂÊÁËÈÍÎÏÌÓÔÒÚÛÙıˆ˜¯˘˙˚¸˝˛ˇ")
+
+// glyphNames covers the glyph names used in /Differences arrays for
+// Portuguese text. Unknown names fall back to the base encoding.
+var glyphNames = map[string]rune{
+	"space": ' ', "exclam": '!', "quotedbl": '"', "numbersign": '#', "dollar": '$',
+	"percent": '%', "ampersand": '&', "quotesingle": '\'', "quoteright": '\u2019',
+	"quoteleft": '\u2018', "parenleft": '(', "parenright": ')', "asterisk": '*',
+	"plus": '+', "comma": ',', "hyphen": '-', "minus": '-', "period": '.', "slash": '/',
+	"zero": '0', "one": '1', "two": '2', "three": '3', "four": '4', "five": '5',
+	"six": '6', "seven": '7', "eight": '8', "nine": '9', "colon": ':', "semicolon": ';',
+	"less": '<', "equal": '=', "greater": '>', "question": '?', "at": '@',
+	"bracketleft": '[', "backslash": '\\', "bracketright": ']', "underscore": '_',
+	"bullet": '\u2022', "endash": '\u2013', "emdash": '\u2014', "ellipsis": '\u2026',
+	"quotedblleft": '\u201c', "quotedblright": '\u201d', "degree": '\u00b0',
+	"ordfeminine": '\u00aa', "ordmasculine": '\u00ba', "Euro": '\u20ac',
+	"aacute": 'á', "agrave": 'à', "acircumflex": 'â', "atilde": 'ã', "adieresis": 'ä',
+	"eacute": 'é', "egrave": 'è', "ecircumflex": 'ê', "iacute": 'í', "ocircumflex": 'ô',
+	"oacute": 'ó', "otilde": 'õ', "uacute": 'ú', "udieresis": 'ü', "ccedilla": 'ç',
+	"Aacute": 'Á', "Agrave": 'À', "Acircumflex": 'Â', "Atilde": 'Ã', "Eacute": 'É',
+	"Ecircumflex": 'Ê', "Iacute": 'Í', "Oacute": 'Ó', "Ocircumflex": 'Ô', "Otilde": 'Õ',
+	"Uacute": 'Ú', "Ccedilla": 'Ç', "fi": '\ufb01', "fl": '\ufb02',
+}
+
+func init() {
+	for c := 'a'; c <= 'z'; c++ {
+		glyphNames[string(c)] = c
+		glyphNames[string(c-32)] = c - 32
+	}
+}
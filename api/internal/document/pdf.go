@@ -0,0 +1,544 @@
+package document
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	maxPDFStream  = 32 << 20 // decompressed bytes per stream
+	maxFormDepth  = 5        // nested form XObjects
+	lineTolerance = 1.0      // vertical text-space units before a new line starts
+)
+
+// pdfObject is an indirect object: its parsed value and decoded stream, if any.
+type pdfObject struct {
+	raw     []byte // object body without the stream
+	stream  []byte // decoded stream data, nil when absent or undecodable
+	value   any
+	parsed  bool
+	hasData bool
+}
+
+type pdfDoc struct {
+	objects map[int]*pdfObject
+	fonts   map[int]*pdfFont // by object number
+}
+
+var objHeader = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+
+// extractPDF returns the text of every page, in page order. It does not rely
+// on the xref table, so slightly damaged files still yield their text.
+func extractPDF(data []byte) (string, error) {
+	doc := &pdfDoc{objects: map[int]*pdfObject{}, fonts: map[int]*pdfFont{}}
+	doc.scanObjects(data)
+	if len(doc.objects) == 0 {
+		return "", fmt.Errorf("document: no PDF objects found")
+	}
+	if doc.encrypted(data) {
+		return "", ErrEncrypted
+	}
+	doc.expandObjectStreams()
+
+	var pages []string
+	for _, page := range doc.pages() {
+		var b strings.Builder
+		res, _ := doc.resolve(doc.inherited(page, "Resources")).(pdfDict)
+		for _, content := range doc.contentStreams(page["Contents"]) {
+			doc.runContent(&b, content, res, 0)
+		}
+		if text := strings.TrimSpace(b.String()); text != "" {
+			pages = append(pages, text)
+		}
+	}
+	return strings.Join(pages, "\n\n"), nil
+}
+
+// scanObjects finds every "n g obj ... endobj" in the file. Later
+// definitions win, which matches how incremental updates work.
+func (d *pdfDoc) scanObjects(data []byte) {
+	for _, m := range objHeader.FindAllSubmatchIndex(data, -1) {
+		num, err := strconv.Atoi(string(data[m[2]:m[3]]))
+		if err != nil {
+			continue
+		}
+		bodyStart := m[1]
+		end := bytes.Index(data[bodyStart:], []byte("endobj"))
+		streamAt := bytes.Index(data[bodyStart:], []byte("stream"))
+
+		obj := &pdfObject{}
+		if streamAt >= 0 && (end < 0 || streamAt < end) {
+			obj.raw = data[bodyStart : bodyStart+streamAt]
+			obj.stream, obj.hasData = d.readStream(data, bodyStart+streamAt+len("stream"), obj)
+		} else {
+			if end < 0 {
+				continue
+			}
+			obj.raw = data[bodyStart : bodyStart+end]
+		}
+		d.objects[num] = obj
+	}
+}
+
+// readStream returns the decoded stream starting at pos (just after the
+// "stream" keyword).
+func (d *pdfDoc) readStream(data []byte, pos int, obj *pdfObject) ([]byte, bool) {
+	if pos < len(data) && data[pos] == '\r' {
+		pos++
+	}
+	if pos < len(data) && data[pos] == '\n' {
+		pos++
+	}
+
+	dict, _ := d.parse(obj).(pdfDict)
+	raw := []byte(nil)
+	if n, ok := dict["Length"].(float64); ok && n >= 0 && pos+int(n) <= len(data) {
+		after := bytes.TrimLeft(data[pos+int(n):min(pos+int(n)+20, len(data))], " \r\n\t")
+		if bytes.HasPrefix(after, []byte("endstream")) {
+			raw = data[pos : pos+int(n)]
+		}
+	}
+	if raw == nil {
+		end := bytes.Index(data[pos:], []byte("endstream"))
+		if end < 0 {
+			return nil, false
+		}
+		raw = bytes.TrimRight(data[pos:pos+end], "\r\n")
+	}
+
+	decoded, ok := decodeStream(raw, dict)
+	return decoded, ok
+}
+
+// decodeStream applies the stream filters. Only FlateDecode is supported;
+// image filters and anything exotic leave the stream undecoded.
+func decodeStream(raw []byte, dict pdfDict) ([]byte, bool) {
+	var filters []any
+	switch f := dict["Filter"].(type) {
+	case nil:
+		return raw, true
+	case pdfName:
+		filters = []any{f}
+	case []any:
+		filters = f
+	}
+
+	out := raw
+	for _, f := range filters {
+		if name, _ := f.(pdfName); name != "FlateDecode" && name != "Fl" {
+			return nil, false
+		}
+		zr, err := zlib.NewReader(bytes.NewReader(out))
+		if err != nil {
+			return nil, false
+		}
+		decoded, err := io.ReadAll(io.LimitReader(zr, maxPDFStream))
+		zr.Close()
+		// Truncated streams are common; keep whatever inflated cleanly.
+		if err != nil && len(decoded) == 0 {
+			return nil, false
+		}
+		out = decoded
+	}
+	return out, true
+}
+
+func (d *pdfDoc) parse(obj *pdfObject) any {
+	if !obj.parsed {
+		obj.parsed = true
+		obj.value, _ = newPDFParser(obj.raw).value()
+	}
+	return obj.value
+}
+
+// resolve follows indirect references.
+func (d *pdfDoc) resolve(v any) any {
+	for range 10 {
+		ref, ok := v.(pdfRef)
+		if !ok {
+			return v
+		}
+		obj := d.objects[ref.num]
+		if obj == nil {
+			return nil
+		}
+		v = d.parse(obj)
+	}
+	return nil
+}
+
+func (d *pdfDoc) stream(v any) []byte {
+	ref, ok := v.(pdfRef)
+	if !ok {
+		return nil
+	}
+	if obj := d.objects[ref.num]; obj != nil {
+		return obj.stream
+	}
+	return nil
+}
+
+func (d *pdfDoc) encrypted(data []byte) bool {
+	for _, obj := range d.objects {
+		if dict, ok := d.parse(obj).(pdfDict); ok {
+			if _, ok := dict["Encrypt"]; ok {
+				return true
+			}
+		}
+	}
+	if i := bytes.LastIndex(data, []byte("trailer")); i >= 0 {
+		return bytes.Contains(data[i:], []byte("/Encrypt"))
+	}
+	return false
+}
+
+// expandObjectStreams unpacks objects stored inside /Type /ObjStm streams
+// (PDF 1.5+), which is where most generators put font dictionaries.
+func (d *pdfDoc) expandObjectStreams() {
+	nums := make([]int, 0, len(d.objects))
+	for n := range d.objects {
+		nums = append(nums, n)
+	}
+	sort.Ints(nums)
+
+	for _, n := range nums {
+		obj := d.objects[n]
+		dict, ok := d.parse(obj).(pdfDict)
+		if !ok || dict["Type"] != pdfName("ObjStm") || obj.stream == nil {
+			continue
+		}
+		count, _ := dict["N"].(float64)
+		first, _ := dict["First"].(float64)
+		if int(first) > len(obj.stream) {
+			continue
+		}
+
+		header := newPDFParser(obj.stream[:int(first)])
+		type entry struct{ num, off int }
+		var entries []entry
+		for range int(count) {
+			a, ok1 := header.value()
+			b, ok2 := header.value()
+			an, _ := a.(float64)
+			bn, _ := b.(float64)
+			if !ok1 || !ok2 {
+				break
+			}
+			entries = append(entries, entry{int(an), int(first) + int(bn)})
+		}
+		for i, e := range entries {
+			end := len(obj.stream)
+			if i+1 < len(entries) {
+				end = entries[i+1].off
+			}
+			if e.off > end || e.off > len(obj.stream) {
+				continue
+			}
+			// Objects defined directly in the file take precedence.
+			if _, exists := d.objects[e.num]; !exists {
+				d.objects[e.num] = &pdfObject{raw: obj.stream[e.off:end]}
+			}
+		}
+	}
+}
+
+// pages returns page dictionaries in document order, walking the page tree
+// from the catalog and falling back to every /Type /Page object.
+func (d *pdfDoc) pages() []pdfDict {
+	var out []pdfDict
+	seen := map[int]bool{}
+	var walk func(v any, depth int)
+	walk = func(v any, depth int) {
+		if depth > 32 {
+			return
+		}
+		if ref, ok := v.(pdfRef); ok {
+			if seen[ref.num] {
+				return
+			}
+			seen[ref.num] = true
+		}
+		node, ok := d.resolve(v).(pdfDict)
+		if !ok {
+			return
+		}
+		switch node["Type"] {
+		case pdfName("Pages"):
+			kids, _ := d.resolve(node["Kids"]).([]any)
+			for _, k := range kids {
+				walk(k, depth+1)
+			}
+		case pdfName("Page"):
+			out = append(out, node)
+		}
+	}
+
+	nums := d.sortedObjectNumbers()
+	for _, n := range nums {
+		if dict, ok := d.parse(d.objects[n]).(pdfDict); ok && dict["Type"] == pdfName("Catalog") {
+			walk(dict["Pages"], 0)
+			break
+		}
+	}
+	if len(out) > 0 {
+		return out
+	}
+
+	for _, n := range nums {
+		if dict, ok := d.parse(d.objects[n]).(pdfDict); ok && dict["Type"] == pdfName("Page") {
+			out = append(out, dict)
+		}
+	}
+	return out
+}
+
+func (d *pdfDoc) sortedObjectNumbers() []int {
+	nums := make([]int, 0, len(d.objects))
+	for n := range d.objects {
+		nums = append(nums, n)
+	}
+	sort.Ints(nums)
+	return nums
+}
+
+// inherited looks a page attribute up the /Parent chain.
+func (d *pdfDoc) inherited(page pdfDict, key pdfName) any {
+	node := page
+	for range 32 {
+		if v, ok := node[key]; ok {
+			return v
+		}
+		parent, ok := d.resolve(node["Parent"]).(pdfDict)
+		if !ok {
+			return nil
+		}
+		node = parent
+	}
+	return nil
+}
+
+func (d *pdfDoc) contentStreams(v any) [][]byte {
+	if ref, ok := v.(pdfRef); ok {
+		if s := d.stream(ref); s != nil {
+			return [][]byte{s}
+		}
+		v = d.resolve(ref)
+	}
+	arr, _ := v.([]any)
+	var out [][]byte
+	for _, item := range arr {
+		if s := d.stream(item); s != nil {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// textState tracks the text line matrix, enough to tell when text moves
+// to a new line.
+type textState struct {
+	a, b, c, d, e, f float64
+	leading          float64
+	font             *pdfFont
+	lastY            float64
+	started          bool
+}
+
+func (ts *textState) moveLine(tx, ty float64) {
+	ts.e += tx*ts.a + ty*ts.c
+	ts.f += tx*ts.b + ty*ts.d
+}
+
+// runContent interprets a content stream and writes its text to b.
+func (d *pdfDoc) runContent(b *strings.Builder, content []byte, res pdfDict, depth int) {
+	fonts, _ := d.resolve(res["Font"]).(pdfDict)
+	xobjects, _ := d.resolve(res["XObject"]).(pdfDict)
+	ts := &textState{a: 1, d: 1}
+
+	// breakIfMoved starts a new line or word when the position changed.
+	breakIfMoved := func() {
+		if !ts.started {
+			ts.started = true
+			ts.lastY = ts.f
+			return
+		}
+		if math.Abs(ts.f-ts.lastY) > lineTolerance {
+			b.WriteByte('\n')
+			ts.lastY = ts.f
+			return
+		}
+		if s := b.String(); s != "" && !strings.HasSuffix(s, " ") && !strings.HasSuffix(s, "\n") {
+			b.WriteByte(' ')
+		}
+	}
+	show := func(s []byte) {
+		if ts.font == nil {
+			ts.font = defaultFont
+		}
+		b.WriteString(ts.font.decode(s))
+	}
+
+	p := newPDFParser(content)
+	var operands []any
+	for {
+		v, ok := p.value()
+		if !ok {
+			return
+		}
+		op, isOp := v.(pdfOp)
+		if !isOp {
+			operands = append(operands, v)
+			continue
+		}
+
+		num := func(i int) float64 {
+			if i < len(operands) {
+				n, _ := operands[i].(float64)
+				return n
+			}
+			return 0
+		}
+		last := func() any {
+			if len(operands) == 0 {
+				return nil
+			}
+			return operands[len(operands)-1]
+		}
+
+		switch op {
+		case "BT":
+			ts.a, ts.b, ts.c, ts.d, ts.e, ts.f = 1, 0, 0, 1, 0, 0
+		case "Tf":
+			if len(operands) >= 1 {
+				name, _ := operands[0].(pdfName)
+				ts.font = d.font(fonts[name])
+			}
+		case "TL":
+			ts.leading = num(0)
+		case "Td":
+			ts.moveLine(num(0), num(1))
+			breakIfMoved()
+		case "TD":
+			ts.leading = -num(1)
+			ts.moveLine(num(0), num(1))
+			breakIfMoved()
+		case "Tm":
+			if len(operands) >= 6 {
+				ts.a, ts.b, ts.c, ts.d, ts.e, ts.f = num(0), num(1), num(2), num(3), num(4), num(5)
+			}
+			breakIfMoved()
+		case "T*":
+			ts.moveLine(0, -ts.leading)
+			b.WriteByte('\n')
+			ts.lastY = ts.f
+		case "Tj":
+			if s, ok := last().([]byte); ok {
+				show(s)
+			}
+		case "'", "\"":
+			ts.moveLine(0, -ts.leading)
+			b.WriteByte('\n')
+			ts.lastY = ts.f
+			if s, ok := last().([]byte); ok {
+				show(s)
+			}
+		case "TJ":
+			arr, _ := last().([]any)
+			for _, item := range arr {
+				switch v := item.(type) {
+				case []byte:
+					show(v)
+				case float64:
+					// Large negative kerning is how many generators encode spaces.
+					if v < -200 {
+						b.WriteByte(' ')
+					}
+				}
+			}
+		case "ET":
+			ts.font = nil
+		case "Do":
+			if depth >= maxFormDepth || len(operands) == 0 {
+				break
+			}
+			name, _ := operands[0].(pdfName)
+			ref := xobjects[name]
+			form, _ := d.resolve(ref).(pdfDict)
+			if form["Subtype"] != pdfName("Form") {
+				break
+			}
+			formRes, ok := d.resolve(form["Resources"]).(pdfDict)
+			if !ok {
+				formRes = res
+			}
+			if s := d.stream(ref); s != nil {
+				b.WriteByte('\n')
+				d.runContent(b, s, formRes, depth+1)
+				b.WriteByte('\n')
+			}
+		case "ID":
+			p.skipInlineImage()
+		}
+		operands = operands[:0]
+	}
+}
+
+// font loads and caches the decoder for a font reference.
+func (d *pdfDoc) font(v any) *pdfFont {
+	ref, isRef := v.(pdfRef)
+	if isRef {
+		if f, ok := d.fonts[ref.num]; ok {
+			return f
+		}
+	}
+	dict, _ := d.resolve(v).(pdfDict)
+	f := d.loadFont(dict)
+	if isRef {
+		d.fonts[ref.num] = f
+	}
+	return f
+}
+
+func (d *pdfDoc) loadFont(dict pdfDict) *pdfFont {
+	f := &pdfFont{codeBytes: 1}
+	if dict == nil {
+		return f
+	}
+	if dict["Subtype"] == pdfName("Type0") {
+		f.codeBytes = 2
+	}
+	if cmap := d.stream(dict["ToUnicode"]); cmap != nil {
+		f.parseToUnicode(cmap)
+	}
+
+	switch enc := d.resolve(dict["Encoding"]).(type) {
+	case pdfName:
+		f.macRoman = enc == "MacRomanEncoding"
+	case pdfDict:
+		f.macRoman = enc["BaseEncoding"] == pdfName("MacRomanEncoding")
+		diffs, _ := d.resolve(enc["Differences"]).([]any)
+		code := 0
+		for _, item := range diffs {
+			switch v := item.(type) {
+			case float64:
+				code = int(v)
+			case pdfName:
+				if r, ok := glyphNames[string(v)]; ok {
+					if f.differences == nil {
+						f.differences = map[byte]rune{}
+					}
+					f.differences[byte(code)] = r
+				}
+				code++
+			}
+		}
+	}
+	return f
+}
@@ -0,0 +1,324 @@
+package document
+
+import (
+	"bytes"
+	"strconv"
+)
+
+// PDF object model, just enough to walk pages and decode text.
+type (
+	pdfName string
+	pdfRef  struct{ num, gen int }
+	pdfOp   string // bare keyword: content stream operator, R, obj, true, null...
+	pdfDict map[pdfName]any
+)
+
+// pdfLexer tokenizes PDF object syntax and content streams.
+type pdfLexer struct {
+	data []byte
+	pos  int
+}
+
+type tokKind int
+
+const (
+	tokEOF tokKind = iota
+	tokNumber
+	tokName
+	tokString
+	tokKeyword
+	tokDictStart
+	tokDictEnd
+	tokArrayStart
+	tokArrayEnd
+)
+
+type token struct {
+	kind tokKind
+	num  float64
+	str  []byte // name, string or keyword bytes
+	end  int    // offset just past the token
+}
+
+func isWhite(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\f' || c == 0
+}
+
+func isDelim(c byte) bool {
+	switch c {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
+
+func (l *pdfLexer) skipSpace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		switch {
+		case isWhite(c):
+			l.pos++
+		case c == '%':
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (l *pdfLexer) next() token {
+	t := l.scan()
+	t.end = l.pos
+	return t
+}
+
+func (l *pdfLexer) scan() token {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return token{kind: tokEOF}
+	}
+	c := l.data[l.pos]
+	switch {
+	case c == '/':
+		l.pos++
+		start := l.pos
+		for l.pos < len(l.data) && !isWhite(l.data[l.pos]) && !isDelim(l.data[l.pos]) {
+			l.pos++
+		}
+		return token{kind: tokName, str: decodeNameEscapes(l.data[start:l.pos])}
+	case c == '(':
+		l.pos++
+		return token{kind: tokString, str: l.literalString()}
+	case c == '<':
+		if l.pos+1 < len(l.data) && l.data[l.pos+1] == '<' {
+			l.pos += 2
+			return token{kind: tokDictStart}
+		}
+		l.pos++
+		return token{kind: tokString, str: l.hexString()}
+	case c == '>':
+		l.pos++
+		if l.pos < len(l.data) && l.data[l.pos] == '>' {
+			l.pos++
+		}
+		return token{kind: tokDictEnd}
+	case c == '[':
+		l.pos++
+		return token{kind: tokArrayStart}
+	case c == ']':
+		l.pos++
+		return token{kind: tokArrayEnd}
+	case c == '{' || c == '}' || c == ')':
+		l.pos++
+		return token{kind: tokKeyword, str: []byte{c}}
+	}
+
+	start := l.pos
+	for l.pos < len(l.data) && !isWhite(l.data[l.pos]) && !isDelim(l.data[l.pos]) {
+		l.pos++
+	}
+	word := l.data[start:l.pos]
+	if n, err := strconv.ParseFloat(string(word), 64); err == nil && (word[0] == '-' || word[0] == '+' || word[0] == '.' || (word[0] >= '0' && word[0] <= '9')) {
+		return token{kind: tokNumber, num: n}
+	}
+	return token{kind: tokKeyword, str: word}
+}
+
+func decodeNameEscapes(b []byte) []byte {
+	if bytes.IndexByte(b, '#') < 0 {
+		return b
+	}
+	out := make([]byte, 0, len(b))
+	for i := 0; i < len(b); i++ {
+		if b[i] == '#' && i+2 < len(b) {
+			if v, err := strconv.ParseUint(string(b[i+1:i+3]), 16, 8); err == nil {
+				out = append(out, byte(v))
+				i += 2
+				continue
+			}
+		}
+		out = append(out, b[i])
+	}
+	return out
+}
+
+func (l *pdfLexer) literalString() []byte {
+	var out []byte
+	depth := 1
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return out
+			}
+		case '\\':
+			if l.pos >= len(l.data) {
+				return out
+			}
+			e := l.data[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				out = append(out, '\n')
+			case 'r':
+				out = append(out, '\r')
+			case 't':
+				out = append(out, '\t')
+			case 'b':
+				out = append(out, '\b')
+			case 'f':
+				out = append(out, '\f')
+			case '\r':
+				if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+			case '\n':
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for i := 0; i < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						v = v*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					out = append(out, byte(v))
+				} else {
+					out = append(out, e)
+				}
+			}
+			continue
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+func (l *pdfLexer) hexString() []byte {
+	var digits []byte
+	for l.pos < len(l.data) && l.data[l.pos] != '>' {
+		c := l.data[l.pos]
+		if (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F') {
+			digits = append(digits, c)
+		}
+		l.pos++
+	}
+	l.pos++ // '>'
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	out := make([]byte, len(digits)/2)
+	for i := range out {
+		v, _ := strconv.ParseUint(string(digits[2*i:2*i+2]), 16, 8)
+		out[i] = byte(v)
+	}
+	return out
+}
+
+// pdfParser builds values from lexer tokens, with one token of lookahead
+// beyond the current one to recognise "num gen R" references.
+type pdfParser struct {
+	lex     *pdfLexer
+	buf     []token
+	lastEnd int // end offset of the last token taken
+}
+
+func newPDFParser(data []byte) *pdfParser {
+	return &pdfParser{lex: &pdfLexer{data: data}}
+}
+
+func (p *pdfParser) peek(i int) token {
+	for len(p.buf) <= i {
+		p.buf = append(p.buf, p.lex.next())
+	}
+	return p.buf[i]
+}
+
+func (p *pdfParser) take() token {
+	t := p.peek(0)
+	p.buf = p.buf[1:]
+	p.lastEnd = t.end
+	return t
+}
+
+// value parses the next complete value. Keywords come back as pdfOp, and
+// a stray closing delimiter comes back as nil.
+func (p *pdfParser) value() (any, bool) {
+	t := p.take()
+	switch t.kind {
+	case tokEOF:
+		return nil, false
+	case tokNumber:
+		// "num gen R" is an indirect reference.
+		if g := p.peek(0); g.kind == tokNumber {
+			if r := p.peek(1); r.kind == tokKeyword && string(r.str) == "R" {
+				p.take()
+				p.take()
+				return pdfRef{num: int(t.num), gen: int(g.num)}, true
+			}
+		}
+		return t.num, true
+	case tokName:
+		return pdfName(t.str), true
+	case tokString:
+		return t.str, true
+	case tokArrayStart:
+		var arr []any
+		for {
+			if k := p.peek(0).kind; k == tokArrayEnd || k == tokEOF {
+				p.take()
+				return arr, true
+			}
+			v, ok := p.value()
+			if !ok {
+				return arr, true
+			}
+			arr = append(arr, v)
+		}
+	case tokDictStart:
+		d := pdfDict{}
+		for {
+			k := p.take()
+			if k.kind == tokDictEnd || k.kind == tokEOF {
+				return d, true
+			}
+			if k.kind != tokName {
+				continue
+			}
+			if p.peek(0).kind == tokDictEnd {
+				continue
+			}
+			v, ok := p.value()
+			if !ok {
+				return d, true
+			}
+			d[pdfName(k.str)] = v
+		}
+	case tokKeyword:
+		return pdfOp(t.str), true
+	}
+	return nil, true
+}
+
+// skipInlineImage advances past inline image data right after an ID
+// operator, discarding any lookahead that was lexed from the binary data.
+func (p *pdfParser) skipInlineImage() {
+	p.buf = nil
+	l := p.lex
+	l.pos = p.lastEnd + 1 // single whitespace after ID
+	for l.pos+2 <= len(l.data) {
+		if l.data[l.pos] == 'E' && l.data[l.pos+1] == 'I' &&
+			(l.pos == 0 || isWhite(l.data[l.pos-1])) &&
+			(l.pos+2 == len(l.data) || isWhite(l.data[l.pos+2])) {
+			l.pos += 2
+			return
+		}
+		l.pos++
+	}
+	l.pos = len(l.data)
+}
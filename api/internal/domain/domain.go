@@ -18,6 +18,10 @@ const (
 	InviteStatusPaymentFailed = "payment_failed"
 )
 
+// BusinessTypeUnknown is the type of a placeholder business created from a
+// WhatsApp message before the client told us what they do.
+const BusinessTypeUnknown = "Desconhecido"
+
 // Asaas webhook event names.
 const (
 	EventPixAuthActivated    = "PIX_AUTOMATIC_RECURRING_AUTHORIZATION_ACTIVATED"
@@ -37,10 +41,21 @@ const (
 
 // Message type values.
 const (
-	MsgTypeText  = "text"
-	MsgTypeAudio = "audio"
-	MsgTypeImage = "image"
-	MsgTypeVideo = "video"
+	MsgTypeText     = "text"
+	MsgTypeAudio    = "audio"
+	MsgTypeImage    = "image"
+	MsgTypeVideo    = "video"
+	MsgTypeDocument = "document"
+)
+
+// Media library collection name.
+const CollMediaItems = "media_items"
+
+// Media status values for messages processed in the background.
+const (
+	MediaStatusPending = "pending"
+	MediaStatusDone    = "done"
+	MediaStatusFailed  = "failed"
 )
 
 // BillingType values for Asaas charges.
@@ -65,3 +80,53 @@ const (
 	CollAgentConversations = "agent_conversations"
 	CollAgentActionLog     = "agent_action_log"
 )
+
+// Outbox collection and status values.
+const (
+	CollOutbox = "outbox"
+
+	OutboxStatusPending = "pending"
+	OutboxStatusSent    = "sent"
+	OutboxStatusFailed  = "failed"
+)
+
+// Delivery status values for outgoing messages, in the order receipts move them.
+const (
+	MsgStatusSent      = "sent"
+	MsgStatusDelivered = "delivered"
+	MsgStatusRead      = "read"
+)
+
+// Campaign collection names and status values.
+const (
+	CollCampaigns          = "campaigns"
+	CollCampaignRecipients = "campaign_recipients"
+
+	CampaignStatusDraft     = "draft"
+	CampaignStatusApproved  = "approved"
+	CampaignStatusDone      = "done"
+	CampaignStatusCancelled = "cancelled"
+
+	RecipientStatusPending   = "pending"
+	RecipientStatusQueued    = "queued"
+	RecipientStatusSent      = "sent"
+	RecipientStatusFailed    = "failed"
+	RecipientStatusOptedOut  = "opted_out"
+	RecipientStatusSkipped   = "skipped"
+	RecipientStatusCancelled = "cancelled"
+)
+
+// Communication preference collection names.
+const (
+	CollCommunicationPreferences = "communication_preferences"
+	CollPreferenceChanges        = "preference_changes"
+)
+
+// Monthly plan run collection and status values.
+const (
+	CollPlanRuns = "plan_runs"
+
+	PlanRunStatusRunning = "running"
+	PlanRunStatusDone    = "done"
+	PlanRunStatusFailed  = "failed"
+)
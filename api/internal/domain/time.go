@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"time"
+	_ "time/tzdata" // the server image may not ship a zoneinfo database
+)
+
+// Location is where clients and operators live. Quiet hours, posting days
+// and dates typed by operators are read in it, whatever TZ the server runs
+// with.
+var Location = mustLoadLocation("America/Sao_Paulo")
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
@@ -0,0 +1,192 @@
+// Package hashtag cleans model-generated hashtags and tops them up from
+// curated niche and city sets.
+package hashtag
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/denisraison/rekan/api/internal/postingtime"
+	"github.com/denisraison/rekan/api/internal/textnorm"
+)
+
+const (
+	// MaxPerPost is the most hashtags a post may carry. Small businesses get
+	// more reach from a few local tags than from a wall of generic ones.
+	MaxPerPost = 5
+	// maxFromModel caps the model's own tags so the city and niche tags
+	// always fit.
+	maxFromModel = 3
+	maxLength    = 30
+)
+
+// niches holds tags per postingtime category, most useful first.
+var niches = map[string][]string{
+	"food":    {"#gastronomia", "#comidaboa", "#feitocomamor"},
+	"beauty":  {"#beleza", "#autoestima", "#cuidadopessoal"},
+	"fashion": {"#modafeminina", "#lookdodia", "#estilo"},
+	"fitness": {"#vidasaudavel", "#treino", "#saude"},
+	"pet":     {"#petshop", "#amopet", "#cachorro"},
+}
+
+// cities holds tags per city, keyed by the folded city name. The first tag
+// is the city itself; the rest are the nicknames and region tags locals use.
+var cities = map[string][]string{
+	"sao paulo":      {"#saopaulo", "#sampa", "#sp"},
+	"rio de janeiro": {"#riodejaneiro", "#rj"},
+	"belo horizonte": {"#belohorizonte", "#bh", "#minasgerais"},
+	"brasilia":       {"#brasilia", "#df"},
+	"salvador":       {"#salvador", "#bahia"},
+	"fortaleza":      {"#fortaleza", "#ceara"},
+	"recife":         {"#recife", "#pernambuco"},
+	"manaus":         {"#manaus", "#amazonas"},
+	"belem":          {"#belem", "#belemdopara"},
+	"curitiba":       {"#curitiba", "#cwb", "#parana"},
+	"porto alegre":   {"#portoalegre", "#poa", "#riograndedosul"},
+	"florianopolis":  {"#florianopolis", "#floripa", "#santacatarina"},
+	"goiania":        {"#goiania", "#goias"},
+	"campinas":       {"#campinas", "#interiordesp"},
+}
+
+// banned are tags Instagram restricts or that mark an account as chasing
+// follows. Using them can hide the whole post.
+var banned = map[string]bool{
+	"followforfollow":  true,
+	"follow4follow":    true,
+	"f4f":              true,
+	"likeforlike":      true,
+	"like4like":        true,
+	"l4l":              true,
+	"sigoevolto":       true,
+	"seguidores":       true,
+	"ganharseguidores": true,
+	"curtidas":         true,
+	"beautyblogger":    true,
+	"alone":            true,
+	"snapchat":         true,
+	"instagramanet":    true,
+	"sigodevolta":      true,
+	"seguesegue":       true,
+}
+
+// lowValue are tags so broad that a local business never shows up in them.
+var lowValue = map[string]bool{
+	"love":          true,
+	"amor":          true,
+	"instagood":     true,
+	"instagram":     true,
+	"insta":         true,
+	"photooftheday": true,
+	"fotododia":     true,
+	"instadaily":    true,
+	"follow":        true,
+	"like":          true,
+	"tbt":           true,
+	"happy":         true,
+	"top":           true,
+	"brasil":        true,
+	"brazil":        true,
+	"sigam":         true,
+	"segue":         true,
+	"curta":         true,
+}
+
+// Process cleans the model's tags, keeps up to three of them and adds the
+// business's city tag and one niche tag. The result never exceeds MaxPerPost.
+func Process(tags []string, businessType, city string) []string {
+	out := make([]string, 0, MaxPerPost)
+	seen := map[string]bool{}
+	add := func(tag string) bool {
+		if seen[key(tag)] {
+			return false
+		}
+		seen[key(tag)] = true
+		out = append(out, tag)
+		return true
+	}
+
+	for _, t := range tags {
+		if len(out) == maxFromModel {
+			break
+		}
+		if tag, ok := clean(t); ok {
+			add(tag)
+		}
+	}
+	if c := ForCity(city); len(c) > 0 {
+		add(c[0])
+	}
+	for _, t := range ForNiche(businessType) {
+		if add(t) {
+			break
+		}
+	}
+	return out
+}
+
+// ForNiche returns the curated tags for a business type, or nil if it
+// matches no category.
+func ForNiche(businessType string) []string {
+	cat, ok := postingtime.Category(businessType)
+	if !ok {
+		return nil
+	}
+	return niches[cat]
+}
+
+// ForCity returns the curated tags for a city. Cities without a set get a
+// single tag made from the name.
+func ForCity(city string) []string {
+	name := textnorm.Fold(strings.TrimSpace(city))
+	if tags, ok := cities[name]; ok {
+		return tags
+	}
+	tag := "#" + strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, name)
+	if !valid(tag) {
+		return nil
+	}
+	return []string{tag}
+}
+
+// clean normalises a tag to "#word" form. It reports false for tags that are
+// malformed, banned or low value.
+func clean(t string) (string, bool) {
+	if !valid(t) {
+		return "", false
+	}
+	if k := key(t); banned[k] || lowValue[k] {
+		return "", false
+	}
+	return "#" + body(t), true
+}
+
+// valid reports whether a tag is non-empty, short enough, and has nothing
+// Instagram would cut the tag at.
+func valid(t string) bool {
+	b := body(t)
+	if b == "" || len([]rune(b)) > maxLength {
+		return false
+	}
+	for _, r := range b {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// body strips the "#" and the spaces the model sometimes leaves inside a
+// multi-word tag.
+func body(t string) string {
+	return strings.TrimLeft(strings.ReplaceAll(t, " ", ""), "#")
+}
+
+// key is the form tags are compared in: no "#", lower case, no accents.
+func key(t string) string {
+	return textnorm.Fold(body(t))
+}
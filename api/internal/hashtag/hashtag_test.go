@@ -0,0 +1,60 @@
+package hashtag
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestProcess(t *testing.T) {
+	tests := []struct {
+		name         string
+		tags         []string
+		businessType string
+		city         string
+		want         []string
+	}{
+		{
+			name:         "adds city and niche",
+			tags:         []string{"#UnhasDeGel"},
+			businessType: "manicure",
+			city:         "Manaus",
+			want:         []string{"#UnhasDeGel", "#manaus", "#beleza"},
+		},
+		{
+			name:         "drops banned, low value and duplicates",
+			tags:         []string{"#instagood", "pão caseiro", "#f4f", "#PãoCaseiro", "#Padaria"},
+			businessType: "padaria",
+			city:         "São Paulo",
+			want:         []string{"#pãocaseiro", "#Padaria", "#saopaulo", "#gastronomia"},
+		},
+		{
+			name:         "caps model tags",
+			tags:         []string{"#a1", "#a2", "#a3", "#a4", "#a5", "#a6"},
+			businessType: "academia",
+			city:         "Curitiba",
+			want:         []string{"#a1", "#a2", "#a3", "#curitiba", "#vidasaudavel"},
+		},
+		{
+			name:         "skips niche already used",
+			tags:         []string{"#Gastronomia"},
+			businessType: "restaurante",
+			city:         "",
+			want:         []string{"#Gastronomia", "#comidaboa"},
+		},
+		{
+			name:         "unknown niche and city",
+			tags:         []string{"#foto-estudio", "#retrato"},
+			businessType: "estúdio de fotografia",
+			city:         "Embu-Guaçu",
+			want:         []string{"#retrato", "#embuguacu"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Process(tt.tags, tt.businessType, tt.city)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/denisraison/rekan/api/internal/domain"
+	"github.com/denisraison/rekan/api/internal/service"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// defaultCalendarDays is the range returned when "to" is not given.
+const defaultCalendarDays = 7
+
+type calendarEntryResponse struct {
+	PostID       string `json:"post_id"`
+	BusinessID   string `json:"business_id"`
+	BusinessName string `json:"business_name"`
+	Date         string `json:"date"`
+	PlannedFor   string `json:"planned_for"`
+	Slot         string `json:"slot"`
+	Role         string `json:"role"`
+	Occasion     string `json:"occasion,omitempty"`
+	Caption      string `json:"caption"`
+	Reviewed     bool   `json:"reviewed"`
+}
+
+// ListCalendar returns planned posts across businesses. Query params: from,
+// to (YYYY-MM-DD, both inclusive; default today and the following week) and
+// business (comma-separated IDs).
+func ListCalendar() func(*core.RequestEvent) error {
+	return func(e *core.RequestEvent) error {
+		var businessIDs []string
+		if b := e.Request.URL.Query().Get("business"); b != "" {
+			businessIDs = strings.Split(b, ",")
+		}
+		return listCalendar(e, businessIDs)
+	}
+}
+
+// ListBusinessCalendar returns planned posts for one business.
+func ListBusinessCalendar() func(*core.RequestEvent) error {
+	return func(e *core.RequestEvent) error {
+		return listCalendar(e, []string{e.Request.PathValue("id")})
+	}
+}
+
+func listCalendar(e *core.RequestEvent, businessIDs []string) error {
+	query := e.Request.URL.Query()
+
+	from := time.Now().In(domain.Location)
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, domain.Location)
+	if s := query.Get("from"); s != "" {
+		parsed, err := time.ParseInLocation(time.DateOnly, s, domain.Location)
+		if err != nil {
+			return e.JSON(http.StatusBadRequest, map[string]string{"message": "data inicial inválida"})
+		}
+		from = parsed
+	}
+	to := from.AddDate(0, 0, defaultCalendarDays)
+	if s := query.Get("to"); s != "" {
+		parsed, err := time.ParseInLocation(time.DateOnly, s, domain.Location)
+		if err != nil {
+			return e.JSON(http.StatusBadRequest, map[string]string{"message": "data final inválida"})
+		}
+		to = parsed.AddDate(0, 0, 1)
+	}
+
+	entries, err := service.ListCalendar(e.App, service.CalendarFilter{
+		BusinessIDs: businessIDs,
+		From:        from,
+		To:          to,
+	})
+	if err != nil {
+		e.App.Logger().Error("list calendar failed", "error", err)
+		return e.JSON(http.StatusInternalServerError, map[string]string{"message": "erro ao buscar calendário"})
+	}
+
+	result := make([]calendarEntryResponse, len(entries))
+	for i, c := range entries {
+		planned := c.PlannedFor.In(domain.Location)
+		result[i] = calendarEntryResponse{
+			PostID:       c.PostID,
+			BusinessID:   c.BusinessID,
+			BusinessName: c.BusinessName,
+			Date:         planned.Format(time.DateOnly),
+			PlannedFor:   planned.Format(time.RFC3339),
+			Slot:         c.Slot,
+			Role:         c.Role,
+			Occasion:     c.Occasion,
+			Caption:      c.Caption,
+			Reviewed:     c.Reviewed,
+		}
+	}
+	return e.JSON(http.StatusOK, result)
+}
+
+// ReschedulePost moves a post to another day and, optionally, posting window.
+func ReschedulePost() func(*core.RequestEvent) error {
+	return func(e *core.RequestEvent) error {
+		postID := e.Request.PathValue("id")
+
+		var body struct {
+			Date string `json:"date"`
+			Slot string `json:"slot"`
+		}
+		if err := json.NewDecoder(e.Request.Body).Decode(&body); err != nil {
+			return e.JSON(http.StatusBadRequest, map[string]string{"message": "corpo inválido"})
+		}
+		day, err := time.ParseInLocation(time.DateOnly, body.Date, domain.Location)
+		if err != nil {
+			return e.JSON(http.StatusBadRequest, map[string]string{"message": "data inválida"})
+		}
+
+		record, err := service.ReschedulePost(e.App, postID, day, body.Slot)
+		if err != nil {
+			if errors.Is(err, service.ErrNotFound) {
+				return e.JSON(http.StatusNotFound, map[string]string{"message": "post não encontrado"})
+			}
+			if errors.Is(err, service.ErrInvalid) {
+				return e.JSON(http.StatusBadRequest, map[string]string{"message": "horário inválido"})
+			}
+			return e.JSON(http.StatusInternalServerError, map[string]string{"message": "erro ao reagendar"})
+		}
+
+		return e.JSON(http.StatusOK, map[string]any{
+			"id":          record.Id,
+			"planned_for": record.GetDateTime("planned_for").Time().In(domain.Location).Format(time.RFC3339),
+			"slot":        record.GetString("planned_slot"),
+		})
+	}
+}
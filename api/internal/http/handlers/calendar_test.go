@@ -0,0 +1,73 @@
+package handlers_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/denisraison/rekan/api/internal/domain"
+	"github.com/denisraison/rekan/api/internal/http/handlers"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestCalendarAndReschedule(t *testing.T) {
+	app, userID, bizID := newHandlerApp(t)
+	defer app.Cleanup()
+
+	col, err := app.FindCollectionByNameOrId("posts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	post := core.NewRecord(col)
+	post.Set("business", bizID)
+	post.Set("caption", "Post planejado")
+	post.Set("planned_for", time.Date(2026, 5, 4, 11, 0, 0, 0, domain.Location))
+	post.Set("planned_slot", "11h-13h")
+	if err := app.Save(post); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "business calendar",
+			Method:          http.MethodGet,
+			URL:             "/api/businesses/" + bizID + "/calendar?from=2026-05-01&to=2026-05-07",
+			ExpectedStatus:  http.StatusOK,
+			ExpectedContent: []string{`"date":"2026-05-04"`, `"slot":"11h-13h"`, `"Post planejado"`},
+		},
+		{
+			Name:            "all businesses, outside range",
+			Method:          http.MethodGet,
+			URL:             "/api/calendar?from=2026-06-01",
+			ExpectedStatus:  http.StatusOK,
+			ExpectedContent: []string{`[]`},
+		},
+		{
+			Name:            "reschedule",
+			Method:          http.MethodPost,
+			URL:             "/api/posts/" + post.Id + "/reschedule",
+			Body:            strings.NewReader(`{"date":"2026-05-06","slot":"18h-20h"}`),
+			ExpectedStatus:  http.StatusOK,
+			ExpectedContent: []string{`"slot":"18h-20h"`, `2026-05-06T18:00:00`},
+		},
+		{
+			Name:            "reschedule with bad slot",
+			Method:          http.MethodPost,
+			URL:             "/api/posts/" + post.Id + "/reschedule",
+			Body:            strings.NewReader(`{"date":"2026-05-06","slot":"de noite"}`),
+			ExpectedStatus:  http.StatusBadRequest,
+			ExpectedContent: []string{`"message"`},
+		},
+	}
+	for _, s := range scenarios {
+		s.TestAppFactory = func(_ testing.TB) *tests.TestApp { return app }
+		s.BeforeTestFunc = func(_ testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+			registerHandlerRoutes(app, e, handlers.Deps{App: app})
+		}
+		s.Headers = map[string]string{"Authorization": authHeader(app, userID)}
+		s.DisableTestAppCleanup = true // scenarios share the app and seeded post
+		s.Test(t)
+	}
+}
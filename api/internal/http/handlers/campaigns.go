@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/denisraison/rekan/api/internal/service"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+type segmentBody struct {
+	Types          []string `json:"types"`
+	Cities         []string `json:"cities"`
+	Tiers          []string `json:"tiers"`
+	InviteStatuses []string `json:"invite_statuses"`
+}
+
+type campaignResponse struct {
+	ID         string         `json:"id"`
+	Name       string         `json:"name"`
+	Template   string         `json:"template"`
+	Segment    segmentBody    `json:"segment"`
+	Status     string         `json:"status"`
+	ApprovedAt string         `json:"approved_at,omitempty"`
+	Created    string         `json:"created"`
+	Counts     map[string]int `json:"counts"`
+}
+
+type recipientResponse struct {
+	ID           string `json:"id,omitempty"`
+	BusinessID   string `json:"business_id"`
+	BusinessName string `json:"business_name"`
+	Phone        string `json:"phone"`
+	Text         string `json:"text"`
+	Status       string `json:"status,omitempty"`
+	Skip         string `json:"skip,omitempty"` // preview only: why the client would not get it
+	Error        string `json:"error,omitempty"`
+	SentAt       string `json:"sent_at,omitempty"`
+}
+
+func toCampaignResponse(c service.Campaign) campaignResponse {
+	return campaignResponse{
+		ID:       c.ID,
+		Name:     c.Name,
+		Template: c.Template,
+		Segment: segmentBody{
+			Types:          c.Segment.Types,
+			Cities:         c.Segment.Cities,
+			Tiers:          c.Segment.Tiers,
+			InviteStatuses: c.Segment.InviteStatuses,
+		},
+		Status:     c.Status,
+		ApprovedAt: formatTime(c.ApprovedAt),
+		Created:    formatTime(c.Created),
+		Counts:     c.Counts,
+	}
+}
+
+func toRecipientResponses(recipients []service.CampaignRecipient) []recipientResponse {
+	result := make([]recipientResponse, len(recipients))
+	for i, r := range recipients {
+		result[i] = recipientResponse{
+			ID:           r.ID,
+			BusinessID:   r.BusinessID,
+			BusinessName: r.BusinessName,
+			Phone:        r.Phone,
+			Text:         r.Text,
+			Status:       r.Status,
+			Skip:         r.Skip,
+			Error:        r.Error,
+			SentAt:       formatTime(r.SentAt),
+		}
+	}
+	return result
+}
+
+// campaignError maps service errors to a response.
+func campaignError(e *core.RequestEvent, err error, action string) error {
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		return e.JSON(http.StatusNotFound, map[string]string{"message": "campanha não encontrada"})
+	case errors.Is(err, service.ErrInvalid), errors.Is(err, service.ErrConflict):
+		status := http.StatusBadRequest
+		if errors.Is(err, service.ErrConflict) {
+			status = http.StatusConflict
+		}
+		return e.JSON(status, map[string]string{"message": err.Error()})
+	}
+	e.App.Logger().Error(action+" failed", "campaign", e.Request.PathValue("id"), "error", err)
+	return e.JSON(http.StatusInternalServerError, map[string]string{"message": "erro na campanha"})
+}
+
+// ListCampaigns returns every campaign with recipient counts by status.
+func ListCampaigns() func(*core.RequestEvent) error {
+	return func(e *core.RequestEvent) error {
+		campaigns, err := service.ListCampaigns(e.App)
+		if err != nil {
+			return campaignError(e, err, "list campaigns")
+		}
+		result := make([]campaignResponse, len(campaigns))
+		for i, c := range campaigns {
+			result[i] = toCampaignResponse(c)
+		}
+		return e.JSON(http.StatusOK, result)
+	}
+}
+
+// CreateCampaign saves a draft campaign.
+// Body: {"name", "template", "segment": {"types", "cities", "tiers", "invite_statuses"}}.
+// Without invite_statuses the campaign goes to active clients only.
+func CreateCampaign() func(*core.RequestEvent) error {
+	return func(e *core.RequestEvent) error {
+		var body struct {
+			Name     string      `json:"name"`
+			Template string      `json:"template"`
+			Segment  segmentBody `json:"segment"`
+		}
+		if err := json.NewDecoder(e.Request.Body).Decode(&body); err != nil {
+			return e.JSON(http.StatusBadRequest, map[string]string{"message": "corpo inválido"})
+		}
+		record, err := service.CreateCampaign(e.App, service.CampaignParams{
+			Name:     body.Name,
+			Template: body.Template,
+			Segment:  service.Segment(body.Segment),
+		})
+		if err != nil {
+			return campaignError(e, err, "create campaign")
+		}
+		return e.JSON(http.StatusCreated, map[string]string{"id": record.Id})
+	}
+}
+
+// GetCampaign returns a campaign and how each recipient's send went.
+func GetCampaign() func(*core.RequestEvent) error {
+	return func(e *core.RequestEvent) error {
+		c, recipients, err := service.GetCampaign(e.App, e.Request.PathValue("id"))
+		if err != nil {
+			return campaignError(e, err, "get campaign")
+		}
+		return e.JSON(http.StatusOK, map[string]any{
+			"campaign":   toCampaignResponse(*c),
+			"recipients": toRecipientResponses(recipients),
+		})
+	}
+}
+
+// PreviewCampaign renders the message for every client in the segment.
+func PreviewCampaign() func(*core.RequestEvent) error {
+	return func(e *core.RequestEvent) error {
+		recipients, err := service.PreviewCampaign(e.App, e.Request.PathValue("id"))
+		if err != nil {
+			return campaignError(e, err, "preview campaign")
+		}
+		return e.JSON(http.StatusOK, toRecipientResponses(recipients))
+	}
+}
+
+// ApproveCampaign starts sending a draft campaign.
+func ApproveCampaign(deps Deps) func(*core.RequestEvent) error {
+	return func(e *core.RequestEvent) error {
+		if deps.WhatsApp == nil {
+			return e.JSON(http.StatusServiceUnavailable, map[string]string{
+				"message": "WhatsApp não configurado",
+			})
+		}
+		n, err := service.ApproveCampaign(e.App, e.Request.PathValue("id"), time.Now())
+		if err != nil {
+			return campaignError(e, err, "approve campaign")
+		}
+		return e.JSON(http.StatusOK, map[string]any{"status": "approved", "recipients": n})
+	}
+}
+
+// CancelCampaign stops a campaign; messages not sent yet are dropped.
+func CancelCampaign() func(*core.RequestEvent) error {
+	return func(e *core.RequestEvent) error {
+		if err := service.CancelCampaign(e.App, e.Request.PathValue("id")); err != nil {
+			return campaignError(e, err, "cancel campaign")
+		}
+		return e.JSON(http.StatusOK, map[string]string{"status": "cancelled"})
+	}
+}
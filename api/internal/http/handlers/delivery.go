@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/denisraison/rekan/api/internal/service"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+type deliveryResponse struct {
+	PostID       string `json:"post_id"`
+	BusinessID   string `json:"business_id"`
+	BusinessName string `json:"business_name,omitempty"`
+	Caption      string `json:"caption"`
+	Status       string `json:"status"`
+	SentAt       string `json:"sent_at,omitempty"`
+	DeliveredAt  string `json:"delivered_at,omitempty"`
+	ReadAt       string `json:"read_at,omitempty"`
+}
+
+func toDeliveryResponse(d service.Delivery) deliveryResponse {
+	return deliveryResponse{
+		PostID:       d.PostID,
+		BusinessID:   d.BusinessID,
+		BusinessName: d.BusinessName,
+		Caption:      d.Caption,
+		Status:       d.Status,
+		SentAt:       formatTime(d.SentAt),
+		DeliveredAt:  formatTime(d.DeliveredAt),
+		ReadAt:       formatTime(d.ReadAt),
+	}
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// PostDelivery returns whether the client received and read a sent post.
+func PostDelivery() func(*core.RequestEvent) error {
+	return func(e *core.RequestEvent) error {
+		d, err := service.PostDelivery(e.App, e.Request.PathValue("id"))
+		if err != nil {
+			if errors.Is(err, service.ErrNotFound) {
+				return e.JSON(http.StatusNotFound, map[string]string{"message": "post ainda não enviado"})
+			}
+			e.App.Logger().Error("post delivery failed", "error", err)
+			return e.JSON(http.StatusInternalServerError, map[string]string{"message": "erro ao buscar status"})
+		}
+		return e.JSON(http.StatusOK, toDeliveryResponse(*d))
+	}
+}
+
+// ListUnreadPosts returns posts delivered but not read. Query param: hours
+// since delivery (default 24).
+func ListUnreadPosts() func(*core.RequestEvent) error {
+	return func(e *core.RequestEvent) error {
+		olderThan := service.DefaultUnreadAfter
+		if s := e.Request.URL.Query().Get("hours"); s != "" {
+			h, err := strconv.Atoi(s)
+			if err != nil || h < 0 {
+				return e.JSON(http.StatusBadRequest, map[string]string{"message": "horas inválidas"})
+			}
+			olderThan = time.Duration(h) * time.Hour
+		}
+
+		unread, err := service.ListUnreadPosts(e.App, olderThan)
+		if err != nil {
+			e.App.Logger().Error("list unread posts failed", "error", err)
+			return e.JSON(http.StatusInternalServerError, map[string]string{"message": "erro ao buscar posts não lidos"})
+		}
+
+		result := make([]deliveryResponse, len(unread))
+		for i, d := range unread {
+			result[i] = toDeliveryResponse(d)
+		}
+		return e.JSON(http.StatusOK, result)
+	}
+}
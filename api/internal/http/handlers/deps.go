@@ -5,6 +5,7 @@ import (
 	"github.com/denisraison/rekan/api/internal/transcribe"
 	"github.com/denisraison/rekan/api/internal/whatsapp"
 	content "github.com/denisraison/rekan/api/internal/content"
+	"github.com/denisraison/rekan/api/internal/service"
 	"github.com/pocketbase/pocketbase/core"
 )
 
@@ -12,10 +13,16 @@ type Deps struct {
 	App                 core.App
 	Asaas               *asaas.Client        // nil when ASAAS_API_KEY is not set
 	WhatsApp            *whatsapp.Client     // nil when WhatsApp is not connected
+	Inbound             *whatsapp.Inbound    // nil when WhatsApp failed to init
 	Transcribe          *transcribe.Client   // nil when GEMINI_API_KEY is not set
 	WebhookToken        string
 	AppURL              string
 	Generate            content.GenerateFunc
+	// Formats holds the generators for reels, stories and carousels.
+	Formats             map[content.Format]content.GenerateFunc
+	// QualityGate checks and regenerates posts before they are saved. nil skips it.
+	QualityGate         *service.QualityGate
 	GenerateFromMessage content.GenerateFromMessageFunc
+	Rewrite             content.RewriteFunc
 	ExtractFromAudio    content.ExtractFromAudioFunc // nil when GEMINI_API_KEY is not set
 }
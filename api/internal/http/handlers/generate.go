@@ -1,18 +1,47 @@
 package handlers
 
 import (
+	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 
+	content "github.com/denisraison/rekan/api/internal/content"
 	"github.com/denisraison/rekan/api/internal/service"
 	"github.com/pocketbase/pocketbase/core"
 )
 
+// GeneratePosts generates a post for a business. The body is optional;
+// "format" picks a reel, story or carousel instead of a feed post, and
+// "variants" asks for up to service.MaxVariants caption options to pick from.
 func GeneratePosts(deps Deps) func(*core.RequestEvent) error {
 	return func(e *core.RequestEvent) error {
 		businessID := e.Request.PathValue("id")
 
-		result, err := service.GeneratePosts(e.Request.Context(), e.App, deps.Generate, businessID)
+		var body struct {
+			Format   string `json:"format"`
+			Variants int    `json:"variants"`
+		}
+		if err := json.NewDecoder(e.Request.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+			return e.JSON(http.StatusBadRequest, map[string]string{"message": "corpo inválido"})
+		}
+
+		format, ok := content.ParseFormat(body.Format)
+		if !ok {
+			return e.JSON(http.StatusBadRequest, map[string]string{"message": "formato inválido"})
+		}
+		generate := deps.Generate
+		if format != content.FormatFeed {
+			generate = deps.Formats[format]
+		}
+		if generate == nil {
+			return e.JSON(http.StatusBadRequest, map[string]string{"message": "formato indisponível"})
+		}
+		if body.Variants < 0 || body.Variants > service.MaxVariants {
+			return e.JSON(http.StatusBadRequest, map[string]string{"message": "número de variantes inválido"})
+		}
+
+		result, err := service.GeneratePosts(e.Request.Context(), e.App, generate, deps.QualityGate, businessID, body.Variants)
 		if err != nil {
 			if errors.Is(err, service.ErrNotFound) {
 				return e.JSON(http.StatusNotFound, map[string]string{"message": "negócio não encontrado"})
@@ -22,12 +51,17 @@ func GeneratePosts(deps Deps) func(*core.RequestEvent) error {
 		}
 
 		type postResponse struct {
-			ID             string   `json:"id"`
-			Caption        string   `json:"caption"`
-			Hashtags       []string `json:"hashtags"`
-			ProductionNote string   `json:"production_note"`
-			Role           string   `json:"role"`
-			Hook           string   `json:"hook"`
+			ID             string                 `json:"id"`
+			Caption        string                 `json:"caption"`
+			Hashtags       []string               `json:"hashtags"`
+			ProductionNote string                 `json:"production_note"`
+			Role           string                 `json:"role"`
+			Hook           string                 `json:"hook"`
+			Format         string                 `json:"format,omitempty"`
+			FormatData     content.FormatData     `json:"format_data,omitzero"`
+			Quality        *service.QualityReport `json:"quality,omitempty"`
+			Variants       []service.PostVariant  `json:"variants,omitempty"`
+			MediaItem      string                 `json:"media_item,omitempty"`
 		}
 
 		posts := make([]postResponse, len(result.Posts))
@@ -39,6 +73,11 @@ func GeneratePosts(deps Deps) func(*core.RequestEvent) error {
 				ProductionNote: p.ProductionNote,
 				Role:           p.Role,
 				Hook:           p.Hook,
+				Format:         string(p.Format),
+				FormatData:     p.FormatData,
+				Quality:        p.Quality,
+				Variants:       p.Variants,
+				MediaItem:      p.MediaItem,
 			}
 		}
 
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/denisraison/rekan/api/internal/domain"
+	"github.com/denisraison/rekan/api/internal/service"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// GeneratePlan starts building the monthly content plan for a business and
+// answers 202 with the plan_runs record. The body is optional; "start"
+// (YYYY-MM-DD) defaults to today. Poll the plan_runs record for done and
+// total; once its status is done, the posts share its batch_id.
+func GeneratePlan(deps Deps) func(*core.RequestEvent) error {
+	return func(e *core.RequestEvent) error {
+		businessID := e.Request.PathValue("id")
+
+		var body struct {
+			Start string `json:"start"`
+		}
+		if err := json.NewDecoder(e.Request.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+			return e.JSON(http.StatusBadRequest, map[string]string{"message": "corpo inválido"})
+		}
+
+		start := time.Now().In(domain.Location)
+		if body.Start != "" {
+			parsed, err := time.ParseInLocation(time.DateOnly, body.Start, domain.Location)
+			if err != nil {
+				return e.JSON(http.StatusBadRequest, map[string]string{"message": "data de início inválida"})
+			}
+			start = parsed
+		}
+
+		run, err := service.StartMonthlyPlan(e.App, businessID, start)
+		if err != nil {
+			switch {
+			case errors.Is(err, service.ErrNotFound):
+				return e.JSON(http.StatusNotFound, map[string]string{"message": "negócio não encontrado"})
+			case errors.Is(err, service.ErrConflict):
+				return e.JSON(http.StatusConflict, map[string]string{"message": "já tem um plano sendo gerado para este negócio"})
+			}
+			e.App.Logger().Error("start plan failed", "business", businessID, "error", err)
+			return e.JSON(http.StatusInternalServerError, map[string]string{"message": "erro ao gerar o plano. Tente novamente."})
+		}
+
+		// A plan is up to 16 generations, each possibly retried by the
+		// quality gate, so it runs past the request.
+		app, runID := e.App, run.Id
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), service.PlanRunTimeout)
+			defer cancel()
+			if err := service.RunMonthlyPlan(ctx, app, deps.Generate, deps.QualityGate, runID); err != nil {
+				app.Logger().Error("generate plan failed", "business", businessID, "run", runID, "error", err)
+			}
+		}()
+
+		return e.JSON(http.StatusAccepted, map[string]any{
+			"id":     run.Id,
+			"status": run.GetString("status"),
+		})
+	}
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	content "github.com/denisraison/rekan/api/internal/content"
+	"github.com/denisraison/rekan/api/internal/domain"
 	"github.com/denisraison/rekan/api/internal/http/handlers"
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/tests"
@@ -59,6 +62,55 @@ func TestGenerateSuccess(t *testing.T) {
 	s.Test(t)
 }
 
+func TestGenerateFormat(t *testing.T) {
+	app, userID, bizID := newHandlerApp(t)
+	defer app.Cleanup()
+
+	stubCarousel := func(_ context.Context, _ content.BusinessProfile, _ []content.Role, _ []string) ([]content.Post, error) {
+		return []content.Post{{
+			Caption: "Salva pra depois.",
+			Format:  content.FormatCarousel,
+			Data:    content.FormatData{Carousel: []content.CarouselSlide{{Title: "3 erros"}}},
+		}}, nil
+	}
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "carousel",
+			Body:            strings.NewReader(`{"format":"carousel"}`),
+			ExpectedStatus:  http.StatusOK,
+			ExpectedContent: []string{`"format":"carousel"`, `"title":"3 erros"`},
+		},
+		{
+			Name:            "unknown format",
+			Body:            strings.NewReader(`{"format":"live"}`),
+			ExpectedStatus:  http.StatusBadRequest,
+			ExpectedContent: []string{`"formato inválido"`},
+		},
+		{
+			Name:            "format without generator",
+			Body:            strings.NewReader(`{"format":"reel"}`),
+			ExpectedStatus:  http.StatusBadRequest,
+			ExpectedContent: []string{`"formato indisponível"`},
+		},
+	}
+	for _, s := range scenarios {
+		s.Method = http.MethodPost
+		s.URL = "/api/businesses/" + bizID + "/posts:generate"
+		s.TestAppFactory = func(_ testing.TB) *tests.TestApp { return app }
+		s.DisableTestAppCleanup = true
+		s.BeforeTestFunc = func(_ testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+			registerHandlerRoutes(app, e, handlers.Deps{
+				App:      app,
+				Generate: stubGenerate,
+				Formats:  map[content.Format]content.GenerateFunc{content.FormatCarousel: stubCarousel},
+			})
+		}
+		s.Headers = map[string]string{"Authorization": authHeader(app, userID)}
+		s.Test(t)
+	}
+}
+
 func TestGenerateError(t *testing.T) {
 	app, userID, bizID := newHandlerApp(t)
 	defer app.Cleanup()
@@ -85,3 +137,76 @@ func TestGenerateError(t *testing.T) {
 	}
 	s.Test(t)
 }
+
+func TestGeneratePlanSuccess(t *testing.T) {
+	app, userID, bizID := newHandlerApp(t)
+	defer app.Cleanup()
+
+	s := &tests.ApiScenario{
+		Method:         http.MethodPost,
+		URL:            "/api/businesses/" + bizID + "/posts:generatePlan",
+		Body:           strings.NewReader(`{"start":"2026-03-01"}`),
+		TestAppFactory: func(_ testing.TB) *tests.TestApp { return app },
+		BeforeTestFunc: func(_ testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+			registerHandlerRoutes(app, e, handlers.Deps{
+				App:      app,
+				Generate: stubGenerate,
+			})
+		},
+		Headers: map[string]string{
+			"Authorization": authHeader(app, userID),
+		},
+		ExpectedStatus:  http.StatusAccepted,
+		ExpectedContent: []string{`"id"`, `"status":"running"`},
+	}
+	s.AfterTestFunc = func(t testing.TB, app *tests.TestApp, _ *http.Response) {
+		// Wait for the background run before the app is cleaned up.
+		deadline := time.Now().Add(10 * time.Second)
+		for {
+			runs, err := app.FindAllRecords(domain.CollPlanRuns)
+			if err != nil || len(runs) != 1 {
+				t.Fatalf("plan runs = %d (%v), want 1", len(runs), err)
+			}
+			run := runs[0]
+			if status := run.GetString("status"); status != domain.PlanRunStatusRunning {
+				if status != domain.PlanRunStatusDone {
+					t.Fatalf("status = %q (%s), want done", status, run.GetString("error"))
+				}
+				posts, err := app.FindRecordsByFilter(domain.CollPosts, "batch_id = {:batch}", "", 0, 0, map[string]any{"batch": run.GetString("batch_id")})
+				if err != nil || len(posts) != run.GetInt("total") {
+					t.Errorf("batch has %d posts (%v), want %d", len(posts), err, run.GetInt("total"))
+				}
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatal("plan run did not finish")
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+	}
+	s.Test(t)
+}
+
+func TestGeneratePlanBadStart(t *testing.T) {
+	app, userID, bizID := newHandlerApp(t)
+	defer app.Cleanup()
+
+	s := &tests.ApiScenario{
+		Method:         http.MethodPost,
+		URL:            "/api/businesses/" + bizID + "/posts:generatePlan",
+		Body:           strings.NewReader(`{"start":"01/03/2026"}`),
+		TestAppFactory: func(_ testing.TB) *tests.TestApp { return app },
+		BeforeTestFunc: func(_ testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+			registerHandlerRoutes(app, e, handlers.Deps{
+				App:      app,
+				Generate: stubGenerate,
+			})
+		},
+		Headers: map[string]string{
+			"Authorization": authHeader(app, userID),
+		},
+		ExpectedStatus:  http.StatusBadRequest,
+		ExpectedContent: []string{`"message"`},
+	}
+	s.Test(t)
+}
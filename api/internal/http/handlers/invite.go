@@ -25,11 +25,14 @@ func InviteSend(deps Deps) func(*core.RequestEvent) error {
 		}
 
 		businessID := e.Request.PathValue("id")
-		result, err := service.SendInvite(e.Request.Context(), e.App, deps.WhatsApp, businessID, deps.AppURL)
+		result, err := service.SendInvite(e.App, businessID, deps.AppURL)
 		if err != nil {
 			if errors.Is(err, service.ErrNoPhone) {
 				return e.JSON(http.StatusBadRequest, map[string]string{"message": "cliente sem telefone cadastrado"})
 			}
+			if errors.Is(err, service.ErrOptedOut) {
+				return e.JSON(http.StatusConflict, map[string]string{"message": "cliente pediu para não receber estas mensagens"})
+			}
 			if errors.Is(err, service.ErrConflict) {
 				return e.JSON(http.StatusConflict, map[string]string{"message": err.Error()})
 			}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/denisraison/rekan/api/internal/domain"
+	"github.com/denisraison/rekan/api/internal/service"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// defaultMediaLimit caps the library items returned when "limit" is not given.
+const defaultMediaLimit = 30
+
+// mediaMessage is the message holding an item's file, shaped so the web
+// client can pass it straight to pb.files.getURL.
+type mediaMessage struct {
+	ID           string `json:"id"`
+	CollectionID string `json:"collectionId"`
+	Media        string `json:"media"`
+}
+
+type mediaItemResponse struct {
+	ID          string       `json:"id"`
+	Type        string       `json:"type"`
+	Description string       `json:"description"`
+	Tags        []string     `json:"tags"`
+	Flags       []string     `json:"flags"`
+	UsedCount   int          `json:"used_count"`
+	LastUsedAt  string       `json:"last_used_at,omitempty"`
+	Created     string       `json:"created"`
+	Message     mediaMessage `json:"message"`
+}
+
+// ListMedia returns a business's photo and video library, best match first.
+// Query params: q (keywords, e.g. "bolo rosa") and limit (default 30).
+func ListMedia() func(*core.RequestEvent) error {
+	return func(e *core.RequestEvent) error {
+		businessID := e.Request.PathValue("id")
+		query := e.Request.URL.Query()
+
+		limit := defaultMediaLimit
+		if s := query.Get("limit"); s != "" {
+			n, err := strconv.Atoi(s)
+			if err != nil || n < 1 {
+				return e.JSON(http.StatusBadRequest, map[string]string{"message": "limite inválido"})
+			}
+			limit = n
+		}
+
+		items, err := service.SearchMedia(e.App, businessID, query.Get("q"), limit)
+		if err != nil {
+			if errors.Is(err, service.ErrNotFound) {
+				return e.JSON(http.StatusNotFound, map[string]string{"message": "negócio não encontrado"})
+			}
+			e.App.Logger().Error("list media failed", "business", businessID, "error", err)
+			return e.JSON(http.StatusInternalServerError, map[string]string{"message": "erro ao buscar mídias"})
+		}
+
+		result := make([]mediaItemResponse, 0, len(items))
+		for _, item := range items {
+			msg, err := e.App.FindRecordById(domain.CollMessages, item.GetString("message"))
+			if err != nil {
+				continue
+			}
+			var tags, flags []string
+			_ = item.UnmarshalJSONField("tags", &tags)
+			_ = item.UnmarshalJSONField("flags", &flags)
+			result = append(result, mediaItemResponse{
+				ID:          item.Id,
+				Type:        item.GetString("type"),
+				Description: item.GetString("description"),
+				Tags:        tags,
+				Flags:       flags,
+				UsedCount:   item.GetInt("used_count"),
+				LastUsedAt:  formatTime(item.GetDateTime("last_used_at").Time()),
+				Created:     formatTime(item.GetDateTime("created").Time()),
+				Message: mediaMessage{
+					ID:           msg.Id,
+					CollectionID: msg.Collection().Id,
+					Media:        msg.GetString("media"),
+				},
+			})
+		}
+		return e.JSON(http.StatusOK, result)
+	}
+}
+
+// PairPostMedia sets the library photo or video that goes with a post.
+// An empty media_item removes it.
+func PairPostMedia() func(*core.RequestEvent) error {
+	return func(e *core.RequestEvent) error {
+		postID := e.Request.PathValue("id")
+
+		var body struct {
+			MediaItem *string `json:"media_item"`
+		}
+		if err := json.NewDecoder(e.Request.Body).Decode(&body); err != nil || body.MediaItem == nil {
+			return e.JSON(http.StatusBadRequest, map[string]string{"message": "corpo inválido"})
+		}
+
+		post, err := service.PairPostMedia(e.App, postID, *body.MediaItem)
+		if err != nil {
+			switch {
+			case errors.Is(err, service.ErrNotFound):
+				return e.JSON(http.StatusNotFound, map[string]string{"message": "post ou mídia não encontrado"})
+			case errors.Is(err, service.ErrInvalid):
+				return e.JSON(http.StatusBadRequest, map[string]string{"message": "mídia de outra cliente"})
+			}
+			e.App.Logger().Error("pair post media failed", "post", postID, "error", err)
+			return e.JSON(http.StatusInternalServerError, map[string]string{"message": "erro ao escolher mídia"})
+		}
+		return e.JSON(http.StatusOK, map[string]string{
+			"id":         post.Id,
+			"media_item": post.GetString("media_item"),
+		})
+	}
+}
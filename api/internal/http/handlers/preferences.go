@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/denisraison/rekan/api/internal/prefs"
+	"github.com/denisraison/rekan/api/internal/service"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+type preferencesResponse struct {
+	Marketing   bool                       `json:"marketing"`
+	Seasonal    bool                       `json:"seasonal"`
+	Operational bool                       `json:"operational"`
+	History     []preferenceChangeResponse `json:"history,omitempty"`
+}
+
+type preferenceChangeResponse struct {
+	Category  string `json:"category"`
+	Allowed   bool   `json:"allowed"`
+	Source    string `json:"source"`
+	Message   string `json:"message,omitempty"`
+	Text      string `json:"text,omitempty"`
+	User      string `json:"user,omitempty"`
+	ChangedAt string `json:"changed_at"`
+}
+
+func toPreferencesResponse(p prefs.Preferences) preferencesResponse {
+	return preferencesResponse{Marketing: p.Marketing, Seasonal: p.Seasonal, Operational: p.Operational}
+}
+
+// GetPreferences returns which messages a client agreed to receive and the
+// log of changes, newest first.
+func GetPreferences() func(*core.RequestEvent) error {
+	return func(e *core.RequestEvent) error {
+		businessID := e.Request.PathValue("id")
+		p, history, err := service.BusinessPreferences(e.App, businessID)
+		if err != nil {
+			if errors.Is(err, service.ErrNotFound) {
+				return e.JSON(http.StatusNotFound, map[string]string{"message": "negócio não encontrado"})
+			}
+			e.App.Logger().Error("get preferences failed", "business", businessID, "error", err)
+			return e.JSON(http.StatusInternalServerError, map[string]string{"message": "erro ao buscar preferências"})
+		}
+
+		result := toPreferencesResponse(p)
+		for _, c := range history {
+			result.History = append(result.History, preferenceChangeResponse{
+				Category:  c.Category,
+				Allowed:   c.Allowed,
+				Source:    c.Source,
+				Message:   c.MessageID,
+				Text:      c.Text,
+				User:      c.UserID,
+				ChangedAt: formatTime(c.ChangedAt),
+			})
+		}
+		return e.JSON(http.StatusOK, result)
+	}
+}
+
+// SetPreferences changes a client's preferences on the operator's behalf.
+// Body: {"marketing"?, "seasonal"?, "operational"?, "note"}; omitted
+// categories are left as they are.
+func SetPreferences() func(*core.RequestEvent) error {
+	return func(e *core.RequestEvent) error {
+		businessID := e.Request.PathValue("id")
+
+		var body struct {
+			Marketing   *bool  `json:"marketing"`
+			Seasonal    *bool  `json:"seasonal"`
+			Operational *bool  `json:"operational"`
+			Note        string `json:"note"`
+		}
+		if err := json.NewDecoder(e.Request.Body).Decode(&body); err != nil {
+			return e.JSON(http.StatusBadRequest, map[string]string{"message": "corpo inválido"})
+		}
+		set := map[string]bool{}
+		for category, v := range map[string]*bool{
+			prefs.Marketing:   body.Marketing,
+			prefs.Seasonal:    body.Seasonal,
+			prefs.Operational: body.Operational,
+		} {
+			if v != nil {
+				set[category] = *v
+			}
+		}
+
+		userID := ""
+		if e.Auth != nil {
+			userID = e.Auth.Id
+		}
+		p, err := service.SetPreferences(e.App, businessID, userID, set, body.Note)
+		if err != nil {
+			switch {
+			case errors.Is(err, service.ErrNotFound):
+				return e.JSON(http.StatusNotFound, map[string]string{"message": "negócio não encontrado"})
+			case errors.Is(err, service.ErrInvalid):
+				return e.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+			}
+			e.App.Logger().Error("set preferences failed", "business", businessID, "error", err)
+			return e.JSON(http.StatusInternalServerError, map[string]string{"message": "erro ao salvar preferências"})
+		}
+		return e.JSON(http.StatusOK, toPreferencesResponse(p))
+	}
+}
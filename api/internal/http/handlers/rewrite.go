@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/denisraison/rekan/api/internal/service"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// RewritePost applies a free-form instruction such as "deixa mais curto" to a
+// pending post and saves the result as an edit.
+func RewritePost(deps Deps) func(*core.RequestEvent) error {
+	return func(e *core.RequestEvent) error {
+		postID := e.Request.PathValue("id")
+
+		var body struct {
+			Instruction string `json:"instruction"`
+		}
+		if err := json.NewDecoder(e.Request.Body).Decode(&body); err != nil {
+			return e.JSON(http.StatusBadRequest, map[string]string{"message": "corpo inválido"})
+		}
+		if deps.Rewrite == nil {
+			return e.JSON(http.StatusServiceUnavailable, map[string]string{"message": "reescrita indisponível"})
+		}
+
+		record, updated, err := service.RewritePost(e.Request.Context(), e.App, deps.Rewrite, postID, body.Instruction)
+		if err != nil {
+			switch {
+			case errors.Is(err, service.ErrNotFound):
+				return e.JSON(http.StatusNotFound, map[string]string{"message": "post não encontrado"})
+			case errors.Is(err, service.ErrInvalid):
+				return e.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+			case errors.Is(err, service.ErrConflict):
+				return e.JSON(http.StatusConflict, map[string]string{"message": "post já foi revisado"})
+			}
+			e.App.Logger().Error("rewrite post failed", "post", postID, "error", err)
+			return e.JSON(http.StatusBadGateway, map[string]string{"message": "erro ao reescrever. Tente novamente."})
+		}
+
+		var hashtags []string
+		_ = json.Unmarshal([]byte(record.GetString("hashtags")), &hashtags)
+		if updated == nil {
+			updated = []string{}
+		}
+		return e.JSON(http.StatusOK, map[string]any{
+			"id":               record.Id,
+			"caption":          record.GetString("caption"),
+			"hashtags":         hashtags,
+			"production_note":  record.GetString("production_note"),
+			"original_caption": record.GetString("original_caption"),
+			"edited":           record.GetBool("edited"),
+			"updated":          updated,
+		})
+	}
+}
@@ -41,14 +41,17 @@ func ApproveScheduledMessage(deps Deps) func(*core.RequestEvent) error {
 		}
 
 		msgID := e.Request.PathValue("id")
-		if err := service.ApproveScheduledMessage(e.Request.Context(), e.App, deps.WhatsApp, msgID); err != nil {
+		if err := service.ApproveScheduledMessage(e.App, msgID); err != nil {
 			if errors.Is(err, service.ErrNoPhone) {
 				return e.JSON(http.StatusBadRequest, map[string]string{"message": "cliente sem telefone cadastrado"})
 			}
+			if errors.Is(err, service.ErrOptedOut) {
+				return e.JSON(http.StatusConflict, map[string]string{"message": "cliente pediu para não receber estas mensagens"})
+			}
 			return e.JSON(http.StatusBadGateway, map[string]string{"message": "Erro ao enviar mensagem. Tente novamente."})
 		}
 
-		return e.JSON(http.StatusOK, map[string]string{"status": "sent"})
+		return e.JSON(http.StatusOK, map[string]string{"status": "queued"})
 	}
 }
 
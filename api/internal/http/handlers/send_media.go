@@ -28,7 +28,7 @@ func SendMedia(deps Deps) func(*core.RequestEvent) error {
 			return e.JSON(http.StatusBadRequest, map[string]string{"message": "Arquivo é obrigatório"})
 		}
 
-		err = service.SendMediaMessage(e.Request.Context(), e.App, deps.WhatsApp, service.SendMediaParams{
+		err = service.SendMediaMessage(e.App, service.SendMediaParams{
 			BusinessID:  businessID,
 			Caption:     caption,
 			Data:        data,
@@ -39,9 +39,12 @@ func SendMedia(deps Deps) func(*core.RequestEvent) error {
 			if errors.Is(err, service.ErrNoPhone) {
 				return e.JSON(http.StatusBadRequest, map[string]string{"message": "Cliente sem telefone cadastrado"})
 			}
+			if errors.Is(err, service.ErrOptedOut) {
+				return e.JSON(http.StatusConflict, map[string]string{"message": "Cliente pediu para não receber estas mensagens"})
+			}
 			return e.JSON(http.StatusBadGateway, map[string]string{"message": "Erro ao enviar mídia. Tente novamente."})
 		}
 
-		return e.JSON(http.StatusOK, map[string]string{"status": "sent"})
+		return e.JSON(http.StatusOK, map[string]string{"status": "queued"})
 	}
 }
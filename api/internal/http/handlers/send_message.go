@@ -20,6 +20,7 @@ func SendMessage(deps Deps) func(*core.RequestEvent) error {
 
 		var body struct {
 			BusinessID     string `json:"business_id"`
+			PostID         string `json:"post_id"`
 			Caption        string `json:"caption"`
 			Hashtags       string `json:"hashtags"`
 			ProductionNote string `json:"production_note"`
@@ -32,8 +33,9 @@ func SendMessage(deps Deps) func(*core.RequestEvent) error {
 			return e.JSON(http.StatusBadRequest, map[string]string{"message": "Negócio e legenda são obrigatórios"})
 		}
 
-		err := service.SendTextMessage(e.Request.Context(), e.App, deps.WhatsApp, service.SendTextParams{
+		err := service.SendTextMessage(e.App, service.SendTextParams{
 			BusinessID:     body.BusinessID,
+			PostID:         body.PostID,
 			Caption:        body.Caption,
 			Hashtags:       body.Hashtags,
 			ProductionNote: body.ProductionNote,
@@ -42,9 +44,15 @@ func SendMessage(deps Deps) func(*core.RequestEvent) error {
 			if errors.Is(err, service.ErrNoPhone) {
 				return e.JSON(http.StatusBadRequest, map[string]string{"message": "Cliente sem telefone cadastrado"})
 			}
+			if errors.Is(err, service.ErrNotFound) {
+				return e.JSON(http.StatusNotFound, map[string]string{"message": "Post não encontrado"})
+			}
+			if errors.Is(err, service.ErrOptedOut) {
+				return e.JSON(http.StatusConflict, map[string]string{"message": "Cliente pediu para não receber estas mensagens"})
+			}
 			return e.JSON(http.StatusBadGateway, map[string]string{"message": "Erro ao enviar mensagem. Tente novamente."})
 		}
 
-		return e.JSON(http.StatusOK, map[string]string{"status": "sent"})
+		return e.JSON(http.StatusOK, map[string]string{"status": "queued"})
 	}
 }
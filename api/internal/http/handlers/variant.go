@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/denisraison/rekan/api/internal/service"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// PickVariant makes one of a pending post's caption variants its content.
+// The other variants stay on the post.
+func PickVariant() func(*core.RequestEvent) error {
+	return func(e *core.RequestEvent) error {
+		postID := e.Request.PathValue("id")
+
+		var body struct {
+			Index *int `json:"index"`
+		}
+		if err := json.NewDecoder(e.Request.Body).Decode(&body); err != nil || body.Index == nil {
+			return e.JSON(http.StatusBadRequest, map[string]string{"message": "corpo inválido"})
+		}
+
+		record, err := service.PickVariant(e.App, postID, *body.Index)
+		if err != nil {
+			switch {
+			case errors.Is(err, service.ErrNotFound):
+				return e.JSON(http.StatusNotFound, map[string]string{"message": "post não encontrado"})
+			case errors.Is(err, service.ErrInvalid):
+				return e.JSON(http.StatusBadRequest, map[string]string{"message": "variante inexistente"})
+			case errors.Is(err, service.ErrConflict):
+				return e.JSON(http.StatusConflict, map[string]string{"message": "post já foi revisado"})
+			}
+			e.App.Logger().Error("pick variant failed", "post", postID, "error", err)
+			return e.JSON(http.StatusInternalServerError, map[string]string{"message": "erro ao escolher variante"})
+		}
+
+		var hashtags []string
+		_ = json.Unmarshal([]byte(record.GetString("hashtags")), &hashtags)
+		var variants []service.PostVariant
+		_ = json.Unmarshal([]byte(record.GetString("variants")), &variants)
+		return e.JSON(http.StatusOK, map[string]any{
+			"id":              record.Id,
+			"caption":         record.GetString("caption"),
+			"hashtags":        hashtags,
+			"production_note": record.GetString("production_note"),
+			"hook":            record.GetString("hook"),
+			"variants":        variants,
+		})
+	}
+}
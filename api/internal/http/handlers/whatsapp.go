@@ -20,6 +20,19 @@ func WhatsAppStatus(deps Deps) func(*core.RequestEvent) error {
 	}
 }
 
+// WhatsAppStats returns the inbound worker pool counters: queue depth,
+// saturation and media retries.
+func WhatsAppStats(deps Deps) func(*core.RequestEvent) error {
+	return func(e *core.RequestEvent) error {
+		if deps.Inbound == nil {
+			return e.JSON(http.StatusServiceUnavailable, map[string]string{
+				"message": "WhatsApp não configurado",
+			})
+		}
+		return e.JSON(http.StatusOK, deps.Inbound.Stats())
+	}
+}
+
 // WhatsAppStatusStream streams WhatsApp status changes as SSE.
 // Sends the current status immediately, then pushes on every state change.
 func WhatsAppStatusStream(deps Deps) func(*core.RequestEvent) error {
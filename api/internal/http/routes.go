@@ -17,6 +17,10 @@ func RegisterRoutes(rtr *router.Router[*core.RequestEvent], deps handlers.Deps)
 	// Custom method on the business resource (Google API style: :verb suffix)
 	rtr.POST("/api/businesses/{id}/posts:generate", handlers.GeneratePosts(deps)).Bind(auth)
 
+	// Monthly plan sized by the business tier, generated in the background
+	// and saved as one batch; progress is on the plan_runs record
+	rtr.POST("/api/businesses/{id}/posts:generatePlan", handlers.GeneratePlan(deps)).Bind(auth)
+
 	// Terms (public, no auth)
 	rtr.GET("/api/terms", handlers.Terms())
 
@@ -37,17 +41,50 @@ func RegisterRoutes(rtr *router.Router[*core.RequestEvent], deps handlers.Deps)
 	// Save a proactively selected idea as a post
 	rtr.POST("/api/businesses/{id}/posts:saveProactive", handlers.SaveProactivePost()).Bind(auth)
 
+	// Content calendar (planned posts per day) and rescheduling
+	rtr.GET("/api/calendar", handlers.ListCalendar()).Bind(auth)
+	rtr.GET("/api/businesses/{id}/calendar", handlers.ListBusinessCalendar()).Bind(auth)
+	rtr.POST("/api/posts/{id}/reschedule", handlers.ReschedulePost()).Bind(auth)
+
+	// Rewrite a pending post from a free-form instruction
+	rtr.POST("/api/posts/{id}/rewrite", handlers.RewritePost(deps)).Bind(auth)
+
+	// Delivery and read status of sent posts
+	rtr.GET("/api/posts:unread", handlers.ListUnreadPosts()).Bind(auth)
+	rtr.GET("/api/posts/{id}/delivery", handlers.PostDelivery()).Bind(auth)
+
+	// Choose one of the caption variants generated for a post
+	rtr.POST("/api/posts/{id}/pick-variant", handlers.PickVariant()).Bind(auth)
+
+	// Photo and video library built from what clients send, and pairing a
+	// library item with a post
+	rtr.GET("/api/businesses/{id}/media", handlers.ListMedia()).Bind(auth)
+	rtr.POST("/api/posts/{id}/pair-media", handlers.PairPostMedia()).Bind(auth)
+
 	// Scheduled messages (seasonal outreach queued by cron)
 	rtr.GET("/api/scheduled-messages", handlers.ListScheduledMessages()).Bind(auth)
 	rtr.POST("/api/scheduled-messages/{id}/approve", handlers.ApproveScheduledMessage(deps)).Bind(auth)
 	rtr.POST("/api/scheduled-messages/{id}/dismiss", handlers.DismissScheduledMessage()).Bind(auth)
 
+	// Broadcast campaigns to a segment of clients, sent after approval
+	rtr.GET("/api/campaigns", handlers.ListCampaigns()).Bind(auth)
+	rtr.POST("/api/campaigns", handlers.CreateCampaign()).Bind(auth)
+	rtr.GET("/api/campaigns/{id}", handlers.GetCampaign()).Bind(auth)
+	rtr.GET("/api/campaigns/{id}/preview", handlers.PreviewCampaign()).Bind(auth)
+	rtr.POST("/api/campaigns/{id}/approve", handlers.ApproveCampaign(deps)).Bind(auth)
+	rtr.POST("/api/campaigns/{id}/cancel", handlers.CancelCampaign()).Bind(auth)
+
+	// Which messages a client agreed to receive, with the change log (LGPD)
+	rtr.GET("/api/businesses/{id}/preferences", handlers.GetPreferences()).Bind(auth)
+	rtr.POST("/api/businesses/{id}/preferences", handlers.SetPreferences()).Bind(auth)
+
 	// Demo generator (no DB save, inline business profile)
 	rtr.POST("/api/demo:generate", handlers.DemoGenerate(deps)).Bind(auth)
 
 	// WhatsApp
 	rtr.GET("/api/whatsapp/status", handlers.WhatsAppStatus(deps)).Bind(auth)
 	rtr.GET("/api/whatsapp/stream", handlers.WhatsAppStatusStream(deps)).Bind(auth)
+	rtr.GET("/api/whatsapp/stats", handlers.WhatsAppStats(deps)).Bind(auth)
 	rtr.POST("/api/messages:send", handlers.SendMessage(deps)).Bind(auth)
 	rtr.POST("/api/messages:sendMedia", handlers.SendMedia(deps)).Bind(auth)
 	rtr.POST("/api/media:describe", handlers.DescribeMedia(deps)).Bind(auth)
@@ -0,0 +1,245 @@
+// Package library keeps each business's photos and videos, collected from
+// what the client sends over WhatsApp, so posts can reuse them instead of
+// asking for new ones. Items are tagged from their Gemini description and
+// found again by keyword, e.g. "foto do bolo rosa".
+package library
+
+import (
+	"cmp"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/denisraison/rekan/api/internal/domain"
+	"github.com/denisraison/rekan/api/internal/textnorm"
+)
+
+// Quality flags. A flagged item can still be used, it just ranks below
+// clean ones.
+const (
+	FlagLowRes     = "low_res"     // short side under minSide pixels
+	FlagScreenshot = "screenshot"  // print of a screen or a chat
+	FlagShortVideo = "short_video" // too short for a reel
+	FlagLongVideo  = "long_video"  // longer than a reel allows
+)
+
+const (
+	minSide         = 720
+	minVideoSeconds = 3
+	maxVideoSeconds = 90
+	maxTags         = 10
+
+	// A caption shares words like "hoje" with plenty of photos; two common
+	// keywords make a pairing worth proposing.
+	minSuggestScore = 2
+)
+
+// AddParams describes an incoming photo or video.
+type AddParams struct {
+	BusinessID  string
+	MessageID   string
+	Type        string // domain.MsgTypeImage or domain.MsgTypeVideo
+	Description string
+	Width       int
+	Height      int
+	Seconds     int // videos only
+}
+
+// Add stores the media of a message in its business's library. A message
+// already in the library is left as it is.
+func Add(app core.App, p AddParams) (*core.Record, error) {
+	if p.Type != domain.MsgTypeImage && p.Type != domain.MsgTypeVideo {
+		return nil, fmt.Errorf("library: unsupported media type %q", p.Type)
+	}
+	existing, err := app.FindFirstRecordByFilter(domain.CollMediaItems, "message = {:id}", dbx.Params{"id": p.MessageID})
+	if err == nil {
+		return existing, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("find media item: %w", err)
+	}
+
+	collection, err := app.FindCachedCollectionByNameOrId(domain.CollMediaItems)
+	if err != nil {
+		return nil, fmt.Errorf("find media items collection: %w", err)
+	}
+	record := core.NewRecord(collection)
+	record.Set("business", p.BusinessID)
+	record.Set("message", p.MessageID)
+	record.Set("type", p.Type)
+	record.Set("description", p.Description)
+	record.Set("tags", Tags(p.Description))
+	record.Set("flags", Flags(p))
+	record.Set("width", p.Width)
+	record.Set("height", p.Height)
+	record.Set("seconds", p.Seconds)
+	if err := app.Save(record); err != nil {
+		return nil, fmt.Errorf("save media item: %w", err)
+	}
+	return record, nil
+}
+
+// Flags returns the quality problems of an item. Unknown dimensions or
+// durations are not flagged.
+func Flags(p AddParams) []string {
+	flags := []string{}
+	if short := min(p.Width, p.Height); short > 0 && short < minSide {
+		flags = append(flags, FlagLowRes)
+	}
+	desc := textnorm.Fold(p.Description)
+	for _, hint := range []string{"captura de tela", "screenshot", "print da tela", "conversa do whatsapp"} {
+		if strings.Contains(desc, hint) {
+			flags = append(flags, FlagScreenshot)
+			break
+		}
+	}
+	if p.Type == domain.MsgTypeVideo && p.Seconds > 0 {
+		if p.Seconds < minVideoSeconds {
+			flags = append(flags, FlagShortVideo)
+		} else if p.Seconds > maxVideoSeconds {
+			flags = append(flags, FlagLongVideo)
+		}
+	}
+	return flags
+}
+
+// Tags returns the keywords of a description: folded to lowercase without
+// accents, stopwords and short words dropped, first occurrence order, at
+// most maxTags.
+func Tags(description string) []string {
+	tags := keywords(description)
+	return tags[:min(len(tags), maxTags)]
+}
+
+func keywords(s string) []string {
+	out := []string{}
+	for _, w := range words(s) {
+		if len(w) < 3 || stopwords[w] || slices.Contains(out, w) {
+			continue
+		}
+		out = append(out, w)
+	}
+	return out
+}
+
+// Search returns up to limit items of a business matching query, best
+// first. Items score by how many query words appear in their tags or
+// description; ties go to clean, less used and newer items. An empty query
+// lists all items in that order. A limit of 0 returns every match.
+func Search(app core.App, businessID, query string, limit int) ([]*core.Record, error) {
+	matches, err := rank(app, businessID, keywords(query))
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	out := make([]*core.Record, len(matches))
+	for i, m := range matches {
+		out[i] = m.record
+	}
+	return out, nil
+}
+
+// Suggest returns the library photo that best fits a caption, or nil when
+// none shares at least minSuggestScore keywords with it. Videos are left to
+// the operator.
+func Suggest(app core.App, businessID, caption string) (*core.Record, error) {
+	matches, err := rank(app, businessID, keywords(caption))
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range matches {
+		if m.score >= minSuggestScore && m.record.GetString("type") == domain.MsgTypeImage {
+			return m.record, nil
+		}
+	}
+	return nil, nil
+}
+
+type scored struct {
+	record *core.Record
+	score  int
+}
+
+// rank scores a business's items against terms, best first, dropping items
+// that match none. Without terms every item is kept with score 0.
+func rank(app core.App, businessID string, terms []string) ([]scored, error) {
+	records, err := app.FindRecordsByFilter(domain.CollMediaItems, "business = {:biz}", "-created", 0, 0, dbx.Params{"biz": businessID})
+	if err != nil {
+		return nil, fmt.Errorf("list media items: %w", err)
+	}
+	var matches []scored
+	for _, r := range records {
+		s := score(r, terms)
+		if len(terms) > 0 && s == 0 {
+			continue
+		}
+		matches = append(matches, scored{r, s})
+	}
+	slices.SortStableFunc(matches, func(a, b scored) int {
+		return cmp.Or(
+			cmp.Compare(b.score, a.score),
+			cmp.Compare(len(flagsOf(a.record)), len(flagsOf(b.record))),
+			cmp.Compare(a.record.GetInt("used_count"), b.record.GetInt("used_count")),
+		)
+	})
+	return matches, nil
+}
+
+// MarkUsed counts one more post using item.
+func MarkUsed(app core.App, item *core.Record, at time.Time) error {
+	item.Set("used_count", item.GetInt("used_count")+1)
+	item.Set("last_used_at", at.UTC())
+	return app.Save(item)
+}
+
+func score(r *core.Record, terms []string) int {
+	if len(terms) == 0 {
+		return 0
+	}
+	var tags []string
+	_ = r.UnmarshalJSONField("tags", &tags)
+	desc := words(r.GetString("description"))
+	n := 0
+	for _, t := range terms {
+		if slices.Contains(tags, t) || slices.Contains(desc, t) {
+			n++
+		}
+	}
+	return n
+}
+
+func flagsOf(r *core.Record) []string {
+	var flags []string
+	_ = r.UnmarshalJSONField("flags", &flags)
+	return flags
+}
+
+func words(s string) []string {
+	return strings.FieldsFunc(textnorm.Fold(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// stopwords are the Portuguese filler words of image descriptions and
+// operator requests. Compared after folding.
+var stopwords = map[string]bool{
+	"uma": true, "uns": true, "umas": true, "com": true, "sem": true, "para": true, "pra": true,
+	"por": true, "pelo": true, "pela": true, "que": true, "sao": true, "esta": true, "estao": true,
+	"dos": true, "das": true, "nos": true, "nas": true, "num": true, "numa": true, "seu": true,
+	"sua": true, "seus": true, "suas": true, "ele": true, "ela": true, "eles": true, "elas": true,
+	"como": true, "mais": true, "muito": true, "muita": true, "bem": true, "tem": true, "ter": true,
+	"sobre": true, "entre": true, "ao": true, "aos": true, "ate": true, "tambem": true, "onde": true,
+	"imagem": true, "foto": true, "fotos": true, "video": true, "mostra": true, "mostrando": true,
+	"aparece": true, "parece": true, "fundo": true, "frente": true, "cima": true, "lado": true,
+	"aquela": true, "aquele": true, "essa": true, "esse": true, "este": true, "isso": true,
+	"quero": true, "manda": true, "acha": true, "acho": true, "legenda": true,
+}
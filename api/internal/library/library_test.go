@@ -0,0 +1,158 @@
+package library_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+
+	"github.com/denisraison/rekan/api/internal/domain"
+	"github.com/denisraison/rekan/api/internal/library"
+	_ "github.com/denisraison/rekan/api/migrations"
+)
+
+func newTestApp(t *testing.T) (*tests.TestApp, string) {
+	t.Helper()
+	app, err := tests.NewTestApp()
+	if err != nil {
+		t.Fatalf("new test app: %v", err)
+	}
+	t.Cleanup(app.Cleanup)
+
+	businesses, err := app.FindCollectionByNameOrId(domain.CollBusinesses)
+	if err != nil {
+		t.Fatal(err)
+	}
+	biz := core.NewRecord(businesses)
+	biz.Set("name", "Doces da Bia")
+	biz.Set("type", "confeitaria")
+	biz.Set("city", "Goiânia")
+	if err := app.Save(biz); err != nil {
+		t.Fatal(err)
+	}
+	return app, biz.Id
+}
+
+// addPhoto saves an incoming image message and its library item.
+func addPhoto(t *testing.T, app core.App, businessID, description string, width, height int) *core.Record {
+	t.Helper()
+	messages, err := app.FindCollectionByNameOrId(domain.CollMessages)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := core.NewRecord(messages)
+	msg.Set("business", businessID)
+	msg.Set("phone", "5562999990000")
+	msg.Set("type", domain.MsgTypeImage)
+	msg.Set("direction", domain.DirectionIncoming)
+	msg.Set("content", description)
+	if err := app.Save(msg); err != nil {
+		t.Fatal(err)
+	}
+	item, err := library.Add(app, library.AddParams{
+		BusinessID:  businessID,
+		MessageID:   msg.Id,
+		Type:        domain.MsgTypeImage,
+		Description: description,
+		Width:       width,
+		Height:      height,
+	})
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	return item
+}
+
+func TestTags(t *testing.T) {
+	got := library.Tags("Um bolo rosa de aniversário com morangos em cima, sobre uma mesa de madeira.")
+	want := []string{"bolo", "rosa", "aniversario", "morangos", "mesa", "madeira"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Tags = %v, want %v", got, want)
+	}
+}
+
+func TestFlags(t *testing.T) {
+	tests := []struct {
+		name string
+		p    library.AddParams
+		want []string
+	}{
+		{"clean photo", library.AddParams{Type: domain.MsgTypeImage, Width: 1080, Height: 1350, Description: "Bolo de pote"}, []string{}},
+		{"small photo", library.AddParams{Type: domain.MsgTypeImage, Width: 480, Height: 640}, []string{library.FlagLowRes}},
+		{"screenshot", library.AddParams{Type: domain.MsgTypeImage, Width: 1080, Height: 2400, Description: "Captura de tela de uma conversa"}, []string{library.FlagScreenshot}},
+		{"unknown size", library.AddParams{Type: domain.MsgTypeImage}, []string{}},
+		{"long video", library.AddParams{Type: domain.MsgTypeVideo, Width: 720, Height: 1280, Seconds: 240}, []string{library.FlagLongVideo}},
+		{"short video", library.AddParams{Type: domain.MsgTypeVideo, Seconds: 1}, []string{library.FlagShortVideo}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := library.Flags(tt.p); !slices.Equal(got, tt.want) {
+				t.Errorf("Flags = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchRanksByKeywordsThenQuality(t *testing.T) {
+	app, bizID := newTestApp(t)
+	pink := addPhoto(t, app, bizID, "Bolo rosa com granulado", 1080, 1080)
+	blurry := addPhoto(t, app, bizID, "Bolo rosa de morango", 400, 400)
+	addPhoto(t, app, bizID, "Brigadeiros numa bandeja", 1080, 1080)
+
+	got, err := library.Search(app, bizID, "aquela foto do bolo rosa", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Id != pink.Id || got[1].Id != blurry.Id {
+		t.Fatalf("Search returned %d items, want the clean pink cake then the low-res one", len(got))
+	}
+
+	all, err := library.Search(app, bizID, "", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 {
+		t.Errorf("empty query with limit 2 returned %d items", len(all))
+	}
+}
+
+func TestSuggestNeedsTwoKeywords(t *testing.T) {
+	app, bizID := newTestApp(t)
+	cake := addPhoto(t, app, bizID, "Bolo de cenoura com cobertura de chocolate", 1080, 1080)
+
+	got, err := library.Suggest(app, bizID, "Hoje tem bolo de cenoura quentinho com cobertura de chocolate!")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || got.Id != cake.Id {
+		t.Fatalf("Suggest = %v, want the carrot cake", got)
+	}
+
+	got, err = library.Suggest(app, bizID, "Encomende seu bolo para o fim de semana")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != nil {
+		t.Errorf("a single shared word should not pick a photo, got %q", got.GetString("description"))
+	}
+}
+
+func TestAddIsIdempotentAndMarkUsed(t *testing.T) {
+	app, bizID := newTestApp(t)
+	item := addPhoto(t, app, bizID, "Torta de limão", 1080, 1080)
+
+	again, err := library.Add(app, library.AddParams{BusinessID: bizID, MessageID: item.GetString("message"), Type: domain.MsgTypeImage})
+	if err != nil || again.Id != item.Id {
+		t.Fatalf("second Add = %v, %v; want the existing item", again, err)
+	}
+
+	if err := library.MarkUsed(app, item, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	fresh, _ := app.FindRecordById(domain.CollMediaItems, item.Id)
+	if fresh.GetInt("used_count") != 1 || fresh.GetDateTime("last_used_at").IsZero() {
+		t.Errorf("used_count %d, last_used_at %v", fresh.GetInt("used_count"), fresh.GetDateTime("last_used_at"))
+	}
+}
@@ -7,6 +7,10 @@ import (
 
 	content "github.com/denisraison/rekan/api/internal/content"
 	"github.com/denisraison/rekan/api/internal/domain"
+	"github.com/denisraison/rekan/api/internal/postingtime"
+	"github.com/denisraison/rekan/api/internal/similarity"
+	"github.com/denisraison/rekan/api/internal/textnorm"
+	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase/core"
 )
 
@@ -50,6 +54,7 @@ func BusinessToProfile(record *core.Record) (content.BusinessProfile, error) {
 		TargetAudience: record.GetString("target_audience"),
 		BrandVibe:      record.GetString("brand_vibe"),
 		Quirks:         quirks,
+		StyleMemo:      record.GetString("style_memo"),
 	}, nil
 }
 
@@ -73,3 +78,214 @@ func LoadPreviousHooks(app core.App, businessID string) ([]string, error) {
 	}
 	return hooks, nil
 }
+
+// roleHistoryPosts is how many of a client's latest posts feed role selection.
+const roleHistoryPosts = 50
+
+// recentRoles is how many of those posts count as recent for rotation.
+const recentRoles = 10
+
+// LoadRoleHistory summarises the roles of a client's latest posts: the most
+// recent ones, and how often each role was approved or sent back with
+// feedback.
+func LoadRoleHistory(app core.App, businessID string) (content.RoleHistory, error) {
+	records, err := app.FindRecordsByFilter(
+		domain.CollPosts,
+		"business = {:business} && role != ''",
+		"-created",
+		roleHistoryPosts,
+		0,
+		map[string]any{"business": businessID},
+	)
+	if err != nil {
+		return content.RoleHistory{}, err
+	}
+	h := content.RoleHistory{
+		Approved: map[string]int{},
+		Rejected: map[string]int{},
+	}
+	for _, r := range records {
+		role := r.GetString("role")
+		if len(h.Recent) < recentRoles {
+			h.Recent = append(h.Recent, role)
+		}
+		if !r.GetBool("reviewed") {
+			continue
+		}
+		if r.GetString("review_note") != "" {
+			h.Rejected[role]++
+		} else {
+			h.Approved[role]++
+		}
+	}
+	return h, nil
+}
+
+// styleFeedbackPosts caps how many rejections and how many edits feed the
+// style memo. Older corrections matter less and make the prompt longer.
+const styleFeedbackPosts = 10
+
+// LoadStyleFeedback returns a client's latest rejected captions with their
+// feedback, from the operator or a client change request, and edited
+// captions with their original text, newest first.
+func LoadStyleFeedback(app core.App, businessID string) (content.StyleFeedback, error) {
+	rejected, err := app.FindRecordsByFilter(
+		domain.CollPosts,
+		"business = {:business} && reviewed = true && review_note != ''",
+		"-updated",
+		styleFeedbackPosts,
+		0,
+		map[string]any{"business": businessID},
+	)
+	if err != nil {
+		return content.StyleFeedback{}, err
+	}
+	edited, err := app.FindRecordsByFilter(
+		domain.CollPosts,
+		"business = {:business} && edited = true && original_caption != ''",
+		"-updated",
+		styleFeedbackPosts,
+		0,
+		map[string]any{"business": businessID},
+	)
+	if err != nil {
+		return content.StyleFeedback{}, err
+	}
+
+	changes, err := app.FindRecordsByFilter(
+		domain.CollPosts,
+		"business = {:business} && client_reply = {:change} && client_feedback != ''",
+		"-client_replied_at",
+		styleFeedbackPosts,
+		0,
+		map[string]any{"business": businessID, "change": content.ReplyChangeRequest},
+	)
+	if err != nil {
+		return content.StyleFeedback{}, err
+	}
+
+	var f content.StyleFeedback
+	for _, r := range rejected {
+		f.Rejections = append(f.Rejections, content.RejectedCaption{
+			Caption:  r.GetString("caption"),
+			Feedback: r.GetString("review_note"),
+		})
+	}
+	// A client asking for a change to a sent post is a rejection too.
+	for _, r := range changes {
+		f.Rejections = append(f.Rejections, content.RejectedCaption{
+			Caption:  r.GetString("caption"),
+			Feedback: r.GetString("client_feedback"),
+		})
+	}
+	if len(f.Rejections) > styleFeedbackPosts {
+		f.Rejections = f.Rejections[:styleFeedbackPosts]
+	}
+	for _, r := range edited {
+		f.Edits = append(f.Edits, content.EditedCaption{
+			Before: r.GetString("original_caption"),
+			After:  r.GetString("caption"),
+		})
+	}
+
+	// An edit to a variant the operator then swapped out stays on that
+	// variant (see service.PickVariant).
+	swapped, err := app.FindRecordsByFilter(
+		domain.CollPosts,
+		"business = {:business} && variants ~ 'edited_caption'",
+		"-updated",
+		styleFeedbackPosts,
+		0,
+		map[string]any{"business": businessID},
+	)
+	if err != nil {
+		return content.StyleFeedback{}, err
+	}
+	for _, r := range swapped {
+		var variants []struct {
+			Caption       string `json:"caption"`
+			EditedCaption string `json:"edited_caption"`
+		}
+		if err := r.UnmarshalJSONField("variants", &variants); err != nil {
+			continue
+		}
+		for _, v := range variants {
+			if v.EditedCaption != "" {
+				f.Edits = append(f.Edits, content.EditedCaption{Before: v.Caption, After: v.EditedCaption})
+			}
+		}
+	}
+	if len(f.Edits) > styleFeedbackPosts {
+		f.Edits = f.Edits[:styleFeedbackPosts]
+	}
+	return f, nil
+}
+
+// peerPosts is how many recent posts from other businesses the similarity
+// guard compares a new post against.
+const peerPosts = 200
+
+// LoadPeerIndex indexes the recent captions and hooks of other businesses in
+// the same city and niche, whose audiences are the most likely to overlap.
+// Cities compare ignoring case and accents, as operators type them both ways.
+// Businesses without a city get an empty index.
+func LoadPeerIndex(app core.App, businessID string) (*similarity.Index, error) {
+	business, err := app.FindRecordById(domain.CollBusinesses, businessID)
+	if err != nil {
+		return nil, err
+	}
+	ix := &similarity.Index{}
+	city := cityKey(business.GetString("city"))
+	if city == "" {
+		return ix, nil
+	}
+
+	candidates, err := app.FindRecordsByFilter(
+		domain.CollBusinesses,
+		"city != '' && id != {:id}",
+		"",
+		0,
+		0,
+		map[string]any{"id": businessID},
+	)
+	if err != nil {
+		return nil, err
+	}
+	niche := nicheOf(business.GetString("type"))
+	var ids []any
+	for _, c := range candidates {
+		if cityKey(c.GetString("city")) == city && nicheOf(c.GetString("type")) == niche {
+			ids = append(ids, c.Id)
+		}
+	}
+	if len(ids) == 0 {
+		return ix, nil
+	}
+
+	var posts []*core.Record
+	if err := app.RecordQuery(domain.CollPosts).
+		AndWhere(dbx.In("business", ids...)).
+		OrderBy("created DESC").
+		Limit(peerPosts).
+		All(&posts); err != nil {
+		return nil, err
+	}
+	for _, p := range posts {
+		ix.Add(p.Id, p.GetString("caption"))
+		ix.Add(p.Id, p.GetString("hook"))
+	}
+	return ix, nil
+}
+
+func cityKey(city string) string {
+	return strings.Join(strings.Fields(textnorm.Fold(city)), " ")
+}
+
+// nicheOf groups business types by their posting time category, falling
+// back to the type itself for businesses outside the known categories.
+func nicheOf(businessType string) string {
+	if cat, ok := postingtime.Category(businessType); ok {
+		return cat
+	}
+	return textnorm.Fold(strings.TrimSpace(businessType))
+}
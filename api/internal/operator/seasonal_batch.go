@@ -1,21 +1,21 @@
 package operator
 
 import (
-	"slices"
 	"strings"
 	"time"
 
 	"github.com/denisraison/rekan/api/internal/domain"
+	"github.com/denisraison/rekan/api/internal/prefs"
 	"github.com/denisraison/rekan/api/internal/seasonal"
 	"github.com/pocketbase/pocketbase/core"
 )
 
 // QueueSeasonalMessages runs daily and creates scheduled_messages for seasonal events
-// that are 7 days away, for eligible businesses that don't already have one queued.
+// that are 7 days away, for eligible businesses that don't already have one queued
+// and still accept seasonal messages.
 func QueueSeasonalMessages(app core.App) {
 	now := time.Now()
 	target := now.AddDate(0, 0, 7)
-	year := now.Year()
 
 	businesses, err := app.FindAllRecords(domain.CollBusinesses)
 	if err != nil {
@@ -30,18 +30,9 @@ func QueueSeasonalMessages(app core.App) {
 	}
 
 	for _, sd := range seasonal.Dates {
-		dateYear := year
-		if sd.Year != 0 {
-			dateYear = sd.Year
-		}
-		date := time.Date(dateYear, time.Month(sd.Month), sd.Day, 0, 0, 0, 0, time.Local)
-		// For fixed calendar dates (Year == 0), roll to next year if already past.
-		// Moveable holidays (Year != 0) are only valid for their specific year; skip if past.
-		if date.Before(now) {
-			if sd.Year != 0 {
-				continue
-			}
-			date = time.Date(year+1, time.Month(sd.Month), sd.Day, 0, 0, 0, 0, time.Local)
+		date, ok := sd.Next(now)
+		if !ok {
+			continue
 		}
 
 		if date.Year() != target.Year() || date.Month() != target.Month() || date.Day() != target.Day() {
@@ -49,14 +40,12 @@ func QueueSeasonalMessages(app core.App) {
 		}
 
 		for _, biz := range businesses {
-			bizType := biz.GetString("type")
-
-			// Check niche eligibility.
-			if len(sd.Niches) > 0 {
-				eligible := slices.Contains(sd.Niches, bizType)
-				if !eligible {
-					continue
-				}
+			if !sd.ForNiche(biz.GetString("type")) {
+				continue
+			}
+			// Clients who stopped seasonal messages are not queued at all.
+			if allowed, err := prefs.Allowed(app, biz.Id, prefs.Seasonal); err != nil || !allowed {
+				continue
 			}
 
 			// Check if a non-dismissed scheduled_message already exists for this business+label.
@@ -1,6 +1,10 @@
 package postingtime
 
-import "strings"
+import (
+	"strconv"
+	"strings"
+	"time"
+)
 
 type Window struct {
 	Primary   string
@@ -96,16 +100,25 @@ var keywords = map[string]string{
 	"banho e tosa": "pet",
 }
 
-// ForBusinessType returns the best posting time windows for a given
-// free-text business type. Falls back to general Brazilian prime time
-// if no category matches.
-func ForBusinessType(businessType string) Window {
+// Category maps a free-text business type to a category key such as "food"
+// or "beauty". Other packages key their own per-niche data on it.
+func Category(businessType string) (string, bool) {
 	lower := strings.ToLower(businessType)
 	for kw, cat := range keywords {
 		if strings.Contains(lower, kw) {
-			return categories[cat]
+			return cat, true
 		}
 	}
+	return "", false
+}
+
+// ForBusinessType returns the best posting time windows for a given
+// free-text business type. Falls back to general Brazilian prime time
+// if no category matches.
+func ForBusinessType(businessType string) Window {
+	if cat, ok := Category(businessType); ok {
+		return categories[cat]
+	}
 	return fallback
 }
 
@@ -114,3 +127,32 @@ func Tip(businessType string) string {
 	w := ForBusinessType(businessType)
 	return "*Melhor horário pra postar:* entre " + w.Primary + " ou entre " + w.Secondary
 }
+
+// Slot returns the window for the nth post of a sequence, alternating
+// primary and secondary so a month's posts cover both audiences.
+func (w Window) Slot(n int) string {
+	if n%2 == 1 {
+		return w.Secondary
+	}
+	return w.Primary
+}
+
+// StartHour returns the hour a slot such as "10h-14h" opens.
+func StartHour(slot string) (int, bool) {
+	start, _, ok := strings.Cut(slot, "-")
+	if !ok {
+		return 0, false
+	}
+	h, err := strconv.Atoi(strings.TrimSuffix(start, "h"))
+	if err != nil || h < 0 || h > 23 {
+		return 0, false
+	}
+	return h, true
+}
+
+// At returns day at the opening hour of slot, in day's location. An
+// unparseable slot leaves the time at midnight.
+func At(day time.Time, slot string) time.Time {
+	h, _ := StartHour(slot)
+	return time.Date(day.Year(), day.Month(), day.Day(), h, 0, 0, 0, day.Location())
+}
@@ -1,6 +1,9 @@
 package postingtime
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestForBusinessType(t *testing.T) {
 	tests := []struct {
@@ -26,6 +29,9 @@ func TestForBusinessType(t *testing.T) {
 		if w.Primary != tt.wantPrim {
 			t.Errorf("ForBusinessType(%q).Primary = %q, want %q", tt.input, w.Primary, tt.wantPrim)
 		}
+		if cat, _ := Category(tt.input); cat != tt.wantCat {
+			t.Errorf("Category(%q) = %q, want %q", tt.input, cat, tt.wantCat)
+		}
 	}
 }
 
@@ -36,3 +42,23 @@ func TestTip(t *testing.T) {
 		t.Errorf("Tip = %q, want %q", tip, want)
 	}
 }
+
+func TestSlot(t *testing.T) {
+	w := ForBusinessType("hamburgueria")
+	if got := w.Slot(0); got != "10h-14h" {
+		t.Errorf("Slot(0) = %q, want primary", got)
+	}
+	if got := w.Slot(3); got != "17h-19h" {
+		t.Errorf("Slot(3) = %q, want secondary", got)
+	}
+}
+
+func TestAt(t *testing.T) {
+	day := time.Date(2026, 5, 7, 0, 0, 0, 0, time.UTC)
+	if got, want := At(day, "6h-8h"), time.Date(2026, 5, 7, 6, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("At = %s, want %s", got, want)
+	}
+	if _, ok := StartHour("manhã"); ok {
+		t.Error("StartHour should reject free text")
+	}
+}
@@ -0,0 +1,103 @@
+package prefs
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/denisraison/rekan/api/internal/textnorm"
+)
+
+// Intent is what a client's message asks about their messages.
+type Intent int
+
+const (
+	IntentNone          Intent = iota
+	IntentStopMarketing        // stop campaigns, invites and seasonal messages
+	IntentStopAll              // stop everything, posts included
+	IntentResume               // start everything again
+
+	// IntentStopAllConfirmed is the client answering StopAllWord, after being
+	// asked whether a stop request was meant to cover their posts too.
+	IntentStopAllConfirmed
+)
+
+// StopAllWord is the reply that stops everything without asking again.
+const StopAllWord = "PARAR TUDO"
+
+// Set returns the preference change the intent asks for.
+func (i Intent) Set() map[string]bool {
+	switch i {
+	case IntentStopMarketing:
+		return map[string]bool{Marketing: false, Seasonal: false}
+	case IntentStopAll, IntentStopAllConfirmed:
+		return map[string]bool{Marketing: false, Seasonal: false, Operational: false}
+	case IntentResume:
+		return map[string]bool{Marketing: true, Seasonal: true, Operational: true}
+	}
+	return nil
+}
+
+// stopWords are whole-message replies that stop promotional messages.
+// Campaign messages end by asking the client to answer SAIR.
+var stopWords = []string{"sair", "parar", "pare", "stop", "descadastrar", "cancelar inscricao"}
+
+// resumeWords are whole-message replies that turn messages back on.
+var resumeWords = []string{"voltar", "retomar", "quero voltar"}
+
+// stopPhrases inside a longer message ask us to stop writing.
+var stopPhrases = []string{
+	"nao quero mais receber", "nao quero receber mais", "nao quero mais mensage",
+	"para de me mandar", "pare de me mandar", "parem de me mandar", "para de mandar mensage", "parem de mandar mensage",
+	"nao me mande mais", "nao me mandem mais", "nao me envie mais", "nao me enviem mais",
+	"me tira da lista", "me tire da lista", "me tirem da lista", "me remove da lista", "me remova da lista",
+	"me descadastra", "me descadastre",
+}
+
+// resumePhrases inside a longer message ask us to write again.
+var resumePhrases = []string{
+	"quero voltar a receber", "pode voltar a mandar", "podem voltar a mandar", "pode voltar a me mandar",
+	"podem voltar a me mandar", "quero receber de novo", "pode mandar de novo", "podem mandar de novo",
+}
+
+// promoWords narrow a stop request to promotional messages.
+var promoWords = []string{"promo", "propaganda", "campanha", "oferta", "anuncio", "data comemorativa", "datas comemorativas"}
+
+// maxIntentLen skips long messages, where a stop phrase is more likely to be
+// about something else ("o cliente disse que não quer mais receber...").
+const maxIntentLen = 200
+
+// Detect reads a client's WhatsApp message for an opt-out or opt-in. A bare
+// SAIR stops promotional messages; a sentence like "não quero mais receber
+// mensagens" stops everything unless it names promotions, and PARAR TUDO
+// confirms stopping everything.
+func Detect(text string) Intent {
+	t := strings.Join(strings.Fields(strings.Trim(textnorm.Fold(text), " .!?")), " ")
+	if t == "" || len(t) > maxIntentLen {
+		return IntentNone
+	}
+	if slices.Contains(resumeWords, t) || containsAny(t, resumePhrases) {
+		return IntentResume
+	}
+	if t == textnorm.Fold(StopAllWord) {
+		return IntentStopAllConfirmed
+	}
+	if slices.Contains(stopWords, t) {
+		return IntentStopMarketing
+	}
+	if containsAny(t, stopPhrases) {
+		if containsAny(t, promoWords) {
+			return IntentStopMarketing
+		}
+		return IntentStopAll
+	}
+	return IntentNone
+}
+
+func containsAny(t string, phrases []string) bool {
+	for _, p := range phrases {
+		if strings.Contains(t, p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+package prefs_test
+
+import (
+	"testing"
+
+	"github.com/denisraison/rekan/api/internal/prefs"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		text string
+		want prefs.Intent
+	}{
+		{"SAIR", prefs.IntentStopMarketing},
+		{" parar. ", prefs.IntentStopMarketing},
+		{"Não quero mais receber mensagens", prefs.IntentStopAll},
+		{"por favor me tira da lista", prefs.IntentStopAll},
+		{"Parar tudo!", prefs.IntentStopAllConfirmed},
+		{"não quero mais receber promoção", prefs.IntentStopMarketing},
+		{"VOLTAR", prefs.IntentResume},
+		{"pode voltar a mandar as mensagens", prefs.IntentResume},
+		{"quero sair mais cedo hoje", prefs.IntentNone},
+		{"ok, obrigada!", prefs.IntentNone},
+		{"", prefs.IntentNone},
+	}
+	for _, tt := range tests {
+		if got := prefs.Detect(tt.text); got != tt.want {
+			t.Errorf("Detect(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+// Package prefs keeps each business's communication preferences: whether we
+// may send it marketing (campaigns and invites), seasonal outreach and
+// operational messages (posts and anything else the operator sends). Every
+// change is logged with when it happened, who made it and, for changes the
+// client asked for on WhatsApp, the message that asked, so an LGPD request
+// can be answered from the log.
+package prefs
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/denisraison/rekan/api/internal/domain"
+)
+
+// Message categories.
+const (
+	Marketing   = "marketing"
+	Seasonal    = "seasonal"
+	Operational = "operational"
+)
+
+// Categories lists every category.
+var Categories = []string{Marketing, Seasonal, Operational}
+
+// Who changed a preference.
+const (
+	SourceClient   = "client"   // asked for it in a WhatsApp message
+	SourceOperator = "operator" // set from the dashboard
+)
+
+// Preferences says which categories a business may receive.
+type Preferences struct {
+	Marketing   bool
+	Seasonal    bool
+	Operational bool
+}
+
+// All allows every category. It is what a business without a preferences
+// record gets.
+var All = Preferences{Marketing: true, Seasonal: true, Operational: true}
+
+// Allows reports whether category may be sent. Unknown categories are
+// treated as operational.
+func (p Preferences) Allows(category string) bool {
+	switch category {
+	case Marketing:
+		return p.Marketing
+	case Seasonal:
+		return p.Seasonal
+	}
+	return p.Operational
+}
+
+func (p *Preferences) set(category string, allowed bool) {
+	switch category {
+	case Marketing:
+		p.Marketing = allowed
+	case Seasonal:
+		p.Seasonal = allowed
+	case Operational:
+		p.Operational = allowed
+	}
+}
+
+// Get returns a business's preferences.
+func Get(app core.App, businessID string) (Preferences, error) {
+	record, err := find(app, businessID)
+	if err != nil || record == nil {
+		return All, err
+	}
+	return fromRecord(record), nil
+}
+
+// Allowed reports whether a business may receive a message of category.
+func Allowed(app core.App, businessID, category string) (bool, error) {
+	p, err := Get(app, businessID)
+	if err != nil {
+		return false, err
+	}
+	return p.Allows(category), nil
+}
+
+// Change is a request to allow or stop some categories.
+type Change struct {
+	BusinessID string
+	Set        map[string]bool // category to allowed
+	Source     string          // SourceClient or SourceOperator
+	MessageID  string          // the client's message, for SourceClient
+	Text       string          // what the client wrote, or the operator's note
+	UserID     string          // the operator, for SourceOperator
+	At         time.Time
+}
+
+// Apply saves a change and logs each category whose value actually moves.
+// It returns the new preferences and whether anything changed.
+func Apply(app core.App, c Change) (Preferences, bool, error) {
+	if c.At.IsZero() {
+		c.At = time.Now()
+	}
+	var result Preferences
+	changed := false
+	err := app.RunInTransaction(func(txApp core.App) error {
+		record, err := find(txApp, c.BusinessID)
+		if err != nil {
+			return err
+		}
+		if record == nil {
+			collection, err := txApp.FindCollectionByNameOrId(domain.CollCommunicationPreferences)
+			if err != nil {
+				return fmt.Errorf("find preferences collection: %w", err)
+			}
+			record = core.NewRecord(collection)
+			record.Set("business", c.BusinessID)
+			toRecord(record, All)
+		}
+		result = fromRecord(record)
+
+		changes, err := txApp.FindCollectionByNameOrId(domain.CollPreferenceChanges)
+		if err != nil {
+			return fmt.Errorf("find preference changes collection: %w", err)
+		}
+		for _, category := range Categories {
+			allowed, ok := c.Set[category]
+			if !ok || result.Allows(category) == allowed {
+				continue
+			}
+			result.set(category, allowed)
+			changed = true
+
+			log := core.NewRecord(changes)
+			log.Set("business", c.BusinessID)
+			log.Set("category", category)
+			log.Set("allowed", allowed)
+			log.Set("source", c.Source)
+			log.Set("message", c.MessageID)
+			log.Set("text", c.Text)
+			log.Set("user", c.UserID)
+			log.Set("changed_at", c.At.UTC())
+			if err := txApp.Save(log); err != nil {
+				return fmt.Errorf("log preference change: %w", err)
+			}
+		}
+		if !changed {
+			return nil
+		}
+		toRecord(record, result)
+		if err := txApp.Save(record); err != nil {
+			return fmt.Errorf("save preferences: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return Preferences{}, false, err
+	}
+	return result, changed, nil
+}
+
+// History returns a business's preference changes, newest first.
+func History(app core.App, businessID string) ([]*core.Record, error) {
+	records, err := app.FindRecordsByFilter(domain.CollPreferenceChanges, "business = {:biz}", "-changed_at,-@rowid", 0, 0,
+		dbx.Params{"biz": businessID})
+	if err != nil {
+		return nil, fmt.Errorf("list preference changes: %w", err)
+	}
+	return records, nil
+}
+
+func find(app core.App, businessID string) (*core.Record, error) {
+	record, err := app.FindFirstRecordByFilter(domain.CollCommunicationPreferences, "business = {:biz}", dbx.Params{"biz": businessID})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find preferences: %w", err)
+	}
+	return record, nil
+}
+
+func fromRecord(r *core.Record) Preferences {
+	return Preferences{
+		Marketing:   r.GetBool(Marketing),
+		Seasonal:    r.GetBool(Seasonal),
+		Operational: r.GetBool(Operational),
+	}
+}
+
+func toRecord(r *core.Record, p Preferences) {
+	r.Set(Marketing, p.Marketing)
+	r.Set(Seasonal, p.Seasonal)
+	r.Set(Operational, p.Operational)
+}
@@ -0,0 +1,75 @@
+package prefs_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+
+	"github.com/denisraison/rekan/api/internal/domain"
+	"github.com/denisraison/rekan/api/internal/prefs"
+	_ "github.com/denisraison/rekan/api/migrations"
+)
+
+func TestApplyLogsOnlyRealChanges(t *testing.T) {
+	app, err := tests.NewTestApp()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer app.Cleanup()
+
+	businesses, err := app.FindCollectionByNameOrId(domain.CollBusinesses)
+	if err != nil {
+		t.Fatal(err)
+	}
+	biz := core.NewRecord(businesses)
+	biz.Set("name", "Doces da Bia")
+	biz.Set("type", "confeitaria")
+	biz.Set("city", "Goiânia")
+	if err := app.Save(biz); err != nil {
+		t.Fatal(err)
+	}
+
+	if p, err := prefs.Get(app, biz.Id); err != nil || p != prefs.All {
+		t.Fatalf("Get without a record = %+v, %v; want everything allowed", p, err)
+	}
+
+	at := time.Date(2026, 10, 5, 14, 30, 0, 0, time.UTC)
+	p, changed, err := prefs.Apply(app, prefs.Change{
+		BusinessID: biz.Id,
+		Set:        prefs.IntentStopMarketing.Set(),
+		Source:     prefs.SourceClient,
+		Text:       "SAIR",
+		At:         at,
+	})
+	if err != nil || !changed {
+		t.Fatalf("Apply = %v, %v", changed, err)
+	}
+	if p.Marketing || p.Seasonal || !p.Operational {
+		t.Errorf("after SAIR: %+v", p)
+	}
+
+	// Stopping everything only logs the category that was still on.
+	if _, _, err := prefs.Apply(app, prefs.Change{BusinessID: biz.Id, Set: prefs.IntentStopAll.Set(), Source: prefs.SourceClient}); err != nil {
+		t.Fatal(err)
+	}
+	if _, changed, _ := prefs.Apply(app, prefs.Change{BusinessID: biz.Id, Set: prefs.IntentStopAll.Set(), Source: prefs.SourceClient}); changed {
+		t.Error("repeating a change should not change anything")
+	}
+
+	history, err := prefs.History(app, biz.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 3 {
+		t.Fatalf("history has %d entries, want 3", len(history))
+	}
+	if got := history[0].GetString("category"); got != prefs.Operational {
+		t.Errorf("newest change is %q, want operational", got)
+	}
+	last := history[len(history)-1]
+	if !last.GetDateTime("changed_at").Time().Equal(at) || last.GetString("text") != "SAIR" {
+		t.Errorf("oldest change at %v with text %q", last.GetDateTime("changed_at"), last.GetString("text"))
+	}
+}
@@ -38,6 +38,22 @@ var Months = map[Commitment]int{
 	Trimestral: 3,
 }
 
+// PostsPerMonth maps a tier to the number of posts promised per month in the terms.
+var PostsPerMonth = map[Tier]int{
+	Basico:       8,
+	Parceiro:     12,
+	Profissional: 16,
+}
+
+// Posts returns the monthly post count for a tier, falling back to Basico
+// for businesses without a valid tier.
+func Posts(tier Tier) int {
+	if n, ok := PostsPerMonth[tier]; ok {
+		return n
+	}
+	return PostsPerMonth[Basico]
+}
+
 // Price returns the total charge amount for a tier+commitment pair.
 // Returns 0 and false if the combination is invalid.
 func Price(tier Tier, commitment Commitment) (float64, bool) {
@@ -1,5 +1,10 @@
 package seasonal
 
+import (
+	"slices"
+	"time"
+)
+
 // Date represents a seasonal marketing opportunity.
 // Year is non-zero for moveable holidays (Carnaval, Páscoa, Dia das Mães) that
 // don't fall on the same date each year. Fixed calendar dates leave Year as 0.
@@ -103,3 +108,26 @@ var Dates = []Date{
 		Template: "{name}, Réveillon vem aí! Bora postar sobre agendamento e preparação?",
 	},
 }
+
+// ForNiche reports whether the date is relevant to a business type.
+func (d Date) ForNiche(niche string) bool {
+	return len(d.Niches) == 0 || slices.Contains(d.Niches, niche)
+}
+
+// Next returns the next occurrence of the date (at midnight) not before now.
+// Fixed dates that already passed roll to next year; moveable holidays that
+// passed report false, since they are only known for their own year.
+func (d Date) Next(now time.Time) (time.Time, bool) {
+	year := now.Year()
+	if d.Year != 0 {
+		year = d.Year
+	}
+	date := time.Date(year, time.Month(d.Month), d.Day, 0, 0, 0, 0, now.Location())
+	if date.Before(now) {
+		if d.Year != 0 {
+			return time.Time{}, false
+		}
+		date = time.Date(now.Year()+1, time.Month(d.Month), d.Day, 0, 0, 0, 0, now.Location())
+	}
+	return date, true
+}
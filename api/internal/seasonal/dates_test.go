@@ -0,0 +1,40 @@
+package seasonal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDateNext(t *testing.T) {
+	now := time.Date(2026, 6, 20, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		date Date
+		want time.Time
+		ok   bool
+	}{
+		{"fixed upcoming", Date{Month: 10, Day: 12}, time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC), true},
+		{"fixed passed rolls over", Date{Month: 3, Day: 8}, time.Date(2027, 3, 8, 0, 0, 0, 0, time.UTC), true},
+		{"fixed today counts as passed", Date{Month: 6, Day: 20}, time.Date(2027, 6, 20, 0, 0, 0, 0, time.UTC), true},
+		{"moveable upcoming", Date{Year: 2026, Month: 12, Day: 1}, time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC), true},
+		{"moveable passed", Date{Year: 2026, Month: 5, Day: 10}, time.Time{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := tt.date.Next(now)
+		if ok != tt.ok || !got.Equal(tt.want) {
+			t.Errorf("%s: got (%s, %v), want (%s, %v)", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestDateForNiche(t *testing.T) {
+	all := Date{Label: "Natal"}
+	if !all.ForNiche("Barbearia") {
+		t.Error("date without niches should apply to every business")
+	}
+	d := Date{Label: "Páscoa", Niches: []string{"Confeitaria"}}
+	if !d.ForNiche("Confeitaria") || d.ForNiche("Barbearia") {
+		t.Error("niche filter mismatch")
+	}
+}
@@ -5,25 +5,17 @@ import (
 	"fmt"
 	"strings"
 	"time"
-	"unicode"
 
 	"github.com/denisraison/rekan/api/internal/domain"
 	"github.com/denisraison/rekan/api/internal/pricing"
+	"github.com/denisraison/rekan/api/internal/textnorm"
 	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase/core"
-	"golang.org/x/text/runes"
-	"golang.org/x/text/transform"
-	"golang.org/x/text/unicode/norm"
 )
 
 // NormalizeForMatch strips accents, lowercases, and trims for fuzzy comparison.
 func NormalizeForMatch(s string) string {
-	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
-	result, _, err := transform.String(t, s)
-	if err != nil {
-		return strings.ToLower(strings.TrimSpace(s))
-	}
-	return strings.ToLower(strings.TrimSpace(result))
+	return strings.TrimSpace(textnorm.Fold(s))
 }
 
 // FindBusinessByName returns business records whose names fuzzy-match the query.
@@ -157,13 +149,14 @@ func CreateBusiness(app core.App, p CreateBusinessParams) (*core.Record, error)
 
 // UpdateBusinessParams holds optional fields for updating a business.
 type UpdateBusinessParams struct {
-	NewName        *string
-	Type           *string
-	City           *string
-	Phone          *string
-	TargetAudience *string
-	BrandVibe      *string
-	Quirks         *string
+	NewName          *string
+	Type             *string
+	City             *string
+	Phone            *string
+	TargetAudience   *string
+	BrandVibe        *string
+	Quirks           *string
+	AssistantEnabled *bool // client-facing DM assistant on or off
 }
 
 // UpdateBusiness applies the given fields to the record and saves.
@@ -202,6 +195,10 @@ func UpdateBusiness(app core.App, record *core.Record, p UpdateBusinessParams) (
 		record.Set("quirks", *p.Quirks)
 		updated = append(updated, "quirks")
 	}
+	if p.AssistantEnabled != nil && *p.AssistantEnabled != record.GetBool("assistant_enabled") {
+		record.Set("assistant_enabled", *p.AssistantEnabled)
+		updated = append(updated, "assistant_enabled")
+	}
 
 	if len(updated) == 0 {
 		return nil, nil
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/denisraison/rekan/api/internal/domain"
+	"github.com/denisraison/rekan/api/internal/postingtime"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// CalendarEntry is a post with a planned publish date.
+type CalendarEntry struct {
+	PostID       string
+	BusinessID   string
+	BusinessName string
+	PlannedFor   time.Time
+	Slot         string
+	Role         string
+	Occasion     string
+	Caption      string
+	Reviewed     bool
+}
+
+// CalendarFilter selects planned posts in [From, To).
+type CalendarFilter struct {
+	BusinessIDs []string // empty means all
+	From        time.Time
+	To          time.Time
+}
+
+// ListCalendar returns posts planned within the filter's range, earliest first.
+func ListCalendar(app core.App, filter CalendarFilter) ([]CalendarEntry, error) {
+	from, err := types.ParseDateTime(filter.From)
+	if err != nil {
+		return nil, fmt.Errorf("calendar from: %w", err)
+	}
+	to, err := types.ParseDateTime(filter.To)
+	if err != nil {
+		return nil, fmt.Errorf("calendar to: %w", err)
+	}
+
+	q := app.RecordQuery(domain.CollPosts).
+		AndWhere(dbx.NewExp("planned_for >= {:from} AND planned_for < {:to}", dbx.Params{
+			"from": from.String(),
+			"to":   to.String(),
+		})).
+		OrderBy("planned_for ASC")
+
+	if len(filter.BusinessIDs) > 0 {
+		params := dbx.Params{}
+		placeholders := make([]string, len(filter.BusinessIDs))
+		for i, id := range filter.BusinessIDs {
+			key := fmt.Sprintf("bid%d", i)
+			placeholders[i] = fmt.Sprintf("{:%s}", key)
+			params[key] = id
+		}
+		q = q.AndWhere(dbx.NewExp("business IN ("+strings.Join(placeholders, ",")+")", params))
+	}
+
+	var posts []*core.Record
+	if err := q.All(&posts); err != nil {
+		return nil, fmt.Errorf("listing calendar: %w", err)
+	}
+
+	names := map[string]string{}
+	entries := make([]CalendarEntry, 0, len(posts))
+	for _, p := range posts {
+		bizID := p.GetString("business")
+		name, ok := names[bizID]
+		if !ok {
+			if biz, err := app.FindRecordById(domain.CollBusinesses, bizID); err == nil {
+				name = biz.GetString("name")
+			}
+			names[bizID] = name
+		}
+		entries = append(entries, CalendarEntry{
+			PostID:       p.Id,
+			BusinessID:   bizID,
+			BusinessName: name,
+			PlannedFor:   p.GetDateTime("planned_for").Time(),
+			Slot:         p.GetString("planned_slot"),
+			Role:         p.GetString("role"),
+			Occasion:     p.GetString("occasion"),
+			Caption:      p.GetString("caption"),
+			Reviewed:     p.GetBool("reviewed"),
+		})
+	}
+	return entries, nil
+}
+
+// ReschedulePost moves a post to day, in the given posting window. An empty
+// slot keeps the post's current window, or uses the business type's primary
+// window if it has none.
+func ReschedulePost(app core.App, postID string, day time.Time, slot string) (*core.Record, error) {
+	record, err := app.FindRecordById(domain.CollPosts, postID)
+	if err != nil {
+		return nil, wrapNotFound(err, "post não encontrado")
+	}
+	return ReschedulePostRecord(app, record, day, slot)
+}
+
+// ReschedulePostRecord moves an already-loaded post. See ReschedulePost.
+func ReschedulePostRecord(app core.App, record *core.Record, day time.Time, slot string) (*core.Record, error) {
+	if slot == "" {
+		slot = record.GetString("planned_slot")
+	}
+	if slot == "" {
+		business, err := app.FindRecordById(domain.CollBusinesses, record.GetString("business"))
+		if err != nil {
+			return nil, wrapNotFound(err, "negócio não encontrado")
+		}
+		slot = postingtime.ForBusinessType(business.GetString("type")).Primary
+	}
+	if _, ok := postingtime.StartHour(slot); !ok {
+		return nil, fmt.Errorf("%w: horário %q (use o formato 10h-14h)", ErrInvalid, slot)
+	}
+
+	record.Set("planned_for", postingtime.At(day, slot))
+	record.Set("planned_slot", slot)
+	if err := app.Save(record); err != nil {
+		return nil, fmt.Errorf("rescheduling post: %w", err)
+	}
+	return record, nil
+}
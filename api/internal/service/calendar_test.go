@@ -0,0 +1,98 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/denisraison/rekan/api/internal/domain"
+	"github.com/denisraison/rekan/api/internal/service"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func seedPlannedPost(t *testing.T, app *tests.TestApp, bizID, caption string, at time.Time, slot string) *core.Record {
+	t.Helper()
+	col, err := app.FindCollectionByNameOrId(domain.CollPosts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := core.NewRecord(col)
+	r.Set("business", bizID)
+	r.Set("caption", caption)
+	r.Set("planned_for", at)
+	r.Set("planned_slot", slot)
+	if err := app.Save(r); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestListCalendar(t *testing.T) {
+	app, _, bizID := newTestApp(t)
+	defer app.Cleanup()
+
+	day := time.Date(2026, 5, 4, 0, 0, 0, 0, time.UTC)
+	seedPlannedPost(t, app, bizID, "Segundo", day.Add(36*time.Hour), "18h-20h")
+	seedPlannedPost(t, app, bizID, "Primeiro", day.Add(11*time.Hour), "11h-13h")
+	seedPlannedPost(t, app, bizID, "Fora", day.AddDate(0, 0, 10), "11h-13h")
+
+	entries, err := service.ListCalendar(app, service.CalendarFilter{From: day, To: day.AddDate(0, 0, 7)})
+	if err != nil {
+		t.Fatalf("ListCalendar: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Caption != "Primeiro" || entries[1].Caption != "Segundo" {
+		t.Errorf("order: got %q, %q", entries[0].Caption, entries[1].Caption)
+	}
+	if entries[0].BusinessName != "Padaria Teste" {
+		t.Errorf("business name: got %q", entries[0].BusinessName)
+	}
+	if entries[0].Slot != "11h-13h" {
+		t.Errorf("slot: got %q", entries[0].Slot)
+	}
+
+	other, err := service.ListCalendar(app, service.CalendarFilter{BusinessIDs: []string{"missing"}, From: day, To: day.AddDate(0, 0, 7)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(other) != 0 {
+		t.Errorf("expected no entries for another business, got %d", len(other))
+	}
+}
+
+func TestReschedulePost(t *testing.T) {
+	app, _, bizID := newTestApp(t)
+	defer app.Cleanup()
+
+	post := seedPlannedPost(t, app, bizID, "Post", time.Date(2026, 5, 4, 11, 0, 0, 0, time.UTC), "11h-13h")
+	newDay := time.Date(2026, 5, 8, 0, 0, 0, 0, time.UTC)
+
+	updated, err := service.ReschedulePost(app, post.Id, newDay, "")
+	if err != nil {
+		t.Fatalf("ReschedulePost: %v", err)
+	}
+	if want := time.Date(2026, 5, 8, 11, 0, 0, 0, time.UTC); !updated.GetDateTime("planned_for").Time().Equal(want) {
+		t.Errorf("planned_for: got %s, want %s", updated.GetDateTime("planned_for"), want)
+	}
+
+	updated, err = service.ReschedulePost(app, post.Id, newDay, "17h-19h")
+	if err != nil {
+		t.Fatalf("ReschedulePost with slot: %v", err)
+	}
+	if got := updated.GetString("planned_slot"); got != "17h-19h" {
+		t.Errorf("planned_slot: got %q", got)
+	}
+	if got := updated.GetDateTime("planned_for").Time().Hour(); got != 17 {
+		t.Errorf("planned hour: got %d, want 17", got)
+	}
+
+	if _, err := service.ReschedulePost(app, post.Id, newDay, "de tarde"); !errors.Is(err, service.ErrInvalid) {
+		t.Errorf("invalid slot: got %v, want ErrInvalid", err)
+	}
+	if _, err := service.ReschedulePost(app, "missing", newDay, ""); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("missing post: got %v, want ErrNotFound", err)
+	}
+}
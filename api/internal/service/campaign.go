@@ -0,0 +1,516 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/denisraison/rekan/api/internal/domain"
+	"github.com/denisraison/rekan/api/internal/prefs"
+	"github.com/denisraison/rekan/api/internal/textnorm"
+)
+
+// campaignVars are the placeholders a campaign template may use.
+var campaignVars = []string{"{name}", "{business}", "{next_charge}"}
+
+// campaignFooter goes under every campaign message. The WhatsApp handler
+// turns a SAIR reply into a marketing opt-out (see prefs.Detect).
+const campaignFooter = "\n\nSe não quiser mais receber estas mensagens, responda SAIR."
+
+// campaignBatch caps how many recipients one dispatch queues. Dispatch runs
+// every minute, so this keeps a campaign well under the outbox rate and
+// leaves room for post deliveries.
+const campaignBatch = 10
+
+var campaignVarRe = regexp.MustCompile(`\{[a-z_]+\}`)
+
+// Segment picks the clients a campaign goes to. A client matches when it
+// matches every non-empty list; values compare ignoring case and accents.
+// Without InviteStatuses only active clients match, so placeholders and
+// leads from onboarding never get a campaign by default.
+type Segment struct {
+	Types          []string `json:"types"`
+	Cities         []string `json:"cities"`
+	Tiers          []string `json:"tiers"`
+	InviteStatuses []string `json:"invite_statuses"`
+}
+
+// Matches reports whether a business falls in the segment.
+func (s Segment) Matches(business *core.Record) bool {
+	statuses := s.InviteStatuses
+	if len(statuses) == 0 {
+		statuses = []string{domain.InviteStatusActive}
+	}
+	return matchAny(s.Types, business.GetString("type")) &&
+		matchAny(s.Cities, business.GetString("city")) &&
+		matchAny(s.Tiers, business.GetString("tier")) &&
+		matchAny(statuses, business.GetString("invite_status"))
+}
+
+func matchAny(values []string, v string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	v = foldSegment(v)
+	return slices.ContainsFunc(values, func(want string) bool {
+		return foldSegment(want) == v
+	})
+}
+
+// foldSegment folds a segment value so "Goiânia " and "goiania" compare
+// equal.
+func foldSegment(s string) string {
+	return strings.Join(strings.Fields(textnorm.Fold(s)), " ")
+}
+
+// CampaignParams is what the operator writes to start a campaign.
+type CampaignParams struct {
+	Name     string
+	Template string
+	Segment  Segment
+}
+
+// Campaign is a campaign with how many recipients are in each status.
+type Campaign struct {
+	ID         string
+	Name       string
+	Template   string
+	Segment    Segment
+	Status     string // domain.CampaignStatusDraft, Approved, Done or Cancelled
+	ApprovedAt time.Time
+	Created    time.Time
+	Counts     map[string]int // recipients by domain.RecipientStatus*
+}
+
+// CampaignRecipient is one client's copy of a campaign. For a preview, Skip
+// says why the client would not get it.
+type CampaignRecipient struct {
+	ID           string
+	BusinessID   string
+	BusinessName string
+	Phone        string
+	Text         string
+	Status       string
+	Skip         string
+	Error        string
+	SentAt       time.Time
+}
+
+// CreateCampaign saves a draft campaign. An empty name or template, or a
+// placeholder other than {name}, {business} and {next_charge}, is ErrInvalid.
+func CreateCampaign(app core.App, p CampaignParams) (*core.Record, error) {
+	p.Name = strings.TrimSpace(p.Name)
+	p.Template = strings.TrimSpace(p.Template)
+	if p.Name == "" || p.Template == "" {
+		return nil, fmt.Errorf("%w: nome e texto são obrigatórios", ErrInvalid)
+	}
+	for _, v := range campaignVarRe.FindAllString(p.Template, -1) {
+		if !slices.Contains(campaignVars, v) {
+			return nil, fmt.Errorf("%w: variável desconhecida %s", ErrInvalid, v)
+		}
+	}
+
+	collection, err := app.FindCollectionByNameOrId(domain.CollCampaigns)
+	if err != nil {
+		return nil, fmt.Errorf("find campaigns collection: %w", err)
+	}
+	record := core.NewRecord(collection)
+	record.Set("name", p.Name)
+	record.Set("template", p.Template)
+	record.Set("segment", p.Segment)
+	record.Set("status", domain.CampaignStatusDraft)
+	if err := app.Save(record); err != nil {
+		return nil, fmt.Errorf("save campaign: %w", err)
+	}
+	return record, nil
+}
+
+// ListCampaigns returns every campaign, newest first.
+func ListCampaigns(app core.App) ([]Campaign, error) {
+	records, err := app.FindRecordsByFilter(domain.CollCampaigns, "", "-created", 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("list campaigns: %w", err)
+	}
+	result := make([]Campaign, 0, len(records))
+	for _, r := range records {
+		c, err := toCampaign(app, r)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, c)
+	}
+	return result, nil
+}
+
+// GetCampaign returns a campaign and its recipients. Drafts have none yet;
+// see PreviewCampaign.
+func GetCampaign(app core.App, id string) (*Campaign, []CampaignRecipient, error) {
+	record, err := app.FindRecordById(domain.CollCampaigns, id)
+	if err != nil {
+		return nil, nil, wrapNotFound(err, "campanha")
+	}
+	c, err := toCampaign(app, record)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	records, err := app.FindRecordsByFilter(domain.CollCampaignRecipients, "campaign = {:id}", "@rowid", 0, 0, dbx.Params{"id": id})
+	if err != nil {
+		return nil, nil, fmt.Errorf("list recipients: %w", err)
+	}
+	if errs := app.ExpandRecords(records, []string{"business"}, nil); len(errs) > 0 {
+		return nil, nil, fmt.Errorf("expand recipients: %v", errs)
+	}
+	recipients := make([]CampaignRecipient, 0, len(records))
+	for _, r := range records {
+		rc := CampaignRecipient{
+			ID:         r.Id,
+			BusinessID: r.GetString("business"),
+			Phone:      r.GetString("phone"),
+			Text:       r.GetString("text"),
+			Status:     r.GetString("status"),
+			Error:      r.GetString("error"),
+			SentAt:     r.GetDateTime("sent_at").Time(),
+		}
+		if biz := r.ExpandedOne("business"); biz != nil {
+			rc.BusinessName = biz.GetString("name")
+		}
+		recipients = append(recipients, rc)
+	}
+	return &c, recipients, nil
+}
+
+// PreviewCampaign renders the campaign for every client in its segment as
+// things stand now, without saving anything.
+func PreviewCampaign(app core.App, id string) ([]CampaignRecipient, error) {
+	record, err := app.FindRecordById(domain.CollCampaigns, id)
+	if err != nil {
+		return nil, wrapNotFound(err, "campanha")
+	}
+	return campaignAudience(app, record)
+}
+
+// ApproveCampaign freezes a draft's audience into recipients, which the
+// dispatcher then queues a few at a time. Clients who opted out or have no
+// phone are recorded but not sent to. A campaign that is not a draft is
+// ErrConflict. It returns how many recipients will be sent to.
+func ApproveCampaign(app core.App, id string, now time.Time) (int, error) {
+	record, err := app.FindRecordById(domain.CollCampaigns, id)
+	if err != nil {
+		return 0, wrapNotFound(err, "campanha")
+	}
+	if record.GetString("status") != domain.CampaignStatusDraft {
+		return 0, fmt.Errorf("%w: campanha já aprovada ou cancelada", ErrConflict)
+	}
+	audience, err := campaignAudience(app, record)
+	if err != nil {
+		return 0, err
+	}
+	if len(audience) == 0 {
+		return 0, fmt.Errorf("%w: nenhum cliente no segmento", ErrInvalid)
+	}
+
+	collection, err := app.FindCollectionByNameOrId(domain.CollCampaignRecipients)
+	if err != nil {
+		return 0, fmt.Errorf("find campaign_recipients collection: %w", err)
+	}
+	pending := 0
+	err = app.RunInTransaction(func(txApp core.App) error {
+		for _, a := range audience {
+			r := core.NewRecord(collection)
+			r.Set("campaign", record.Id)
+			r.Set("business", a.BusinessID)
+			r.Set("phone", a.Phone)
+			r.Set("text", a.Text)
+			switch a.Skip {
+			case "":
+				r.Set("status", domain.RecipientStatusPending)
+				pending++
+			case skipOptedOut:
+				r.Set("status", domain.RecipientStatusOptedOut)
+			default:
+				r.Set("status", domain.RecipientStatusSkipped)
+				r.Set("error", a.Skip)
+			}
+			if err := txApp.Save(r); err != nil {
+				return fmt.Errorf("save recipient: %w", err)
+			}
+		}
+		record.Set("status", domain.CampaignStatusApproved)
+		record.Set("approved_at", now.UTC())
+		if pending == 0 {
+			record.Set("status", domain.CampaignStatusDone)
+		}
+		return txApp.Save(record)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return pending, nil
+}
+
+// CancelCampaign stops a campaign. Recipients not sent yet are cancelled,
+// including ones already in the outbox. A finished campaign is ErrConflict.
+func CancelCampaign(app core.App, id string) error {
+	record, err := app.FindRecordById(domain.CollCampaigns, id)
+	if err != nil {
+		return wrapNotFound(err, "campanha")
+	}
+	switch record.GetString("status") {
+	case domain.CampaignStatusDone, domain.CampaignStatusCancelled:
+		return fmt.Errorf("%w: campanha já encerrada", ErrConflict)
+	}
+
+	recipients, err := app.FindRecordsByFilter(domain.CollCampaignRecipients,
+		"campaign = {:id} && (status = {:pending} || status = {:queued})", "", 0, 0,
+		dbx.Params{"id": id, "pending": domain.RecipientStatusPending, "queued": domain.RecipientStatusQueued})
+	if err != nil {
+		return fmt.Errorf("list recipients: %w", err)
+	}
+	return app.RunInTransaction(func(txApp core.App) error {
+		for _, r := range recipients {
+			if r.GetString("status") == domain.RecipientStatusQueued {
+				out, err := txApp.FindRecordById(domain.CollOutbox, r.GetString("outbox"))
+				if err == nil && out.GetString("status") != domain.OutboxStatusPending {
+					continue // already went out or failed; the dispatcher records which
+				}
+				if err == nil {
+					out.Set("status", domain.OutboxStatusFailed)
+					out.Set("last_error", "campanha cancelada")
+					if err := txApp.Save(out); err != nil {
+						return fmt.Errorf("cancel outbox message: %w", err)
+					}
+				}
+			}
+			r.Set("status", domain.RecipientStatusCancelled)
+			if err := txApp.Save(r); err != nil {
+				return fmt.Errorf("cancel recipient: %w", err)
+			}
+		}
+		record.Set("status", domain.CampaignStatusCancelled)
+		return txApp.Save(record)
+	})
+}
+
+// DispatchCampaigns runs every minute. It records how queued campaign
+// messages went, queues the next few pending recipients of approved
+// campaigns and marks campaigns with nothing left to send as done. Nothing
+// is queued in the outbox's quiet hours. It returns how many were queued.
+func DispatchCampaigns(app core.App, now time.Time) int {
+	syncCampaignRecipients(app)
+
+	campaigns, err := app.FindRecordsByFilter(domain.CollCampaigns, "status = {:approved}", "approved_at", 0, 0,
+		dbx.Params{"approved": domain.CampaignStatusApproved})
+	if err != nil {
+		app.Logger().Error("campaigns: list approved", "error", err)
+		return 0
+	}
+
+	// Older campaigns go first; a campaign only gets what is left of the
+	// batch once the ones before it are fully queued.
+	queued := 0
+	for _, c := range campaigns {
+		if DefaultOutboxLimits.Quiet(now) || queued >= campaignBatch {
+			break
+		}
+		pending, err := app.FindRecordsByFilter(domain.CollCampaignRecipients,
+			"campaign = {:id} && status = {:pending}", "@rowid", campaignBatch-queued, 0,
+			dbx.Params{"id": c.Id, "pending": domain.RecipientStatusPending})
+		if err != nil {
+			app.Logger().Error("campaigns: list pending recipients", "campaign", c.Id, "error", err)
+			continue
+		}
+		for _, r := range pending {
+			if queueRecipient(app, r) {
+				queued++
+			}
+		}
+	}
+
+	finishCampaigns(app, campaigns)
+	return queued
+}
+
+// queueRecipient puts one recipient's message in the outbox. Enqueue checks
+// the client's preferences again, since they may have answered SAIR after
+// approval.
+func queueRecipient(app core.App, r *core.Record) bool {
+	err := app.RunInTransaction(func(txApp core.App) error {
+		out, err := Enqueue(txApp, OutboxMessage{
+			BusinessID: r.GetString("business"),
+			Phone:      r.GetString("phone"),
+			Text:       r.GetString("text"),
+			Category:   prefs.Marketing,
+		})
+		if err != nil {
+			return err
+		}
+		r.Set("outbox", out.Id)
+		r.Set("status", domain.RecipientStatusQueued)
+		return txApp.Save(r)
+	})
+	if errors.Is(err, ErrOptedOut) {
+		r.Set("status", domain.RecipientStatusOptedOut)
+		err = app.Save(r)
+	}
+	if err != nil {
+		app.Logger().Error("campaigns: queue recipient", "recipient", r.Id, "error", err)
+		return false
+	}
+	return r.GetString("status") == domain.RecipientStatusQueued
+}
+
+// syncCampaignRecipients copies the outbox outcome onto queued recipients.
+func syncCampaignRecipients(app core.App) {
+	queued, err := app.FindRecordsByFilter(domain.CollCampaignRecipients, "status = {:queued}", "", 0, 0,
+		dbx.Params{"queued": domain.RecipientStatusQueued})
+	if err != nil {
+		app.Logger().Error("campaigns: list queued recipients", "error", err)
+		return
+	}
+	for _, r := range queued {
+		out, err := app.FindRecordById(domain.CollOutbox, r.GetString("outbox"))
+		switch {
+		case err != nil:
+			r.Set("status", domain.RecipientStatusFailed)
+			r.Set("error", "mensagem removida da fila")
+		case out.GetString("status") == domain.OutboxStatusSent:
+			r.Set("status", domain.RecipientStatusSent)
+			r.Set("sent_at", out.GetDateTime("sent_at"))
+		case out.GetString("status") == domain.OutboxStatusFailed && out.GetString("last_error") == ErrOptedOut.Error():
+			r.Set("status", domain.RecipientStatusOptedOut)
+		case out.GetString("status") == domain.OutboxStatusFailed:
+			r.Set("status", domain.RecipientStatusFailed)
+			r.Set("error", out.GetString("last_error"))
+		default:
+			continue
+		}
+		if err := app.Save(r); err != nil {
+			app.Logger().Error("campaigns: save recipient outcome", "recipient", r.Id, "error", err)
+		}
+	}
+}
+
+// finishCampaigns marks approved campaigns without pending or queued
+// recipients as done.
+func finishCampaigns(app core.App, campaigns []*core.Record) {
+	for _, c := range campaigns {
+		open, err := app.CountRecords(domain.CollCampaignRecipients,
+			dbx.HashExp{"campaign": c.Id, "status": []any{domain.RecipientStatusPending, domain.RecipientStatusQueued}})
+		if err != nil || open > 0 {
+			continue
+		}
+		c.Set("status", domain.CampaignStatusDone)
+		if err := app.Save(c); err != nil {
+			app.Logger().Error("campaigns: mark done", "campaign", c.Id, "error", err)
+		}
+	}
+}
+
+// Reasons a client in the segment does not get the message.
+const (
+	skipOptedOut = "pediu para não receber"
+	skipNoPhone  = "sem telefone"
+	skipNoCharge = "sem data de cobrança"
+)
+
+// campaignAudience renders the campaign for each business in its segment,
+// sorted by name.
+func campaignAudience(app core.App, campaign *core.Record) ([]CampaignRecipient, error) {
+	var segment Segment
+	if err := campaign.UnmarshalJSONField("segment", &segment); err != nil {
+		return nil, fmt.Errorf("campaign segment: %w", err)
+	}
+	businesses, err := app.FindRecordsByFilter(domain.CollBusinesses, "", "name", 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("list businesses: %w", err)
+	}
+
+	template := campaign.GetString("template")
+	var result []CampaignRecipient
+	for _, biz := range businesses {
+		if !segment.Matches(biz) {
+			continue
+		}
+		p, err := prefs.Get(app, biz.Id)
+		if err != nil {
+			return nil, err
+		}
+		text, skip := renderCampaign(template, biz)
+		phone := biz.GetString("phone")
+		switch {
+		case !p.Marketing:
+			skip = skipOptedOut
+		case phone == "":
+			skip = skipNoPhone
+		}
+		result = append(result, CampaignRecipient{
+			BusinessID:   biz.Id,
+			BusinessName: biz.GetString("name"),
+			Phone:        phone,
+			Text:         text,
+			Skip:         skip,
+		})
+	}
+	return result, nil
+}
+
+// renderCampaign fills a template for one business and adds the opt-out
+// footer. skip is set when a placeholder has no value for this business.
+func renderCampaign(template string, business *core.Record) (text, skip string) {
+	clientName := business.GetString("client_name")
+	if clientName == "" {
+		clientName = business.GetString("name")
+	}
+	firstName := strings.SplitN(strings.TrimSpace(clientName), " ", 2)[0]
+
+	nextCharge := ""
+	if next := business.GetDateTime("next_charge_date"); !next.IsZero() {
+		nextCharge = next.Time().In(domain.Location).Format("02/01/2006")
+	} else if strings.Contains(template, "{next_charge}") {
+		skip = skipNoCharge
+	}
+
+	text = strings.NewReplacer(
+		"{name}", firstName,
+		"{business}", business.GetString("name"),
+		"{next_charge}", nextCharge,
+	).Replace(template)
+	return text + campaignFooter, skip
+}
+
+func toCampaign(app core.App, r *core.Record) (Campaign, error) {
+	c := Campaign{
+		ID:         r.Id,
+		Name:       r.GetString("name"),
+		Template:   r.GetString("template"),
+		Status:     r.GetString("status"),
+		ApprovedAt: r.GetDateTime("approved_at").Time(),
+		Created:    r.GetDateTime("created").Time(),
+		Counts:     map[string]int{},
+	}
+	_ = r.UnmarshalJSONField("segment", &c.Segment)
+
+	var rows []struct {
+		Status string `db:"status"`
+		N      int    `db:"n"`
+	}
+	err := app.DB().Select("status", "COUNT(*) AS n").
+		From(domain.CollCampaignRecipients).
+		Where(dbx.HashExp{"campaign": r.Id}).
+		GroupBy("status").
+		All(&rows)
+	if err != nil {
+		return c, fmt.Errorf("count recipients: %w", err)
+	}
+	for _, row := range rows {
+		c.Counts[row.Status] = row.N
+	}
+	return c, nil
+}
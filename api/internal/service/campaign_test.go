@@ -0,0 +1,199 @@
+package service_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/denisraison/rekan/api/internal/domain"
+	"github.com/denisraison/rekan/api/internal/prefs"
+	"github.com/denisraison/rekan/api/internal/service"
+)
+
+// seedCampaignClient saves an active client in the given city.
+func seedCampaignClient(t *testing.T, app core.App, name, clientName, city, phone string) *core.Record {
+	t.Helper()
+	businesses, err := app.FindCollectionByNameOrId(domain.CollBusinesses)
+	if err != nil {
+		t.Fatal(err)
+	}
+	biz := core.NewRecord(businesses)
+	biz.Set("name", name)
+	biz.Set("client_name", clientName)
+	biz.Set("type", "confeitaria")
+	biz.Set("city", city)
+	biz.Set("phone", phone)
+	biz.Set("tier", "parceiro")
+	biz.Set("invite_status", domain.InviteStatusActive)
+	biz.Set("next_charge_date", time.Date(2026, 11, 5, 12, 0, 0, 0, domain.Location))
+	if err := app.Save(biz); err != nil {
+		t.Fatal(err)
+	}
+	return biz
+}
+
+// stopMarketing records the client answering SAIR.
+func stopMarketing(t *testing.T, app core.App, businessID string) {
+	t.Helper()
+	if _, _, err := prefs.Apply(app, prefs.Change{BusinessID: businessID, Set: prefs.IntentStopMarketing.Set(), Source: prefs.SourceClient, Text: "SAIR"}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCreateCampaignRejectsUnknownVariable(t *testing.T) {
+	app, _, _ := newTestApp(t)
+	defer app.Cleanup()
+
+	_, err := service.CreateCampaign(app, service.CampaignParams{Name: "Natal", Template: "Oi {nome}!"})
+	if !errors.Is(err, service.ErrInvalid) {
+		t.Fatalf("err = %v, want ErrInvalid", err)
+	}
+}
+
+func TestPreviewCampaign(t *testing.T) {
+	app, _, _ := newTestApp(t)
+	defer app.Cleanup()
+
+	seedCampaignClient(t, app, "Doces da Bia", "Beatriz Souza", "Goiânia", "5562999990001")
+	noPhone := seedCampaignClient(t, app, "Bolos da Cris", "Cristina", "goiânia", "")
+	seedCampaignClient(t, app, "Torta & Cia", "Marta", "Recife", "5581999990002")
+	lead := seedCampaignClient(t, app, "Doces da Jô", "Joana", "Goiania", "5562999990003")
+	lead.Set("invite_status", domain.InviteStatusDraft)
+	if err := app.Save(lead); err != nil {
+		t.Fatal(err)
+	}
+
+	campaign, err := service.CreateCampaign(app, service.CampaignParams{
+		Name:     "Renovação",
+		Template: "Oi {name}! A próxima cobrança da {business} é em {next_charge}.",
+		Segment:  service.Segment{Types: []string{"Confeitaria"}, Cities: []string{"GOIANIA"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	preview, err := service.PreviewCampaign(app, campaign.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(preview) != 2 {
+		t.Fatalf("preview has %d clients, want the two active ones in Goiânia", len(preview))
+	}
+	// Sorted by business name.
+	if preview[0].BusinessID != noPhone.Id || preview[0].Skip == "" {
+		t.Errorf("client without phone should be first and skipped, got %+v", preview[0])
+	}
+	bia := preview[1]
+	if !strings.HasPrefix(bia.Text, "Oi Beatriz! A próxima cobrança da Doces da Bia é em 05/11/2026.") {
+		t.Errorf("text = %q", bia.Text)
+	}
+	if !strings.Contains(bia.Text, "SAIR") || bia.Skip != "" {
+		t.Errorf("want the opt-out footer and no skip, got %+v", bia)
+	}
+}
+
+func TestCampaignApproveDispatchAndOptOut(t *testing.T) {
+	app, _, _ := newTestApp(t)
+	defer app.Cleanup()
+
+	bia := seedCampaignClient(t, app, "Doces da Bia", "Beatriz", "Goiânia", "5562999990001")
+	cris := seedCampaignClient(t, app, "Bolos da Cris", "Cristina", "Goiânia", "5562999990002")
+	lu := seedCampaignClient(t, app, "Brigadeiros da Lu", "Luana", "Goiânia", "5562999990003")
+	stopMarketing(t, app, lu.Id)
+
+	campaign, err := service.CreateCampaign(app, service.CampaignParams{
+		Name:     "Dia das Crianças",
+		Template: "Oi {name}, bora preparar os posts do Dia das Crianças?",
+		Segment:  service.Segment{Cities: []string{"Goiânia"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	noon := time.Date(2026, 10, 5, 12, 0, 0, 0, domain.Location)
+	n, err := service.ApproveCampaign(app, campaign.Id, noon)
+	if err != nil || n != 2 {
+		t.Fatalf("ApproveCampaign = %d, %v; want 2 to send", n, err)
+	}
+	if _, err := service.ApproveCampaign(app, campaign.Id, noon); !errors.Is(err, service.ErrConflict) {
+		t.Errorf("second approve: err = %v, want ErrConflict", err)
+	}
+
+	// Cristina answers SAIR to something else before her turn comes.
+	stopMarketing(t, app, cris.Id)
+
+	if got := service.DispatchCampaigns(app, time.Date(2026, 10, 5, 23, 0, 0, 0, domain.Location)); got != 0 {
+		t.Errorf("queued %d in quiet hours", got)
+	}
+	if got := service.DispatchCampaigns(app, noon); got != 1 {
+		t.Fatalf("queued %d, want only Beatriz", got)
+	}
+	out, err := app.FindFirstRecordByFilter(domain.CollOutbox, "business = {:id}", map[string]any{"id": bia.Id})
+	if err != nil {
+		t.Fatalf("Beatriz's message not in the outbox: %v", err)
+	}
+
+	// The outbox worker sends it; the next dispatch records the outcome.
+	out.Set("status", domain.OutboxStatusSent)
+	out.Set("sent_at", noon)
+	if err := app.Save(out); err != nil {
+		t.Fatal(err)
+	}
+	service.DispatchCampaigns(app, noon.Add(time.Minute))
+
+	c, recipients, err := service.GetCampaign(app, campaign.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Status != domain.CampaignStatusDone {
+		t.Errorf("campaign status = %q, want done", c.Status)
+	}
+	if c.Counts[domain.RecipientStatusSent] != 1 || c.Counts[domain.RecipientStatusOptedOut] != 2 {
+		t.Errorf("counts = %v, want 1 sent and 2 opted out", c.Counts)
+	}
+	for _, r := range recipients {
+		if r.BusinessID == bia.Id && (r.Status != domain.RecipientStatusSent || r.SentAt.IsZero()) {
+			t.Errorf("Beatriz: status %q, sent_at %v", r.Status, r.SentAt)
+		}
+	}
+}
+
+func TestCancelCampaignDropsQueuedMessages(t *testing.T) {
+	app, _, _ := newTestApp(t)
+	defer app.Cleanup()
+
+	bia := seedCampaignClient(t, app, "Doces da Bia", "Beatriz", "Goiânia", "5562999990001")
+	campaign, err := service.CreateCampaign(app, service.CampaignParams{Name: "Promo", Template: "Oi {name}!"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	noon := time.Date(2026, 10, 5, 12, 0, 0, 0, domain.Location)
+	if _, err := service.ApproveCampaign(app, campaign.Id, noon); err != nil {
+		t.Fatal(err)
+	}
+	service.DispatchCampaigns(app, noon)
+
+	if err := service.CancelCampaign(app, campaign.Id); err != nil {
+		t.Fatal(err)
+	}
+	out, err := app.FindFirstRecordByFilter(domain.CollOutbox, "business = {:id}", map[string]any{"id": bia.Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.GetString("status") != domain.OutboxStatusFailed {
+		t.Errorf("outbox status = %q, want failed", out.GetString("status"))
+	}
+	c, _, err := service.GetCampaign(app, campaign.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Status != domain.CampaignStatusCancelled || c.Counts[domain.RecipientStatusCancelled] != 1 {
+		t.Errorf("status %q, counts %v", c.Status, c.Counts)
+	}
+	if err := service.CancelCampaign(app, campaign.Id); !errors.Is(err, service.ErrConflict) {
+		t.Errorf("second cancel: err = %v, want ErrConflict", err)
+	}
+}
@@ -6,10 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	content "github.com/denisraison/rekan/api/internal/content"
 	"github.com/denisraison/rekan/api/internal/domain"
 	"github.com/denisraison/rekan/api/internal/operator"
+	"github.com/denisraison/rekan/api/internal/similarity"
 	"github.com/google/uuid"
 	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase/core"
@@ -22,6 +24,14 @@ type GeneratedPost struct {
 	ProductionNote string
 	Role           string
 	Hook           string
+	PlannedFor     time.Time // zero unless generated as part of a monthly plan
+	PlannedSlot    string    // posting window for PlannedFor, e.g. "10h-14h"
+	Occasion       string    // seasonal date label for plan posts
+	Format         content.Format
+	FormatData     content.FormatData
+	Quality        *QualityReport // nil when generated without a gate
+	Variants       []PostVariant  // nil unless more than one variant was asked for
+	MediaItem      string         // library photo paired with the caption, if one fits
 }
 
 type GenerateBatchResult struct {
@@ -29,7 +39,12 @@ type GenerateBatchResult struct {
 	Posts   []GeneratedPost
 }
 
-func GeneratePosts(ctx context.Context, app core.App, generate content.GenerateFunc, businessID string) (*GenerateBatchResult, error) {
+// GeneratePosts generates a batch of posts for a business. With a gate, the
+// batch is checked and regenerated as needed, and its report is stored on
+// every post. A nil gate saves whatever the generator returns. With variants
+// above one, each post is generated that many times with different hooks and
+// the first variant is saved as the caption until the operator picks another.
+func GeneratePosts(ctx context.Context, app core.App, generate content.GenerateFunc, gate *QualityGate, businessID string, variants int) (*GenerateBatchResult, error) {
 	business, err := app.FindRecordById(domain.CollBusinesses, businessID)
 	if err != nil {
 		return nil, wrapNotFound(err, "negócio não encontrado")
@@ -40,18 +55,38 @@ func GeneratePosts(ctx context.Context, app core.App, generate content.GenerateF
 		return nil, fmt.Errorf("business to profile: %w", err)
 	}
 
-	roles := content.PickRoles(1, nil)
+	history, err := operator.LoadRoleHistory(app, businessID)
+	if err != nil {
+		return nil, fmt.Errorf("load role history: %w", err)
+	}
+	roles := content.PickRolesFor(1, history)
 
 	previousHooks, err := operator.LoadPreviousHooks(app, businessID)
 	if err != nil {
 		return nil, fmt.Errorf("load previous hooks: %w", err)
 	}
 
-	posts, err := generate(ctx, profile, roles, previousHooks)
+	runRoles, err := postRunner(ctx, app, generate, gate, profile, businessID)
+	if err != nil {
+		return nil, err
+	}
+	run := func(hooks []string) ([]content.Post, *QualityReport, error) {
+		return runRoles(roles, hooks)
+	}
+
+	posts, quality, err := run(previousHooks)
 	if err != nil {
 		return nil, err
 	}
 
+	var options [][]PostVariant
+	if variants > 1 {
+		options, err = generateVariants(posts, quality, min(variants, MaxVariants), previousHooks, run)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	hooks := content.ExtractHooks(posts)
 
 	batchID := uuid.New().String()
@@ -73,6 +108,15 @@ func GeneratePosts(ctx context.Context, app core.App, generate content.GenerateF
 		record.Set("production_note", post.ProductionNote)
 		record.Set("edited", false)
 		record.Set("batch_id", batchID)
+		setPostFormat(record, post.Format, post.Data)
+		if quality != nil {
+			record.Set("quality", quality)
+		}
+		var postVariants []PostVariant
+		if i < len(options) {
+			postVariants = options[i]
+			record.Set("variants", postVariants)
+		}
 
 		roleName := ""
 		if i < len(roles) {
@@ -89,6 +133,7 @@ func GeneratePosts(ctx context.Context, app core.App, generate content.GenerateF
 		if err := app.Save(record); err != nil {
 			return nil, fmt.Errorf("save post %d: %w", i, err)
 		}
+		mediaItem := pairSuggestedMedia(app, record)
 
 		result.Posts = append(result.Posts, GeneratedPost{
 			ID:             record.Id,
@@ -97,12 +142,49 @@ func GeneratePosts(ctx context.Context, app core.App, generate content.GenerateF
 			ProductionNote: post.ProductionNote,
 			Role:           roleName,
 			Hook:           hook,
+			Format:         post.Format,
+			FormatData:     post.Data,
+			Quality:        quality,
+			Variants:       postVariants,
+			MediaItem:      mediaItem,
 		})
 	}
 
 	return result, nil
 }
 
+// postRunner returns the generation step shared by single posts and monthly
+// plans: straight to the generator without a gate, otherwise through the
+// gate, checked against posts of peers in the same city and niche.
+func postRunner(ctx context.Context, app core.App, generate content.GenerateFunc, gate *QualityGate, profile content.BusinessProfile, businessID string) (func([]content.Role, []string) ([]content.Post, *QualityReport, error), error) {
+	var peers *similarity.Index
+	if gate != nil && gate.MaxSimilarity > 0 {
+		var err error
+		if peers, err = operator.LoadPeerIndex(app, businessID); err != nil {
+			return nil, fmt.Errorf("load peer posts: %w", err)
+		}
+	}
+	return func(roles []content.Role, hooks []string) ([]content.Post, *QualityReport, error) {
+		if gate == nil {
+			posts, err := generate(ctx, profile, roles, hooks)
+			return posts, nil, err
+		}
+		posts, report, err := gate.Run(ctx, generate, profile, roles, hooks, peers)
+		return posts, &report, err
+	}, nil
+}
+
+// setPostFormat stores a post's format and its reel script, story frames or
+// carousel slides. Feed posts leave both fields empty, like posts created
+// before formats existed.
+func setPostFormat(record *core.Record, format content.Format, data content.FormatData) {
+	if format == "" || format == content.FormatFeed {
+		return
+	}
+	record.Set("format", string(format))
+	record.Set("format_data", data)
+}
+
 func GenerateFromMessage(ctx context.Context, app core.App, genFn content.GenerateFromMessageFunc, businessID, message, messageID string) (*GeneratedPost, error) {
 	business, err := app.FindRecordById(domain.CollBusinesses, businessID)
 	if err != nil {
@@ -176,7 +258,12 @@ func GenerateIdeas(ctx context.Context, app core.App, generate content.GenerateF
 		return nil, fmt.Errorf("load previous hooks: %w", err)
 	}
 
-	return generate(ctx, profile, content.PickRoles(1, nil), previousHooks)
+	history, err := operator.LoadRoleHistory(app, businessID)
+	if err != nil {
+		return nil, fmt.Errorf("load role history: %w", err)
+	}
+
+	return generate(ctx, profile, content.PickRolesFor(1, history), previousHooks)
 }
 
 type SaveProactiveParams struct {
@@ -212,6 +299,7 @@ type ListPostsFilter struct {
 	BusinessIDs  []string // empty means all
 	Status       string   // "pending", "reviewed", or "" for all
 	PostIDPrefix string   // non-empty: filter by id LIKE 'prefix%'
+	BatchID      string   // non-empty: only posts from this generation batch
 }
 
 // ListPosts returns posts matching the filter, ordered by creation date descending.
@@ -229,6 +317,10 @@ func ListPosts(app core.App, filter ListPostsFilter) ([]*core.Record, error) {
 		q = q.AndWhere(dbx.NewExp("id LIKE {:prefix}", dbx.Params{"prefix": filter.PostIDPrefix + "%"}))
 	}
 
+	if filter.BatchID != "" {
+		q = q.AndWhere(dbx.HashExp{"batch_id": filter.BatchID})
+	}
+
 	if len(filter.BusinessIDs) > 0 {
 		params := dbx.Params{}
 		placeholders := make([]string, len(filter.BusinessIDs))
@@ -256,12 +348,16 @@ func ApprovePost(app core.App, postID string) (*core.Record, error) {
 	return ApprovePostRecord(app, record)
 }
 
-// ApprovePostRecord marks an already-loaded post as reviewed.
+// ApprovePostRecord marks an already-loaded post as reviewed and counts the
+// use of its library photo.
 func ApprovePostRecord(app core.App, record *core.Record) (*core.Record, error) {
 	record.Set("reviewed", true)
 	if err := app.Save(record); err != nil {
 		return nil, fmt.Errorf("approving post: %w", err)
 	}
+	if err := countPostMedia(app, record); err != nil {
+		app.Logger().Warn("count approved post media failed", "post", record.Id, "error", err)
+	}
 	return record, nil
 }
 
@@ -291,8 +387,9 @@ type RevisePostParams struct {
 	ProductionNote *string
 }
 
-// RevisePost updates fields on a pending post and sets edited=true.
-// Returns the list of updated field keys.
+// RevisePost updates fields on a pending post and sets edited=true. The
+// caption as generated is kept in original_caption so the style memo can
+// learn from the edit. Returns the list of updated field keys.
 func RevisePost(app core.App, record *core.Record, params RevisePostParams) ([]string, error) {
 	if record.GetBool("reviewed") {
 		return nil, errors.New("post já foi revisado")
@@ -300,6 +397,9 @@ func RevisePost(app core.App, record *core.Record, params RevisePostParams) ([]s
 
 	var updated []string
 	if params.Caption != nil && *params.Caption != record.GetString("caption") {
+		if record.GetString("original_caption") == "" {
+			record.Set("original_caption", record.GetString("caption"))
+		}
 		record.Set("caption", *params.Caption)
 		updated = append(updated, "caption")
 	}
@@ -2,12 +2,14 @@ package service_test
 
 import (
 	"context"
+	"slices"
 	"testing"
 
 	"github.com/denisraison/rekan/api/internal/domain"
 	"github.com/denisraison/rekan/api/internal/service"
 	content "github.com/denisraison/rekan/api/internal/content"
 	_ "github.com/denisraison/rekan/api/migrations"
+	"github.com/pocketbase/pocketbase/core"
 )
 
 func stubGenerate(_ context.Context, _ content.BusinessProfile, _ []content.Role, _ []string) ([]content.Post, error) {
@@ -32,7 +34,7 @@ func TestGeneratePosts(t *testing.T) {
 	app, _, bizID := newTestApp(t)
 	defer app.Cleanup()
 
-	result, err := service.GeneratePosts(context.Background(), app, stubGenerate, bizID)
+	result, err := service.GeneratePosts(context.Background(), app, stubGenerate, nil, bizID, 1)
 	if err != nil {
 		t.Fatalf("GeneratePosts: %v", err)
 	}
@@ -60,6 +62,45 @@ func TestGeneratePosts(t *testing.T) {
 	}
 }
 
+func TestGeneratePostsFormat(t *testing.T) {
+	app, _, bizID := newTestApp(t)
+	defer app.Cleanup()
+
+	reel := func(_ context.Context, _ content.BusinessProfile, _ []content.Role, _ []string) ([]content.Post, error) {
+		return []content.Post{{
+			Caption: "Sábado tem horário.",
+			Format:  content.FormatReel,
+			Data: content.FormatData{Reel: &content.Reel{
+				Hook:   "Você lixa a unha errado",
+				Scenes: []content.ReelScene{{Visual: "Lixa na mão"}},
+			}},
+		}}, nil
+	}
+
+	result, err := service.GeneratePosts(context.Background(), app, reel, nil, bizID, 1)
+	if err != nil {
+		t.Fatalf("GeneratePosts: %v", err)
+	}
+	if got := result.Posts[0].Hook; got != "Você lixa a unha errado" {
+		t.Errorf("hook: got %q, want the reel hook", got)
+	}
+
+	post, err := app.FindRecordById(domain.CollPosts, result.Posts[0].ID)
+	if err != nil {
+		t.Fatalf("find post: %v", err)
+	}
+	if got := post.GetString("format"); got != "reel" {
+		t.Errorf("format: got %q, want %q", got, "reel")
+	}
+	var data content.FormatData
+	if err := post.UnmarshalJSONField("format_data", &data); err != nil {
+		t.Fatalf("unmarshal format_data: %v", err)
+	}
+	if data.Reel == nil || len(data.Reel.Scenes) != 1 || data.Reel.Scenes[0].Visual != "Lixa na mão" {
+		t.Errorf("format_data: got %+v", data)
+	}
+}
+
 func TestGenerateFromMessage(t *testing.T) {
 	app, _, bizID := newTestApp(t)
 	defer app.Cleanup()
@@ -112,6 +153,45 @@ func TestGenerateIdeas(t *testing.T) {
 	}
 }
 
+func TestGenerateIdeasRotatesRoles(t *testing.T) {
+	app, _, bizID := newTestApp(t)
+	defer app.Cleanup()
+
+	col, err := app.FindCollectionByNameOrId(domain.CollPosts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recent := []string{"Bastidor", "Útil", "Pessoal"}
+	for _, role := range recent {
+		r := core.NewRecord(col)
+		r.Set("business", bizID)
+		r.Set("caption", "Post de "+role)
+		r.Set("role", role)
+		if err := app.Save(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var got []string
+	capture := func(_ context.Context, _ content.BusinessProfile, roles []content.Role, _ []string) ([]content.Post, error) {
+		for _, r := range roles {
+			got = append(got, r.Name)
+		}
+		return nil, nil
+	}
+	for range 20 {
+		if _, err := service.GenerateIdeas(context.Background(), app, capture, bizID); err != nil {
+			t.Fatalf("GenerateIdeas: %v", err)
+		}
+	}
+
+	for _, name := range got {
+		if slices.Contains(recent, name) {
+			t.Errorf("picked recently used role %q", name)
+		}
+	}
+}
+
 func TestSaveProactivePost(t *testing.T) {
 	app, _, bizID := newTestApp(t)
 	defer app.Cleanup()
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/denisraison/rekan/api/internal/domain"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// DefaultUnreadAfter is how long a delivered post may sit unread before it
+// shows up in the unread list.
+const DefaultUnreadAfter = 24 * time.Hour
+
+// Delivery is the WhatsApp status of the message that carried a post.
+// Times are zero until the matching receipt arrives.
+type Delivery struct {
+	PostID       string
+	BusinessID   string
+	BusinessName string
+	Caption      string
+	Status       string // domain.MsgStatusSent, MsgStatusDelivered or MsgStatusRead
+	SentAt       time.Time
+	DeliveredAt  time.Time
+	ReadAt       time.Time
+}
+
+// PostDelivery returns the status of the latest message sent for a post.
+// ErrNotFound means the post was never sent.
+func PostDelivery(app core.App, postID string) (*Delivery, error) {
+	var msg core.Record
+	err := app.RecordQuery(domain.CollMessages).
+		AndWhere(dbx.HashExp{"post": postID, "direction": domain.DirectionOutgoing}).
+		OrderBy("created DESC").
+		Limit(1).
+		One(&msg)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: post não enviado", ErrNotFound)
+		}
+		return nil, fmt.Errorf("find post message: %w", err)
+	}
+	d := toDelivery(&msg)
+	return &d, nil
+}
+
+// ListUnreadPosts returns posts delivered more than olderThan ago that the
+// client has not read, oldest delivery first.
+func ListUnreadPosts(app core.App, olderThan time.Duration) ([]Delivery, error) {
+	cutoff, err := types.ParseDateTime(time.Now().Add(-olderThan))
+	if err != nil {
+		return nil, fmt.Errorf("unread cutoff: %w", err)
+	}
+
+	var msgs []*core.Record
+	err = app.RecordQuery(domain.CollMessages).
+		AndWhere(dbx.HashExp{"direction": domain.DirectionOutgoing, "status": domain.MsgStatusDelivered}).
+		AndWhere(dbx.NewExp("post != '' AND delivered_at < {:cutoff}", dbx.Params{"cutoff": cutoff.String()})).
+		OrderBy("delivered_at ASC").
+		All(&msgs)
+	if err != nil {
+		return nil, fmt.Errorf("listing unread posts: %w", err)
+	}
+
+	names := map[string]string{}
+	seen := map[string]bool{}
+	result := make([]Delivery, 0, len(msgs))
+	for _, m := range msgs {
+		postID := m.GetString("post")
+		if seen[postID] {
+			continue
+		}
+		seen[postID] = true
+
+		d := toDelivery(m)
+		if post, err := app.FindRecordById(domain.CollPosts, postID); err == nil {
+			d.Caption = post.GetString("caption")
+		}
+		name, ok := names[d.BusinessID]
+		if !ok {
+			if biz, err := app.FindRecordById(domain.CollBusinesses, d.BusinessID); err == nil {
+				name = biz.GetString("name")
+			}
+			names[d.BusinessID] = name
+		}
+		d.BusinessName = name
+		result = append(result, d)
+	}
+	return result, nil
+}
+
+func toDelivery(msg *core.Record) Delivery {
+	return Delivery{
+		PostID:      msg.GetString("post"),
+		BusinessID:  msg.GetString("business"),
+		Caption:     msg.GetString("content"),
+		Status:      msg.GetString("status"),
+		SentAt:      msg.GetDateTime("sent_at").Time(),
+		DeliveredAt: msg.GetDateTime("delivered_at").Time(),
+		ReadAt:      msg.GetDateTime("read_at").Time(),
+	}
+}
@@ -0,0 +1,90 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/denisraison/rekan/api/internal/domain"
+	"github.com/denisraison/rekan/api/internal/service"
+)
+
+func TestPostDeliveryAndUnread(t *testing.T) {
+	app, _, bizID := newTestApp(t)
+	defer app.Cleanup()
+	setPhone(t, app, bizID, "5511999990000")
+
+	posts, err := app.FindCollectionByNameOrId(domain.CollPosts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	captions := []string{"Pão quentinho", "Bolo de fubá"}
+	var postIDs []string
+	for _, caption := range captions {
+		p := core.NewRecord(posts)
+		p.Set("business", bizID)
+		p.Set("caption", caption)
+		if err := app.Save(p); err != nil {
+			t.Fatal(err)
+		}
+		postIDs = append(postIDs, p.Id)
+	}
+
+	if _, err := service.PostDelivery(app, postIDs[0]); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("unsent post: got %v, want ErrNotFound", err)
+	}
+
+	for i, id := range postIDs {
+		err := service.SendTextMessage(app, service.SendTextParams{BusinessID: bizID, PostID: id, Caption: captions[i]})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, domain.Location)
+	w := &service.OutboxWorker{App: app, Client: &fakeWA{}, Limits: service.DefaultOutboxLimits, Now: func() time.Time { return now }}
+	w.Drain(context.Background())
+	now = now.Add(time.Minute)
+	w.Drain(context.Background())
+
+	d, err := service.PostDelivery(app, postIDs[0])
+	if err != nil {
+		t.Fatalf("PostDelivery: %v", err)
+	}
+	if d.Status != domain.MsgStatusSent || d.SentAt.IsZero() {
+		t.Errorf("delivery: got %+v", d)
+	}
+
+	// Both delivered two days ago; only the first is still unread.
+	msgs, err := app.FindRecordsByFilter(domain.CollMessages, "post != ''", "", 0, 0)
+	if err != nil || len(msgs) != 2 {
+		t.Fatalf("post messages: %d, %v", len(msgs), err)
+	}
+	for _, m := range msgs {
+		if m.GetString("wa_message_id") == "" {
+			t.Errorf("message %s stored without its WhatsApp ID", m.Id)
+		}
+		m.Set("delivered_at", time.Now().Add(-48*time.Hour).UTC())
+		m.Set("status", domain.MsgStatusDelivered)
+		if m.GetString("post") == postIDs[1] {
+			m.Set("read_at", time.Now().UTC())
+			m.Set("status", domain.MsgStatusRead)
+		}
+		if err := app.Save(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	unread, err := service.ListUnreadPosts(app, service.DefaultUnreadAfter)
+	if err != nil {
+		t.Fatalf("ListUnreadPosts: %v", err)
+	}
+	if len(unread) != 1 || unread[0].PostID != postIDs[0] || unread[0].Caption != "Pão quentinho" || unread[0].BusinessName != "Padaria Teste" {
+		t.Fatalf("unread: got %+v", unread)
+	}
+	if unread, _ := service.ListUnreadPosts(app, 72*time.Hour); len(unread) != 0 {
+		t.Errorf("nothing was delivered 72h ago, got %+v", unread)
+	}
+}
@@ -10,12 +10,10 @@ import (
 
 	asaasclient "github.com/denisraison/rekan/api/internal/asaas"
 	"github.com/denisraison/rekan/api/internal/domain"
+	"github.com/denisraison/rekan/api/internal/prefs"
 	"github.com/denisraison/rekan/api/internal/pricing"
 	"github.com/denisraison/rekan/api/internal/terms"
-	"github.com/denisraison/rekan/api/internal/whatsapp"
 	"github.com/pocketbase/pocketbase/core"
-	"go.mau.fi/whatsmeow/proto/waE2E"
-	"go.mau.fi/whatsmeow/types"
 )
 
 var errAlreadyClaimed = errors.New("invite already claimed")
@@ -24,7 +22,9 @@ type SendInviteResult struct {
 	InviteURL string
 }
 
-func SendInvite(ctx context.Context, app core.App, wa *whatsapp.Client, businessID, appURL string) (*SendInviteResult, error) {
+// SendInvite creates an invite token for the business and queues the link to
+// the client's WhatsApp.
+func SendInvite(app core.App, businessID, appURL string) (*SendInviteResult, error) {
 	business, err := app.FindRecordById(domain.CollBusinesses, businessID)
 	if err != nil {
 		return nil, wrapNotFound(err, "negócio não encontrado")
@@ -56,24 +56,20 @@ func SendInvite(ctx context.Context, app core.App, wa *whatsapp.Client, business
 	clientName := business.GetString("client_name")
 	text := "Oi " + clientName + "! Segue o link pra ativar seu acesso ao Rekan: " + inviteURL
 
-	jid := types.NewJID(phone, types.DefaultUserServer)
-	_, err = wa.SendMessage(ctx, jid, &waE2E.Message{
-		Conversation: &text,
+	now := time.Now().UTC().Format(time.RFC3339)
+	err = app.RunInTransaction(func(txApp core.App) error {
+		if _, err := Enqueue(txApp, OutboxMessage{BusinessID: businessID, Phone: phone, Text: text, Category: prefs.Marketing}); err != nil {
+			return err
+		}
+		business.Set("invite_token", token)
+		business.Set("invite_status", domain.InviteStatusInvited)
+		business.Set("invite_sent_at", now)
+		return txApp.Save(business)
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	now := time.Now().UTC().Format(time.RFC3339)
-	business.Set("invite_token", token)
-	business.Set("invite_status", domain.InviteStatusInvited)
-	business.Set("invite_sent_at", now)
-	if err := app.Save(business); err != nil {
-		return nil, err
-	}
-
-	StoreOutgoingMessage(app, businessID, phone, domain.MsgTypeText, text, nil)
-
 	return &SendInviteResult{InviteURL: inviteURL}, nil
 }
 
@@ -28,7 +28,7 @@ func TestSendInviteNoPhone(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	_, err = service.SendInvite(context.Background(), app, nil, bizID, "https://app.rekan.com.br")
+	_, err = service.SendInvite(app, bizID, "https://app.rekan.com.br")
 	if err == nil {
 		t.Fatal("expected error for missing phone")
 	}
@@ -48,7 +48,7 @@ func TestSendInviteMissingTierCommitment(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	_, err = service.SendInvite(context.Background(), app, nil, bizID, "https://app.rekan.com.br")
+	_, err = service.SendInvite(app, bizID, "https://app.rekan.com.br")
 	if err == nil {
 		t.Fatal("expected error for missing tier/commitment")
 	}
@@ -69,7 +69,7 @@ func TestSendInviteRejectsActiveOrAccepted(t *testing.T) {
 				t.Fatal(err)
 			}
 
-			_, err = service.SendInvite(context.Background(), app, nil, bizID, "https://app.rekan.com.br")
+			_, err = service.SendInvite(app, bizID, "https://app.rekan.com.br")
 			if err == nil {
 				t.Fatalf("expected error for status %q", status)
 			}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/denisraison/rekan/api/internal/domain"
+	"github.com/denisraison/rekan/api/internal/library"
+)
+
+// SearchMedia returns a business's library items matching query, best first.
+func SearchMedia(app core.App, businessID, query string, limit int) ([]*core.Record, error) {
+	if _, err := app.FindRecordById(domain.CollBusinesses, businessID); err != nil {
+		return nil, wrapNotFound(err, "negócio não encontrado")
+	}
+	return library.Search(app, businessID, query, limit)
+}
+
+// PairPostMedia records the library photo or video that goes with a post.
+// The use counts right away only if the post is already approved or sent;
+// otherwise it counts when that happens. An empty itemID removes the pairing.
+func PairPostMedia(app core.App, postID, itemID string) (*core.Record, error) {
+	post, err := app.FindRecordById(domain.CollPosts, postID)
+	if err != nil {
+		return nil, wrapNotFound(err, "post não encontrado")
+	}
+	if itemID == post.GetString("media_item") {
+		return post, nil
+	}
+
+	var item *core.Record
+	if itemID != "" {
+		item, err = app.FindRecordById(domain.CollMediaItems, itemID)
+		if err != nil {
+			return nil, wrapNotFound(err, "mídia não encontrada")
+		}
+		if item.GetString("business") != post.GetString("business") {
+			return nil, fmt.Errorf("%w: mídia de outra cliente", ErrInvalid)
+		}
+	}
+
+	err = app.RunInTransaction(func(txApp core.App) error {
+		post.Set("media_item", itemID)
+		if err := txApp.Save(post); err != nil {
+			return fmt.Errorf("save post media: %w", err)
+		}
+		if post.GetString("media_counted") != "" || postApproved(post) {
+			return countPostMedia(txApp, post)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return post, nil
+}
+
+// postApproved reports whether the operator approved the post: reviewed
+// without a rejection note.
+func postApproved(post *core.Record) bool {
+	return post.GetBool("reviewed") && post.GetString("review_note") == ""
+}
+
+// countPostMedia counts one use of the post's library item, unless that
+// item was already counted for this post. Called once the post is approved
+// or sent, so suggestions for drafts never wear an item out.
+func countPostMedia(app core.App, post *core.Record) error {
+	itemID := post.GetString("media_item")
+	if itemID == "" || itemID == post.GetString("media_counted") {
+		return nil
+	}
+	item, err := app.FindRecordById(domain.CollMediaItems, itemID)
+	if err != nil {
+		return fmt.Errorf("find post media: %w", err)
+	}
+	if err := library.MarkUsed(app, item, time.Now()); err != nil {
+		return fmt.Errorf("mark media used: %w", err)
+	}
+	post.Set("media_counted", itemID)
+	if err := app.Save(post); err != nil {
+		return fmt.Errorf("save counted media: %w", err)
+	}
+	return nil
+}
+
+// pairSuggestedMedia pairs a freshly saved post with the library photo that
+// best fits its caption, if any. Returns the paired item ID. The use is not
+// counted until the post is approved or sent. Errors are logged: a post
+// without a photo is still a post.
+func pairSuggestedMedia(app core.App, post *core.Record) string {
+	item, err := library.Suggest(app, post.GetString("business"), post.GetString("caption"))
+	if err != nil {
+		app.Logger().Warn("suggest media failed", "post", post.Id, "error", err)
+		return ""
+	}
+	if item == nil {
+		return ""
+	}
+	post.Set("media_item", item.Id)
+	if err := app.Save(post); err != nil {
+		app.Logger().Warn("pair suggested media failed", "post", post.Id, "error", err)
+		return ""
+	}
+	return item.Id
+}
@@ -0,0 +1,141 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+
+	content "github.com/denisraison/rekan/api/internal/content"
+	"github.com/denisraison/rekan/api/internal/domain"
+	"github.com/denisraison/rekan/api/internal/library"
+	"github.com/denisraison/rekan/api/internal/service"
+)
+
+func seedLibraryPhoto(t *testing.T, app core.App, businessID, description string) *core.Record {
+	t.Helper()
+	messages, err := app.FindCollectionByNameOrId(domain.CollMessages)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := core.NewRecord(messages)
+	msg.Set("business", businessID)
+	msg.Set("phone", "5511999990000")
+	msg.Set("type", domain.MsgTypeImage)
+	msg.Set("direction", domain.DirectionIncoming)
+	if err := app.Save(msg); err != nil {
+		t.Fatal(err)
+	}
+	item, err := library.Add(app, library.AddParams{BusinessID: businessID, MessageID: msg.Id, Type: domain.MsgTypeImage, Description: description})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return item
+}
+
+func TestPairPostMedia(t *testing.T) {
+	app, _, bizID := newTestApp(t)
+	defer app.Cleanup()
+
+	item := seedLibraryPhoto(t, app, bizID, "Pão francês saindo do forno")
+	posts, _ := app.FindCollectionByNameOrId(domain.CollPosts)
+	post := core.NewRecord(posts)
+	post.Set("business", bizID)
+	post.Set("caption", "Pão quentinho!")
+	if err := app.Save(post); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := service.PairPostMedia(app, post.Id, item.Id); err != nil {
+		t.Fatalf("pair: %v", err)
+	}
+	post, _ = app.FindRecordById(domain.CollPosts, post.Id)
+	item, _ = app.FindRecordById(domain.CollMediaItems, item.Id)
+	if post.GetString("media_item") != item.Id || item.GetInt("used_count") != 0 {
+		t.Errorf("draft: media_item %q, used_count %d; a draft's photo is not a use yet", post.GetString("media_item"), item.GetInt("used_count"))
+	}
+
+	if _, err := service.ApprovePost(app, post.Id); err != nil {
+		t.Fatal(err)
+	}
+	// Pairing the same item again is not a second use.
+	if _, err := service.PairPostMedia(app, post.Id, item.Id); err != nil {
+		t.Fatal(err)
+	}
+	item, _ = app.FindRecordById(domain.CollMediaItems, item.Id)
+	if item.GetInt("used_count") != 1 {
+		t.Errorf("approved: used_count %d, want 1", item.GetInt("used_count"))
+	}
+
+	// Swapping the photo on an approved post counts the new one.
+	second := seedLibraryPhoto(t, app, bizID, "Cesta de pães")
+	if _, err := service.PairPostMedia(app, post.Id, second.Id); err != nil {
+		t.Fatal(err)
+	}
+	second, _ = app.FindRecordById(domain.CollMediaItems, second.Id)
+	if second.GetInt("used_count") != 1 {
+		t.Errorf("swapped: used_count %d, want 1", second.GetInt("used_count"))
+	}
+
+	if _, err := service.PairPostMedia(app, post.Id, ""); err != nil {
+		t.Fatal(err)
+	}
+	post, _ = app.FindRecordById(domain.CollPosts, post.Id)
+	if post.GetString("media_item") != "" {
+		t.Error("empty item should unpair")
+	}
+
+	businesses, _ := app.FindCollectionByNameOrId(domain.CollBusinesses)
+	other := core.NewRecord(businesses)
+	other.Set("name", "Salão da Lu")
+	other.Set("type", "salão")
+	other.Set("city", "São Paulo")
+	if err := app.Save(other); err != nil {
+		t.Fatal(err)
+	}
+	foreign := seedLibraryPhoto(t, app, other.Id, "Salão decorado")
+	if _, err := service.PairPostMedia(app, post.Id, foreign.Id); !errors.Is(err, service.ErrInvalid) {
+		t.Errorf("pairing another client's photo: err = %v, want ErrInvalid", err)
+	}
+}
+
+func TestGeneratePostsPairsLibraryPhoto(t *testing.T) {
+	app, _, bizID := newTestApp(t)
+	defer app.Cleanup()
+
+	seedLibraryPhoto(t, app, bizID, "Balcão com pães franceses")
+	photo := seedLibraryPhoto(t, app, bizID, "Pão de queijo dourado numa cesta de palha")
+
+	generate := func(context.Context, content.BusinessProfile, []content.Role, []string) ([]content.Post, error) {
+		return []content.Post{{Caption: "Pão de queijo dourado saindo agora, corre!"}}, nil
+	}
+	result, err := service.GeneratePosts(context.Background(), app, generate, nil, bizID, 1)
+	if err != nil {
+		t.Fatalf("GeneratePosts: %v", err)
+	}
+	if got := result.Posts[0].MediaItem; got != photo.Id {
+		t.Fatalf("MediaItem = %q, want the photo sharing the caption's words", got)
+	}
+	post, _ := app.FindRecordById(domain.CollPosts, result.Posts[0].ID)
+	photo, _ = app.FindRecordById(domain.CollMediaItems, photo.Id)
+	if post.GetString("media_item") != photo.Id || photo.GetInt("used_count") != 0 {
+		t.Errorf("post media_item %q, photo used_count %d; a suggestion is not a use", post.GetString("media_item"), photo.GetInt("used_count"))
+	}
+
+	// Sending the post counts the use, once.
+	biz, _ := app.FindRecordById(domain.CollBusinesses, bizID)
+	biz.Set("phone", "5511999990000")
+	if err := app.Save(biz); err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		if err := service.SendTextMessage(app, service.SendTextParams{BusinessID: bizID, PostID: post.Id, Caption: post.GetString("caption")}); err != nil {
+			t.Fatalf("SendTextMessage: %v", err)
+		}
+	}
+	photo, _ = app.FindRecordById(domain.CollMediaItems, photo.Id)
+	if photo.GetInt("used_count") != 1 {
+		t.Errorf("sent: used_count %d, want 1", photo.GetInt("used_count"))
+	}
+}
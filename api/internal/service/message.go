@@ -5,16 +5,15 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"math/rand/v2"
 	"strings"
 	"time"
 
 	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/proto/waE2E"
 	"go.mau.fi/whatsmeow/types"
+	content "github.com/denisraison/rekan/api/internal/content"
 	"github.com/denisraison/rekan/api/internal/domain"
 	"github.com/denisraison/rekan/api/internal/postingtime"
-	wa "github.com/denisraison/rekan/api/internal/whatsapp"
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/tools/filesystem"
 )
@@ -23,6 +22,8 @@ var (
 	ErrNoPhone  = errors.New("cliente sem telefone cadastrado")
 	ErrNotFound = errors.New("não encontrado")
 	ErrConflict = errors.New("conflito")
+	ErrInvalid  = errors.New("inválido")
+	ErrOptedOut = errors.New("cliente pediu para não receber estas mensagens")
 )
 
 // WAClient is the subset of whatsmeow used for sending messages.
@@ -40,37 +41,60 @@ func wrapNotFound(err error, msg string) error {
 	return err
 }
 
-// StoreOutgoingMessage saves an outgoing message record. Errors are logged but
-// not returned since message storage is best-effort.
-func StoreOutgoingMessage(app core.App, businessID, phone, msgType, content string, media *filesystem.File) {
+// OutgoingMessage is a message we sent, as stored in the conversation.
+type OutgoingMessage struct {
+	BusinessID  string
+	Phone       string
+	Type        string
+	Content     string
+	WAMessageID string // from the SendResponse; receipts are matched on it
+	PostID      string // set when the message carries a post's caption
+	Media       *filesystem.File
+}
+
+// StoreOutgoingMessage saves an outgoing message record with status sent.
+// Errors are logged but not returned since message storage is best-effort.
+func StoreOutgoingMessage(app core.App, msg OutgoingMessage) {
 	collection, err := app.FindCollectionByNameOrId(domain.CollMessages)
 	if err != nil {
 		app.Logger().Error("storeOutgoingMessage: collection not found", "error", err)
 		return
 	}
+	now := time.Now().UTC()
 	record := core.NewRecord(collection)
-	record.Set("business", businessID)
-	record.Set("phone", phone)
-	record.Set("type", msgType)
-	record.Set("content", content)
+	record.Set("business", msg.BusinessID)
+	record.Set("phone", msg.Phone)
+	record.Set("type", msg.Type)
+	record.Set("content", msg.Content)
 	record.Set("direction", domain.DirectionOutgoing)
-	record.Set("wa_timestamp", time.Now().UTC().Format(time.RFC3339))
-	if media != nil {
-		record.Set("media", media)
+	record.Set("wa_timestamp", now.Format(time.RFC3339))
+	record.Set("wa_message_id", msg.WAMessageID)
+	record.Set("status", domain.MsgStatusSent)
+	record.Set("sent_at", now)
+	if msg.PostID != "" {
+		record.Set("post", msg.PostID)
+	}
+	if msg.Media != nil {
+		record.Set("media", msg.Media)
 	}
 	if err := app.Save(record); err != nil {
-		app.Logger().Error("storeOutgoingMessage: failed", "type", msgType, "error", err)
+		app.Logger().Error("storeOutgoingMessage: failed", "type", msg.Type, "error", err)
 	}
 }
 
 type SendTextParams struct {
 	BusinessID     string
+	PostID         string // optional; links the caption message to the post for receipts
 	Caption        string
 	Hashtags       string
 	ProductionNote string
 }
 
-func SendTextMessage(ctx context.Context, app core.App, waClient WAClient, params SendTextParams) error {
+// SendTextMessage queues a post for the client: the caption with hashtags,
+// then the reel script, story frames or carousel slides of the linked post,
+// then the production note, and a posting time tip after either. Stories
+// have no caption of their own, so their frames replace it.
+func SendTextMessage(app core.App, params SendTextParams) error {
 	business, err := app.FindRecordById(domain.CollBusinesses, params.BusinessID)
 	if err != nil {
 		return err
@@ -81,50 +105,48 @@ func SendTextMessage(ctx context.Context, app core.App, waClient WAClient, param
 		return ErrNoPhone
 	}
 
-	jid := types.NewJID(phone, types.DefaultUserServer)
+	var guide string
+	var format content.Format
+	var postRecord *core.Record
+	if params.PostID != "" {
+		record, err := app.FindRecordById(domain.CollPosts, params.PostID)
+		if err != nil {
+			return wrapNotFound(err, "post não encontrado")
+		}
+		postRecord = record
+		post, err := recordToPost(record)
+		if err != nil {
+			return err
+		}
+		guide, format = content.ClientGuide(post), post.Format
+	}
 
 	text := params.Caption
 	if strings.TrimSpace(params.Hashtags) != "" {
 		text += "\n\n" + params.Hashtags
 	}
+	if format == content.FormatStory && guide != "" {
+		text, guide = guide, ""
+	}
+	msgs := []OutboxMessage{{BusinessID: params.BusinessID, PostID: params.PostID, Phone: phone, Text: text}}
+	if guide != "" {
+		msgs = append(msgs, OutboxMessage{BusinessID: params.BusinessID, Phone: phone, Text: guide})
+	}
+	if strings.TrimSpace(params.ProductionNote) != "" {
+		msgs = append(msgs, OutboxMessage{BusinessID: params.BusinessID, Phone: phone, Text: "*Dica de foto:* " + params.ProductionNote})
+	}
+	if len(msgs) > 1 {
+		msgs = append(msgs, OutboxMessage{BusinessID: params.BusinessID, Phone: phone, Text: postingtime.Tip(business.GetString("type"))})
+	}
 
-	stop := wa.Typing(ctx, waClient, jid)
-	if _, err = waClient.SendMessage(ctx, jid, &waE2E.Message{
-		Conversation: &text,
-	}); err != nil {
-		stop()
+	if err := enqueueAll(app, msgs...); err != nil {
 		return err
 	}
-	stop()
-
-	StoreOutgoingMessage(app, params.BusinessID, phone, domain.MsgTypeText, text, nil)
-
-	if strings.TrimSpace(params.ProductionNote) != "" {
-		time.Sleep(time.Duration(500+rand.IntN(1000)) * time.Millisecond)
-
-		stop = wa.Typing(ctx, waClient, jid)
-		noteText := "*Dica de foto:* " + params.ProductionNote
-		if _, err := waClient.SendMessage(ctx, jid, &waE2E.Message{
-			Conversation: &noteText,
-		}); err != nil {
-			app.Logger().Error("sendTextMessage: production note", "error", err)
-		}
-		stop()
-		StoreOutgoingMessage(app, params.BusinessID, phone, domain.MsgTypeText, noteText, nil)
-
-		// Posting time tip
-		time.Sleep(time.Duration(500+rand.IntN(1000)) * time.Millisecond)
-		stop = wa.Typing(ctx, waClient, jid)
-		tipText := postingtime.Tip(business.GetString("type"))
-		if _, err := waClient.SendMessage(ctx, jid, &waE2E.Message{
-			Conversation: &tipText,
-		}); err != nil {
-			app.Logger().Error("sendTextMessage: posting time tip", "error", err)
+	if postRecord != nil {
+		if err := countPostMedia(app, postRecord); err != nil {
+			app.Logger().Warn("count sent post media failed", "post", postRecord.Id, "error", err)
 		}
-		stop()
-		StoreOutgoingMessage(app, params.BusinessID, phone, domain.MsgTypeText, tipText, nil)
 	}
-
 	return nil
 }
 
@@ -136,7 +158,8 @@ type SendMediaParams struct {
 	Filename    string
 }
 
-func SendMediaMessage(ctx context.Context, app core.App, waClient *wa.Client, params SendMediaParams) error {
+// SendMediaMessage queues an image or video for the client.
+func SendMediaMessage(app core.App, params SendMediaParams) error {
 	business, err := app.FindRecordById(domain.CollBusinesses, params.BusinessID)
 	if err != nil {
 		return err
@@ -147,66 +170,19 @@ func SendMediaMessage(ctx context.Context, app core.App, waClient *wa.Client, pa
 		return ErrNoPhone
 	}
 
-	jid := types.NewJID(phone, types.DefaultUserServer)
-	isVideo := strings.HasPrefix(params.ContentType, "video/")
-
-	var waMediaType whatsmeow.MediaType
-	if isVideo {
-		waMediaType = whatsmeow.MediaVideo
-	} else {
-		waMediaType = whatsmeow.MediaImage
-	}
-
-	resp, err := waClient.Upload(ctx, params.Data, waMediaType)
-	if err != nil {
-		return err
-	}
-
-	var msg *waE2E.Message
-	if isVideo {
-		msg = &waE2E.Message{
-			VideoMessage: &waE2E.VideoMessage{
-				Caption:       new(params.Caption),
-				Mimetype:      new(params.ContentType),
-				URL:           &resp.URL,
-				DirectPath:    &resp.DirectPath,
-				MediaKey:      resp.MediaKey,
-				FileEncSHA256: resp.FileEncSHA256,
-				FileSHA256:    resp.FileSHA256,
-				FileLength:    &resp.FileLength,
-			},
-		}
-	} else {
-		msg = &waE2E.Message{
-			ImageMessage: &waE2E.ImageMessage{
-				Caption:       new(params.Caption),
-				Mimetype:      new(params.ContentType),
-				URL:           &resp.URL,
-				DirectPath:    &resp.DirectPath,
-				MediaKey:      resp.MediaKey,
-				FileEncSHA256: resp.FileEncSHA256,
-				FileSHA256:    resp.FileSHA256,
-				FileLength:    &resp.FileLength,
-			},
-		}
-	}
-
-	stop := wa.Typing(ctx, waClient, jid)
-	defer stop()
-
-	if _, err = waClient.SendMessage(ctx, jid, msg); err != nil {
-		return err
-	}
-
 	msgType := domain.MsgTypeImage
-	if isVideo {
+	if strings.HasPrefix(params.ContentType, "video/") {
 		msgType = domain.MsgTypeVideo
 	}
-	mediaFile, err := filesystem.NewFileFromBytes(params.Data, params.Filename)
-	if err != nil {
-		app.Logger().Error("sendMediaMessage: create file from bytes", "error", err)
-	}
-	StoreOutgoingMessage(app, params.BusinessID, phone, msgType, params.Caption, mediaFile)
 
-	return nil
+	_, err = Enqueue(app, OutboxMessage{
+		BusinessID:  params.BusinessID,
+		Phone:       phone,
+		Type:        msgType,
+		Text:        params.Caption,
+		Media:       params.Data,
+		ContentType: params.ContentType,
+		Filename:    params.Filename,
+	})
+	return err
 }
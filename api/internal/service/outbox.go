@@ -0,0 +1,403 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+
+	"github.com/denisraison/rekan/api/internal/domain"
+	"github.com/denisraison/rekan/api/internal/prefs"
+	wa "github.com/denisraison/rekan/api/internal/whatsapp"
+)
+
+// OutboxMessage is a WhatsApp message to queue for a client.
+type OutboxMessage struct {
+	BusinessID  string
+	PostID      string // set when the message carries a post's caption
+	Phone       string
+	Type        string // domain.MsgTypeText (default), MsgTypeImage or MsgTypeVideo
+	Text        string // body, or caption for media
+	Media       []byte
+	ContentType string
+	Filename    string
+	After       time.Time // not sent before this; zero sends as soon as the limits allow
+	Category    string    // prefs category; empty is prefs.Operational
+}
+
+// Enqueue saves a message to the outbox. The outbox worker sends it. A
+// message in a category the client stopped is ErrOptedOut.
+func Enqueue(app core.App, msg OutboxMessage) (*core.Record, error) {
+	if msg.Phone == "" {
+		return nil, ErrNoPhone
+	}
+	if msg.Type == "" {
+		msg.Type = domain.MsgTypeText
+	}
+	if msg.Category == "" {
+		msg.Category = prefs.Operational
+	}
+	if msg.BusinessID != "" {
+		allowed, err := prefs.Allowed(app, msg.BusinessID, msg.Category)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, ErrOptedOut
+		}
+	}
+
+	collection, err := app.FindCollectionByNameOrId(domain.CollOutbox)
+	if err != nil {
+		return nil, fmt.Errorf("find outbox collection: %w", err)
+	}
+	record := core.NewRecord(collection)
+	record.Set("business", msg.BusinessID)
+	if msg.PostID != "" {
+		record.Set("post", msg.PostID)
+	}
+	record.Set("phone", msg.Phone)
+	record.Set("type", msg.Type)
+	record.Set("text", msg.Text)
+	record.Set("category", msg.Category)
+	record.Set("status", domain.OutboxStatusPending)
+	record.Set("attempts", 0)
+	if !msg.After.IsZero() {
+		record.Set("next_attempt_at", msg.After.UTC())
+	}
+	if msg.Media != nil {
+		file, err := filesystem.NewFileFromBytes(msg.Media, msg.Filename)
+		if err != nil {
+			return nil, fmt.Errorf("outbox media: %w", err)
+		}
+		record.Set("media", file)
+		record.Set("content_type", msg.ContentType)
+	}
+
+	if err := app.Save(record); err != nil {
+		return nil, fmt.Errorf("enqueue message: %w", err)
+	}
+	return record, nil
+}
+
+// OutboxLimits controls how fast and when the outbox worker sends. Bursts and
+// late-night messages are what get a number flagged by WhatsApp.
+type OutboxLimits struct {
+	RecipientGap time.Duration // minimum time between two messages to the same phone
+	PerMinute    int           // messages per minute across all recipients
+	MaxAttempts  int           // a message is marked failed after this many errors
+	RetryBase    time.Duration // delay after the first error, doubled for each further one
+	RetryMax     time.Duration
+	QuietStart   int // hour in domain.Location when sending stops
+	QuietEnd     int // hour in domain.Location when sending resumes
+}
+
+var DefaultOutboxLimits = OutboxLimits{
+	RecipientGap: 3 * time.Second,
+	PerMinute:    20,
+	MaxAttempts:  5,
+	RetryBase:    30 * time.Second,
+	RetryMax:     30 * time.Minute,
+	QuietStart:   21,
+	QuietEnd:     8,
+}
+
+// Quiet reports whether t falls in the quiet hours of the clients' time zone.
+func (l OutboxLimits) Quiet(t time.Time) bool {
+	h := t.In(domain.Location).Hour()
+	if l.QuietStart > l.QuietEnd {
+		return h >= l.QuietStart || h < l.QuietEnd
+	}
+	return h >= l.QuietStart && h < l.QuietEnd
+}
+
+func (l OutboxLimits) backoff(attempts int) time.Duration {
+	d := l.RetryBase
+	for range attempts - 1 {
+		d *= 2
+		if d >= l.RetryMax {
+			return l.RetryMax
+		}
+	}
+	return d
+}
+
+// OutboxClient is the subset of the WhatsApp client the outbox worker needs.
+type OutboxClient interface {
+	WAClient
+	Upload(ctx context.Context, data []byte, mediaType whatsmeow.MediaType) (whatsmeow.UploadResponse, error)
+}
+
+// outboxBatch caps how many pending messages one drain looks at.
+const outboxBatch = 200
+
+// OutboxWorker sends queued messages. Messages to one phone go out in the
+// order they were queued: a message waiting on a retry holds back the ones
+// behind it.
+type OutboxWorker struct {
+	App    core.App
+	Client OutboxClient
+	Limits OutboxLimits
+	Now    func() time.Time // defaults to time.Now
+
+	lastTo map[string]time.Time
+	recent []time.Time // send attempts in the last minute
+}
+
+// Run drains the outbox every interval until ctx is cancelled.
+func (w *OutboxWorker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		w.Drain(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Drain makes one pass over the outbox, sending at most one due message per
+// phone. It returns how many messages were sent.
+func (w *OutboxWorker) Drain(ctx context.Context) int {
+	now := w.now()
+	if w.Limits.Quiet(now) {
+		return 0
+	}
+	if w.lastTo == nil {
+		w.lastTo = map[string]time.Time{}
+	}
+
+	// @rowid is insertion order; created can tie for messages queued together.
+	records, err := w.App.FindRecordsByFilter(domain.CollOutbox, "status = {:status}", "@rowid", outboxBatch, 0,
+		dbx.Params{"status": domain.OutboxStatusPending})
+	if err != nil {
+		w.App.Logger().Error("outbox: list pending", "error", err)
+		return 0
+	}
+
+	sent := 0
+	seen := map[string]bool{}
+	for _, r := range records {
+		phone := r.GetString("phone")
+		if seen[phone] {
+			continue
+		}
+		seen[phone] = true
+
+		if next := r.GetDateTime("next_attempt_at"); !next.IsZero() && next.Time().After(now) {
+			continue
+		}
+		if last, ok := w.lastTo[phone]; ok && now.Sub(last) < w.Limits.RecipientGap {
+			continue
+		}
+		if w.optedOut(r) {
+			continue
+		}
+		if !w.takeSlot(now) {
+			break
+		}
+		w.lastTo[phone] = now
+		if w.send(ctx, r, now) {
+			sent++
+		}
+	}
+	return sent
+}
+
+// takeSlot reports whether the global per-minute limit allows another send,
+// and counts it if so.
+func (w *OutboxWorker) takeSlot(now time.Time) bool {
+	cutoff := now.Add(-time.Minute)
+	i := 0
+	for i < len(w.recent) && !w.recent[i].After(cutoff) {
+		i++
+	}
+	w.recent = w.recent[i:]
+	if w.Limits.PerMinute > 0 && len(w.recent) >= w.Limits.PerMinute {
+		return false
+	}
+	w.recent = append(w.recent, now)
+	return true
+}
+
+// optedOut fails a message whose category the client stopped after it was
+// queued, so it never goes out.
+func (w *OutboxWorker) optedOut(r *core.Record) bool {
+	businessID := r.GetString("business")
+	if businessID == "" {
+		return false
+	}
+	allowed, err := prefs.Allowed(w.App, businessID, r.GetString("category"))
+	if err != nil {
+		w.App.Logger().Error("outbox: check preferences", "id", r.Id, "error", err)
+		return true
+	}
+	if allowed {
+		return false
+	}
+	r.Set("status", domain.OutboxStatusFailed)
+	r.Set("last_error", ErrOptedOut.Error())
+	if err := w.App.Save(r); err != nil {
+		w.App.Logger().Error("outbox: save opted out message", "id", r.Id, "error", err)
+	}
+	w.App.Logger().Info("outbox: client opted out, message dropped", "id", r.Id, "business", businessID)
+	return true
+}
+
+// send makes one attempt at a message and records the outcome.
+func (w *OutboxWorker) send(ctx context.Context, r *core.Record, now time.Time) bool {
+	phone := r.GetString("phone")
+	jid := types.NewJID(phone, types.DefaultUserServer)
+
+	msg, data, err := w.buildMessage(ctx, r)
+	var resp whatsmeow.SendResponse
+	if err == nil {
+		stop := wa.Typing(ctx, w.Client, jid)
+		resp, err = w.Client.SendMessage(ctx, jid, msg)
+		stop()
+	}
+
+	attempts := r.GetInt("attempts") + 1
+	r.Set("attempts", attempts)
+	if err != nil {
+		r.Set("last_error", err.Error())
+		if attempts >= w.Limits.MaxAttempts {
+			r.Set("status", domain.OutboxStatusFailed)
+			w.App.Logger().Error("outbox: giving up", "id", r.Id, "phone", phone, "attempts", attempts, "error", err)
+		} else {
+			r.Set("next_attempt_at", now.Add(w.Limits.backoff(attempts)).UTC())
+			w.App.Logger().Warn("outbox: send failed, will retry", "id", r.Id, "attempts", attempts, "error", err)
+		}
+	} else {
+		r.Set("status", domain.OutboxStatusSent)
+		r.Set("sent_at", now.UTC())
+		r.Set("wa_message_id", resp.ID)
+		r.Set("last_error", "")
+	}
+	if saveErr := w.App.Save(r); saveErr != nil {
+		w.App.Logger().Error("outbox: save status", "id", r.Id, "error", saveErr)
+	}
+	if err != nil {
+		return false
+	}
+
+	var media *filesystem.File
+	if data != nil {
+		if media, err = filesystem.NewFileFromBytes(data, r.GetString("media")); err != nil {
+			w.App.Logger().Error("outbox: copy media to messages", "error", err)
+		}
+	}
+	StoreOutgoingMessage(w.App, OutgoingMessage{
+		BusinessID:  r.GetString("business"),
+		Phone:       phone,
+		Type:        r.GetString("type"),
+		Content:     r.GetString("text"),
+		WAMessageID: resp.ID,
+		PostID:      r.GetString("post"),
+		Media:       media,
+	})
+	return true
+}
+
+// buildMessage turns an outbox record into a WhatsApp message, uploading its
+// media first if it has any. The media bytes are returned so the sent copy can
+// be stored with the conversation.
+func (w *OutboxWorker) buildMessage(ctx context.Context, r *core.Record) (*waE2E.Message, []byte, error) {
+	text := r.GetString("text")
+	msgType := r.GetString("type")
+	if msgType == domain.MsgTypeText {
+		return &waE2E.Message{Conversation: &text}, nil, nil
+	}
+
+	data, err := w.readMedia(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	mediaType := whatsmeow.MediaImage
+	if msgType == domain.MsgTypeVideo {
+		mediaType = whatsmeow.MediaVideo
+	}
+	resp, err := w.Client.Upload(ctx, data, mediaType)
+	if err != nil {
+		return nil, nil, fmt.Errorf("upload media: %w", err)
+	}
+
+	contentType := r.GetString("content_type")
+	if msgType == domain.MsgTypeVideo {
+		return &waE2E.Message{
+			VideoMessage: &waE2E.VideoMessage{
+				Caption:       new(text),
+				Mimetype:      new(contentType),
+				URL:           &resp.URL,
+				DirectPath:    &resp.DirectPath,
+				MediaKey:      resp.MediaKey,
+				FileEncSHA256: resp.FileEncSHA256,
+				FileSHA256:    resp.FileSHA256,
+				FileLength:    &resp.FileLength,
+			},
+		}, data, nil
+	}
+	return &waE2E.Message{
+		ImageMessage: &waE2E.ImageMessage{
+			Caption:       new(text),
+			Mimetype:      new(contentType),
+			URL:           &resp.URL,
+			DirectPath:    &resp.DirectPath,
+			MediaKey:      resp.MediaKey,
+			FileEncSHA256: resp.FileEncSHA256,
+			FileSHA256:    resp.FileSHA256,
+			FileLength:    &resp.FileLength,
+		},
+	}, data, nil
+}
+
+func (w *OutboxWorker) readMedia(r *core.Record) ([]byte, error) {
+	name := r.GetString("media")
+	if name == "" {
+		return nil, fmt.Errorf("outbox %s: %s message without media", r.Id, r.GetString("type"))
+	}
+	fsys, err := w.App.NewFilesystem()
+	if err != nil {
+		return nil, fmt.Errorf("open filesystem: %w", err)
+	}
+	defer fsys.Close()
+
+	reader, err := fsys.GetReader(r.BaseFilesPath() + "/" + name)
+	if err != nil {
+		return nil, fmt.Errorf("open media: %w", err)
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+func (w *OutboxWorker) now() time.Time {
+	if w.Now != nil {
+		return w.Now()
+	}
+	return time.Now()
+}
+
+// enqueueAll queues several messages in order, all or none.
+func enqueueAll(app core.App, msgs ...OutboxMessage) error {
+	return app.RunInTransaction(func(txApp core.App) error {
+		for _, msg := range msgs {
+			if strings.TrimSpace(msg.Text) == "" && msg.Media == nil {
+				continue
+			}
+			if _, err := Enqueue(txApp, msg); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
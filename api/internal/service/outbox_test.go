@@ -0,0 +1,246 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+
+	content "github.com/denisraison/rekan/api/internal/content"
+	"github.com/denisraison/rekan/api/internal/domain"
+	"github.com/denisraison/rekan/api/internal/service"
+)
+
+// fakeWA records sent texts and fails while failures is positive.
+type fakeWA struct {
+	sent     []string
+	failures int
+}
+
+func (f *fakeWA) SendMessage(_ context.Context, _ types.JID, msg *waE2E.Message) (whatsmeow.SendResponse, error) {
+	if f.failures > 0 {
+		f.failures--
+		return whatsmeow.SendResponse{}, errors.New("not connected")
+	}
+	text := msg.GetConversation()
+	if img := msg.GetImageMessage(); img != nil {
+		text = "image: " + img.GetCaption()
+	}
+	f.sent = append(f.sent, text)
+	return whatsmeow.SendResponse{ID: "wa-" + text}, nil
+}
+
+func (f *fakeWA) SendChatPresence(context.Context, types.JID, types.ChatPresence, types.ChatPresenceMedia) error {
+	return nil
+}
+
+func (f *fakeWA) Upload(context.Context, []byte, whatsmeow.MediaType) (whatsmeow.UploadResponse, error) {
+	return whatsmeow.UploadResponse{URL: "https://mmg.whatsapp.net/x"}, nil
+}
+
+func TestOutboxSendsInOrderWithinLimits(t *testing.T) {
+	app, _, bizID := newTestApp(t)
+	defer app.Cleanup()
+	setPhone(t, app, bizID, "5511999990000")
+
+	err := service.SendTextMessage(app, service.SendTextParams{
+		BusinessID:     bizID,
+		Caption:        "Pão quentinho saindo agora",
+		Hashtags:       "#padaria",
+		ProductionNote: "Foto do balcão",
+	})
+	if err != nil {
+		t.Fatalf("SendTextMessage: %v", err)
+	}
+	if err := service.SendMediaMessage(app, service.SendMediaParams{
+		BusinessID: bizID, Caption: "Fornada", Data: []byte("jpeg"), ContentType: "image/jpeg", Filename: "pao.jpg",
+	}); err != nil {
+		t.Fatalf("SendMediaMessage: %v", err)
+	}
+
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, domain.Location)
+	wa := &fakeWA{}
+	w := &service.OutboxWorker{App: app, Client: wa, Limits: service.DefaultOutboxLimits, Now: func() time.Time { return now }}
+
+	if n := w.Drain(context.Background()); n != 1 {
+		t.Fatalf("first drain sent %d, want 1 per phone", n)
+	}
+	now = now.Add(time.Second)
+	if n := w.Drain(context.Background()); n != 0 {
+		t.Errorf("drain inside the recipient gap sent %d", n)
+	}
+	for range 3 {
+		now = now.Add(service.DefaultOutboxLimits.RecipientGap)
+		w.Drain(context.Background())
+	}
+
+	want := []string{"Pão quentinho saindo agora\n\n#padaria", "*Dica de foto:* Foto do balcão"}
+	if len(wa.sent) != 4 || wa.sent[0] != want[0] || wa.sent[1] != want[1] || wa.sent[3] != "image: Fornada" {
+		t.Fatalf("sent: got %q", wa.sent)
+	}
+
+	stored, err := app.FindAllRecords(domain.CollMessages, dbx.HashExp{"business": bizID, "direction": domain.DirectionOutgoing})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != 4 {
+		t.Errorf("sent messages should be stored in the conversation, got %d", len(stored))
+	}
+	pending, err := app.FindAllRecords(domain.CollOutbox, dbx.HashExp{"status": domain.OutboxStatusPending})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("outbox should be drained, %d pending", len(pending))
+	}
+}
+
+func TestOutboxQuietHoursAndGlobalLimit(t *testing.T) {
+	app, _, bizID := newTestApp(t)
+	defer app.Cleanup()
+
+	for _, phone := range []string{"5511999990001", "5511999990002"} {
+		if _, err := service.Enqueue(app, service.OutboxMessage{BusinessID: bizID, Phone: phone, Text: "Oi"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	now := time.Date(2026, 10, 19, 22, 30, 0, 0, domain.Location)
+	limits := service.DefaultOutboxLimits
+	limits.PerMinute = 1
+	wa := &fakeWA{}
+	w := &service.OutboxWorker{App: app, Client: wa, Limits: limits, Now: func() time.Time { return now }}
+
+	if n := w.Drain(context.Background()); n != 0 {
+		t.Errorf("sent %d during quiet hours", n)
+	}
+	now = time.Date(2026, 10, 20, 8, 0, 0, 0, domain.Location)
+	if n := w.Drain(context.Background()); n != 1 {
+		t.Errorf("sent %d with a limit of 1 per minute", n)
+	}
+	now = now.Add(time.Minute)
+	if n := w.Drain(context.Background()); n != 1 {
+		t.Errorf("sent %d after the minute passed, want 1", n)
+	}
+}
+
+func TestOutboxQuietHoursUseClientTimeZone(t *testing.T) {
+	limits := service.DefaultOutboxLimits
+	// 00:30 UTC is 21:30 in São Paulo; 11:00 UTC is 08:00.
+	if !limits.Quiet(time.Date(2026, 10, 20, 0, 30, 0, 0, time.UTC)) {
+		t.Error("21:30 in São Paulo should be quiet")
+	}
+	if limits.Quiet(time.Date(2026, 10, 20, 11, 0, 0, 0, time.UTC)) {
+		t.Error("08:00 in São Paulo should not be quiet")
+	}
+}
+
+func TestOutboxRetriesThenFails(t *testing.T) {
+	app, _, bizID := newTestApp(t)
+	defer app.Cleanup()
+
+	first, err := service.Enqueue(app, service.OutboxMessage{BusinessID: bizID, Phone: "5511999990000", Text: "primeira"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Enqueue(app, service.OutboxMessage{BusinessID: bizID, Phone: "5511999990000", Text: "segunda"}); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, domain.Location)
+	limits := service.DefaultOutboxLimits
+	limits.MaxAttempts = 2
+	wa := &fakeWA{failures: 2}
+	w := &service.OutboxWorker{App: app, Client: wa, Limits: limits, Now: func() time.Time { return now }}
+
+	w.Drain(context.Background())
+	now = now.Add(limits.RetryBase / 2)
+	if n := w.Drain(context.Background()); n != 0 {
+		t.Errorf("message behind a pending retry should wait, sent %d", n)
+	}
+
+	now = now.Add(limits.RetryBase)
+	w.Drain(context.Background())
+	record, err := app.FindRecordById(domain.CollOutbox, first.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.GetString("status") != domain.OutboxStatusFailed || record.GetInt("attempts") != 2 || record.GetString("last_error") == "" {
+		t.Errorf("after max attempts: status %q, attempts %d, error %q",
+			record.GetString("status"), record.GetInt("attempts"), record.GetString("last_error"))
+	}
+
+	now = now.Add(limits.RecipientGap)
+	if n := w.Drain(context.Background()); n != 1 || wa.sent[0] != "segunda" {
+		t.Errorf("next message should go once the failed one is out of the way, sent %q", wa.sent)
+	}
+}
+
+func TestSendTextMessageNoPhone(t *testing.T) {
+	app, _, bizID := newTestApp(t)
+	defer app.Cleanup()
+
+	err := service.SendTextMessage(app, service.SendTextParams{BusinessID: bizID, Caption: "Oi"})
+	if !errors.Is(err, service.ErrNoPhone) {
+		t.Errorf("got %v, want ErrNoPhone", err)
+	}
+}
+
+func setPhone(t *testing.T, app core.App, bizID, phone string) {
+	t.Helper()
+	biz, err := app.FindRecordById(domain.CollBusinesses, bizID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	biz.Set("phone", phone)
+	if err := app.Save(biz); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSendTextMessageIncludesFormatGuide(t *testing.T) {
+	app, _, bizID := newTestApp(t)
+	defer app.Cleanup()
+	setPhone(t, app, bizID, "5511999990000")
+
+	posts, err := app.FindCollectionByNameOrId(domain.CollPosts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	post := core.NewRecord(posts)
+	post.Set("business", bizID)
+	post.Set("caption", "Pão na chapa em 3 passos")
+	post.Set("format", string(content.FormatReel))
+	post.Set("format_data", content.FormatData{Reel: &content.Reel{
+		Hook:   "Você tosta errado",
+		Scenes: []content.ReelScene{{Visual: "Pão na chapa", Voiceover: "Manteiga dos dois lados"}},
+	}})
+	if err := app.Save(post); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := service.SendTextMessage(app, service.SendTextParams{BusinessID: bizID, PostID: post.Id, Caption: post.GetString("caption")}); err != nil {
+		t.Fatalf("SendTextMessage: %v", err)
+	}
+
+	queued, err := app.FindRecordsByFilter(domain.CollOutbox, "business = {:id}", "created", 0, 0, dbx.Params{"id": bizID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(queued) != 3 {
+		t.Fatalf("queued %d messages, want caption, script and posting tip", len(queued))
+	}
+	if queued[0].GetString("post") != post.Id {
+		t.Errorf("caption message not linked to the post")
+	}
+	if script := queued[1].GetString("text"); !strings.Contains(script, "*Cena 1:* Pão na chapa") || !strings.Contains(script, "Fala: Manteiga dos dois lados") {
+		t.Errorf("script message: %q", script)
+	}
+}
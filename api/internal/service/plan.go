@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	content "github.com/denisraison/rekan/api/internal/content"
+	"github.com/denisraison/rekan/api/internal/domain"
+	"github.com/denisraison/rekan/api/internal/operator"
+	"github.com/denisraison/rekan/api/internal/postingtime"
+	"github.com/denisraison/rekan/api/internal/pricing"
+	"github.com/google/uuid"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// GenerateMonthlyPlan builds the month's posts for a business, sized by its
+// tier, starting at start. Each slot of content.PlanMonth is generated with
+// its own role, and earlier hooks in the plan are passed along so the month
+// doesn't repeat itself. Roles follow the client's role history, and with a
+// gate every post goes through the same checks as a single post. Posts
+// alternate between the business type's primary and secondary posting
+// windows. They are saved together under one batch_id, or not at all if any
+// generation fails.
+func GenerateMonthlyPlan(ctx context.Context, app core.App, generate content.GenerateFunc, gate *QualityGate, businessID string, start time.Time) (*GenerateBatchResult, error) {
+	return generateMonthlyPlan(ctx, app, generate, gate, businessID, start, nil)
+}
+
+// generateMonthlyPlan is GenerateMonthlyPlan with a progress callback, called
+// with the plan size before the first generation and after each post.
+func generateMonthlyPlan(ctx context.Context, app core.App, generate content.GenerateFunc, gate *QualityGate, businessID string, start time.Time, progress func(done, total int)) (*GenerateBatchResult, error) {
+	business, err := app.FindRecordById(domain.CollBusinesses, businessID)
+	if err != nil {
+		return nil, wrapNotFound(err, "negócio não encontrado")
+	}
+
+	profile, err := operator.BusinessToProfile(business)
+	if err != nil {
+		return nil, fmt.Errorf("business to profile: %w", err)
+	}
+
+	previousHooks, err := operator.LoadPreviousHooks(app, businessID)
+	if err != nil {
+		return nil, fmt.Errorf("load previous hooks: %w", err)
+	}
+
+	history, err := operator.LoadRoleHistory(app, businessID)
+	if err != nil {
+		return nil, fmt.Errorf("load role history: %w", err)
+	}
+
+	run, err := postRunner(ctx, app, generate, gate, profile, businessID)
+	if err != nil {
+		return nil, err
+	}
+
+	count := pricing.Posts(pricing.Tier(business.GetString("tier")))
+	slots := content.PlanMonth(count, start, business.GetString("type"), history)
+	window := postingtime.ForBusinessType(business.GetString("type"))
+
+	result := &GenerateBatchResult{
+		BatchID: uuid.New().String(),
+		Posts:   make([]GeneratedPost, 0, len(slots)),
+	}
+	if progress != nil {
+		progress(0, len(slots))
+	}
+	for i, slot := range slots {
+		posts, quality, err := run([]content.Role{slot.Role}, previousHooks)
+		if err != nil {
+			return nil, fmt.Errorf("generate plan post %d: %w", i, err)
+		}
+		if len(posts) == 0 {
+			return nil, fmt.Errorf("generate plan post %d: empty result", i)
+		}
+		post := posts[0]
+
+		hook := ""
+		if hooks := content.ExtractHooks(posts[:1]); len(hooks) > 0 {
+			hook = hooks[0]
+			previousHooks = append(previousHooks, hook)
+		}
+
+		result.Posts = append(result.Posts, GeneratedPost{
+			Caption:        post.Caption,
+			Hashtags:       post.Hashtags,
+			ProductionNote: post.ProductionNote,
+			Role:           slot.Role.Name,
+			Hook:           hook,
+			PlannedFor:     postingtime.At(slot.Date, window.Slot(i)),
+			PlannedSlot:    window.Slot(i),
+			Occasion:       slot.Occasion,
+			Format:         post.Format,
+			FormatData:     post.Data,
+			Quality:        quality,
+		})
+		if progress != nil {
+			progress(i+1, len(slots))
+		}
+	}
+
+	collection, err := app.FindCollectionByNameOrId(domain.CollPosts)
+	if err != nil {
+		return nil, fmt.Errorf("find posts collection: %w", err)
+	}
+
+	err = app.RunInTransaction(func(txApp core.App) error {
+		for i := range result.Posts {
+			p := &result.Posts[i]
+			record := core.NewRecord(collection)
+			record.Set("business", businessID)
+			record.Set("caption", p.Caption)
+			record.Set("hashtags", p.Hashtags)
+			record.Set("production_note", p.ProductionNote)
+			record.Set("role", p.Role)
+			record.Set("hook", p.Hook)
+			record.Set("edited", false)
+			record.Set("batch_id", result.BatchID)
+			record.Set("planned_for", p.PlannedFor)
+			record.Set("planned_slot", p.PlannedSlot)
+			record.Set("occasion", p.Occasion)
+			setPostFormat(record, p.Format, p.FormatData)
+			if p.Quality != nil {
+				record.Set("quality", p.Quality)
+			}
+			if err := txApp.Save(record); err != nil {
+				return fmt.Errorf("save plan post %d: %w", i, err)
+			}
+			p.ID = record.Id
+			p.MediaItem = pairSuggestedMedia(txApp, record)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
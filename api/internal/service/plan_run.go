@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	content "github.com/denisraison/rekan/api/internal/content"
+	"github.com/denisraison/rekan/api/internal/domain"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// PlanRunTimeout bounds a background monthly plan. A run still marked
+// running after this long without progress is treated as dead (the server
+// restarted mid-run, say) and no longer blocks a new one.
+const PlanRunTimeout = 15 * time.Minute
+
+// StartMonthlyPlan records a monthly plan run for a business, to be carried
+// out by RunMonthlyPlan. A run already going for the business is ErrConflict.
+func StartMonthlyPlan(app core.App, businessID string, start time.Time) (*core.Record, error) {
+	if _, err := app.FindRecordById(domain.CollBusinesses, businessID); err != nil {
+		return nil, wrapNotFound(err, "negócio não encontrado")
+	}
+
+	since, err := types.ParseDateTime(time.Now().Add(-PlanRunTimeout))
+	if err != nil {
+		return nil, fmt.Errorf("plan run cutoff: %w", err)
+	}
+	var running []*core.Record
+	err = app.RecordQuery(domain.CollPlanRuns).
+		AndWhere(dbx.HashExp{"business": businessID, "status": domain.PlanRunStatusRunning}).
+		AndWhere(dbx.NewExp("updated > {:since}", dbx.Params{"since": since.String()})).
+		Limit(1).
+		All(&running)
+	if err != nil {
+		return nil, fmt.Errorf("find running plan: %w", err)
+	}
+	if len(running) > 0 {
+		return nil, fmt.Errorf("%w: já tem um plano sendo gerado", ErrConflict)
+	}
+
+	collection, err := app.FindCollectionByNameOrId(domain.CollPlanRuns)
+	if err != nil {
+		return nil, fmt.Errorf("find plan runs collection: %w", err)
+	}
+	run := core.NewRecord(collection)
+	run.Set("business", businessID)
+	run.Set("start", start)
+	run.Set("status", domain.PlanRunStatusRunning)
+	if err := app.Save(run); err != nil {
+		return nil, fmt.Errorf("save plan run: %w", err)
+	}
+	return run, nil
+}
+
+// RunMonthlyPlan generates the plan recorded by StartMonthlyPlan, saving
+// progress on the run after each post. The run ends done with the batch_id
+// of the saved posts, or failed with the error.
+func RunMonthlyPlan(ctx context.Context, app core.App, generate content.GenerateFunc, gate *QualityGate, runID string) error {
+	run, err := app.FindRecordById(domain.CollPlanRuns, runID)
+	if err != nil {
+		return wrapNotFound(err, "geração de plano não encontrada")
+	}
+
+	progress := func(done, total int) {
+		run.Set("done", done)
+		run.Set("total", total)
+		if err := app.Save(run); err != nil {
+			app.Logger().Warn("plan run: save progress failed", "run", runID, "error", err)
+		}
+	}
+
+	start := run.GetDateTime("start").Time().In(domain.Location)
+	result, genErr := generateMonthlyPlan(ctx, app, generate, gate, run.GetString("business"), start, progress)
+	if genErr != nil {
+		run.Set("status", domain.PlanRunStatusFailed)
+		run.Set("error", genErr.Error())
+	} else {
+		run.Set("status", domain.PlanRunStatusDone)
+		run.Set("batch_id", result.BatchID)
+	}
+	if err := app.Save(run); err != nil {
+		return fmt.Errorf("save plan run: %w", err)
+	}
+	return genErr
+}
@@ -0,0 +1,208 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+
+	content "github.com/denisraison/rekan/api/internal/content"
+	"github.com/denisraison/rekan/api/internal/domain"
+	"github.com/denisraison/rekan/api/internal/service"
+)
+
+func TestGenerateMonthlyPlan(t *testing.T) {
+	app, _, bizID := newTestApp(t)
+	defer app.Cleanup()
+
+	biz, err := app.FindRecordById(domain.CollBusinesses, bizID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	biz.Set("tier", "parceiro")
+	if err := app.Save(biz); err != nil {
+		t.Fatal(err)
+	}
+
+	var gotRoles []string
+	generate := func(ctx context.Context, p content.BusinessProfile, roles []content.Role, hooks []string) ([]content.Post, error) {
+		gotRoles = append(gotRoles, roles[0].Name)
+		return stubGenerate(ctx, p, roles, hooks)
+	}
+
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	result, err := service.GenerateMonthlyPlan(context.Background(), app, generate, nil, bizID, start)
+	if err != nil {
+		t.Fatalf("GenerateMonthlyPlan: %v", err)
+	}
+
+	if len(result.Posts) != 12 {
+		t.Fatalf("expected 12 posts for parceiro, got %d", len(result.Posts))
+	}
+	if len(gotRoles) != 12 {
+		t.Errorf("expected one generation per slot, got %d", len(gotRoles))
+	}
+
+	posts, err := app.FindRecordsByFilter(domain.CollPosts, "batch_id = {:batch}", "", 0, 0, map[string]any{"batch": result.BatchID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(posts) != 12 {
+		t.Fatalf("expected 12 posts in batch, got %d", len(posts))
+	}
+	for _, p := range posts {
+		if p.GetDateTime("planned_for").IsZero() {
+			t.Errorf("post %s has no planned_for", p.Id)
+		}
+		if p.GetString("role") == "" {
+			t.Errorf("post %s has no role", p.Id)
+		}
+	}
+}
+
+func TestGenerateMonthlyPlan_FailureSavesNothing(t *testing.T) {
+	app, _, bizID := newTestApp(t)
+	defer app.Cleanup()
+
+	calls := 0
+	generate := func(ctx context.Context, p content.BusinessProfile, roles []content.Role, hooks []string) ([]content.Post, error) {
+		calls++
+		if calls == 3 {
+			return nil, errors.New("provider down")
+		}
+		return stubGenerate(ctx, p, roles, hooks)
+	}
+
+	if _, err := service.GenerateMonthlyPlan(context.Background(), app, generate, nil, bizID, time.Now()); err == nil {
+		t.Fatal("expected error")
+	}
+
+	posts, err := app.FindAllRecords(domain.CollPosts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(posts) != 0 {
+		t.Errorf("expected no posts after a failed plan, got %d", len(posts))
+	}
+}
+
+func TestGenerateMonthlyPlan_GateAndRoleHistory(t *testing.T) {
+	app, _, bizID := newTestApp(t)
+	defer app.Cleanup()
+
+	col, err := app.FindCollectionByNameOrId(domain.CollPosts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recent := []string{"Bastidor", "Útil", "Pessoal"}
+	for _, role := range recent {
+		r := core.NewRecord(col)
+		r.Set("business", bizID)
+		r.Set("caption", "Post de "+role)
+		r.Set("role", role)
+		if err := app.Save(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var gotRoles []string
+	generate := func(ctx context.Context, p content.BusinessProfile, roles []content.Role, hooks []string) ([]content.Post, error) {
+		gotRoles = append(gotRoles, roles[0].Name)
+		return stubGenerate(ctx, p, roles, hooks)
+	}
+	gate := &service.QualityGate{}
+
+	// Basico is 8 posts, fewer than the roles left once the recent ones go last.
+	start := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	result, err := service.GenerateMonthlyPlan(context.Background(), app, generate, gate, bizID, start)
+	if err != nil {
+		t.Fatalf("GenerateMonthlyPlan: %v", err)
+	}
+
+	for _, name := range gotRoles {
+		if slices.Contains(recent, name) {
+			t.Errorf("plan used recently used role %q", name)
+		}
+	}
+	for _, p := range result.Posts {
+		if p.Quality == nil {
+			t.Fatalf("post %s has no quality report", p.ID)
+		}
+		saved, err := app.FindRecordById(domain.CollPosts, p.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if saved.GetString("quality") == "" || saved.GetString("quality") == "null" {
+			t.Errorf("post %s saved without its quality report", p.ID)
+		}
+	}
+}
+
+func TestRunMonthlyPlan(t *testing.T) {
+	app, _, bizID := newTestApp(t)
+	defer app.Cleanup()
+
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, domain.Location)
+	run, err := service.StartMonthlyPlan(app, bizID, start)
+	if err != nil {
+		t.Fatalf("StartMonthlyPlan: %v", err)
+	}
+	if _, err := service.StartMonthlyPlan(app, bizID, start); !errors.Is(err, service.ErrConflict) {
+		t.Errorf("second start: err = %v, want ErrConflict", err)
+	}
+
+	if err := service.RunMonthlyPlan(context.Background(), app, stubGenerate, nil, run.Id); err != nil {
+		t.Fatalf("RunMonthlyPlan: %v", err)
+	}
+	run, err = app.FindRecordById(domain.CollPlanRuns, run.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := run.GetString("status"); got != domain.PlanRunStatusDone {
+		t.Fatalf("status = %q, want done (error %q)", got, run.GetString("error"))
+	}
+	if run.GetInt("total") == 0 || run.GetInt("done") != run.GetInt("total") {
+		t.Errorf("progress = %d/%d, want all done", run.GetInt("done"), run.GetInt("total"))
+	}
+	posts, err := app.FindRecordsByFilter(domain.CollPosts, "batch_id = {:batch}", "", 0, 0, map[string]any{"batch": run.GetString("batch_id")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(posts) != run.GetInt("total") {
+		t.Errorf("batch has %d posts, want %d", len(posts), run.GetInt("total"))
+	}
+	if got := posts[0].GetDateTime("planned_for").Time().In(domain.Location).Format(time.DateOnly); got != "2026-03-01" {
+		t.Errorf("first post planned for %s, want the run's start", got)
+	}
+
+	// A finished run no longer blocks the next one.
+	if _, err := service.StartMonthlyPlan(app, bizID, start); err != nil {
+		t.Errorf("start after done: %v", err)
+	}
+}
+
+func TestRunMonthlyPlan_Failure(t *testing.T) {
+	app, _, bizID := newTestApp(t)
+	defer app.Cleanup()
+
+	run, err := service.StartMonthlyPlan(app, bizID, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	generate := func(context.Context, content.BusinessProfile, []content.Role, []string) ([]content.Post, error) {
+		return nil, errors.New("provider down")
+	}
+	if err := service.RunMonthlyPlan(context.Background(), app, generate, nil, run.Id); err == nil {
+		t.Fatal("expected error")
+	}
+	run, err = app.FindRecordById(domain.CollPlanRuns, run.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.GetString("status") != domain.PlanRunStatusFailed || run.GetString("error") == "" {
+		t.Errorf("status = %q, error = %q; want failed with the error", run.GetString("status"), run.GetString("error"))
+	}
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/denisraison/rekan/api/internal/domain"
+	"github.com/denisraison/rekan/api/internal/prefs"
+)
+
+// PreferenceChange is one logged change to a client's communication
+// preferences.
+type PreferenceChange struct {
+	Category  string
+	Allowed   bool
+	Source    string // prefs.SourceClient or prefs.SourceOperator
+	MessageID string // the client's message that asked for it
+	Text      string
+	UserID    string // the operator who made it
+	ChangedAt time.Time
+}
+
+// BusinessPreferences returns a business's communication preferences and
+// their change log, newest first.
+func BusinessPreferences(app core.App, businessID string) (prefs.Preferences, []PreferenceChange, error) {
+	if _, err := app.FindRecordById(domain.CollBusinesses, businessID); err != nil {
+		return prefs.Preferences{}, nil, wrapNotFound(err, "negócio")
+	}
+	p, err := prefs.Get(app, businessID)
+	if err != nil {
+		return prefs.Preferences{}, nil, err
+	}
+	records, err := prefs.History(app, businessID)
+	if err != nil {
+		return prefs.Preferences{}, nil, err
+	}
+	history := make([]PreferenceChange, 0, len(records))
+	for _, r := range records {
+		history = append(history, PreferenceChange{
+			Category:  r.GetString("category"),
+			Allowed:   r.GetBool("allowed"),
+			Source:    r.GetString("source"),
+			MessageID: r.GetString("message"),
+			Text:      r.GetString("text"),
+			UserID:    r.GetString("user"),
+			ChangedAt: r.GetDateTime("changed_at").Time(),
+		})
+	}
+	return p, history, nil
+}
+
+// SetPreferences records an operator's change, e.g. when the client asked
+// over the phone. set maps categories to allowed; note says why and goes in
+// the log. An unknown category is ErrInvalid.
+func SetPreferences(app core.App, businessID, userID string, set map[string]bool, note string) (prefs.Preferences, error) {
+	if len(set) == 0 {
+		return prefs.Preferences{}, fmt.Errorf("%w: nenhuma preferência informada", ErrInvalid)
+	}
+	for category := range set {
+		if !slices.Contains(prefs.Categories, category) {
+			return prefs.Preferences{}, fmt.Errorf("%w: categoria desconhecida %q", ErrInvalid, category)
+		}
+	}
+	if _, err := app.FindRecordById(domain.CollBusinesses, businessID); err != nil {
+		return prefs.Preferences{}, wrapNotFound(err, "negócio")
+	}
+	p, _, err := prefs.Apply(app, prefs.Change{
+		BusinessID: businessID,
+		Set:        set,
+		Source:     prefs.SourceOperator,
+		Text:       note,
+		UserID:     userID,
+	})
+	return p, err
+}
@@ -0,0 +1,78 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/denisraison/rekan/api/internal/domain"
+	"github.com/denisraison/rekan/api/internal/prefs"
+	"github.com/denisraison/rekan/api/internal/service"
+)
+
+func TestOptedOutMessagesAreNotSent(t *testing.T) {
+	app, userID, bizID := newTestApp(t)
+	defer app.Cleanup()
+	setPhone(t, app, bizID, "5511999990000")
+
+	// A post queued before the client asked us to stop.
+	if err := service.SendTextMessage(app, service.SendTextParams{BusinessID: bizID, Caption: "Pão quentinho"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := prefs.Apply(app, prefs.Change{BusinessID: bizID, Set: prefs.IntentStopAll.Set(), Source: prefs.SourceClient, Text: "não quero mais receber mensagens"}); err != nil {
+		t.Fatal(err)
+	}
+
+	wa := &fakeWA{}
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, domain.Location)
+	w := &service.OutboxWorker{App: app, Client: wa, Limits: service.DefaultOutboxLimits, Now: func() time.Time { return now }}
+	if n := w.Drain(context.Background()); n != 0 || len(wa.sent) != 0 {
+		t.Fatalf("sent %q after the client opted out", wa.sent)
+	}
+	out, err := app.FindFirstRecordByFilter(domain.CollOutbox, "business = {:id}", map[string]any{"id": bizID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.GetString("status") != domain.OutboxStatusFailed {
+		t.Errorf("queued post status = %q, want failed", out.GetString("status"))
+	}
+
+	if err := service.SendTextMessage(app, service.SendTextParams{BusinessID: bizID, Caption: "Outro post"}); !errors.Is(err, service.ErrOptedOut) {
+		t.Errorf("SendTextMessage: err = %v, want ErrOptedOut", err)
+	}
+	msgID := createScheduledMessage(t, app, bizID, "Feliz Dia das Crianças!")
+	if err := service.ApproveScheduledMessage(app, msgID); !errors.Is(err, service.ErrOptedOut) {
+		t.Errorf("ApproveScheduledMessage: err = %v, want ErrOptedOut", err)
+	}
+
+	// The operator turns posts back on after a call with the client.
+	p, err := service.SetPreferences(app, bizID, userID, map[string]bool{prefs.Operational: true}, "cliente ligou pedindo os posts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.Operational || p.Marketing {
+		t.Errorf("preferences = %+v", p)
+	}
+	if err := service.SendTextMessage(app, service.SendTextParams{BusinessID: bizID, Caption: "Outro post"}); err != nil {
+		t.Errorf("SendTextMessage after opt-in: %v", err)
+	}
+
+	_, history, err := service.BusinessPreferences(app, bizID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 4 || history[0].Source != prefs.SourceOperator || history[0].UserID != userID {
+		t.Errorf("history = %+v, want the operator's change first of 4", history)
+	}
+}
+
+func TestSetPreferencesRejectsUnknownCategory(t *testing.T) {
+	app, userID, bizID := newTestApp(t)
+	defer app.Cleanup()
+
+	_, err := service.SetPreferences(app, bizID, userID, map[string]bool{"newsletter": false}, "")
+	if !errors.Is(err, service.ErrInvalid) {
+		t.Errorf("err = %v, want ErrInvalid", err)
+	}
+}
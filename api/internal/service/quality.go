@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	content "github.com/denisraison/rekan/api/internal/content"
+	"github.com/denisraison/rekan/api/internal/similarity"
+)
+
+// JudgeFunc runs one judge criterion against rendered posts.
+type JudgeFunc func(ctx context.Context, name string, profile content.BusinessProfile, rendered string) (content.JudgeResult, error)
+
+// DefaultGateJudges are the criteria the production gate runs. Variedade needs
+// a batch to compare and the others are cheap enough to leave to the eval.
+var DefaultGateJudges = []string{"naturalidade", "especificidade"}
+
+// DefaultMaxSimilarity is the estimated overlap with another client's recent
+// post above which a caption counts as a near copy.
+const DefaultMaxSimilarity = 0.5
+
+// QualityGate checks generated posts before operators see them. Heuristics
+// and the similarity check run first; judges only run once those pass. A
+// failing batch is regenerated with the failure reasons up to MaxRetries
+// times, and the last attempt is kept either way so the operator can see why
+// it did not pass.
+type QualityGate struct {
+	MaxRetries    int
+	Judges        []string  // criteria from content.JudgeNames
+	Judge         JudgeFunc // nil skips the judges
+	MaxSimilarity float64   // 0 skips the similarity check
+}
+
+// QualityCheck is one heuristic or judge outcome, as stored on the post.
+type QualityCheck struct {
+	Name   string `json:"name"`
+	Pass   bool   `json:"pass"`
+	Reason string `json:"reason,omitempty"`
+	Error  string `json:"error,omitempty"` // judge could not run; does not fail the gate
+}
+
+// QualityReport is stored in the post's quality field.
+type QualityReport struct {
+	Passed   bool           `json:"passed"`
+	Attempts int            `json:"attempts"`
+	Checks   []QualityCheck `json:"checks"`
+	Judges   []QualityCheck `json:"judges,omitempty"`
+}
+
+// failures returns the reasons of every failed check and judge.
+func (r QualityReport) failures() []string {
+	var out []string
+	for _, c := range slices.Concat(r.Checks, r.Judges) {
+		if !c.Pass && c.Error == "" {
+			out = append(out, c.Name+": "+c.Reason)
+		}
+	}
+	return out
+}
+
+// Run generates posts until they pass the gate or the retries run out. peers
+// holds recent posts of similar businesses; nil skips the similarity check.
+func (g *QualityGate) Run(ctx context.Context, generate content.GenerateFunc, profile content.BusinessProfile, roles []content.Role, previousHooks []string, peers *similarity.Index) ([]content.Post, QualityReport, error) {
+	var failures []string
+	for attempt := 1; ; attempt++ {
+		posts, err := generate(ctx, withGateFeedback(profile, failures), roles, previousHooks)
+		if err != nil {
+			return nil, QualityReport{}, err
+		}
+
+		report := g.check(ctx, profile, posts, peers)
+		report.Attempts = attempt
+		if report.Passed || attempt > g.MaxRetries {
+			return posts, report, nil
+		}
+		failures = report.failures()
+	}
+}
+
+func (g *QualityGate) check(ctx context.Context, profile content.BusinessProfile, posts []content.Post, peers *similarity.Index) QualityReport {
+	report := QualityReport{Passed: true}
+	for _, c := range content.RunChecks(posts) {
+		report.Checks = append(report.Checks, QualityCheck{Name: c.Name, Pass: c.Pass, Reason: c.Reason})
+		report.Passed = report.Passed && c.Pass
+	}
+	if peers != nil && g.MaxSimilarity > 0 {
+		c := g.checkSimilarity(posts, peers)
+		report.Checks = append(report.Checks, c)
+		report.Passed = report.Passed && c.Pass
+	}
+	if !report.Passed || g.Judge == nil {
+		return report
+	}
+
+	rendered := content.RenderPosts(posts)
+	for _, name := range g.Judges {
+		res, err := g.Judge(ctx, name, profile, rendered)
+		if err != nil {
+			report.Judges = append(report.Judges, QualityCheck{Name: name, Error: err.Error()})
+			continue
+		}
+		report.Judges = append(report.Judges, QualityCheck{Name: name, Pass: res.Verdict, Reason: res.Reasoning})
+		report.Passed = report.Passed && res.Verdict
+	}
+	return report
+}
+
+// checkSimilarity compares each post's caption and hook against the peers
+// and fails on the closest match above MaxSimilarity.
+func (g *QualityGate) checkSimilarity(posts []content.Post, peers *similarity.Index) QualityCheck {
+	var closest similarity.Match
+	hooks := content.ExtractHooks(posts)
+	for i, p := range posts {
+		texts := []string{p.Caption}
+		if i < len(hooks) {
+			texts = append(texts, hooks[i])
+		}
+		for _, t := range texts {
+			if m, ok := peers.Nearest(t); ok && m.Score > closest.Score {
+				closest = m
+			}
+		}
+	}
+	if closest.Score <= g.MaxSimilarity {
+		return QualityCheck{Name: "similarity", Pass: true}
+	}
+	return QualityCheck{
+		Name:   "similarity",
+		Reason: fmt.Sprintf("%.0f%% similar to a recent post for another business in the same city and niche: %q", closest.Score*100, excerpt(closest.Text)),
+	}
+}
+
+// excerpt shortens a peer caption for the report and the retry prompt.
+func excerpt(s string) string {
+	const max = 80
+	if r := []rune(s); len(r) > max {
+		return string(r[:max]) + "…"
+	}
+	return s
+}
+
+// withGateFeedback appends the previous attempt's failures to the style memo,
+// the one free-form instruction every generator already receives.
+func withGateFeedback(profile content.BusinessProfile, failures []string) content.BusinessProfile {
+	if len(failures) == 0 {
+		return profile
+	}
+	var b strings.Builder
+	if profile.StyleMemo != "" {
+		b.WriteString(profile.StyleMemo)
+		b.WriteByte('\n')
+	}
+	b.WriteString("- A versão anterior foi recusada na revisão. Corrija:")
+	for _, f := range failures {
+		fmt.Fprintf(&b, "\n  - %s", f)
+	}
+	profile.StyleMemo = b.String()
+	return profile
+}
@@ -0,0 +1,180 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	content "github.com/denisraison/rekan/api/internal/content"
+	"github.com/denisraison/rekan/api/internal/domain"
+	"github.com/denisraison/rekan/api/internal/operator"
+	"github.com/denisraison/rekan/api/internal/service"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func TestGeneratePostsQualityGate(t *testing.T) {
+	app, _, bizID := newTestApp(t)
+	defer app.Cleanup()
+
+	var memos []string
+	generate := func(_ context.Context, p content.BusinessProfile, _ []content.Role, _ []string) ([]content.Post, error) {
+		memos = append(memos, p.StyleMemo)
+		caption := "Pão saindo agora do forno, quentinho e crocante por fora."
+		if len(memos) > 1 {
+			caption = "Bora buscar pão quentinho? Acabou de sair do forno, passa aqui pra levar."
+		}
+		return []content.Post{{Caption: caption, ProductionNote: "Foto do pão no balcão"}}, nil
+	}
+	var judged []string
+	judge := func(_ context.Context, name string, _ content.BusinessProfile, _ string) (content.JudgeResult, error) {
+		judged = append(judged, name)
+		if name == "especificidade" {
+			return content.JudgeResult{}, errors.New("timeout")
+		}
+		return content.JudgeResult{Name: name, Verdict: true, Reasoning: "soa como o dono"}, nil
+	}
+	gate := &service.QualityGate{MaxRetries: 2, Judges: service.DefaultGateJudges, Judge: judge}
+
+	result, err := service.GeneratePosts(context.Background(), app, generate, gate, bizID, 1)
+	if err != nil {
+		t.Fatalf("GeneratePosts: %v", err)
+	}
+	if len(memos) != 2 {
+		t.Fatalf("expected 2 attempts, got %d", len(memos))
+	}
+	if memos[0] != "" {
+		t.Errorf("first attempt memo: got %q, want empty", memos[0])
+	}
+	if !strings.Contains(memos[1], "brazilian_portuguese") {
+		t.Errorf("retry memo should carry the failed check, got %q", memos[1])
+	}
+	if len(judged) != 2 {
+		t.Errorf("judges should only run on the passing attempt, ran %v", judged)
+	}
+
+	q := result.Posts[0].Quality
+	if q == nil || !q.Passed || q.Attempts != 2 {
+		t.Fatalf("quality: got %+v", q)
+	}
+	if len(q.Judges) != 2 || q.Judges[1].Error == "" {
+		t.Errorf("judge error should be recorded without failing the gate: %+v", q.Judges)
+	}
+
+	record, err := app.FindRecordById(domain.CollPosts, result.Posts[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stored service.QualityReport
+	if err := json.Unmarshal([]byte(record.GetString("quality")), &stored); err != nil {
+		t.Fatalf("unmarshal quality: %v", err)
+	}
+	if !stored.Passed || stored.Attempts != 2 || len(stored.Checks) == 0 {
+		t.Errorf("stored quality: got %+v", stored)
+	}
+}
+
+func TestGeneratePostsQualityGateExhausted(t *testing.T) {
+	app, _, bizID := newTestApp(t)
+	defer app.Cleanup()
+
+	calls := 0
+	generate := func(_ context.Context, _ content.BusinessProfile, _ []content.Role, _ []string) ([]content.Post, error) {
+		calls++
+		return []content.Post{{Caption: "Bora provar o pão de queijo da casa, tá saindo agora.", ProductionNote: "Foto"}}, nil
+	}
+	judge := func(_ context.Context, name string, _ content.BusinessProfile, _ string) (content.JudgeResult, error) {
+		return content.JudgeResult{Name: name, Reasoning: "genérico demais"}, nil
+	}
+	gate := &service.QualityGate{MaxRetries: 1, Judges: []string{"naturalidade"}, Judge: judge}
+
+	result, err := service.GeneratePosts(context.Background(), app, generate, gate, bizID, 1)
+	if err != nil {
+		t.Fatalf("GeneratePosts: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 1 retry, got %d calls", calls)
+	}
+	q := result.Posts[0].Quality
+	if q.Passed || q.Attempts != 2 {
+		t.Errorf("quality: got %+v", q)
+	}
+	if len(q.Judges) != 1 || q.Judges[0].Reason != "genérico demais" {
+		t.Errorf("judges: got %+v", q.Judges)
+	}
+}
+
+func TestGeneratePostsSimilarityGuard(t *testing.T) {
+	app, _, bizID := newTestApp(t)
+	defer app.Cleanup()
+
+	const copied = "Bora provar o pão de queijo que acabou de sair do forno? Passa aqui pra levar o seu quentinho."
+	businesses, err := app.FindCollectionByNameOrId(domain.CollBusinesses)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seedPeer := func(name, typ, city string) string {
+		b := core.NewRecord(businesses)
+		b.Set("name", name)
+		b.Set("type", typ)
+		b.Set("city", city)
+		if err := app.Save(b); err != nil {
+			t.Fatal(err)
+		}
+		return b.Id
+	}
+	posts, err := app.FindCollectionByNameOrId(domain.CollPosts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	peerPost := core.NewRecord(posts)
+	peerPost.Set("business", seedPeer("Outra Padaria", "confeitaria", "São Paulo"))
+	peerPost.Set("caption", copied)
+	if err := app.Save(peerPost); err != nil {
+		t.Fatal(err)
+	}
+
+	var memos []string
+	generate := func(_ context.Context, p content.BusinessProfile, _ []content.Role, _ []string) ([]content.Post, error) {
+		memos = append(memos, p.StyleMemo)
+		caption := copied
+		if len(memos) > 1 {
+			caption = "Tá chegando o fim de semana e a fornada de sonho de creme vai sair às 7h. Bora garantir o seu?"
+		}
+		return []content.Post{{Caption: caption, ProductionNote: "Foto do balcão"}}, nil
+	}
+	gate := &service.QualityGate{MaxRetries: 1, MaxSimilarity: service.DefaultMaxSimilarity}
+
+	result, err := service.GeneratePosts(context.Background(), app, generate, gate, bizID, 1)
+	if err != nil {
+		t.Fatalf("GeneratePosts: %v", err)
+	}
+	if len(memos) != 2 || !strings.Contains(memos[1], "similarity") {
+		t.Fatalf("expected a retry carrying the similarity failure, got %q", memos)
+	}
+	q := result.Posts[0].Quality
+	if !q.Passed || q.Attempts != 2 {
+		t.Errorf("quality: got %+v", q)
+	}
+
+	// The same caption for a business in another city or niche is fine.
+	for _, peer := range []string{seedPeer("Padaria Rio", "padaria", "Rio de Janeiro"), seedPeer("Pet Shop SP", "pet shop", "São Paulo")} {
+		ix, err := operator.LoadPeerIndex(app, peer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m, ok := ix.Nearest(copied); ok && m.Score > service.DefaultMaxSimilarity {
+			t.Errorf("business %s should not be compared with the São Paulo bakeries", peer)
+		}
+	}
+
+	// A city typed without accents or in another case is still the same city.
+	ix, err := operator.LoadPeerIndex(app, seedPeer("Padaria Centro", "padaria", "sao paulo "))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m, ok := ix.Nearest(copied); !ok || m.Score <= service.DefaultMaxSimilarity {
+		t.Error("a business in \"sao paulo\" should be compared with the São Paulo bakeries")
+	}
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	content "github.com/denisraison/rekan/api/internal/content"
+	"github.com/denisraison/rekan/api/internal/domain"
+	"github.com/denisraison/rekan/api/internal/operator"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// RewritePost rewrites a pending post following a free-form instruction,
+// looking it up by ID. See RewritePostRecord.
+func RewritePost(ctx context.Context, app core.App, rewrite content.RewriteFunc, postID, instruction string) (*core.Record, []string, error) {
+	record, err := app.FindRecordById(domain.CollPosts, postID)
+	if err != nil {
+		return nil, nil, wrapNotFound(err, "post não encontrado")
+	}
+	updated, err := RewritePostRecord(ctx, app, rewrite, record, instruction)
+	return record, updated, err
+}
+
+// RewritePostRecord rewrites an already-loaded pending feed post with the
+// client's profile and recent hooks, then saves the result through RevisePost
+// so the edited flag and original caption are kept as for a manual edit.
+// Returns the list of updated field keys.
+func RewritePostRecord(ctx context.Context, app core.App, rewrite content.RewriteFunc, record *core.Record, instruction string) ([]string, error) {
+	instruction = strings.TrimSpace(instruction)
+	if instruction == "" {
+		return nil, fmt.Errorf("%w: instrução vazia", ErrInvalid)
+	}
+	if record.GetBool("reviewed") {
+		return nil, fmt.Errorf("%w: post já foi revisado", ErrConflict)
+	}
+	// The caption of a story or carousel is tied to its frames or slides, and
+	// a reel's to its script; rewriting only the caption would leave them
+	// out of step, so those are generated again instead.
+	if f, _ := content.ParseFormat(record.GetString("format")); f != content.FormatFeed {
+		return nil, fmt.Errorf("%w: só posts de feed podem ser reescritos, gere outro %s", ErrInvalid, formatLabel(f))
+	}
+
+	business, err := app.FindRecordById(domain.CollBusinesses, record.GetString("business"))
+	if err != nil {
+		return nil, wrapNotFound(err, "negócio não encontrado")
+	}
+	profile, err := operator.BusinessToProfile(business)
+	if err != nil {
+		return nil, fmt.Errorf("business to profile: %w", err)
+	}
+	previousHooks, err := operator.LoadPreviousHooks(app, business.Id)
+	if err != nil {
+		return nil, fmt.Errorf("load previous hooks: %w", err)
+	}
+
+	current, err := recordToPost(record)
+	if err != nil {
+		return nil, err
+	}
+	post, err := rewrite(ctx, profile, current, instruction, previousHooks)
+	if err != nil {
+		return nil, err
+	}
+
+	params := RevisePostParams{Caption: &post.Caption, ProductionNote: &post.ProductionNote}
+	if post.Hashtags != nil {
+		params.Hashtags = &post.Hashtags
+	}
+	return RevisePost(app, record, params)
+}
+
+// recordToPost reads the content fields of a post record.
+func recordToPost(record *core.Record) (content.Post, error) {
+	p := content.Post{
+		Caption:        record.GetString("caption"),
+		ProductionNote: record.GetString("production_note"),
+	}
+	if raw := record.GetString("hashtags"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &p.Hashtags); err != nil {
+			return content.Post{}, fmt.Errorf("decode hashtags: %w", err)
+		}
+	}
+	if f, ok := content.ParseFormat(record.GetString("format")); ok {
+		p.Format = f
+	}
+	if raw := record.GetString("format_data"); raw != "" && raw != "null" {
+		if err := json.Unmarshal([]byte(raw), &p.Data); err != nil {
+			return content.Post{}, fmt.Errorf("decode format data: %w", err)
+		}
+	}
+	return p, nil
+}
+
+// formatLabel names a format the way operators say it.
+func formatLabel(f content.Format) string {
+	switch f {
+	case content.FormatReel:
+		return "reel"
+	case content.FormatStory:
+		return "story"
+	case content.FormatCarousel:
+		return "carrossel"
+	}
+	return "post"
+}
@@ -0,0 +1,99 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	content "github.com/denisraison/rekan/api/internal/content"
+	"github.com/denisraison/rekan/api/internal/domain"
+	"github.com/denisraison/rekan/api/internal/service"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func TestRewritePost(t *testing.T) {
+	app, _, bizID := newTestApp(t)
+	defer app.Cleanup()
+
+	col, err := app.FindCollectionByNameOrId(domain.CollPosts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	post := core.NewRecord(col)
+	post.Set("business", bizID)
+	post.Set("caption", "Hoje a fornada saiu às 6h e o cheiro de pão tomou a rua inteira. Passa aqui pra provar.")
+	post.Set("hashtags", []string{"#padaria"})
+	post.Set("production_note", "Foto da fornada")
+	post.Set("hook", "a fornada das 6h")
+	if err := app.Save(post); err != nil {
+		t.Fatal(err)
+	}
+
+	var gotProfile content.BusinessProfile
+	var gotPost content.Post
+	var gotInstruction string
+	rewrite := func(_ context.Context, profile content.BusinessProfile, p content.Post, instruction string, _ []string) (content.Post, error) {
+		gotProfile, gotPost, gotInstruction = profile, p, instruction
+		return content.Post{Caption: "Fornada das 6h na rua! Corre que acaba.", Hashtags: p.Hashtags, ProductionNote: p.ProductionNote}, nil
+	}
+
+	record, updated, err := service.RewritePost(context.Background(), app, rewrite, post.Id, "  deixa mais curto  ")
+	if err != nil {
+		t.Fatalf("RewritePost: %v", err)
+	}
+	if gotProfile.BusinessName != "Padaria Teste" {
+		t.Errorf("profile: got %q", gotProfile.BusinessName)
+	}
+	if gotInstruction != "deixa mais curto" {
+		t.Errorf("instruction: got %q", gotInstruction)
+	}
+	if !slices.Equal(gotPost.Hashtags, []string{"#padaria"}) || gotPost.ProductionNote != "Foto da fornada" {
+		t.Errorf("current post: got %+v", gotPost)
+	}
+	if !slices.Equal(updated, []string{"caption"}) {
+		t.Errorf("updated: got %v, want [caption]", updated)
+	}
+	if !record.GetBool("edited") {
+		t.Error("post should be marked edited")
+	}
+	if got := record.GetString("original_caption"); got != gotPost.Caption {
+		t.Errorf("original_caption: got %q", got)
+	}
+
+	// A second rewrite keeps the caption as first generated.
+	if _, _, err := service.RewritePost(context.Background(), app, func(_ context.Context, _ content.BusinessProfile, p content.Post, _ string, _ []string) (content.Post, error) {
+		p.Caption = "Fornada das 6h! 🥖"
+		return p, nil
+	}, post.Id, "coloca um emoji"); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := app.FindRecordById(domain.CollPosts, post.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.GetString("original_caption") != gotPost.Caption {
+		t.Errorf("original_caption changed on second rewrite: %q", saved.GetString("original_caption"))
+	}
+
+	if _, _, err := service.RewritePost(context.Background(), app, rewrite, post.Id, " "); !errors.Is(err, service.ErrInvalid) {
+		t.Errorf("empty instruction: got %v, want ErrInvalid", err)
+	}
+	saved.Set("format", string(content.FormatCarousel))
+	if err := app.Save(saved); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := service.RewritePost(context.Background(), app, rewrite, post.Id, "mais curto"); !errors.Is(err, service.ErrInvalid) {
+		t.Errorf("carousel: got %v, want ErrInvalid so the slides don't go stale", err)
+	}
+	saved.Set("reviewed", true)
+	if err := app.Save(saved); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := service.RewritePost(context.Background(), app, rewrite, post.Id, "mais curto"); !errors.Is(err, service.ErrConflict) {
+		t.Errorf("reviewed post: got %v, want ErrConflict", err)
+	}
+	if _, _, err := service.RewritePost(context.Background(), app, rewrite, "missing", "mais curto"); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("missing post: got %v, want ErrNotFound", err)
+	}
+}
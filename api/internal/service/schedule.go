@@ -1,13 +1,8 @@
 package service
 
 import (
-	"context"
-
-	"go.mau.fi/whatsmeow/proto/waE2E"
-	"go.mau.fi/whatsmeow/types"
-
 	"github.com/denisraison/rekan/api/internal/domain"
-	"github.com/denisraison/rekan/api/internal/whatsapp"
+	"github.com/denisraison/rekan/api/internal/prefs"
 	"github.com/pocketbase/pocketbase/core"
 )
 
@@ -40,7 +35,9 @@ func ListScheduledMessages(app core.App) ([]ScheduledMessage, error) {
 	return result, nil
 }
 
-func ApproveScheduledMessage(ctx context.Context, app core.App, wa *whatsapp.Client, id string) error {
+// ApproveScheduledMessage queues a scheduled message for sending and marks it
+// approved.
+func ApproveScheduledMessage(app core.App, id string) error {
 	record, err := app.FindRecordById(domain.CollScheduledMessages, id)
 	if err != nil {
 		return err
@@ -57,22 +54,13 @@ func ApproveScheduledMessage(ctx context.Context, app core.App, wa *whatsapp.Cli
 		return ErrNoPhone
 	}
 
-	text := record.GetString("text")
-	jid := types.NewJID(phone, types.DefaultUserServer)
-
-	stop := whatsapp.Typing(ctx, wa, jid)
-	defer stop()
-
-	if _, err = wa.SendMessage(ctx, jid, &waE2E.Message{
-		Conversation: &text,
-	}); err != nil {
-		return err
-	}
-
-	StoreOutgoingMessage(app, businessID, phone, domain.MsgTypeText, text, nil)
-
-	record.Set("approved", true)
-	return app.Save(record)
+	return app.RunInTransaction(func(txApp core.App) error {
+		if _, err := Enqueue(txApp, OutboxMessage{BusinessID: businessID, Phone: phone, Text: record.GetString("text"), Category: prefs.Seasonal}); err != nil {
+			return err
+		}
+		record.Set("approved", true)
+		return txApp.Save(record)
+	})
 }
 
 func DismissScheduledMessage(app core.App, id string) error {
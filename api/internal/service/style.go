@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	content "github.com/denisraison/rekan/api/internal/content"
+	"github.com/denisraison/rekan/api/internal/domain"
+	"github.com/denisraison/rekan/api/internal/operator"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// RefreshStyleMemo distills a client's rejections and caption edits into the
+// business's style_memo, which every generator receives from then on.
+// Returns the new memo, or the current one unchanged if there is no
+// feedback to learn from.
+func RefreshStyleMemo(ctx context.Context, app core.App, distill content.DistillStyleFunc, businessID string) (string, error) {
+	business, err := app.FindRecordById(domain.CollBusinesses, businessID)
+	if err != nil {
+		return "", wrapNotFound(err, "negócio não encontrado")
+	}
+
+	feedback, err := operator.LoadStyleFeedback(app, businessID)
+	if err != nil {
+		return "", fmt.Errorf("load style feedback: %w", err)
+	}
+	if feedback.Empty() {
+		return business.GetString("style_memo"), nil
+	}
+
+	memo, err := distill(ctx, business.GetString("name"), feedback)
+	if err != nil {
+		return "", err
+	}
+
+	// The distill call can take a while; reload so edits made to the business
+	// meanwhile are not overwritten.
+	business, err = app.FindRecordById(domain.CollBusinesses, businessID)
+	if err != nil {
+		return "", wrapNotFound(err, "negócio não encontrado")
+	}
+	business.Set("style_memo", memo)
+	if err := app.Save(business); err != nil {
+		return "", fmt.Errorf("save style memo: %w", err)
+	}
+	return memo, nil
+}
+
+// styleMemoTimeout bounds a background memo refresh.
+const styleMemoTimeout = time.Minute
+
+// WatchStyleFeedback re-distills a client's style memo in the background
+// whenever one of their posts gets something new to learn from: a rejection
+// note, a caption edit or a client's change request. Hooking the posts
+// collection covers every path that writes those fields, from the agent tools
+// to the dashboard and the WhatsApp reply classifier.
+func WatchStyleFeedback(app core.App, distill content.DistillStyleFunc) {
+	r := &styleRefresher{app: app, distill: distill, again: map[string]bool{}}
+	app.OnRecordAfterUpdateSuccess(domain.CollPosts).BindFunc(func(e *core.RecordEvent) error {
+		if hasNewStyleFeedback(e.Record) {
+			r.refresh(e.Record.GetString("business"))
+		}
+		return e.Next()
+	})
+}
+
+// hasNewStyleFeedback reports whether a save gave the post a new rejection
+// note, caption edit or client change request.
+func hasNewStyleFeedback(post *core.Record) bool {
+	old := post.Original()
+	changed := func(field string) bool {
+		return post.GetString(field) != "" && post.GetString(field) != old.GetString(field)
+	}
+	switch {
+	case changed("review_note"):
+		return true
+	case post.GetBool("edited") && post.GetString("original_caption") != "" && changed("caption"):
+		return true
+	case post.GetString("client_reply") == content.ReplyChangeRequest && changed("client_feedback"):
+		return true
+	}
+	return false
+}
+
+// styleRefresher runs at most one refresh per business at a time. Feedback
+// arriving mid-refresh queues one more run, so the memo ends up reflecting it
+// without a distill call per save.
+type styleRefresher struct {
+	app     core.App
+	distill content.DistillStyleFunc
+
+	mu    sync.Mutex
+	again map[string]bool // businesses with a refresh running; true if another is due
+}
+
+func (r *styleRefresher) refresh(businessID string) {
+	r.mu.Lock()
+	if _, running := r.again[businessID]; running {
+		r.again[businessID] = true
+		r.mu.Unlock()
+		return
+	}
+	r.again[businessID] = false
+	r.mu.Unlock()
+
+	go func() {
+		for {
+			ctx, cancel := context.WithTimeout(context.Background(), styleMemoTimeout)
+			if _, err := RefreshStyleMemo(ctx, r.app, r.distill, businessID); err != nil {
+				r.app.Logger().Error("refresh style memo failed", "business", businessID, "error", err)
+			}
+			cancel()
+
+			r.mu.Lock()
+			if !r.again[businessID] {
+				delete(r.again, businessID)
+				r.mu.Unlock()
+				return
+			}
+			r.again[businessID] = false
+			r.mu.Unlock()
+		}
+	}()
+}
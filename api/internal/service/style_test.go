@@ -0,0 +1,193 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	content "github.com/denisraison/rekan/api/internal/content"
+	"github.com/denisraison/rekan/api/internal/domain"
+	"github.com/denisraison/rekan/api/internal/service"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func TestRefreshStyleMemo(t *testing.T) {
+	app, _, bizID := newTestApp(t)
+	defer app.Cleanup()
+
+	col, err := app.FindCollectionByNameOrId(domain.CollPosts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rejected := core.NewRecord(col)
+	rejected.Set("business", bizID)
+	rejected.Set("caption", "🔥 Promoção imperdível!")
+	if err := app.Save(rejected); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.RejectPostRecord(app, rejected, "sem emoji de fogo"); err != nil {
+		t.Fatal(err)
+	}
+
+	edited := core.NewRecord(col)
+	edited.Set("business", bizID)
+	edited.Set("caption", "Oi clientes!")
+	if err := app.Save(edited); err != nil {
+		t.Fatal(err)
+	}
+	caption := "Oi meninas!"
+	if _, err := service.RevisePost(app, edited, service.RevisePostParams{Caption: &caption}); err != nil {
+		t.Fatal(err)
+	}
+	if got := edited.GetString("original_caption"); got != "Oi clientes!" {
+		t.Errorf("original_caption: got %q, want %q", got, "Oi clientes!")
+	}
+
+	var got content.StyleFeedback
+	distill := func(_ context.Context, _ string, f content.StyleFeedback) (string, error) {
+		got = f
+		// The operator edits the profile while the memo is being distilled.
+		biz, err := app.FindRecordById(domain.CollBusinesses, bizID)
+		if err != nil {
+			return "", err
+		}
+		biz.Set("brand_vibe", "divertida")
+		if err := app.Save(biz); err != nil {
+			return "", err
+		}
+		return "- Sem emoji de fogo\n- Chame de meninas", nil
+	}
+	memo, err := service.RefreshStyleMemo(context.Background(), app, distill, bizID)
+	if err != nil {
+		t.Fatalf("RefreshStyleMemo: %v", err)
+	}
+
+	if len(got.Rejections) != 1 || got.Rejections[0].Feedback != "sem emoji de fogo" {
+		t.Errorf("rejections: got %+v", got.Rejections)
+	}
+	if len(got.Edits) != 1 || got.Edits[0].Before != "Oi clientes!" || got.Edits[0].After != "Oi meninas!" {
+		t.Errorf("edits: got %+v", got.Edits)
+	}
+
+	biz, err := app.FindRecordById(domain.CollBusinesses, bizID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if biz.GetString("style_memo") != memo {
+		t.Errorf("style_memo: got %q, want %q", biz.GetString("style_memo"), memo)
+	}
+	if biz.GetString("brand_vibe") != "divertida" {
+		t.Errorf("brand_vibe edited during the refresh was overwritten: got %q", biz.GetString("brand_vibe"))
+	}
+
+	// The memo reaches the generator through the profile.
+	var seen string
+	capture := func(_ context.Context, p content.BusinessProfile, _ []content.Role, _ []string) ([]content.Post, error) {
+		seen = p.StyleMemo
+		return nil, nil
+	}
+	if _, err := service.GenerateIdeas(context.Background(), app, capture, bizID); err != nil {
+		t.Fatal(err)
+	}
+	if seen != memo {
+		t.Errorf("generator style memo: got %q, want %q", seen, memo)
+	}
+}
+
+func TestRefreshStyleMemoNoFeedback(t *testing.T) {
+	app, _, bizID := newTestApp(t)
+	defer app.Cleanup()
+
+	distill := func(_ context.Context, _ string, _ content.StyleFeedback) (string, error) {
+		t.Error("distill should not run without feedback")
+		return "", nil
+	}
+	memo, err := service.RefreshStyleMemo(context.Background(), app, distill, bizID)
+	if err != nil {
+		t.Fatalf("RefreshStyleMemo: %v", err)
+	}
+	if memo != "" {
+		t.Errorf("memo: got %q, want empty", memo)
+	}
+}
+
+func TestWatchStyleFeedback(t *testing.T) {
+	app, _, bizID := newTestApp(t)
+	defer app.Cleanup()
+
+	calls := make(chan content.StyleFeedback, 10)
+	service.WatchStyleFeedback(app, func(_ context.Context, _ string, f content.StyleFeedback) (string, error) {
+		calls <- f
+		if len(f.Edits) > 0 {
+			return "- Sem emoji de fogo\n- Legenda curta", nil
+		}
+		return "- Sem emoji de fogo", nil
+	})
+	waitCall := func(what string) content.StyleFeedback {
+		t.Helper()
+		select {
+		case f := <-calls:
+			return f
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%s did not refresh the style memo", what)
+			return content.StyleFeedback{}
+		}
+	}
+
+	col, err := app.FindCollectionByNameOrId(domain.CollPosts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	post := core.NewRecord(col)
+	post.Set("business", bizID)
+	post.Set("caption", "🔥 Promoção imperdível!")
+	if err := app.Save(post); err != nil {
+		t.Fatal(err)
+	}
+
+	// Approving has nothing to learn from.
+	if _, err := service.ApprovePostRecord(app, post); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-calls:
+		t.Fatal("approval refreshed the style memo")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	// A client change request reaches the memo as a rejection.
+	post.Set("client_reply", content.ReplyChangeRequest)
+	post.Set("client_feedback", "tira o emoji de fogo")
+	post.Set("reviewed", false)
+	if err := app.Save(post); err != nil {
+		t.Fatal(err)
+	}
+	f := waitCall("a client change request")
+	if len(f.Rejections) != 1 || f.Rejections[0].Feedback != "tira o emoji de fogo" {
+		t.Errorf("rejections: got %+v", f.Rejections)
+	}
+
+	caption := "Promoção da semana!"
+	if _, err := service.RevisePost(app, post, service.RevisePostParams{Caption: &caption}); err != nil {
+		t.Fatal(err)
+	}
+	if f := waitCall("a caption edit"); len(f.Edits) != 1 || f.Edits[0].After != caption {
+		t.Errorf("edits: got %+v", f.Edits)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		biz, err := app.FindRecordById(domain.CollBusinesses, bizID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The last refresh saw the edit; wait for it before cleanup.
+		if biz.GetString("style_memo") == "- Sem emoji de fogo\n- Legenda curta" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("style_memo = %q, want the distilled memo", biz.GetString("style_memo"))
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
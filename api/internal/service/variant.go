@@ -0,0 +1,132 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	content "github.com/denisraison/rekan/api/internal/content"
+	"github.com/denisraison/rekan/api/internal/domain"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// MaxVariants caps how many caption options one generation may ask for. Each
+// variant is a full generator call, plus gate retries.
+const MaxVariants = 3
+
+// PostVariant is one caption option generated for a post. All variants stay on
+// the post after the operator picks one, so we can later see which hooks win.
+type PostVariant struct {
+	Caption        string             `json:"caption"`
+	Hashtags       []string           `json:"hashtags"`
+	ProductionNote string             `json:"production_note"`
+	Hook           string             `json:"hook"`
+	FormatData     content.FormatData `json:"format_data,omitzero"`
+	Quality        *QualityReport     `json:"quality,omitempty"`
+	Chosen         bool               `json:"chosen"`
+	// EditedCaption is the operator's edit of this variant's caption, kept
+	// here when another variant is picked over it so the style memo still
+	// learns from the edit.
+	EditedCaption string `json:"edited_caption,omitempty"`
+}
+
+// generateVariants turns the first generation into the first variant of each
+// post and runs n-1 more, passing every hook so far so each variant opens
+// differently.
+func generateVariants(first []content.Post, quality *QualityReport, n int, previousHooks []string, run func([]string) ([]content.Post, *QualityReport, error)) ([][]PostVariant, error) {
+	options := make([][]PostVariant, len(first))
+	for i, p := range first {
+		options[i] = []PostVariant{toVariant(p, quality)}
+	}
+	seen := slices.Concat(previousHooks, content.ExtractHooks(first))
+	for range n - 1 {
+		posts, q, err := run(seen)
+		if err != nil {
+			return nil, fmt.Errorf("generate variant: %w", err)
+		}
+		for i := range min(len(posts), len(options)) {
+			options[i] = append(options[i], toVariant(posts[i], q))
+		}
+		seen = append(seen, content.ExtractHooks(posts)...)
+	}
+	return options, nil
+}
+
+func toVariant(p content.Post, quality *QualityReport) PostVariant {
+	v := PostVariant{
+		Caption:        p.Caption,
+		Hashtags:       p.Hashtags,
+		ProductionNote: p.ProductionNote,
+		FormatData:     p.Data,
+		Quality:        quality,
+	}
+	if hooks := content.ExtractHooks([]content.Post{p}); len(hooks) > 0 {
+		v.Hook = hooks[0]
+	}
+	return v
+}
+
+// PickVariant makes one of a pending post's variants its content and flags it
+// as chosen. A manual edit of the caption moves onto the variant it was made
+// to, and comes back if that variant is picked again, so the edited flag and
+// original caption only ever describe edits to the current variant.
+func PickVariant(app core.App, postID string, index int) (*core.Record, error) {
+	record, err := app.FindRecordById(domain.CollPosts, postID)
+	if err != nil {
+		return nil, wrapNotFound(err, "post não encontrado")
+	}
+	if record.GetBool("reviewed") {
+		return nil, fmt.Errorf("%w: post já foi revisado", ErrConflict)
+	}
+
+	var variants []PostVariant
+	if raw := record.GetString("variants"); raw != "" && raw != "null" {
+		if err := json.Unmarshal([]byte(raw), &variants); err != nil {
+			return nil, fmt.Errorf("decode variants: %w", err)
+		}
+	}
+	if index < 0 || index >= len(variants) {
+		return nil, fmt.Errorf("%w: variante inexistente", ErrInvalid)
+	}
+
+	if record.GetBool("edited") && record.GetString("original_caption") != "" {
+		if i := currentVariant(variants, record.GetString("original_caption")); i >= 0 {
+			variants[i].EditedCaption = record.GetString("caption")
+		}
+	}
+	for i := range variants {
+		variants[i].Chosen = i == index
+	}
+	v := variants[index]
+	if v.EditedCaption != "" {
+		record.Set("caption", v.EditedCaption)
+		record.Set("original_caption", v.Caption)
+		record.Set("edited", true)
+		variants[index].EditedCaption = ""
+	} else {
+		record.Set("caption", v.Caption)
+		record.Set("original_caption", "")
+		record.Set("edited", false)
+	}
+	record.Set("hashtags", v.Hashtags)
+	record.Set("production_note", v.ProductionNote)
+	record.Set("hook", v.Hook)
+	record.Set("quality", v.Quality)
+	if f, ok := content.ParseFormat(record.GetString("format")); ok && f != content.FormatFeed {
+		record.Set("format_data", v.FormatData)
+	}
+	record.Set("variants", variants)
+
+	if err := app.Save(record); err != nil {
+		return nil, fmt.Errorf("saving picked variant: %w", err)
+	}
+	return record, nil
+}
+
+// currentVariant returns the index of the variant a post's caption was taken
+// from, matching its caption as generated, or -1.
+func currentVariant(variants []PostVariant, generated string) int {
+	return slices.IndexFunc(variants, func(v PostVariant) bool {
+		return v.Caption == generated
+	})
+}
@@ -0,0 +1,180 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+
+	content "github.com/denisraison/rekan/api/internal/content"
+	"github.com/denisraison/rekan/api/internal/domain"
+	"github.com/denisraison/rekan/api/internal/operator"
+	"github.com/denisraison/rekan/api/internal/service"
+)
+
+func TestGeneratePostsVariants(t *testing.T) {
+	app, _, bizID := newTestApp(t)
+	defer app.Cleanup()
+
+	captions := []string{
+		"Pão quentinho saindo agora. Passa aqui!",
+		"Sabe aquele cheiro de fornada nova? Tá no ar.",
+		"Hoje tem sonho de creme. Corre que acaba!",
+		"Nunca deveria ser chamado.",
+	}
+	var seen [][]string
+	generate := func(_ context.Context, _ content.BusinessProfile, _ []content.Role, hooks []string) ([]content.Post, error) {
+		seen = append(seen, hooks)
+		return []content.Post{{Caption: captions[len(seen)-1], ProductionNote: "Foto"}}, nil
+	}
+
+	result, err := service.GeneratePosts(context.Background(), app, generate, nil, bizID, service.MaxVariants+1)
+	if err != nil {
+		t.Fatalf("GeneratePosts: %v", err)
+	}
+	if len(seen) != service.MaxVariants {
+		t.Fatalf("expected %d generations, got %d", service.MaxVariants, len(seen))
+	}
+	if !slices.Contains(seen[2], "Pão quentinho saindo agora.") || !slices.Contains(seen[2], "Sabe aquele cheiro de fornada nova?") {
+		t.Errorf("later variants should avoid earlier hooks, got %q", seen[2])
+	}
+
+	posts, err := app.FindAllRecords(domain.CollPosts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(posts) != 1 || len(result.Posts) != 1 {
+		t.Fatalf("variants should share one post, got %d records", len(posts))
+	}
+	post := result.Posts[0]
+	if post.Caption != captions[0] || len(post.Variants) != service.MaxVariants {
+		t.Fatalf("post: got caption %q with %d variants", post.Caption, len(post.Variants))
+	}
+
+	record, err := service.PickVariant(app, post.ID, 2)
+	if err != nil {
+		t.Fatalf("PickVariant: %v", err)
+	}
+	if got := record.GetString("caption"); got != captions[2] {
+		t.Errorf("caption: got %q, want %q", got, captions[2])
+	}
+	if got := record.GetString("hook"); got != "Hoje tem sonho de creme." {
+		t.Errorf("hook: got %q", got)
+	}
+	var stored []service.PostVariant
+	if err := json.Unmarshal([]byte(record.GetString("variants")), &stored); err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != service.MaxVariants || stored[0].Chosen || !stored[2].Chosen {
+		t.Errorf("variants: got %+v", stored)
+	}
+
+	if _, err := service.PickVariant(app, post.ID, 3); !errors.Is(err, service.ErrInvalid) {
+		t.Errorf("out of range index: got %v, want ErrInvalid", err)
+	}
+	if _, err := service.ApprovePost(app, post.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.PickVariant(app, post.ID, 0); !errors.Is(err, service.ErrConflict) {
+		t.Errorf("reviewed post: got %v, want ErrConflict", err)
+	}
+}
+
+func TestPickVariantTakesItsQualityReport(t *testing.T) {
+	app, _, bizID := newTestApp(t)
+	defer app.Cleanup()
+
+	captions := []string{
+		"Sabe aquele cheiro de pão saindo do forno às 6h? A fornada de hoje tá no balcão. Passa aqui e leva o seu quentinho.",
+		"Hoje tem sonho de creme recheado na hora, do jeito que a vó fazia. Corre pra cá que às 10h já acabou. Te espero!",
+	}
+	calls := 0
+	generate := func(_ context.Context, _ content.BusinessProfile, _ []content.Role, _ []string) ([]content.Post, error) {
+		calls++
+		return []content.Post{{Caption: captions[calls-1], ProductionNote: "Foto do balcão"}}, nil
+	}
+	judge := func(_ context.Context, name string, _ content.BusinessProfile, rendered string) (content.JudgeResult, error) {
+		return content.JudgeResult{Name: name, Verdict: true, Reasoning: rendered}, nil
+	}
+	gate := &service.QualityGate{Judges: []string{"naturalidade"}, Judge: judge}
+
+	result, err := service.GeneratePosts(context.Background(), app, generate, gate, bizID, 2)
+	if err != nil {
+		t.Fatalf("GeneratePosts: %v", err)
+	}
+	record, err := service.PickVariant(app, result.Posts[0].ID, 1)
+	if err != nil {
+		t.Fatalf("PickVariant: %v", err)
+	}
+
+	var quality service.QualityReport
+	if err := json.Unmarshal([]byte(record.GetString("quality")), &quality); err != nil {
+		t.Fatal(err)
+	}
+	want := result.Posts[0].Variants[1].Quality
+	if want == nil || len(quality.Judges) != len(want.Judges) || len(quality.Checks) != len(want.Checks) {
+		t.Fatalf("quality: got %+v, want the second variant's %+v", quality, want)
+	}
+	if len(want.Judges) == 0 || quality.Judges[0].Reason != want.Judges[0].Reason {
+		t.Errorf("quality judges: got %+v, want %+v", quality.Judges, want.Judges)
+	}
+}
+
+func TestPickVariantKeepsEditHistory(t *testing.T) {
+	app, _, bizID := newTestApp(t)
+	defer app.Cleanup()
+
+	captions := []string{
+		"Sabe aquele cheiro de pão saindo do forno às 6h? A fornada de hoje tá no balcão. Passa aqui e leva o seu quentinho.",
+		"Hoje tem sonho de creme recheado na hora, do jeito que a vó fazia. Corre pra cá que às 10h já acabou. Te espero!",
+	}
+	calls := 0
+	generate := func(_ context.Context, _ content.BusinessProfile, _ []content.Role, _ []string) ([]content.Post, error) {
+		calls++
+		return []content.Post{{Caption: captions[calls-1], ProductionNote: "Foto do balcão"}}, nil
+	}
+	result, err := service.GeneratePosts(context.Background(), app, generate, nil, bizID, 2)
+	if err != nil {
+		t.Fatalf("GeneratePosts: %v", err)
+	}
+	record, err := app.FindRecordById(domain.CollPosts, result.Posts[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edit := "Pão quentinho saindo agora, às 6h. Passa aqui!"
+	if _, err := service.RevisePost(app, record, service.RevisePostParams{Caption: &edit}); err != nil {
+		t.Fatal(err)
+	}
+
+	record, err = service.PickVariant(app, record.Id, 1)
+	if err != nil {
+		t.Fatalf("PickVariant: %v", err)
+	}
+	if record.GetBool("edited") || record.GetString("caption") != captions[1] {
+		t.Errorf("after picking 1: edited = %v, caption = %q", record.GetBool("edited"), record.GetString("caption"))
+	}
+	feedback, err := operator.LoadStyleFeedback(app, bizID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(feedback.Edits) != 1 || feedback.Edits[0].Before != captions[0] || feedback.Edits[0].After != edit {
+		t.Errorf("edits = %+v, want the edit to the swapped-out variant", feedback.Edits)
+	}
+
+	// Picking the edited variant again brings the edit back.
+	record, err = service.PickVariant(app, record.Id, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !record.GetBool("edited") || record.GetString("caption") != edit || record.GetString("original_caption") != captions[0] {
+		t.Errorf("after picking 0: edited = %v, caption = %q, original = %q", record.GetBool("edited"), record.GetString("caption"), record.GetString("original_caption"))
+	}
+	feedback, err = operator.LoadStyleFeedback(app, bizID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(feedback.Edits) != 1 {
+		t.Errorf("edits = %+v, want the edit counted once", feedback.Edits)
+	}
+}
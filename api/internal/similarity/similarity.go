@@ -0,0 +1,148 @@
+// Package similarity estimates how alike two texts are with MinHash over
+// character shingles, so near-identical captions are caught even when a few
+// words were swapped.
+package similarity
+
+import (
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/denisraison/rekan/api/internal/textnorm"
+)
+
+const (
+	numHashes   = 128
+	shingleSize = 5
+)
+
+// seeds are fixed so signatures are comparable across runs.
+var seeds = func() [numHashes][2]uint64 {
+	var s [numHashes][2]uint64
+	x := uint64(0x9e3779b97f4a7c15)
+	for i := range s {
+		for j := range s[i] {
+			// splitmix64
+			x += 0x9e3779b97f4a7c15
+			z := x
+			z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+			z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+			s[i][j] = z ^ (z >> 31)
+		}
+		s[i][0] |= 1 // odd multiplier
+	}
+	return s
+}()
+
+// Signature is the MinHash of a text. The zero value is the signature of a
+// text too short to shingle and matches nothing.
+type Signature struct {
+	mins  [numHashes]uint64
+	empty bool
+}
+
+// Sign computes the signature of text, ignoring case, accents, punctuation,
+// emojis and spacing.
+func Sign(text string) Signature {
+	norm := []rune(normalize(text))
+	if len(norm) < shingleSize {
+		return Signature{empty: true}
+	}
+	var sig Signature
+	for i := range sig.mins {
+		sig.mins[i] = math.MaxUint64
+	}
+	for i := 0; i+shingleSize <= len(norm); i++ {
+		h := fnv.New64a()
+		h.Write([]byte(string(norm[i : i+shingleSize])))
+		base := h.Sum64()
+		for j, s := range seeds {
+			if v := base*s[0] + s[1]; v < sig.mins[j] {
+				sig.mins[j] = v
+			}
+		}
+	}
+	return sig
+}
+
+// Similarity estimates the Jaccard similarity of the two texts' shingle sets,
+// from 0 (nothing shared) to 1 (same text).
+func Similarity(a, b Signature) float64 {
+	if a.empty || b.empty {
+		return 0
+	}
+	same := 0
+	for i := range a.mins {
+		if a.mins[i] == b.mins[i] {
+			same++
+		}
+	}
+	return float64(same) / numHashes
+}
+
+// Match is the closest indexed text to a query.
+type Match struct {
+	ID    string
+	Text  string
+	Score float64
+}
+
+type entry struct {
+	id   string
+	text string
+	sig  Signature
+}
+
+// Index holds signatures of recent texts. It is a linear scan: a city and
+// niche rarely have more than a few hundred recent posts.
+type Index struct {
+	entries []entry
+}
+
+// Add indexes text under id. Several texts may share an id, e.g. a post's
+// caption and its hook.
+func (ix *Index) Add(id, text string) {
+	if strings.TrimSpace(text) == "" {
+		return
+	}
+	ix.entries = append(ix.entries, entry{id: id, text: text, sig: Sign(text)})
+}
+
+// Len returns the number of indexed texts.
+func (ix *Index) Len() int {
+	return len(ix.entries)
+}
+
+// Nearest returns the indexed text most similar to text. ok is false when the
+// index is empty or nothing shares a shingle with text.
+func (ix *Index) Nearest(text string) (m Match, ok bool) {
+	sig := Sign(text)
+	for _, e := range ix.entries {
+		if s := Similarity(sig, e.sig); s > m.Score {
+			m = Match{ID: e.id, Text: e.text, Score: s}
+			ok = true
+		}
+	}
+	return m, ok
+}
+
+// normalize lowercases, strips accents and keeps letters and digits with
+// single spaces between words.
+func normalize(s string) string {
+	s = textnorm.Fold(s)
+	var b strings.Builder
+	space := false
+	for _, r := range s {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteRune(r)
+			space = false
+			continue
+		}
+		space = true
+	}
+	return b.String()
+}
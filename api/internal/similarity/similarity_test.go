@@ -0,0 +1,49 @@
+package similarity
+
+import "testing"
+
+const caption = "Bolo de pote de ninho com morango saindo agora! Passa aqui na confeitaria pra garantir o seu antes que acabe."
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     string
+		min, max float64
+	}{
+		{"same text", caption, caption, 1, 1},
+		{"case, accents and punctuation", caption, "BOLO DE POTE DE NINHO COM MORANGO SAINDO AGORA... passa aqui na confeitária, pra garantir o seu antes que acabe", 1, 1},
+		{"few words swapped", caption, "Bolo de pote de ninho com morango saindo agora! Corre aqui na confeitaria pra garantir o seu antes que termine.", 0.6, 1},
+		{"different post", caption, "Hoje a fornada de pão de queijo atrasou porque o forno resolveu tirar folga. Já voltou, tá tudo quentinho.", 0, 0.2},
+		{"too short", "oi", "oi", 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Similarity(Sign(tt.a), Sign(tt.b))
+			if got < tt.min || got > tt.max {
+				t.Errorf("got %.2f, want between %.2f and %.2f", got, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestIndexNearest(t *testing.T) {
+	var ix Index
+	if _, ok := ix.Nearest(caption); ok {
+		t.Error("empty index should not match")
+	}
+
+	ix.Add("a", "Hoje a fornada de pão de queijo atrasou porque o forno resolveu tirar folga.")
+	ix.Add("b", caption)
+	ix.Add("c", "")
+	if ix.Len() != 2 {
+		t.Errorf("blank text should not be indexed, got %d entries", ix.Len())
+	}
+
+	m, ok := ix.Nearest("Bolo de pote de ninho com morango saindo agora! Corre pra garantir o seu.")
+	if !ok || m.ID != "b" {
+		t.Fatalf("got %+v, want match on b", m)
+	}
+	if m.Score < 0.3 {
+		t.Errorf("score too low: %.2f", m.Score)
+	}
+}
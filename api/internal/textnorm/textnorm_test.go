@@ -0,0 +1,20 @@
+package textnorm
+
+import "testing"
+
+func TestFold(t *testing.T) {
+	tests := map[string]string{
+		"São Paulo":      "sao paulo",
+		"GOIÂNIA":        "goiania",
+		"Não quero mais": "nao quero mais",
+		"Açaí":           "acai",
+		"Crème brûlée":   "creme brulee",
+		"Piñata":         "pinata",
+		"PÒ ÀS ÑANDU":    "po as nandu",
+	}
+	for in, want := range tests {
+		if got := Fold(in); got != want {
+			t.Errorf("Fold(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
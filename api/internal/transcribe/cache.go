@@ -0,0 +1,140 @@
+package transcribe
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Default cache limits used by NewClient.
+const (
+	DefaultCacheTTL     = 24 * time.Hour
+	DefaultCacheEntries = 2000
+)
+
+// Cache keeps transcripts and descriptions in memory so media forwarded
+// between chats is only sent to Gemini once. Entries expire after ttl and
+// the least recently used ones are evicted past maxEntries. A nil *Cache
+// caches nothing.
+type Cache struct {
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+
+	mu      sync.Mutex
+	lru     *list.List // front is most recently used
+	entries map[string]*list.Element
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+type cacheEntry struct {
+	key       string
+	value     string
+	expiresAt time.Time
+}
+
+// CacheStats is a snapshot of the cache counters.
+type CacheStats struct {
+	Entries int   `json:"entries"`
+	Hits    int64 `json:"hits"`
+	Misses  int64 `json:"misses"`
+}
+
+// NewCache creates a cache holding up to maxEntries results for ttl each.
+func NewCache(ttl time.Duration, maxEntries int) *Cache {
+	return &Cache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		now:        time.Now,
+		lru:        list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+// MediaKey builds the cache key for an analysis of kind ("audio", "image",
+// "video") of the file with the given SHA-256, as sent by WhatsApp. The
+// caption is part of the key because it is part of the prompt. Returns ""
+// when the hash is unknown, which Do treats as uncacheable.
+func MediaKey(kind string, fileSHA256 []byte, caption string) string {
+	if len(fileSHA256) == 0 {
+		return ""
+	}
+	key := kind + ":" + hex.EncodeToString(fileSHA256)
+	if caption != "" {
+		sum := sha256.Sum256([]byte(caption))
+		key += ":" + hex.EncodeToString(sum[:8])
+	}
+	return key
+}
+
+// Do returns the cached result for key, or calls fn and caches what it
+// returns. Errors and empty results are not cached.
+func (c *Cache) Do(key string, fn func() (string, error)) (string, error) {
+	if c == nil || key == "" {
+		return fn()
+	}
+	if value, ok := c.get(key); ok {
+		c.hits.Add(1)
+		return value, nil
+	}
+	c.misses.Add(1)
+
+	value, err := fn()
+	if err == nil && value != "" {
+		c.put(key, value)
+	}
+	return value, err
+}
+
+// Stats returns the current counters.
+func (c *Cache) Stats() CacheStats {
+	if c == nil {
+		return CacheStats{}
+	}
+	c.mu.Lock()
+	n := c.lru.Len()
+	c.mu.Unlock()
+	return CacheStats{Entries: n, Hits: c.hits.Load(), Misses: c.misses.Load()}
+}
+
+func (c *Cache) get(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+	entry := el.Value.(*cacheEntry)
+	if c.now().After(entry.expiresAt) {
+		c.remove(el)
+		return "", false
+	}
+	c.lru.MoveToFront(el)
+	return entry.value, true
+}
+
+func (c *Cache) put(key, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expiresAt := c.now().Add(c.ttl)
+	if el, ok := c.entries[key]; ok {
+		entry := el.Value.(*cacheEntry)
+		entry.value, entry.expiresAt = value, expiresAt
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(&cacheEntry{key: key, value: value, expiresAt: expiresAt})
+	for c.lru.Len() > c.maxEntries {
+		c.remove(c.lru.Back())
+	}
+}
+
+func (c *Cache) remove(el *list.Element) {
+	c.lru.Remove(el)
+	delete(c.entries, el.Value.(*cacheEntry).key)
+}
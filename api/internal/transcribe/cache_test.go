@@ -0,0 +1,94 @@
+package transcribe
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func counting(value string, err error) (func() (string, error), *int) {
+	calls := 0
+	return func() (string, error) {
+		calls++
+		return value, err
+	}, &calls
+}
+
+func TestCacheReusesResultsByMediaKey(t *testing.T) {
+	c := NewCache(time.Hour, 10)
+	sha := []byte{0xab, 0xcd}
+	fn, calls := counting("Um bolo de cenoura.", nil)
+
+	for range 3 {
+		got, err := c.Do(MediaKey("image", sha, ""), fn)
+		if err != nil || got != "Um bolo de cenoura." {
+			t.Fatalf("Do = %q, %v", got, err)
+		}
+	}
+	if *calls != 1 {
+		t.Errorf("fn called %d times, want 1", *calls)
+	}
+
+	// A different caption changes the prompt, so it is a different entry.
+	if _, err := c.Do(MediaKey("image", sha, "promoção"), fn); err != nil {
+		t.Fatal(err)
+	}
+	if *calls != 2 {
+		t.Errorf("captioned image served from the uncaptioned entry")
+	}
+	if s := c.Stats(); s.Hits != 2 || s.Misses != 2 || s.Entries != 2 {
+		t.Errorf("stats = %+v", s)
+	}
+}
+
+func TestCacheSkipsErrorsAndUnknownHashes(t *testing.T) {
+	c := NewCache(time.Hour, 10)
+
+	failing, calls := counting("", errors.New("gemini API error 503"))
+	c.Do(MediaKey("audio", []byte{1}, ""), failing)
+	c.Do(MediaKey("audio", []byte{1}, ""), failing)
+	if *calls != 2 {
+		t.Errorf("failed result was cached")
+	}
+
+	fn, calls := counting("oi", nil)
+	c.Do(MediaKey("audio", nil, ""), fn)
+	c.Do(MediaKey("audio", nil, ""), fn)
+	if *calls != 2 {
+		t.Errorf("media without a hash was cached")
+	}
+
+	var nilCache *Cache
+	if got, err := nilCache.Do("audio:01", fn); err != nil || got != "oi" {
+		t.Errorf("nil cache Do = %q, %v", got, err)
+	}
+}
+
+func TestCacheExpiresAndEvicts(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	c := NewCache(time.Hour, 2)
+	c.now = func() time.Time { return now }
+
+	fn, calls := counting("x", nil)
+	c.Do("a", fn)
+	c.Do("b", fn)
+	c.Do("a", fn) // a is now the most recently used
+	c.Do("c", fn) // evicts b
+	if *calls != 3 {
+		t.Fatalf("fn called %d times, want 3", *calls)
+	}
+	c.Do("a", fn)
+	if *calls != 3 {
+		t.Error("recently used entry was evicted")
+	}
+	c.Do("b", fn)
+	if *calls != 4 {
+		t.Error("least recently used entry was kept")
+	}
+
+	now = now.Add(2 * time.Hour)
+	c.Do("b", fn)
+	if *calls != 5 {
+		t.Error("expired entry was served")
+	}
+}
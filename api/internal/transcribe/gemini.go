@@ -24,13 +24,18 @@ const geminiEndpoint = "https://generativelanguage.googleapis.com/v1beta/models/
 type Client struct {
 	apiKey string
 	http   *http.Client
+
+	// Cache holds results keyed by MediaKey for callers that know the
+	// WhatsApp file hash. Everyone sharing a Client shares its cache.
+	Cache *Cache
 }
 
-// NewClient creates a Gemini transcription client.
+// NewClient creates a Gemini transcription client with a default-sized cache.
 func NewClient(apiKey string) *Client {
 	return &Client{
 		apiKey: apiKey,
 		http:   &http.Client{Timeout: 30 * time.Second},
+		Cache:  NewCache(DefaultCacheTTL, DefaultCacheEntries),
 	}
 }
 
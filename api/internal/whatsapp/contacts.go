@@ -9,6 +9,7 @@ import (
 	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/types"
 
+	content "github.com/denisraison/rekan/api/internal/content"
 	"github.com/denisraison/rekan/api/internal/domain"
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/tools/filesystem"
@@ -16,12 +17,13 @@ import (
 
 // findOrCreateBusiness returns the business ID, invite status, and type for the given
 // phone number, creating a placeholder business if none exists yet. pushName is the
-// sender's WhatsApp display name (empty for outgoing messages).
-func findOrCreateBusiness(deps HandlerDeps, phone, pushName string) (id, inviteStatus, businessType string) {
+// sender's WhatsApp display name (empty for outgoing messages). created reports
+// whether the placeholder was made by this call.
+func findOrCreateBusiness(deps HandlerDeps, phone, pushName string) (id, inviteStatus, businessType string, created bool) {
 	business, err := deps.App.FindFirstRecordByFilter(domain.CollBusinesses, "phone = {:phone}", map[string]any{"phone": phone})
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		deps.Logger.Error("whatsapp: find business by phone", "phone", phone, "error", err)
-		return "", "", ""
+		return "", "", "", false
 	}
 	if business != nil {
 		// Update name if the placeholder still uses the raw phone and we now have a real name.
@@ -32,13 +34,13 @@ func findOrCreateBusiness(deps HandlerDeps, phone, pushName string) (id, inviteS
 				deps.Logger.Error("whatsapp: failed to update placeholder name", "phone", phone, "error", err)
 			}
 		}
-		return business.Id, business.GetString("invite_status"), business.GetString("type")
+		return business.Id, business.GetString("invite_status"), business.GetString("type"), false
 	}
 
 	collection, err := deps.App.FindCachedCollectionByNameOrId(domain.CollBusinesses)
 	if err != nil {
 		deps.Logger.Error("whatsapp: businesses collection not found", "error", err)
-		return "", "", ""
+		return "", "", "", false
 	}
 
 	name := "+" + phone
@@ -50,25 +52,25 @@ func findOrCreateBusiness(deps HandlerDeps, phone, pushName string) (id, inviteS
 	record.Set("phone", phone)
 	record.Set("name", name)
 	record.Set("client_name", pushName)
-	record.Set("type", "Desconhecido")
+	record.Set("type", domain.BusinessTypeUnknown)
 	record.Set("city", "-")
 
 	if err := deps.App.Save(record); err != nil {
 		deps.Logger.Error("whatsapp: failed to create placeholder business", "phone", phone, "error", err)
-		return "", "", ""
+		return "", "", "", false
 	}
 
 	deps.Logger.Info("whatsapp: created placeholder business", "phone", phone, "name", name)
-	return record.Id, "", "Desconhecido"
+	return record.Id, "", domain.BusinessTypeUnknown, true
 }
 
 // extractAndSaveSignal checks whether the message content contains profile-relevant
 // information and saves a profile_suggestions row when it does. Runs in a goroutine.
-func extractAndSaveSignal(deps HandlerDeps, businessID, businessType, content string) {
+func extractAndSaveSignal(deps HandlerDeps, businessID, businessType, text string) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	signal, err := deps.ExtractSignal(ctx, content, businessType)
+	signal, err := deps.ExtractSignal(ctx, text, businessType)
 	if err != nil {
 		deps.Logger.Warn("whatsapp: profile signal extraction failed", "error", err)
 		return
@@ -77,20 +79,55 @@ func extractAndSaveSignal(deps HandlerDeps, businessID, businessType, content st
 		return
 	}
 
-	collection, err := deps.App.FindCachedCollectionByNameOrId(domain.CollProfileSuggestions)
+	if _, err := SaveProfileSuggestions(deps.App, businessID, []content.ProfileSignal{*signal}); err != nil {
+		deps.Logger.Error("whatsapp: failed to save profile suggestion", "error", err)
+	}
+}
+
+// extractAndSaveDocumentProfile runs profile extraction over the text of a
+// document (price list, menu) and saves every field it finds as a
+// profile_suggestions row. Runs in a goroutine.
+func extractAndSaveDocumentProfile(deps HandlerDeps, businessID, businessType, text string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	profile, err := deps.ExtractProfile(ctx, text, businessType)
 	if err != nil {
-		deps.Logger.Error("whatsapp: profile_suggestions collection not found", "error", err)
+		deps.Logger.Warn("whatsapp: document profile extraction failed", "error", err)
 		return
 	}
 
-	record := core.NewRecord(collection)
-	record.Set("business", businessID)
-	record.Set("field", signal.Field)
-	record.Set("suggestion", signal.Value)
+	n, err := SaveProfileSuggestions(deps.App, businessID, profile.Signals())
+	if err != nil {
+		deps.Logger.Error("whatsapp: failed to save document suggestions", "error", err)
+		return
+	}
+	deps.Logger.Info("whatsapp: document suggestions saved", "business", businessID, "count", n)
+}
 
-	if err := deps.App.Save(record); err != nil {
-		deps.Logger.Error("whatsapp: failed to save profile suggestion", "error", err)
+// SaveProfileSuggestions stores each signal as a profile_suggestions row for
+// the operator to review. Returns how many rows were saved.
+func SaveProfileSuggestions(app core.App, businessID string, signals []content.ProfileSignal) (int, error) {
+	if len(signals) == 0 {
+		return 0, nil
+	}
+	collection, err := app.FindCachedCollectionByNameOrId(domain.CollProfileSuggestions)
+	if err != nil {
+		return 0, err
+	}
+
+	saved := 0
+	for _, signal := range signals {
+		record := core.NewRecord(collection)
+		record.Set("business", businessID)
+		record.Set("field", signal.Field)
+		record.Set("suggestion", signal.Value)
+		if err := app.Save(record); err != nil {
+			return saved, err
+		}
+		saved++
 	}
+	return saved, nil
 }
 
 // refreshProfilePicture fetches the WhatsApp profile picture for jid and stores
@@ -10,6 +10,7 @@ import (
 
 	content "github.com/denisraison/rekan/api/internal/content"
 	"github.com/denisraison/rekan/api/internal/domain"
+	"github.com/denisraison/rekan/api/internal/prefs"
 	"github.com/denisraison/rekan/api/internal/transcribe"
 	"github.com/pocketbase/pocketbase/core"
 )
@@ -18,6 +19,10 @@ import (
 // Set by the agent package to break the import cycle.
 type GroupMessageHandler func(evt *events.Message)
 
+// ClientMessageHandler is called for each text or transcribed audio an active
+// client sends directly. Set by the agent package to break the import cycle.
+type ClientMessageHandler func(businessID, phone, messageID, text string)
+
 // HandlerDeps holds dependencies for the message event handler.
 type HandlerDeps struct {
 	Client            *Client
@@ -25,25 +30,60 @@ type HandlerDeps struct {
 	Logger            *slog.Logger
 	Transcribe        *transcribe.Client         // nil if GEMINI_API_KEY not set
 	ExtractSignal     content.ExtractSignalFunc   // nil if GEMINI_API_KEY not set
+	ExtractProfile    content.ExtractProfileFunc  // nil if GEMINI_API_KEY not set
+	ClassifyReply     content.ClassifyReplyFunc   // nil if GEMINI_API_KEY not set
 	HandleGroupMsg    GroupMessageHandler         // nil if agent not configured
+	HandleClientMsg   ClientMessageHandler        // nil if DM assistant not configured
 	AgentGroupJID     string                      // filter to this group; empty means all groups
+	OnboardLeads      bool                        // ask unknown numbers for their profile over WhatsApp
+	Inbound           *Inbound                    // nil processes events inline on the whatsmeow event loop
 }
 
-// RegisterMessageHandler wires incoming WhatsApp messages to PocketBase storage.
+// RegisterMessageHandler wires incoming WhatsApp messages to PocketBase storage
+// and delivery receipts to the status of the messages we sent.
 func RegisterMessageHandler(deps HandlerDeps) {
 	deps.Client.AddEventHandler(func(evt any) {
-		v, ok := evt.(*events.Message)
-		if !ok {
-			return
-		}
-		if v.Info.IsGroup {
-			handleGroupMessage(deps, v)
-		} else {
-			handleDirectMessage(deps, v)
+		switch v := evt.(type) {
+		case *events.Message:
+			deps.submit(v.Info.Chat.String(), func() {
+				if v.Info.IsGroup {
+					handleGroupMessage(deps, v)
+				} else {
+					handleDirectMessage(deps, v)
+				}
+			})
+		case *events.Receipt:
+			deps.submit(v.Chat.String(), func() { handleReceipt(deps, v) })
 		}
 	})
 }
 
+// submit runs fn on the inbound event pool, queued behind earlier events of
+// the same chat, or right away when there is no pool.
+func (deps HandlerDeps) submit(chat string, fn func()) {
+	if deps.Inbound == nil {
+		fn()
+		return
+	}
+	deps.Inbound.Events.Submit(chat, fn)
+}
+
+// directMessage is a saved direct message and what the steps after saving
+// need to know about its sender.
+type directMessage struct {
+	evt          *events.Message
+	record       *core.Record // nil if saving failed
+	sender       resolvedMessage
+	businessID   string
+	inviteStatus string
+	businessType string
+	created      bool // the business was created for this message
+}
+
+func (m directMessage) chat() string {
+	return m.evt.Info.Chat.String()
+}
+
 func handleDirectMessage(deps HandlerDeps, evt *events.Message) {
 	if evt.Message == nil {
 		return
@@ -62,25 +102,122 @@ func handleDirectMessage(deps HandlerDeps, evt *events.Message) {
 		return
 	}
 
-	parsed, ok := extractContent(ctx, deps, evt)
-	if !ok {
+	msgType := messageType(evt.Message)
+	if msgType == "" {
 		return
 	}
 
-	businessID, inviteStatus, businessType := findOrCreateBusiness(deps, resolved.phone, resolved.pushName)
+	businessID, inviteStatus, businessType, created := findOrCreateBusiness(deps, resolved.phone, resolved.pushName)
 
 	if resolved.direction == domain.DirectionIncoming && deps.Client != nil {
 		senderJID := types.JID{User: resolved.phone, Server: "s.whatsapp.net"}
 		go refreshProfilePicture(deps, businessID, senderJID)
 	}
 
-	saveMessageRecord(deps, businessID, resolved.phone, resolved.direction, parsed.msgType, parsed.content, evt.Info.Timestamp, waMessageID, parsed.mediaFile)
+	m := directMessage{
+		evt:          evt,
+		sender:       resolved,
+		businessID:   businessID,
+		inviteStatus: inviteStatus,
+		businessType: businessType,
+		created:      created,
+	}
+
+	// Media takes seconds to download and describe. With an inbound pool the
+	// message is stored as pending now and finished on the media workers.
+	if msgType != domain.MsgTypeText && deps.Inbound != nil {
+		m.record = newMessageRecord(deps, businessID, resolved.phone, resolved.direction, msgType, "", evt.Info.Timestamp, waMessageID, nil)
+		if m.record == nil {
+			return
+		}
+		m.record.Set("media_status", domain.MediaStatusPending)
+		if err := deps.App.Save(m.record); err != nil {
+			deps.Logger.Error("whatsapp: failed to save message", "error", err)
+			return
+		}
+		deps.Inbound.submitMedia(deps, m)
+		return
+	}
+
+	parsed, _ := extractContent(ctx, deps, evt)
+	m.record = saveMessageRecord(deps, businessID, resolved.phone, resolved.direction, parsed.msgType, parsed.content, evt.Info.Timestamp, waMessageID, parsed.mediaFile)
+	if deps.Inbound != nil {
+		deps.Inbound.afterSaved(deps, m, parsed)
+		return
+	}
+	afterDirectMessage(deps, m, parsed)
+}
 
-	if resolved.direction == domain.DirectionIncoming && len(parsed.content) >= 20 && businessID != "" && deps.ExtractSignal != nil && inviteStatus == domain.InviteStatusActive {
+// afterDirectMessage runs the steps that need a message's text: linking
+// replies to posts, onboarding, the DM assistant, profile signals and the
+// media library.
+func afterDirectMessage(deps HandlerDeps, m directMessage, parsed parsedContent) {
+	evt := m.evt
+	businessID, inviteStatus, businessType := m.businessID, m.inviteStatus, m.businessType
+	resolved := m.sender
+
+	// Opt-out and opt-in requests ("SAIR", "não quero mais receber
+	// mensagens") change the client's communication preferences and go
+	// nowhere else: not to the approval loop, onboarding or the assistant.
+	intent := prefs.IntentNone
+	if resolved.direction == domain.DirectionIncoming && businessID != "" && isReplyType(parsed.msgType) {
+		intent = prefs.Detect(parsed.content)
+	}
+	if intent != prefs.IntentNone {
+		messageID := ""
+		if m.record != nil {
+			messageID = m.record.Id
+		}
+		applyPreferenceIntent(deps, businessID, resolved.phone, messageID, parsed.content, intent, inviteStatus == domain.InviteStatusActive)
+	}
+	asks := intent == prefs.IntentNone
+
+	// A reply to a post that approves it or asks for a change belongs to the
+	// approval loop. Anything else, a question about the post included, still
+	// needs an answer.
+	var repliedPost, replyKind string
+	if m.record != nil && resolved.direction == domain.DirectionIncoming && businessID != "" && isReplyType(parsed.msgType) {
+		repliedPost = linkClientReply(deps, m.record, evt)
+		if asks && repliedPost != "" && parsed.content != "" && deps.ClassifyReply != nil {
+			replyKind = classifyClientReply(deps, repliedPost, parsed.content, evt.Info.Timestamp)
+		}
+	}
+
+	// Unknown numbers are walked through the onboarding questions until
+	// their profile is a complete draft. Answers are applied here, on the
+	// chat's worker, so two quick answers land on their own questions.
+	if deps.OnboardLeads && resolved.direction == domain.DirectionIncoming && businessID != "" && inviteStatus == "" {
+		if m.created {
+			startOnboarding(deps, businessID, resolved.phone, resolved.pushName)
+		} else if asks && isReplyType(parsed.msgType) && parsed.content != "" {
+			advanceOnboarding(deps, businessID, resolved.phone, parsed.content)
+		}
+	}
+
+	incomingActive := resolved.direction == domain.DirectionIncoming && businessID != "" && inviteStatus == domain.InviteStatusActive
+
+	// Everything else an active client says goes to the DM assistant. A
+	// question about a post nobody can answer there goes to the operators.
+	if incomingActive && asks && !settlesPost(replyKind) && isReplyType(parsed.msgType) && parsed.content != "" && deps.HandleClientMsg != nil {
+		deps.HandleClientMsg(businessID, resolved.phone, evt.Info.ID, parsed.content)
+	} else if replyKind == content.ReplyQuestion {
+		notifyOperatorsAsync(deps, questionTaskText(deps.App, repliedPost, resolved.phone, parsed.content))
+	}
+	if parsed.msgType == domain.MsgTypeDocument {
+		// Documents are usually price lists or menus: extract the whole profile, not a single signal.
+		if incomingActive && parsed.content != "" && deps.ExtractProfile != nil {
+			go extractAndSaveDocumentProfile(deps, businessID, businessType, parsed.content)
+		}
+	} else if incomingActive && asks && len(parsed.content) >= 20 && deps.ExtractSignal != nil {
 		go extractAndSaveSignal(deps, businessID, businessType, parsed.content)
 	}
 
+	if m.record != nil && resolved.direction == domain.DirectionIncoming && businessID != "" && parsed.mediaFile != nil &&
+		(parsed.msgType == domain.MsgTypeImage || parsed.msgType == domain.MsgTypeVideo) {
+		addToLibrary(deps, m, parsed)
+	}
+
 	if parsed.extraCaption != "" {
-		saveMessageRecord(deps, businessID, resolved.phone, resolved.direction, domain.MsgTypeText, parsed.extraCaption, evt.Info.Timestamp, waMessageID+"_caption", nil)
+		saveMessageRecord(deps, businessID, resolved.phone, resolved.direction, domain.MsgTypeText, parsed.extraCaption, evt.Info.Timestamp, evt.Info.ID+"_caption", nil)
 	}
 }
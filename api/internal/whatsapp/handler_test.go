@@ -30,6 +30,12 @@ func newHandlerTestApp(t *testing.T) *tests.TestApp {
 		&core.JSONField{Name: "services"},
 		&core.TextField{Name: "user"},
 		&core.TextField{Name: "phone"},
+		&core.TextField{Name: "invite_status"},
+		&core.TextField{Name: "brand_vibe"},
+		&core.TextField{Name: "target_audience"},
+		&core.TextField{Name: "quirks"},
+		&core.NumberField{Name: "onboarding_step"},
+		&core.JSONField{Name: "collected_fields"},
 	)
 	if err := app.Save(businesses); err != nil {
 		t.Fatalf("save businesses collection: %v", err)
@@ -44,11 +50,56 @@ func newHandlerTestApp(t *testing.T) *tests.TestApp {
 		&core.SelectField{Name: "direction", Values: []string{"incoming", "outgoing"}, Required: true, MaxSelect: 1},
 		&core.DateField{Name: "wa_timestamp"},
 		&core.TextField{Name: "wa_message_id"},
+		&core.SelectField{Name: "status", Values: []string{"sent", "delivered", "read"}, MaxSelect: 1},
+		&core.DateField{Name: "delivered_at"},
+		&core.DateField{Name: "read_at"},
+		&core.TextField{Name: "post"},
+		&core.DateField{Name: "sent_at"},
+		&core.SelectField{Name: "media_status", Values: []string{"pending", "done", "failed"}, MaxSelect: 1},
 	)
 	if err := app.Save(messages); err != nil {
 		t.Fatalf("save messages collection: %v", err)
 	}
 
+	posts := core.NewBaseCollection("posts")
+	posts.Fields.Add(
+		&core.TextField{Name: "business"},
+		&core.TextField{Name: "caption"},
+		&core.BoolField{Name: "reviewed"},
+		&core.SelectField{Name: "client_reply", Values: []string{"approved", "change_request", "question"}, MaxSelect: 1},
+		&core.TextField{Name: "client_feedback"},
+		&core.DateField{Name: "client_replied_at"},
+	)
+	if err := app.Save(posts); err != nil {
+		t.Fatalf("save posts collection: %v", err)
+	}
+
+	preferences := core.NewBaseCollection("communication_preferences")
+	preferences.Fields.Add(
+		&core.TextField{Name: "business"},
+		&core.BoolField{Name: "marketing"},
+		&core.BoolField{Name: "seasonal"},
+		&core.BoolField{Name: "operational"},
+	)
+	if err := app.Save(preferences); err != nil {
+		t.Fatalf("save communication_preferences collection: %v", err)
+	}
+
+	changes := core.NewBaseCollection("preference_changes")
+	changes.Fields.Add(
+		&core.TextField{Name: "business"},
+		&core.TextField{Name: "category"},
+		&core.BoolField{Name: "allowed"},
+		&core.TextField{Name: "source"},
+		&core.TextField{Name: "message"},
+		&core.TextField{Name: "text"},
+		&core.TextField{Name: "user"},
+		&core.DateField{Name: "changed_at"},
+	)
+	if err := app.Save(changes); err != nil {
+		t.Fatalf("save preference_changes collection: %v", err)
+	}
+
 	return app
 }
 
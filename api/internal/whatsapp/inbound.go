@@ -0,0 +1,185 @@
+package whatsapp
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.mau.fi/whatsmeow/types/events"
+
+	"github.com/denisraison/rekan/api/internal/domain"
+)
+
+// Inbound moves incoming WhatsApp events off the whatsmeow event loop, which
+// waits for each handler to return before delivering the next event. Events
+// run on a pool keyed by chat so one chat's messages keep their order while
+// other chats proceed. Media (downloads, transcription, descriptions) runs
+// on a second pool so a slow video never holds up text, and is retried with
+// backoff when it fails. What happens after a message is saved (the
+// assistant, onboarding, opt-outs, signals) still follows the chat's order:
+// a text sent after a voice note waits for the note's transcript.
+type Inbound struct {
+	Events *Pool
+	Media  *Pool
+
+	MediaAttempts int           // tries per media message, including the first
+	MediaBackoff  time.Duration // wait before the first retry, doubled after each
+
+	// process does the media work; tests swap it for a stub.
+	process func(ctx context.Context, deps HandlerDeps, evt *events.Message, msgType string) (parsedContent, error)
+
+	mediaRetries atomic.Int64
+	mediaFailed  atomic.Int64
+
+	order chatOrder
+}
+
+// InboundStats is a snapshot of the inbound pools, served by the stats endpoint.
+type InboundStats struct {
+	Events       PoolStats `json:"events"`
+	Media        PoolStats `json:"media"`
+	MediaRetries int64     `json:"media_retries"`
+	MediaFailed  int64     `json:"media_failed"`
+}
+
+// NewInbound starts the event and media pools with production sizes.
+func NewInbound(logger *slog.Logger) *Inbound {
+	return &Inbound{
+		Events:        NewPool("events", 8, 64, logger),
+		Media:         NewPool("media", 4, 32, logger),
+		MediaAttempts: 3,
+		MediaBackoff:  10 * time.Second,
+		process:       processMedia,
+	}
+}
+
+// Stats returns the current counters of both pools.
+func (in *Inbound) Stats() InboundStats {
+	return InboundStats{
+		Events:       in.Events.Stats(),
+		Media:        in.Media.Stats(),
+		MediaRetries: in.mediaRetries.Load(),
+		MediaFailed:  in.mediaFailed.Load(),
+	}
+}
+
+// Close drains the event pool, then the media pool it feeds. Retries still
+// waiting on their backoff are dropped, and so are the follow-ups of later
+// messages in their chats.
+func (in *Inbound) Close() {
+	in.Events.Close()
+	in.Media.Close()
+}
+
+// afterSaved runs the follow-up steps of a text message once every earlier
+// message in its chat has run theirs.
+func (in *Inbound) afterSaved(deps HandlerDeps, m directMessage, parsed parsedContent) {
+	in.order.reserve(m.chat())(func() { afterDirectMessage(deps, m, parsed) })
+}
+
+// submitMedia queues a pending media message on the media pool, holding the
+// follow-up steps of later messages in the chat until it is done.
+func (in *Inbound) submitMedia(deps HandlerDeps, m directMessage) {
+	release := in.order.reserve(m.chat())
+	in.submitMediaAttempt(deps, m, release, 1)
+}
+
+func (in *Inbound) submitMediaAttempt(deps HandlerDeps, m directMessage, release func(func()), attempt int) {
+	in.Media.Submit(m.chat(), func() { in.processMedia(deps, m, release, attempt) })
+}
+
+// processMedia fills in a message saved as pending and runs the follow-up
+// steps a direct message gets. A failed attempt is queued again after the
+// backoff; the last one settles for whatever it got, usually the caption.
+func (in *Inbound) processMedia(deps HandlerDeps, m directMessage, release func(func()), attempt int) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	parsed, err := in.process(ctx, deps, m.evt, m.record.GetString("type"))
+	if err != nil && attempt < in.MediaAttempts {
+		in.mediaRetries.Add(1)
+		delay := in.MediaBackoff << (attempt - 1)
+		deps.Logger.Warn("whatsapp: media processing failed, retrying", "wa_message_id", m.evt.Info.ID, "attempt", attempt, "retry_in", delay, "error", err)
+		time.AfterFunc(delay, func() { in.submitMediaAttempt(deps, m, release, attempt+1) })
+		return
+	}
+
+	status := domain.MediaStatusDone
+	if err != nil {
+		in.mediaFailed.Add(1)
+		status = domain.MediaStatusFailed
+		deps.Logger.Error("whatsapp: media processing gave up", "wa_message_id", m.evt.Info.ID, "attempts", attempt, "error", err)
+	}
+	m.record.Set("content", parsed.content)
+	if parsed.mediaFile != nil {
+		m.record.Set("media", parsed.mediaFile)
+	}
+	m.record.Set("media_status", status)
+	if err := deps.App.Save(m.record); err != nil {
+		deps.Logger.Error("whatsapp: failed to save processed media", "wa_message_id", m.evt.Info.ID, "error", err)
+		release(func() {})
+		return
+	}
+
+	release(func() { afterDirectMessage(deps, m, parsed) })
+}
+
+// chatOrder runs each chat's follow-up steps in the order their messages
+// arrived, whichever goroutine finishes a message first. The zero value is
+// ready to use.
+type chatOrder struct {
+	mu    sync.Mutex
+	chats map[string]*chatQueue
+}
+
+type chatQueue struct {
+	slots   []*orderSlot
+	running bool // a caller is running the ready slots at the head
+}
+
+type orderSlot struct {
+	fn func() // nil until released
+}
+
+// reserve takes the next place in chat's line and returns the function that
+// fills it. fn runs once every earlier place has run, on the goroutine that
+// released the last of them; later places that are already filled run right
+// after it.
+func (o *chatOrder) reserve(chat string) func(fn func()) {
+	o.mu.Lock()
+	if o.chats == nil {
+		o.chats = map[string]*chatQueue{}
+	}
+	q := o.chats[chat]
+	if q == nil {
+		q = &chatQueue{}
+		o.chats[chat] = q
+	}
+	slot := &orderSlot{}
+	q.slots = append(q.slots, slot)
+	o.mu.Unlock()
+
+	return func(fn func()) {
+		o.mu.Lock()
+		slot.fn = fn
+		if q.running {
+			o.mu.Unlock()
+			return
+		}
+		q.running = true
+		for len(q.slots) > 0 && q.slots[0].fn != nil {
+			next := q.slots[0].fn
+			q.slots = q.slots[1:]
+			o.mu.Unlock()
+			next()
+			o.mu.Lock()
+		}
+		q.running = false
+		if len(q.slots) == 0 {
+			delete(o.chats, chat)
+		}
+		o.mu.Unlock()
+	}
+}
@@ -0,0 +1,157 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types/events"
+
+	"github.com/denisraison/rekan/api/internal/domain"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// seedActiveBusiness saves a client whose messages reach the DM assistant.
+func seedActiveBusiness(t *testing.T, app core.App, phone string) {
+	t.Helper()
+	businesses, err := app.FindCollectionByNameOrId(domain.CollBusinesses)
+	if err != nil {
+		t.Fatal(err)
+	}
+	biz := core.NewRecord(businesses)
+	biz.Set("name", "Padaria da Ana")
+	biz.Set("phone", phone)
+	biz.Set("invite_status", domain.InviteStatusActive)
+	if err := app.Save(biz); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func incomingAudioEvt(msgID, phone string) *events.Message {
+	evt := incomingTextEvt(msgID, phone)
+	evt.Message = &waE2E.Message{AudioMessage: &waE2E.AudioMessage{}}
+	return evt
+}
+
+// testInbound returns small pools whose media step fails failures times
+// before transcribing to text.
+func testInbound(t *testing.T, failures int32, text string) (*Inbound, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	in := &Inbound{
+		Events:        NewPool("events", 2, 4, discardLogger()),
+		Media:         NewPool("media", 2, 4, discardLogger()),
+		MediaAttempts: 3,
+		MediaBackoff:  time.Millisecond,
+		process: func(_ context.Context, _ HandlerDeps, _ *events.Message, msgType string) (parsedContent, error) {
+			if calls.Add(1) <= failures {
+				return parsedContent{msgType: msgType}, errors.New("download failed")
+			}
+			return parsedContent{msgType: msgType, content: text}, nil
+		},
+	}
+	t.Cleanup(in.Close)
+	return in, &calls
+}
+
+func waitMediaStatus(t *testing.T, deps HandlerDeps, waID, want string) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		rec, err := deps.App.FindFirstRecordByFilter(domain.CollMessages, "wa_message_id = {:id}", map[string]any{"id": waID})
+		if err == nil && rec.GetString("media_status") == want {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("%s never reached media_status %q", waID, want)
+}
+
+// TestInboundMediaRetriedUntilDone verifies that media is saved as pending,
+// retried after a failure and then filled in and handed downstream.
+func TestInboundMediaRetriedUntilDone(t *testing.T) {
+	app := newHandlerTestApp(t)
+	deps := makeDeps(t, app)
+	in, calls := testInbound(t, 1, "oi, quero mudar o horário do post")
+	deps.Inbound = in
+
+	handled := make(chan string, 1)
+	seedActiveBusiness(t, app, "5511777770020")
+	deps.HandleClientMsg = func(_, _, _, text string) { handled <- text }
+
+	handleDirectMessage(deps, incomingAudioEvt("in-audio", "5511777770020"))
+	rec, err := app.FindFirstRecordByFilter(domain.CollMessages, "wa_message_id = 'in-audio'")
+	if err != nil {
+		t.Fatalf("media message not saved before processing: %v", err)
+	}
+	if got := rec.GetString("media_status"); got != domain.MediaStatusPending && got != domain.MediaStatusDone {
+		t.Fatalf("media_status = %q right after saving", got)
+	}
+
+	waitMediaStatus(t, deps, "in-audio", domain.MediaStatusDone)
+	rec, _ = app.FindFirstRecordByFilter(domain.CollMessages, "wa_message_id = 'in-audio'")
+	if rec.GetString("content") != "oi, quero mudar o horário do post" {
+		t.Errorf("content = %q", rec.GetString("content"))
+	}
+	if calls.Load() != 2 || in.Stats().MediaRetries != 1 {
+		t.Errorf("process ran %d times with %d retries, want 2 and 1", calls.Load(), in.Stats().MediaRetries)
+	}
+	select {
+	case text := <-handled:
+		if text != "oi, quero mudar o horário do post" {
+			t.Errorf("assistant got %q", text)
+		}
+	case <-time.After(time.Second):
+		t.Error("transcript never reached the DM assistant")
+	}
+}
+
+// TestInboundMediaGivesUp verifies that media failing every attempt is marked
+// failed and counted.
+func TestInboundMediaGivesUp(t *testing.T) {
+	app := newHandlerTestApp(t)
+	deps := makeDeps(t, app)
+	in, calls := testInbound(t, 10, "")
+	deps.Inbound = in
+
+	handleDirectMessage(deps, incomingAudioEvt("in-broken", "5511777770021"))
+
+	waitMediaStatus(t, deps, "in-broken", domain.MediaStatusFailed)
+	if calls.Load() != 3 || in.Stats().MediaFailed != 1 {
+		t.Errorf("process ran %d times, failed %d, want 3 and 1", calls.Load(), in.Stats().MediaFailed)
+	}
+}
+
+// TestInboundTextWaitsForEarlierMedia verifies that a text sent after a voice
+// note reaches the assistant after the note's transcript, even when the
+// note needed a retry.
+func TestInboundTextWaitsForEarlierMedia(t *testing.T) {
+	app := newHandlerTestApp(t)
+	deps := makeDeps(t, app)
+	in, _ := testInbound(t, 1, "quero mudar o horário")
+	in.MediaBackoff = 50 * time.Millisecond
+	deps.Inbound = in
+
+	handled := make(chan string, 2)
+	seedActiveBusiness(t, app, "5511777770022")
+	deps.HandleClientMsg = func(_, _, _, text string) { handled <- text }
+
+	handleDirectMessage(deps, incomingAudioEvt("in-note", "5511777770022"))
+	handleDirectMessage(deps, incomingTextEvt("in-after", "5511777770022"))
+
+	var got []string
+	for range 2 {
+		select {
+		case text := <-handled:
+			got = append(got, text)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("assistant got only %q", got)
+		}
+	}
+	if got[0] != "quero mudar o horário" {
+		t.Errorf("assistant got %q, want the transcript first", got)
+	}
+}
@@ -2,48 +2,59 @@ package whatsapp
 
 import (
 	"context"
+	"path/filepath"
 
 	"go.mau.fi/whatsmeow/types/events"
 
+	"github.com/denisraison/rekan/api/internal/document"
+	"github.com/denisraison/rekan/api/internal/library"
+	"github.com/denisraison/rekan/api/internal/transcribe"
 	"github.com/pocketbase/pocketbase/tools/filesystem"
 )
 
-func transcribeAudio(ctx context.Context, deps HandlerDeps, evt *events.Message) string {
+// The media helpers below return what they could get even when they fail.
+// The error is set only for failures a retry may fix (downloads and model
+// calls), so callers processing in the background know when to try again.
+
+func transcribeAudio(ctx context.Context, deps HandlerDeps, evt *events.Message) (string, error) {
 	if deps.Transcribe == nil {
 		deps.Logger.Warn("whatsapp: audio received but no transcription client configured")
-		return ""
+		return "", nil
 	}
 
 	audio := evt.Message.GetAudioMessage()
 	if audio == nil {
-		return ""
-	}
-
-	data, err := deps.Client.Download(ctx, audio)
-	if err != nil {
-		deps.Logger.Error("whatsapp: failed to download audio", "error", err)
-		return ""
+		return "", nil
 	}
 
-	text, err := deps.Transcribe.Transcribe(ctx, data, "audio/ogg")
-	if err != nil {
-		deps.Logger.Error("whatsapp: transcription failed", "error", err)
-		return ""
-	}
+	// A cached transcript skips the download too: audio is not stored.
+	key := transcribe.MediaKey("audio", audio.GetFileSHA256(), "")
+	return deps.Transcribe.Cache.Do(key, func() (string, error) {
+		data, err := deps.Client.Download(ctx, audio)
+		if err != nil {
+			deps.Logger.Error("whatsapp: failed to download audio", "error", err)
+			return "", err
+		}
 
-	return text
+		text, err := deps.Transcribe.Transcribe(ctx, data, "audio/ogg")
+		if err != nil {
+			deps.Logger.Error("whatsapp: transcription failed", "error", err)
+			return "", err
+		}
+		return text, nil
+	})
 }
 
-func processVideo(ctx context.Context, deps HandlerDeps, evt *events.Message) (description, caption string, file *filesystem.File) {
+func processVideo(ctx context.Context, deps HandlerDeps, evt *events.Message) (description, caption string, file *filesystem.File, err error) {
 	vid := evt.Message.GetVideoMessage()
 	if vid == nil {
-		return "", "", nil
+		return "", "", nil, nil
 	}
 
 	data, err := deps.Client.Download(ctx, vid)
 	if err != nil {
 		deps.Logger.Error("whatsapp: failed to download video", "error", err)
-		return "", "", nil
+		return "", "", nil, err
 	}
 
 	mimeType := vid.GetMimetype()
@@ -56,34 +67,37 @@ func processVideo(ctx context.Context, deps HandlerDeps, evt *events.Message) (d
 	f, err := filesystem.NewFileFromBytes(data, filename)
 	if err != nil {
 		deps.Logger.Error("whatsapp: failed to create file from bytes", "error", err)
-		return "", "", nil
+		return "", "", nil, nil
 	}
 
 	caption = vid.GetCaption()
 	description = caption // fallback if Gemini is unavailable
 
 	if deps.Transcribe != nil {
-		desc, err := deps.Transcribe.DescribeVideo(ctx, data, mimeType, caption)
-		if err != nil {
-			deps.Logger.Error("whatsapp: failed to describe video", "error", err)
-		} else {
-			description = desc
+		key := transcribe.MediaKey("video", vid.GetFileSHA256(), caption)
+		desc, descErr := deps.Transcribe.Cache.Do(key, func() (string, error) {
+			return deps.Transcribe.DescribeVideo(ctx, data, mimeType, caption)
+		})
+		if descErr != nil {
+			deps.Logger.Error("whatsapp: failed to describe video", "error", descErr)
+			return description, caption, f, descErr
 		}
+		description = desc
 	}
 
-	return description, caption, f
+	return description, caption, f, nil
 }
 
-func processImage(ctx context.Context, deps HandlerDeps, evt *events.Message) (description, caption string, file *filesystem.File) {
+func processImage(ctx context.Context, deps HandlerDeps, evt *events.Message) (description, caption string, file *filesystem.File, err error) {
 	img := evt.Message.GetImageMessage()
 	if img == nil {
-		return "", "", nil
+		return "", "", nil, nil
 	}
 
 	data, err := deps.Client.Download(ctx, img)
 	if err != nil {
 		deps.Logger.Error("whatsapp: failed to download image", "error", err)
-		return "", "", nil
+		return "", "", nil, err
 	}
 
 	mimeType := img.GetMimetype()
@@ -99,20 +113,80 @@ func processImage(ctx context.Context, deps HandlerDeps, evt *events.Message) (d
 	f, err := filesystem.NewFileFromBytes(data, filename)
 	if err != nil {
 		deps.Logger.Error("whatsapp: failed to create file from bytes", "error", err)
-		return "", "", nil
+		return "", "", nil, nil
 	}
 
 	caption = img.GetCaption()
 	description = caption // fallback if Gemini is unavailable
 
 	if deps.Transcribe != nil {
-		desc, err := deps.Transcribe.DescribeImage(ctx, data, mimeType, caption)
-		if err != nil {
-			deps.Logger.Error("whatsapp: failed to describe image", "error", err)
-		} else {
-			description = desc
+		key := transcribe.MediaKey("image", img.GetFileSHA256(), caption)
+		desc, descErr := deps.Transcribe.Cache.Do(key, func() (string, error) {
+			return deps.Transcribe.DescribeImage(ctx, data, mimeType, caption)
+		})
+		if descErr != nil {
+			deps.Logger.Error("whatsapp: failed to describe image", "error", descErr)
+			return description, caption, f, descErr
 		}
+		description = desc
+	}
+
+	return description, caption, f, nil
+}
+
+// processDocument downloads a PDF or DOCX and returns its extracted text.
+// Other document types are stored without text.
+func processDocument(ctx context.Context, deps HandlerDeps, evt *events.Message) (text, caption string, file *filesystem.File, err error) {
+	doc := evt.Message.GetDocumentMessage()
+	if doc == nil {
+		return "", "", nil, nil
 	}
+	caption = doc.GetCaption()
 
-	return description, caption, f
+	data, err := deps.Client.Download(ctx, doc)
+	if err != nil {
+		deps.Logger.Error("whatsapp: failed to download document", "error", err)
+		return "", caption, nil, err
+	}
+
+	name := doc.GetFileName()
+	ext := filepath.Ext(name)
+	if ext == "" {
+		ext = ".bin"
+	}
+	f, err := filesystem.NewFileFromBytes(data, evt.Info.ID+ext)
+	if err != nil {
+		deps.Logger.Error("whatsapp: failed to create file from bytes", "error", err)
+		return "", caption, nil, nil
+	}
+
+	if !document.Supported(doc.GetMimetype(), name) {
+		return "", caption, f, nil
+	}
+	// A file that cannot be parsed will not parse on a retry either.
+	text, extractErr := document.ExtractText(data, doc.GetMimetype(), name)
+	if extractErr != nil {
+		deps.Logger.Warn("whatsapp: failed to extract document text", "file", name, "error", extractErr)
+		return "", caption, f, nil
+	}
+	return text, caption, f, nil
+}
+
+// addToLibrary puts a client's photo or video in their media library once
+// its description is known, so posts can reuse it later.
+func addToLibrary(deps HandlerDeps, m directMessage, parsed parsedContent) {
+	p := library.AddParams{
+		BusinessID:  m.businessID,
+		MessageID:   m.record.Id,
+		Type:        parsed.msgType,
+		Description: parsed.content,
+	}
+	if img := m.evt.Message.GetImageMessage(); img != nil {
+		p.Width, p.Height = int(img.GetWidth()), int(img.GetHeight())
+	} else if vid := m.evt.Message.GetVideoMessage(); vid != nil {
+		p.Width, p.Height, p.Seconds = int(vid.GetWidth()), int(vid.GetHeight()), int(vid.GetSeconds())
+	}
+	if _, err := library.Add(deps.App, p); err != nil {
+		deps.Logger.Warn("whatsapp: failed to add media to library", "wa_message_id", m.evt.Info.ID, "error", err)
+	}
 }
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"time"
 
+	"go.mau.fi/whatsmeow/proto/waE2E"
 	"go.mau.fi/whatsmeow/types/events"
 
 	"github.com/denisraison/rekan/api/internal/domain"
@@ -48,35 +49,62 @@ type parsedContent struct {
 	mediaFile    *filesystem.File
 }
 
-// extractContent extracts the message type, text content, and optional media from an event.
-// Returns false if the message type is unsupported.
-func extractContent(ctx context.Context, deps HandlerDeps, evt *events.Message) (parsedContent, bool) {
-	msg := evt.Message
-	var p parsedContent
-
+// messageType returns the stored type of a message, or "" when the type is
+// unsupported.
+func messageType(msg *waE2E.Message) string {
 	switch {
-	case msg.GetConversation() != "":
-		p.msgType = domain.MsgTypeText
-		p.content = msg.GetConversation()
-	case msg.GetExtendedTextMessage() != nil:
-		p.msgType = domain.MsgTypeText
-		p.content = msg.GetExtendedTextMessage().GetText()
+	case msg.GetConversation() != "", msg.GetExtendedTextMessage() != nil:
+		return domain.MsgTypeText
 	case msg.GetAudioMessage() != nil:
-		p.msgType = domain.MsgTypeAudio
-		p.content = transcribeAudio(ctx, deps, evt)
+		return domain.MsgTypeAudio
 	case msg.GetImageMessage() != nil:
-		p.msgType = domain.MsgTypeImage
-		p.content, p.extraCaption, p.mediaFile = processImage(ctx, deps, evt)
+		return domain.MsgTypeImage
 	case msg.GetVideoMessage() != nil:
-		p.msgType = domain.MsgTypeVideo
-		p.content, p.extraCaption, p.mediaFile = processVideo(ctx, deps, evt)
-	default:
-		return parsedContent{}, false
+		return domain.MsgTypeVideo
+	case msg.GetDocumentMessage() != nil:
+		return domain.MsgTypeDocument
 	}
+	return ""
+}
 
+// extractContent extracts the message type, text content, and optional media from an event.
+// Returns false if the message type is unsupported. Media errors are logged and
+// whatever could be extracted is kept.
+func extractContent(ctx context.Context, deps HandlerDeps, evt *events.Message) (parsedContent, bool) {
+	msgType := messageType(evt.Message)
+	switch msgType {
+	case "":
+		return parsedContent{}, false
+	case domain.MsgTypeText:
+		text := evt.Message.GetConversation()
+		if text == "" {
+			text = evt.Message.GetExtendedTextMessage().GetText()
+		}
+		return parsedContent{msgType: msgType, content: text}, true
+	}
+	p, _ := processMedia(ctx, deps, evt, msgType)
 	return p, true
 }
 
+// processMedia downloads the media of a message and turns it into text:
+// transcribed audio, described images and videos, extracted documents.
+// The error is set when a retry may succeed.
+func processMedia(ctx context.Context, deps HandlerDeps, evt *events.Message, msgType string) (parsedContent, error) {
+	p := parsedContent{msgType: msgType}
+	var err error
+	switch msgType {
+	case domain.MsgTypeAudio:
+		p.content, err = transcribeAudio(ctx, deps, evt)
+	case domain.MsgTypeImage:
+		p.content, p.extraCaption, p.mediaFile, err = processImage(ctx, deps, evt)
+	case domain.MsgTypeVideo:
+		p.content, p.extraCaption, p.mediaFile, err = processVideo(ctx, deps, evt)
+	case domain.MsgTypeDocument:
+		p.content, p.extraCaption, p.mediaFile, err = processDocument(ctx, deps, evt)
+	}
+	return p, err
+}
+
 // isDuplicate returns true if a message with the given wa_message_id already exists.
 func isDuplicate(deps HandlerDeps, waMessageID string) bool {
 	existing, err := deps.App.FindFirstRecordByFilter(domain.CollMessages, "wa_message_id = {:id}", map[string]any{"id": waMessageID})
@@ -88,6 +116,21 @@ func isDuplicate(deps HandlerDeps, waMessageID string) bool {
 
 // saveMessageRecord creates and saves a message record. Returns the saved record or nil on error.
 func saveMessageRecord(deps HandlerDeps, businessID, phone, direction, msgType, content string, waTimestamp time.Time, waMessageID string, mediaFile *filesystem.File) *core.Record {
+	record := newMessageRecord(deps, businessID, phone, direction, msgType, content, waTimestamp, waMessageID, mediaFile)
+	if record == nil {
+		return nil
+	}
+	if err := deps.App.Save(record); err != nil {
+		deps.Logger.Error("whatsapp: failed to save message", "error", err)
+		return nil
+	}
+
+	return record
+}
+
+// newMessageRecord builds an unsaved message record. Returns nil if the
+// messages collection is missing.
+func newMessageRecord(deps HandlerDeps, businessID, phone, direction, msgType, content string, waTimestamp time.Time, waMessageID string, mediaFile *filesystem.File) *core.Record {
 	collection, err := deps.App.FindCachedCollectionByNameOrId(domain.CollMessages)
 	if err != nil {
 		deps.Logger.Error("whatsapp: messages collection not found", "error", err)
@@ -109,10 +152,5 @@ func saveMessageRecord(deps HandlerDeps, businessID, phone, direction, msgType,
 		record.Set("media", mediaFile)
 	}
 
-	if err := deps.App.Save(record); err != nil {
-		deps.Logger.Error("whatsapp: failed to save message", "error", err)
-		return nil
-	}
-
 	return record
 }
@@ -0,0 +1,325 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+
+	content "github.com/denisraison/rekan/api/internal/content"
+	"github.com/denisraison/rekan/api/internal/domain"
+)
+
+// Onboarding steps, stored in businesses.onboarding_step. Each step except
+// the first and last means that question was asked and its answer is due.
+const (
+	OnboardingNone     = 0 // not onboarding over WhatsApp
+	OnboardingName     = 1
+	OnboardingType     = 2
+	OnboardingCity     = 3
+	OnboardingServices = 4
+	OnboardingVibe     = 5
+	OnboardingDone     = 6 // draft profile complete, waiting for an invite
+)
+
+// onboardingStep is one question of the onboarding conversation. field is
+// the collected_fields key the raw answer is kept under; apply returns the
+// business fields the answer sets, reading but not changing business.
+type onboardingStep struct {
+	field    string
+	question string
+	apply    func(ctx context.Context, deps HandlerDeps, business *core.Record, answer string) map[string]any
+}
+
+var onboardingSteps = map[int]onboardingStep{
+	OnboardingName: {
+		field:    "name",
+		question: "Qual o nome do seu negócio?",
+		apply: func(_ context.Context, _ HandlerDeps, _ *core.Record, answer string) map[string]any {
+			return map[string]any{"name": clip(answer, 80)}
+		},
+	},
+	OnboardingType: {
+		field:    "type",
+		question: "E o que vocês fazem? Salão, confeitaria, manicure, loja de roupa...",
+		apply: func(_ context.Context, _ HandlerDeps, _ *core.Record, answer string) map[string]any {
+			return map[string]any{"type": clip(answer, 60)}
+		},
+	},
+	OnboardingCity: {
+		field:    "city",
+		question: "Em que cidade você atende?",
+		apply: func(_ context.Context, _ HandlerDeps, _ *core.Record, answer string) map[string]any {
+			city, state := splitCityState(answer)
+			fields := map[string]any{"city": clip(city, 80)}
+			if state != "" {
+				fields["state"] = state
+			}
+			return fields
+		},
+	},
+	OnboardingServices: {
+		field:    "services",
+		question: "Quais serviços ou produtos você oferece? Se puder, fala o preço também. Pode mandar áudio!",
+		apply:    applyServices,
+	},
+	OnboardingVibe: {
+		field:    "vibe",
+		question: "Pra fechar: como você quer que seu Instagram soe (divertido, elegante, acolhedor...) e quem são seus clientes?",
+		apply:    applyVibe,
+	},
+}
+
+// onboardingLocks serialises onboarding answers per business so two
+// messages from a lead cannot answer the same question twice, even outside
+// the chat's ordered worker. Only the read-check-save of the step is locked;
+// extraction and sending are not, so a slow model call never holds up other
+// leads.
+var onboardingLocks = newKeyedMutex()
+
+// startOnboarding greets a new number and asks the first question.
+func startOnboarding(deps HandlerDeps, businessID, phone, pushName string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	unlock := onboardingLocks.Lock(businessID)
+	business, err := deps.App.FindRecordById(domain.CollBusinesses, businessID)
+	if err != nil || business.GetInt("onboarding_step") != OnboardingNone {
+		unlock()
+		return
+	}
+	business.Set("onboarding_step", OnboardingName)
+	err = deps.App.Save(business)
+	unlock()
+	if err != nil {
+		deps.Logger.Error("whatsapp: failed to start onboarding", "business", businessID, "error", err)
+		return
+	}
+
+	greeting := "Oi! "
+	if pushName != "" {
+		greeting = "Oi, " + pushName + "! "
+	}
+	greeting += "Aqui é da Rekan, a gente cuida do Instagram de pequenos negócios. " +
+		"Vou te fazer umas perguntas rápidas pra montar seu perfil, pode responder por texto ou áudio.\n\n" +
+		onboardingSteps[OnboardingName].question
+	sendToLead(ctx, deps, businessID, phone, greeting)
+}
+
+// advanceOnboarding records answer for the open question and asks the next
+// one. After the last answer the business becomes a draft and the operator
+// group is told the lead is ready for an invite. It runs on the chat's
+// worker, so the lead's next message waits for this answer.
+//
+// The answer is applied to a snapshot of the business outside the lock and
+// committed only if the question is still open; when another message
+// answered it meanwhile, the answer is applied again to the next question.
+func advanceOnboarding(deps HandlerDeps, businessID, phone, answer string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	answer = strings.TrimSpace(answer)
+	for {
+		snapshot, err := deps.App.FindRecordById(domain.CollBusinesses, businessID)
+		if err != nil {
+			return
+		}
+		current := snapshot.GetInt("onboarding_step")
+		step, ok := onboardingSteps[current]
+		if !ok {
+			return
+		}
+		fields := step.apply(ctx, deps, snapshot, answer)
+
+		business, next, err := commitOnboardingAnswer(deps, businessID, current, step, answer, fields)
+		if err != nil {
+			deps.Logger.Error("whatsapp: failed to save onboarding answer", "business", businessID, "error", err)
+			return
+		}
+		if business == nil {
+			continue
+		}
+
+		if next == OnboardingDone {
+			sendToLead(ctx, deps, businessID, phone, "Prontinho, anotei tudo! Alguém da equipe vai te mandar o link pra ativar sua conta.")
+			notifyOperators(ctx, deps, leadReadyText(business))
+			return
+		}
+		sendToLead(ctx, deps, businessID, phone, onboardingSteps[next].question)
+		return
+	}
+}
+
+// commitOnboardingAnswer saves answer and fields and moves the business past
+// step current. It returns a nil record when the business is no longer at
+// that step.
+func commitOnboardingAnswer(deps HandlerDeps, businessID string, current int, step onboardingStep, answer string, fields map[string]any) (*core.Record, int, error) {
+	unlock := onboardingLocks.Lock(businessID)
+	defer unlock()
+
+	business, err := deps.App.FindRecordById(domain.CollBusinesses, businessID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if business.GetInt("onboarding_step") != current {
+		return nil, 0, nil
+	}
+
+	collected := map[string]string{}
+	if err := business.UnmarshalJSONField("collected_fields", &collected); err != nil || collected == nil {
+		collected = map[string]string{}
+	}
+	collected[step.field] = answer
+	business.Set("collected_fields", collected)
+	for k, v := range fields {
+		business.Set(k, v)
+	}
+
+	next := current + 1
+	business.Set("onboarding_step", next)
+	if next == OnboardingDone {
+		business.Set("invite_status", domain.InviteStatusDraft)
+	}
+	if err := deps.App.Save(business); err != nil {
+		return nil, 0, err
+	}
+	return business, next, nil
+}
+
+// applyServices turns the services answer into the services list. Without an
+// extractor, or when it finds nothing, the raw answer stays in collected_fields.
+func applyServices(ctx context.Context, deps HandlerDeps, business *core.Record, answer string) map[string]any {
+	profile, ok := extractOnboardingProfile(ctx, deps, business, answer)
+	if !ok || len(profile.Services) == 0 {
+		return nil
+	}
+	services := make([]map[string]any, len(profile.Services))
+	for i, s := range profile.Services {
+		price := 0.0
+		if s.PriceBRL != nil {
+			price = *s.PriceBRL
+		}
+		services[i] = map[string]any{"name": s.Name, "price_brl": price}
+	}
+	return map[string]any{"services": services}
+}
+
+// applyVibe fills brand vibe, audience and quirks from the last answer. The
+// raw answer becomes the vibe when nothing can be extracted.
+func applyVibe(ctx context.Context, deps HandlerDeps, business *core.Record, answer string) map[string]any {
+	profile, ok := extractOnboardingProfile(ctx, deps, business, answer)
+	if !ok || (profile.BrandVibe == nil && profile.TargetAudience == nil) {
+		return map[string]any{"brand_vibe": answer}
+	}
+	fields := map[string]any{}
+	if profile.BrandVibe != nil {
+		fields["brand_vibe"] = *profile.BrandVibe
+	}
+	if profile.TargetAudience != nil {
+		fields["target_audience"] = *profile.TargetAudience
+	}
+	if len(profile.Quirks) > 0 {
+		fields["quirks"] = strings.Join(profile.Quirks, "; ")
+	}
+	return fields
+}
+
+func extractOnboardingProfile(ctx context.Context, deps HandlerDeps, business *core.Record, answer string) (content.PartialBusinessProfile, bool) {
+	if deps.ExtractProfile == nil || answer == "" {
+		return content.PartialBusinessProfile{}, false
+	}
+	profile, err := deps.ExtractProfile(ctx, answer, business.GetString("type"))
+	if err != nil {
+		deps.Logger.Warn("whatsapp: onboarding profile extraction failed", "business", business.Id, "error", err)
+		return content.PartialBusinessProfile{}, false
+	}
+	return profile, true
+}
+
+// leadReadyText is the operator group notice for a finished onboarding.
+func leadReadyText(business *core.Record) string {
+	var services []map[string]any
+	_ = business.UnmarshalJSONField("services", &services)
+	return fmt.Sprintf("Novo lead pelo WhatsApp pronto pro convite:\n%s (%s, %s)\nTel: +%s\nServiços: %d\nVibe: %s\n\nDefina plano e compromisso e mande o convite.",
+		business.GetString("name"), business.GetString("type"), business.GetString("city"),
+		business.GetString("phone"), len(services), business.GetString("brand_vibe"))
+}
+
+// sendToLead sends text to the lead and stores it as an outgoing message.
+// Without a connected client nothing is sent or stored.
+func sendToLead(ctx context.Context, deps HandlerDeps, businessID, phone, text string) {
+	if deps.Client == nil {
+		deps.Logger.Info("whatsapp: onboarding message not sent, no client", "phone", phone)
+		return
+	}
+	resp, err := deps.Client.SendMessage(ctx, types.JID{User: phone, Server: types.DefaultUserServer}, &waE2E.Message{Conversation: &text})
+	if err != nil {
+		deps.Logger.Error("whatsapp: failed to send onboarding message", "phone", phone, "error", err)
+		return
+	}
+	saveMessageRecord(deps, businessID, phone, domain.DirectionOutgoing, domain.MsgTypeText, text, time.Now(), resp.ID, nil)
+}
+
+// splitCityState splits answers like "Goiânia - GO" or "Belo Horizonte/MG"
+// into city and state. State is empty when the answer has no two-letter UF.
+func splitCityState(answer string) (city, state string) {
+	for _, sep := range []string{" - ", "/", ","} {
+		if i := strings.LastIndex(answer, sep); i > 0 {
+			uf := strings.TrimSpace(answer[i+len(sep):])
+			if len(uf) == 2 {
+				return strings.TrimSpace(answer[:i]), strings.ToUpper(uf)
+			}
+		}
+	}
+	return answer, ""
+}
+
+func clip(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n])
+	}
+	return s
+}
+
+// keyedMutex hands out one mutex per key, dropping it once nobody holds or
+// waits for it.
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*keyedLock
+}
+
+type keyedLock struct {
+	sync.Mutex
+	refs int
+}
+
+func newKeyedMutex() *keyedMutex {
+	return &keyedMutex{locks: map[string]*keyedLock{}}
+}
+
+// Lock locks key and returns the function that unlocks it.
+func (k *keyedMutex) Lock(key string) func() {
+	k.mu.Lock()
+	l := k.locks[key]
+	if l == nil {
+		l = &keyedLock{}
+		k.locks[key] = l
+	}
+	l.refs++
+	k.mu.Unlock()
+
+	l.Lock()
+	return func() {
+		l.Unlock()
+		k.mu.Lock()
+		if l.refs--; l.refs == 0 {
+			delete(k.locks, key)
+		}
+		k.mu.Unlock()
+	}
+}
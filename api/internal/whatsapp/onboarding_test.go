@@ -0,0 +1,222 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	content "github.com/denisraison/rekan/api/internal/content"
+	"github.com/denisraison/rekan/api/internal/domain"
+)
+
+// TestOnboardingBuildsDraftProfile walks a new number through every question
+// and checks the business ends up as a complete draft.
+func TestOnboardingBuildsDraftProfile(t *testing.T) {
+	app := newHandlerTestApp(t)
+	deps := makeDeps(t, app)
+	price := 45.0
+	vibe, audience := "divertida", "mulheres de 25 a 40"
+	deps.ExtractProfile = func(_ context.Context, text, businessType string) (content.PartialBusinessProfile, error) {
+		if businessType != "Manicure" {
+			t.Errorf("extraction got type %q, want the answered one", businessType)
+		}
+		if text == "pé e mão 45" {
+			return content.PartialBusinessProfile{Services: []content.PartialService{{Name: "Pé e mão", PriceBRL: &price}}}, nil
+		}
+		return content.PartialBusinessProfile{BrandVibe: &vibe, TargetAudience: &audience}, nil
+	}
+
+	handleDirectMessage(deps, incomingTextEvtWithName("lead1", "5562988880000", "Ana"))
+	biz, err := app.FindFirstRecordByFilter(domain.CollBusinesses, "phone = '5562988880000'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	startOnboarding(deps, biz.Id, "5562988880000", "Ana")
+	if got := reload(t, app, biz).GetInt("onboarding_step"); got != OnboardingName {
+		t.Fatalf("onboarding_step = %d after start, want %d", got, OnboardingName)
+	}
+
+	for _, answer := range []string{"Unhas da Ana", "Manicure", "Goiânia - go", "pé e mão 45", "bem divertida, pra mulherada"} {
+		advanceOnboarding(deps, biz.Id, "5562988880000", answer)
+	}
+
+	biz = reload(t, app, biz)
+	if got := biz.GetInt("onboarding_step"); got != OnboardingDone {
+		t.Errorf("onboarding_step = %d, want done", got)
+	}
+	if got := biz.GetString("invite_status"); got != domain.InviteStatusDraft {
+		t.Errorf("invite_status = %q, want draft", got)
+	}
+	checks := map[string]string{
+		"name": "Unhas da Ana", "type": "Manicure", "city": "Goiânia", "state": "GO",
+		"brand_vibe": "divertida", "target_audience": "mulheres de 25 a 40",
+	}
+	for field, want := range checks {
+		if got := biz.GetString(field); got != want {
+			t.Errorf("%s = %q, want %q", field, got, want)
+		}
+	}
+	var services []map[string]any
+	if err := biz.UnmarshalJSONField("services", &services); err != nil || len(services) != 1 || services[0]["name"] != "Pé e mão" {
+		t.Errorf("services = %v (%v)", services, err)
+	}
+	var collected map[string]string
+	if err := biz.UnmarshalJSONField("collected_fields", &collected); err != nil || collected["services"] != "pé e mão 45" {
+		t.Errorf("collected_fields = %v (%v)", collected, err)
+	}
+
+	// Later messages are regular conversation, not answers.
+	advanceOnboarding(deps, biz.Id, "5562988880000", "obrigada!")
+	if got := reload(t, app, biz).GetString("brand_vibe"); got != "divertida" {
+		t.Errorf("message after onboarding changed the profile: brand_vibe = %q", got)
+	}
+}
+
+// TestOnboardingExtractionDoesNotBlockOtherLeads checks that a slow profile
+// extraction for one lead leaves other leads' answers going through.
+func TestOnboardingExtractionDoesNotBlockOtherLeads(t *testing.T) {
+	app := newHandlerTestApp(t)
+	deps := makeDeps(t, app)
+	entered, release := make(chan struct{}), make(chan struct{})
+	deps.ExtractProfile = func(_ context.Context, text, _ string) (content.PartialBusinessProfile, error) {
+		if text == "bolo de pote 12" {
+			close(entered)
+			<-release
+		}
+		return content.PartialBusinessProfile{}, nil
+	}
+
+	var leads []string
+	for i, phone := range []string{"5562988880010", "5562988880011"} {
+		handleDirectMessage(deps, incomingTextEvtWithName(fmt.Sprintf("lead%d", i), phone, "Lead"))
+		biz, err := app.FindFirstRecordByFilter(domain.CollBusinesses, "phone = {:phone}", map[string]any{"phone": phone})
+		if err != nil {
+			t.Fatal(err)
+		}
+		biz.Set("onboarding_step", OnboardingServices)
+		if err := app.Save(biz); err != nil {
+			t.Fatal(err)
+		}
+		leads = append(leads, biz.Id)
+	}
+
+	slow := make(chan struct{})
+	go func() {
+		advanceOnboarding(deps, leads[0], "5562988880010", "bolo de pote 12")
+		close(slow)
+	}()
+	<-entered
+
+	fast := make(chan struct{})
+	go func() {
+		advanceOnboarding(deps, leads[1], "5562988880011", "escova 50")
+		close(fast)
+	}()
+	select {
+	case <-fast:
+	case <-time.After(5 * time.Second):
+		t.Fatal("second lead waited for the first lead's extraction")
+	}
+	close(release)
+	<-slow
+
+	for _, id := range leads {
+		biz, err := app.FindRecordById(domain.CollBusinesses, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := biz.GetInt("onboarding_step"); got != OnboardingVibe {
+			t.Errorf("onboarding_step = %d, want %d", got, OnboardingVibe)
+		}
+	}
+}
+
+// TestOnboardingAnswersKeepTheirOrder sends two answers back to back while
+// the first one's extraction is slow, and checks each lands on its own
+// question.
+func TestOnboardingAnswersKeepTheirOrder(t *testing.T) {
+	app := newHandlerTestApp(t)
+	deps := makeDeps(t, app)
+	deps.OnboardLeads = true
+	in, _ := testInbound(t, 0, "")
+	deps.Inbound = in
+	entered, release := make(chan struct{}), make(chan struct{})
+	deps.ExtractProfile = func(_ context.Context, text, _ string) (content.PartialBusinessProfile, error) {
+		if text == "bolo de pote 12" {
+			close(entered)
+			<-release
+		}
+		return content.PartialBusinessProfile{}, nil
+	}
+
+	phone := "5562988880020"
+	handleDirectMessage(deps, incomingTextEvtWithName("lead-hi", phone, "Bia"))
+	biz, err := app.FindFirstRecordByFilter(domain.CollBusinesses, "phone = {:phone}", map[string]any{"phone": phone})
+	if err != nil {
+		t.Fatal(err)
+	}
+	biz.Set("onboarding_step", OnboardingServices)
+	if err := app.Save(biz); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, answer := range []string{"bolo de pote 12", "bem acolhedora, pra famílias"} {
+		evt := incomingTextEvtWithName(fmt.Sprintf("lead-answer%d", i), phone, "Bia")
+		evt.Message.Conversation = &answer
+		deps.submit(evt.Info.Chat.String(), func() { handleDirectMessage(deps, evt) })
+	}
+	<-entered
+	// Give the second answer time to overtake the first if it could.
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for reload(t, app, biz).GetInt("onboarding_step") != OnboardingDone {
+		if time.Now().After(deadline) {
+			t.Fatalf("onboarding_step = %d, want done", reload(t, app, biz).GetInt("onboarding_step"))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	var collected map[string]string
+	if err := reload(t, app, biz).UnmarshalJSONField("collected_fields", &collected); err != nil {
+		t.Fatal(err)
+	}
+	if collected["services"] != "bolo de pote 12" || collected["vibe"] != "bem acolhedora, pra famílias" {
+		t.Errorf("collected_fields = %v, want each answer on its own question", collected)
+	}
+}
+
+// TestOnboardingOnlyForNewIncomingNumbers verifies that only an incoming
+// message that created the placeholder starts onboarding.
+func TestOnboardingOnlyForNewIncomingNumbers(t *testing.T) {
+	app := newHandlerTestApp(t)
+	deps := makeDeps(t, app)
+
+	handleDirectMessage(deps, outgoingTextEvt("out1", "5562988880001"))
+	biz, err := app.FindFirstRecordByFilter(domain.CollBusinesses, "phone = '5562988880001'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	advanceOnboarding(deps, biz.Id, "5562988880001", "Padaria")
+	if got := reload(t, app, biz).GetString("type"); got != domain.BusinessTypeUnknown {
+		t.Errorf("type = %q; a contact we messaged first should not be onboarded", got)
+	}
+}
+
+func TestSplitCityState(t *testing.T) {
+	tests := []struct{ in, city, state string }{
+		{"Goiânia - GO", "Goiânia", "GO"},
+		{"Belo Horizonte/mg", "Belo Horizonte", "MG"},
+		{"São Paulo, SP", "São Paulo", "SP"},
+		{"Contagem", "Contagem", ""},
+		{"Rio - zona sul", "Rio - zona sul", ""},
+	}
+	for _, tt := range tests {
+		city, state := splitCityState(tt.in)
+		if city != tt.city || state != tt.state {
+			t.Errorf("splitCityState(%q) = %q, %q; want %q, %q", tt.in, city, state, tt.city, tt.state)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+
+	"github.com/denisraison/rekan/api/internal/domain"
+	"github.com/denisraison/rekan/api/internal/prefs"
+)
+
+// preferenceConfirmations answer a client whose opt-out or opt-in changed
+// something. They go out directly, not through the outbox: they reply to the
+// client's own message and must reach them even after a full opt-out.
+var preferenceConfirmations = map[prefs.Intent]string{
+	prefs.IntentStopMarketing: "Pronto, você não vai mais receber nossas campanhas e mensagens de datas comemorativas. " +
+		"Mensagens sobre seus posts continuam normalmente. Se mudar de ideia, é só responder VOLTAR.",
+	prefs.IntentStopAll:          "Pronto, não vamos mais te mandar mensagens. Se mudar de ideia, é só responder VOLTAR.",
+	prefs.IntentStopAllConfirmed: "Pronto, não vamos mais te mandar mensagens. Se mudar de ideia, é só responder VOLTAR.",
+	prefs.IntentResume:           "Combinado, você volta a receber nossas mensagens!",
+}
+
+// stopAllQuestion answers an active client whose stop request was narrowed to
+// promotions: their posts reach them through the messages they asked to stop.
+var stopAllQuestion = "Pronto, você não vai mais receber nossas campanhas e mensagens de datas comemorativas. " +
+	"As mensagens sobre seus posts continuam, é por elas que seu conteúdo chega até você. " +
+	"Se quiser parar essas também, responda " + prefs.StopAllWord + "."
+
+// applyPreferenceIntent saves the opt-out or opt-in the client asked for,
+// logged with their message, and confirms it. An active client asking in a
+// sentence to stop everything only stops promotions and is asked to confirm,
+// and the operator group hears whenever an active client's post messages are
+// turned off or back on. The change is saved on the chat's worker, so a
+// quick SAIR then VOLTAR ends up as the client left it; only the messages go
+// out in a goroutine.
+func applyPreferenceIntent(deps HandlerDeps, businessID, phone, messageID, text string, intent prefs.Intent, active bool) {
+	before, err := prefs.Get(deps.App, businessID)
+	if err != nil {
+		deps.Logger.Error("whatsapp: failed to load communication preferences", "business", businessID, "error", err)
+		return
+	}
+	ask := active && intent == prefs.IntentStopAll && before.Operational
+	set := intent.Set()
+	if ask {
+		set = prefs.IntentStopMarketing.Set()
+	}
+
+	after, changed, err := prefs.Apply(deps.App, prefs.Change{
+		BusinessID: businessID,
+		Set:        set,
+		Source:     prefs.SourceClient,
+		MessageID:  messageID,
+		Text:       text,
+	})
+	if err != nil {
+		deps.Logger.Error("whatsapp: failed to save communication preferences", "business", businessID, "error", err)
+		return
+	}
+	if !changed && !ask {
+		return
+	}
+	deps.Logger.Info("whatsapp: client changed communication preferences", "business", businessID, "intent", intent, "asked_to_confirm", ask)
+
+	reply := preferenceConfirmations[intent]
+	if ask {
+		reply = stopAllQuestion
+	}
+	notice := ""
+	if active && before.Operational != after.Operational {
+		notice = operationalChangeText(deps, businessID, phone, text, after.Operational)
+	}
+	go confirmPreferenceChange(deps, businessID, phone, reply, notice)
+}
+
+// confirmPreferenceChange sends the client reply and, when not empty, the
+// operator group notice for a saved preference change. Runs in a goroutine.
+func confirmPreferenceChange(deps HandlerDeps, businessID, phone, reply, notice string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if notice != "" {
+		notifyOperators(ctx, deps, notice)
+	}
+
+	if deps.Client == nil {
+		return
+	}
+	resp, err := deps.Client.SendMessage(ctx, types.JID{User: phone, Server: types.DefaultUserServer}, &waE2E.Message{Conversation: &reply})
+	if err != nil {
+		deps.Logger.Error("whatsapp: failed to confirm preference change", "phone", phone, "error", err)
+		return
+	}
+	saveMessageRecord(deps, businessID, phone, domain.DirectionOutgoing, domain.MsgTypeText, reply, time.Now(), resp.ID, nil)
+}
+
+// operationalChangeText is the operator group notice for an active client
+// turning messages about their posts off or on.
+func operationalChangeText(deps HandlerDeps, businessID, phone, text string, allowed bool) string {
+	name := "+" + phone
+	if business, err := deps.App.FindRecordById(domain.CollBusinesses, businessID); err == nil && business.GetString("name") != "" {
+		name = business.GetString("name") + " (+" + phone + ")"
+	}
+	if allowed {
+		return fmt.Sprintf("%s voltou a aceitar mensagens sobre os posts.\nMensagem: \"%s\"", name, text)
+	}
+	return fmt.Sprintf("%s pediu pra não receber mais nenhuma mensagem, nem dos posts. Nada sai pra esse número até a resposta VOLTAR.\nMensagem: \"%s\"", name, text)
+}
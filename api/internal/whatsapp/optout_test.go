@@ -0,0 +1,133 @@
+package whatsapp
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.mau.fi/whatsmeow/proto/waE2E"
+
+	"github.com/denisraison/rekan/api/internal/domain"
+	"github.com/denisraison/rekan/api/internal/prefs"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// TestPreferenceIntentStopsMessages verifies that "não quero mais receber
+// mensagens" from an active client only stops promotions until they answer
+// PARAR TUDO, that each change is logged with the client's message, that the
+// operator group hears about the full stop and that none of it reaches the DM
+// assistant.
+func TestPreferenceIntentStopsMessages(t *testing.T) {
+	app := newHandlerTestApp(t)
+	deps := makeDeps(t, app)
+	var logs lockedBuffer
+	deps.Logger = slog.New(slog.NewTextHandler(&logs, nil))
+	var assistantCalls atomic.Int32
+	deps.HandleClientMsg = func(string, string, string, string) { assistantCalls.Add(1) }
+	seedActiveBusiness(t, app, "5511999990001")
+	biz, err := app.FindFirstRecordByFilter(domain.CollBusinesses, "phone = '5511999990001'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	evt := incomingTextEvt("msg1", "5511999990001")
+	evt.Message = &waE2E.Message{Conversation: new("Oi, não quero mais receber mensagens, obrigada")}
+	handleDirectMessage(deps, evt)
+	history := waitPreferenceHistory(t, deps, biz.Id, 2)
+	if p, _ := prefs.Get(app, biz.Id); p.Marketing || p.Seasonal || !p.Operational {
+		t.Errorf("preferences = %+v, want only promotions stopped", p)
+	}
+	for _, r := range history {
+		msg, err := app.FindRecordById(domain.CollMessages, r.GetString("message"))
+		if err != nil || msg.GetString("wa_message_id") != "msg1" {
+			t.Errorf("%s change not linked to the client's message", r.GetString("category"))
+		}
+	}
+
+	evt = incomingTextEvt("msg2", "5511999990001")
+	evt.Message = &waE2E.Message{Conversation: new("PARAR TUDO")}
+	handleDirectMessage(deps, evt)
+	waitPreferenceHistory(t, deps, biz.Id, 3)
+	if p, _ := prefs.Get(app, biz.Id); p.Operational {
+		t.Error("operational messages still allowed after PARAR TUDO")
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.Contains(logs.String(), "nem dos posts") && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if !strings.Contains(logs.String(), "nem dos posts") {
+		t.Error("operator group not told about the full stop")
+	}
+	if n := assistantCalls.Load(); n != 0 {
+		t.Errorf("assistant got %d calls, want none", n)
+	}
+}
+
+// TestPreferenceIntentsApplyInOrder verifies that a preference change is
+// saved before the handler returns, so SAIR followed right away by VOLTAR
+// leaves the client receiving everything.
+func TestPreferenceIntentsApplyInOrder(t *testing.T) {
+	app := newHandlerTestApp(t)
+	deps := makeDeps(t, app)
+	seedActiveBusiness(t, app, "5511999990002")
+	biz, err := app.FindFirstRecordByFilter(domain.CollBusinesses, "phone = '5511999990002'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	evt := incomingTextEvt("stop1", "5511999990002")
+	evt.Message = &waE2E.Message{Conversation: new("SAIR")}
+	handleDirectMessage(deps, evt)
+	if p, _ := prefs.Get(app, biz.Id); p.Marketing {
+		t.Error("marketing still allowed right after SAIR")
+	}
+
+	evt = incomingTextEvt("resume1", "5511999990002")
+	evt.Message = &waE2E.Message{Conversation: new("VOLTAR")}
+	handleDirectMessage(deps, evt)
+	if p, _ := prefs.Get(app, biz.Id); !p.Marketing || !p.Seasonal || !p.Operational {
+		t.Errorf("preferences = %+v after VOLTAR, want everything allowed", p)
+	}
+}
+
+// waitPreferenceHistory waits until the business has n preference changes
+// logged and returns them.
+func waitPreferenceHistory(t *testing.T, deps HandlerDeps, businessID string, n int) []*core.Record {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		records, err := prefs.History(deps.App, businessID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(records) >= n {
+			return records
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("logged %d preference changes, want %d", len(records), n)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// lockedBuffer is a log sink safe to read while handlers write to it.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
@@ -0,0 +1,139 @@
+package whatsapp
+
+import (
+	"hash/fnv"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Pool runs jobs on a fixed number of workers. Jobs with the same key always
+// land on the same worker, so they run one at a time and in submit order;
+// jobs with different keys run in parallel.
+type Pool struct {
+	name   string
+	logger *slog.Logger
+	queues []chan poolJob
+	wg     sync.WaitGroup
+
+	mu     sync.RWMutex // held for reading while sending, for writing by Close
+	closed bool
+
+	submitted atomic.Int64
+	processed atomic.Int64
+	panics    atomic.Int64
+	saturated atomic.Int64 // submits that found their queue full and had to wait
+	waitNanos atomic.Int64 // total time jobs spent queued
+}
+
+type poolJob struct {
+	fn       func()
+	queuedAt time.Time
+}
+
+// PoolStats is a snapshot of a pool's counters.
+type PoolStats struct {
+	Workers   int     `json:"workers"`
+	Depth     int     `json:"depth"`    // jobs waiting right now
+	Capacity  int     `json:"capacity"` // total queue slots
+	Submitted int64   `json:"submitted"`
+	Processed int64   `json:"processed"`
+	Panics    int64   `json:"panics"`
+	Saturated int64   `json:"saturated"`
+	AvgWaitMS float64 `json:"avg_wait_ms"`
+}
+
+// NewPool starts workers goroutines, each with a queue of depth jobs.
+func NewPool(name string, workers, depth int, logger *slog.Logger) *Pool {
+	p := &Pool{name: name, logger: logger, queues: make([]chan poolJob, workers)}
+	for i := range p.queues {
+		q := make(chan poolJob, depth)
+		p.queues[i] = q
+		p.wg.Go(func() { p.work(q) })
+	}
+	return p
+}
+
+// Submit queues fn under key. When the key's queue is full Submit blocks
+// until there is room, which pushes back on the caller instead of dropping
+// events. Jobs submitted after Close are dropped.
+func (p *Pool) Submit(key string, fn func()) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		p.logger.Warn("whatsapp: job dropped, pool closed", "pool", p.name, "key", key)
+		return
+	}
+	p.submitted.Add(1)
+
+	q := p.queues[p.index(key)]
+	job := poolJob{fn: fn, queuedAt: time.Now()}
+	select {
+	case q <- job:
+	default:
+		p.saturated.Add(1)
+		p.logger.Warn("whatsapp: worker queue full, waiting", "pool", p.name, "key", key)
+		q <- job
+	}
+}
+
+// Close stops accepting jobs and waits for queued ones to finish.
+func (p *Pool) Close() {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+	p.closed = true
+	for _, q := range p.queues {
+		close(q)
+	}
+	p.mu.Unlock()
+	p.wg.Wait()
+}
+
+// Stats returns the pool's current counters.
+func (p *Pool) Stats() PoolStats {
+	s := PoolStats{
+		Workers:   len(p.queues),
+		Submitted: p.submitted.Load(),
+		Processed: p.processed.Load(),
+		Panics:    p.panics.Load(),
+		Saturated: p.saturated.Load(),
+	}
+	for _, q := range p.queues {
+		s.Depth += len(q)
+		s.Capacity += cap(q)
+	}
+	if s.Processed > 0 {
+		s.AvgWaitMS = float64(p.waitNanos.Load()) / float64(s.Processed) / float64(time.Millisecond)
+	}
+	return s
+}
+
+func (p *Pool) index(key string) int {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return int(h.Sum32() % uint32(len(p.queues)))
+}
+
+func (p *Pool) work(q chan poolJob) {
+	for job := range q {
+		p.waitNanos.Add(int64(time.Since(job.queuedAt)))
+		p.run(job.fn)
+		p.processed.Add(1)
+	}
+}
+
+// run calls fn, turning a panic into a log line so one bad event cannot take
+// the worker down.
+func (p *Pool) run(fn func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			p.panics.Add(1)
+			p.logger.Error("whatsapp: job panicked", "pool", p.name, "panic", r)
+		}
+	}()
+	fn()
+}
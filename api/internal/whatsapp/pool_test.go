@@ -0,0 +1,84 @@
+package whatsapp
+
+import (
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// TestPoolKeepsOrderPerKey verifies that jobs sharing a key run in submit
+// order even when other keys are busy on the same pool.
+func TestPoolKeepsOrderPerKey(t *testing.T) {
+	p := NewPool("test", 4, 8, discardLogger())
+
+	var mu sync.Mutex
+	got := map[string][]int{}
+	keys := []string{"a@s.whatsapp.net", "b@s.whatsapp.net", "c@s.whatsapp.net"}
+	for i := range 50 {
+		for _, key := range keys {
+			p.Submit(key, func() {
+				mu.Lock()
+				got[key] = append(got[key], i)
+				mu.Unlock()
+			})
+		}
+	}
+	p.Close()
+
+	for _, key := range keys {
+		if len(got[key]) != 50 {
+			t.Fatalf("%s: ran %d jobs, want 50", key, len(got[key]))
+		}
+		for i, n := range got[key] {
+			if n != i {
+				t.Fatalf("%s: job %d ran at position %d", key, n, i)
+			}
+		}
+	}
+	if s := p.Stats(); s.Submitted != 150 || s.Processed != 150 || s.Depth != 0 {
+		t.Errorf("stats = %+v", s)
+	}
+}
+
+// TestPoolSaturationAndPanics verifies that a full queue makes Submit wait
+// and is counted, and that a panicking job does not stop the worker.
+func TestPoolSaturationAndPanics(t *testing.T) {
+	p := NewPool("test", 1, 1, discardLogger())
+	defer p.Close()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	p.Submit("k", func() { close(started); <-release })
+	<-started // the worker is busy and the queue empty
+	p.Submit("k", func() { panic("boom") })
+
+	submitted := make(chan struct{})
+	ran := make(chan struct{})
+	go func() {
+		p.Submit("k", func() { close(ran) }) // queue full: waits
+		close(submitted)
+	}()
+
+	select {
+	case <-submitted:
+		t.Fatal("Submit returned while the queue was full")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+
+	select {
+	case <-ran:
+	case <-time.After(time.Second):
+		t.Fatal("job after the panic never ran")
+	}
+	s := p.Stats()
+	if s.Saturated != 1 || s.Panics != 1 {
+		t.Errorf("saturated %d, panics %d, want 1 and 1", s.Saturated, s.Panics)
+	}
+}
@@ -0,0 +1,77 @@
+package whatsapp
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+
+	"github.com/denisraison/rekan/api/internal/domain"
+)
+
+// statusRank orders delivery statuses so a late delivered receipt never
+// downgrades a message that was already read.
+var statusRank = map[string]int{
+	domain.MsgStatusSent:      1,
+	domain.MsgStatusDelivered: 2,
+	domain.MsgStatusRead:      3,
+}
+
+// handleReceipt updates the delivery status of our outgoing messages. Only
+// receipts from the client count: read-self receipts come from our own
+// devices, and group receipts are for the agent's group.
+func handleReceipt(deps HandlerDeps, evt *events.Receipt) {
+	if evt.IsFromMe || evt.IsGroup {
+		return
+	}
+	var status string
+	switch evt.Type {
+	case types.ReceiptTypeDelivered:
+		status = domain.MsgStatusDelivered
+	case types.ReceiptTypeRead, types.ReceiptTypePlayed:
+		status = domain.MsgStatusRead
+	default:
+		return
+	}
+
+	for _, id := range evt.MessageIDs {
+		record, err := deps.App.FindFirstRecordByFilter(domain.CollMessages,
+			"wa_message_id = {:id} && direction = {:direction}",
+			map[string]any{"id": id, "direction": domain.DirectionOutgoing})
+		if err != nil {
+			if !errors.Is(err, sql.ErrNoRows) {
+				deps.Logger.Error("whatsapp: find message for receipt", "wa_message_id", id, "error", err)
+			}
+			continue
+		}
+		if !applyReceipt(record, status, evt.Timestamp) {
+			continue
+		}
+		if err := deps.App.Save(record); err != nil {
+			deps.Logger.Error("whatsapp: save receipt", "wa_message_id", id, "error", err)
+		}
+	}
+}
+
+// applyReceipt records a receipt on a message. A read receipt also fills
+// delivered_at, since WhatsApp may skip the delivered receipt when the client
+// reads right away. It reports whether anything changed.
+func applyReceipt(record *core.Record, status string, at time.Time) bool {
+	changed := false
+	if record.GetDateTime("delivered_at").IsZero() {
+		record.Set("delivered_at", at.UTC())
+		changed = true
+	}
+	if status == domain.MsgStatusRead && record.GetDateTime("read_at").IsZero() {
+		record.Set("read_at", at.UTC())
+		changed = true
+	}
+	if statusRank[status] > statusRank[record.GetString("status")] {
+		record.Set("status", status)
+		changed = true
+	}
+	return changed
+}
@@ -0,0 +1,91 @@
+package whatsapp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/denisraison/rekan/api/internal/domain"
+	"github.com/pocketbase/pocketbase/core"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+func receiptEvt(msgID, phone string, typ types.ReceiptType, at time.Time) *events.Receipt {
+	return &events.Receipt{
+		MessageSource: types.MessageSource{
+			Sender: types.JID{User: phone, Server: "s.whatsapp.net"},
+			Chat:   types.JID{User: phone, Server: "s.whatsapp.net"},
+		},
+		MessageIDs: []types.MessageID{msgID},
+		Timestamp:  at,
+		Type:       typ,
+	}
+}
+
+// TestHandleReceiptTracksDeliveryAndRead verifies that receipts move an
+// outgoing message from sent to delivered to read, and never back.
+func TestHandleReceiptTracksDeliveryAndRead(t *testing.T) {
+	app := newHandlerTestApp(t)
+	deps := makeDeps(t, app)
+
+	handleDirectMessage(deps, outgoingTextEvt("out1", "5511777770001"))
+	msg, err := app.FindFirstRecordByFilter(domain.CollMessages, "wa_message_id = 'out1'")
+	if err != nil {
+		t.Fatalf("message not found: %v", err)
+	}
+	msg.Set("status", domain.MsgStatusSent)
+	if err := app.Save(msg); err != nil {
+		t.Fatal(err)
+	}
+
+	delivered := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	read := delivered.Add(time.Hour)
+	handleReceipt(deps, receiptEvt("out1", "5511777770001", types.ReceiptTypeDelivered, delivered))
+	handleReceipt(deps, receiptEvt("out1", "5511777770001", types.ReceiptTypeRead, read))
+	handleReceipt(deps, receiptEvt("out1", "5511777770001", types.ReceiptTypeDelivered, read.Add(time.Minute)))
+
+	msg = reload(t, app, msg)
+	if got := msg.GetString("status"); got != domain.MsgStatusRead {
+		t.Errorf("status = %q, want read", got)
+	}
+	if got := msg.GetDateTime("delivered_at").Time(); !got.Equal(delivered) {
+		t.Errorf("delivered_at = %v, want %v", got, delivered)
+	}
+	if got := msg.GetDateTime("read_at").Time(); !got.Equal(read) {
+		t.Errorf("read_at = %v, want %v", got, read)
+	}
+}
+
+// TestHandleReceiptIgnoresOwnAndIncoming verifies that read-self receipts
+// and receipts for incoming messages change nothing.
+func TestHandleReceiptIgnoresOwnAndIncoming(t *testing.T) {
+	app := newHandlerTestApp(t)
+	deps := makeDeps(t, app)
+
+	handleDirectMessage(deps, incomingTextEvt("in1", "5511777770002"))
+	handleDirectMessage(deps, outgoingTextEvt("out2", "5511777770002"))
+
+	handleReceipt(deps, receiptEvt("in1", "5511777770002", types.ReceiptTypeRead, time.Now()))
+	own := receiptEvt("out2", "5511777770002", types.ReceiptTypeReadSelf, time.Now())
+	own.IsFromMe = true
+	handleReceipt(deps, own)
+
+	for _, id := range []string{"in1", "out2"} {
+		msg, err := app.FindFirstRecordByFilter(domain.CollMessages, "wa_message_id = {:id}", map[string]any{"id": id})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if msg.GetString("status") != "" || !msg.GetDateTime("read_at").IsZero() {
+			t.Errorf("%s: status %q, read_at %v; want untouched", id, msg.GetString("status"), msg.GetDateTime("read_at"))
+		}
+	}
+}
+
+func reload(t *testing.T, app core.App, r *core.Record) *core.Record {
+	t.Helper()
+	fresh, err := app.FindRecordById(r.Collection().Name, r.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fresh
+}
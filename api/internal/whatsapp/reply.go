@@ -0,0 +1,184 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	pbtypes "github.com/pocketbase/pocketbase/tools/types"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+
+	content "github.com/denisraison/rekan/api/internal/content"
+	"github.com/denisraison/rekan/api/internal/domain"
+)
+
+// replyWindow is how long after a post is sent an unquoted client message is
+// still taken as a reply to it.
+const replyWindow = 2 * time.Hour
+
+// quotedMessageID returns the WhatsApp ID of the message msg replies to, or
+// "" if it is not a reply.
+func quotedMessageID(msg *waE2E.Message) string {
+	for _, ctx := range []*waE2E.ContextInfo{
+		msg.GetExtendedTextMessage().GetContextInfo(),
+		msg.GetImageMessage().GetContextInfo(),
+		msg.GetVideoMessage().GetContextInfo(),
+		msg.GetAudioMessage().GetContextInfo(),
+	} {
+		if id := ctx.GetStanzaID(); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// isReplyType reports whether a message of msgType can answer a post. Audio
+// counts because its content is the transcription.
+func isReplyType(msgType string) bool {
+	return msgType == domain.MsgTypeText || msgType == domain.MsgTypeAudio
+}
+
+// findRepliedPost returns the post an incoming client message answers: the
+// post carried by the quoted message, or else the latest post sent to phone
+// within replyWindow before at. Returns "" when there is none.
+func findRepliedPost(app core.App, phone, quotedID string, at time.Time) string {
+	if quotedID != "" {
+		quoted, err := app.FindFirstRecordByFilter(domain.CollMessages,
+			"wa_message_id = {:id} && direction = {:dir}",
+			dbx.Params{"id": quotedID, "dir": domain.DirectionOutgoing})
+		if err == nil && quoted.GetString("post") != "" {
+			return quoted.GetString("post")
+		}
+	}
+
+	since, err := pbtypes.ParseDateTime(at.Add(-replyWindow))
+	if err != nil {
+		return ""
+	}
+	var msg core.Record
+	err = app.RecordQuery(domain.CollMessages).
+		AndWhere(dbx.HashExp{"phone": phone, "direction": domain.DirectionOutgoing}).
+		AndWhere(dbx.NewExp("post != '' AND sent_at >= {:since}", dbx.Params{"since": since.String()})).
+		OrderBy("sent_at DESC").
+		Limit(1).
+		One(&msg)
+	if err != nil {
+		return ""
+	}
+	return msg.GetString("post")
+}
+
+// linkClientReply attaches an incoming message to the post it answers and
+// returns the post ID, or "" if the message is not a reply to a post.
+func linkClientReply(deps HandlerDeps, record *core.Record, evt *events.Message) string {
+	postID := findRepliedPost(deps.App, record.GetString("phone"), quotedMessageID(evt.Message), evt.Info.Timestamp)
+	if postID == "" {
+		return ""
+	}
+	record.Set("post", postID)
+	if err := deps.App.Save(record); err != nil {
+		deps.Logger.Error("whatsapp: failed to link reply to post", "post", postID, "error", err)
+		return ""
+	}
+	return postID
+}
+
+// classifyClientReply classifies a client's reply to a post and records it on
+// the post. A change request reopens the post for review and is sent to the
+// operator group as a revision task. It returns the reply kind, or "" when
+// the reply could not be classified.
+func classifyClientReply(deps HandlerDeps, postID, text string, at time.Time) string {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	post, err := deps.App.FindRecordById(domain.CollPosts, postID)
+	if err != nil {
+		deps.Logger.Warn("whatsapp: replied post not found", "post", postID, "error", err)
+		return ""
+	}
+
+	reply, err := deps.ClassifyReply(ctx, post.GetString("caption"), text)
+	if err != nil {
+		deps.Logger.Warn("whatsapp: client reply classification failed", "post", postID, "error", err)
+		return ""
+	}
+	if reply.Kind == content.ReplyOther {
+		return reply.Kind
+	}
+
+	feedback := reply.Summary
+	if feedback == "" && reply.Kind != content.ReplyApproved {
+		feedback = text
+	}
+	post.Set("client_reply", reply.Kind)
+	post.Set("client_feedback", feedback)
+	post.Set("client_replied_at", at.UTC())
+	if reply.Kind == content.ReplyChangeRequest {
+		post.Set("reviewed", false)
+	}
+	if err := deps.App.Save(post); err != nil {
+		deps.Logger.Error("whatsapp: failed to save client reply", "post", postID, "error", err)
+		return reply.Kind
+	}
+
+	if reply.Kind == content.ReplyChangeRequest {
+		notifyOperatorsAsync(deps, revisionTaskText(deps.App, post, text))
+	}
+	return reply.Kind
+}
+
+// settlesPost reports whether a classified reply is handled by the approval
+// loop. Questions and anything else still need an answer from the assistant
+// or an operator.
+func settlesPost(kind string) bool {
+	return kind == content.ReplyApproved || kind == content.ReplyChangeRequest
+}
+
+// revisionTaskText is the message the operator group gets when a client asks
+// for a change to a sent post.
+func revisionTaskText(app core.App, post *core.Record, text string) string {
+	name := post.GetString("business")
+	if biz, err := app.FindRecordById(domain.CollBusinesses, name); err == nil && biz.GetString("name") != "" {
+		name = biz.GetString("name")
+	}
+	return fmt.Sprintf("%s pediu mudança no post %s:\n\"%s\"\n\nO post voltou pra revisão.", name, post.Id, text)
+}
+
+// questionTaskText is the message the operator group gets when a client
+// asks about a sent post and no assistant can answer.
+func questionTaskText(app core.App, postID, phone, text string) string {
+	name := "+" + phone
+	if post, err := app.FindRecordById(domain.CollPosts, postID); err == nil {
+		if biz, err := app.FindRecordById(domain.CollBusinesses, post.GetString("business")); err == nil && biz.GetString("name") != "" {
+			name = biz.GetString("name")
+		}
+	}
+	return fmt.Sprintf("%s perguntou sobre o post %s:\n\"%s\"\n\nNinguém respondeu ainda.", name, postID, text)
+}
+
+// notifyOperators sends text to the agent group. Without a connected client
+// or a configured group the notice is only logged.
+func notifyOperators(ctx context.Context, deps HandlerDeps, text string) {
+	if deps.Client == nil || deps.AgentGroupJID == "" {
+		deps.Logger.Info("whatsapp: operator notice not sent, no operator group", "text", text)
+		return
+	}
+	group := types.NewJID(deps.AgentGroupJID, types.GroupServer)
+	if _, err := deps.Client.SendMessage(ctx, group, &waE2E.Message{Conversation: &text}); err != nil {
+		deps.Logger.Error("whatsapp: failed to send operator notice", "error", err)
+	}
+}
+
+// notifyOperatorsAsync sends an operator notice in the background, so the
+// chat's worker does not wait on it.
+func notifyOperatorsAsync(deps HandlerDeps, text string) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		notifyOperators(ctx, deps, text)
+	}()
+}
@@ -0,0 +1,177 @@
+package whatsapp
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types/events"
+
+	content "github.com/denisraison/rekan/api/internal/content"
+	"github.com/denisraison/rekan/api/internal/domain"
+)
+
+// sentPost saves a reviewed post and the outgoing message that carried it.
+func sentPost(t *testing.T, app *tests.TestApp, phone, waID string, sentAt time.Time) *core.Record {
+	t.Helper()
+	posts, err := app.FindCollectionByNameOrId(domain.CollPosts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	post := core.NewRecord(posts)
+	post.Set("caption", "Pão de queijo saindo agora!")
+	post.Set("reviewed", true)
+	if err := app.Save(post); err != nil {
+		t.Fatal(err)
+	}
+
+	messages, err := app.FindCollectionByNameOrId(domain.CollMessages)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := core.NewRecord(messages)
+	msg.Set("phone", phone)
+	msg.Set("type", domain.MsgTypeText)
+	msg.Set("direction", domain.DirectionOutgoing)
+	msg.Set("content", post.GetString("caption"))
+	msg.Set("wa_message_id", waID)
+	msg.Set("post", post.Id)
+	msg.Set("sent_at", sentAt.UTC())
+	if err := app.Save(msg); err != nil {
+		t.Fatal(err)
+	}
+	return post
+}
+
+func replyEvt(msgID, phone, text, quotedID string, at time.Time) *events.Message {
+	evt := incomingTextEvt(msgID, phone)
+	evt.Info.Timestamp = at
+	evt.Message = &waE2E.Message{ExtendedTextMessage: &waE2E.ExtendedTextMessage{
+		Text:        &text,
+		ContextInfo: &waE2E.ContextInfo{StanzaID: &quotedID},
+	}}
+	return evt
+}
+
+func stubClassifier(kind, summary string) content.ClassifyReplyFunc {
+	return func(context.Context, string, string) (content.ClientReply, error) {
+		return content.ClientReply{Kind: kind, Summary: summary}, nil
+	}
+}
+
+// TestClientReplyQuotedChangeRequest verifies that a reply quoting a sent post
+// is linked to it and that a change request reopens the post with the
+// client's words.
+func TestClientReplyQuotedChangeRequest(t *testing.T) {
+	app := newHandlerTestApp(t)
+	deps := makeDeps(t, app)
+
+	// Sent long ago: only the quote can tie the reply to it.
+	post := sentPost(t, app, "5511777770010", "out-post", time.Now().Add(-48*time.Hour))
+	handleDirectMessage(deps, replyEvt("in-reply", "5511777770010", "amei, só troca a foto", "out-post", time.Now()))
+
+	msg, err := app.FindFirstRecordByFilter(domain.CollMessages, "wa_message_id = 'in-reply'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := msg.GetString("post"); got != post.Id {
+		t.Fatalf("reply linked to %q, want %q", got, post.Id)
+	}
+
+	deps.ClassifyReply = stubClassifier(content.ReplyChangeRequest, "troca a foto")
+	classifyClientReply(deps, post.Id, "amei, só troca a foto", time.Now())
+	post = reload(t, app, post)
+	if got := post.GetString("client_reply"); got != content.ReplyChangeRequest {
+		t.Errorf("client_reply = %q, want change_request", got)
+	}
+	if got := post.GetString("client_feedback"); got != "troca a foto" {
+		t.Errorf("client_feedback = %q", got)
+	}
+	if post.GetBool("reviewed") {
+		t.Error("change request should reopen the post for review")
+	}
+	if post.GetDateTime("client_replied_at").IsZero() {
+		t.Error("client_replied_at not set")
+	}
+}
+
+// TestClientReplyWindow verifies that unquoted replies only attach to a post
+// sent within the reply window, and that unrelated replies leave it alone.
+func TestClientReplyWindow(t *testing.T) {
+	app := newHandlerTestApp(t)
+	deps := makeDeps(t, app)
+
+	now := time.Now()
+	post := sentPost(t, app, "5511777770011", "out-recent", now.Add(-30*time.Minute))
+	sentPost(t, app, "5511777770012", "out-old", now.Add(-3*time.Hour))
+
+	handleDirectMessage(deps, replyEvt("in-recent", "5511777770011", "bom dia!", "", now))
+	handleDirectMessage(deps, replyEvt("in-old", "5511777770012", "amei", "", now))
+
+	recent, _ := app.FindFirstRecordByFilter(domain.CollMessages, "wa_message_id = 'in-recent'")
+	if got := recent.GetString("post"); got != post.Id {
+		t.Errorf("recent reply linked to %q, want %q", got, post.Id)
+	}
+	old, _ := app.FindFirstRecordByFilter(domain.CollMessages, "wa_message_id = 'in-old'")
+	if got := old.GetString("post"); got != "" {
+		t.Errorf("reply outside the window linked to %q", got)
+	}
+
+	deps.ClassifyReply = stubClassifier(content.ReplyOther, "")
+	classifyClientReply(deps, post.Id, "bom dia!", now)
+	post = reload(t, app, post)
+	if post.GetString("client_reply") != "" || !post.GetBool("reviewed") {
+		t.Errorf("unrelated reply changed the post: reply %q, reviewed %v", post.GetString("client_reply"), post.GetBool("reviewed"))
+	}
+}
+
+// TestClientReplyQuestionIsAnswered verifies that a question about a sent
+// post reaches the DM assistant, an approval stays in the approval loop, and
+// a question nobody can answer there goes to the operator group.
+func TestClientReplyQuestionIsAnswered(t *testing.T) {
+	app := newHandlerTestApp(t)
+	deps := makeDeps(t, app)
+	var logs lockedBuffer
+	deps.Logger = slog.New(slog.NewTextHandler(&logs, nil))
+	kind := content.ReplyQuestion
+	deps.ClassifyReply = func(context.Context, string, string) (content.ClientReply, error) {
+		return content.ClientReply{Kind: kind}, nil
+	}
+	var handled []string
+	deps.HandleClientMsg = func(_, _, _, text string) { handled = append(handled, text) }
+
+	now := time.Now()
+	seedActiveBusiness(t, app, "5511777770013")
+	post := sentPost(t, app, "5511777770013", "out-question", now.Add(-30*time.Minute))
+
+	handleDirectMessage(deps, replyEvt("in-question", "5511777770013", "esse preço vale pro fim de semana?", "", now))
+	if len(handled) != 1 || handled[0] != "esse preço vale pro fim de semana?" {
+		t.Fatalf("assistant got %q, want the question", handled)
+	}
+	if got := reload(t, app, post).GetString("client_reply"); got != content.ReplyQuestion {
+		t.Errorf("client_reply = %q, want question", got)
+	}
+
+	kind = content.ReplyApproved
+	handleDirectMessage(deps, replyEvt("in-approve", "5511777770013", "amei, pode postar", "out-question", now))
+	if len(handled) != 1 {
+		t.Errorf("assistant got the approval too: %q", handled)
+	}
+
+	// Without an assistant the operators hear about the question.
+	deps.HandleClientMsg = nil
+	kind = content.ReplyQuestion
+	handleDirectMessage(deps, replyEvt("in-question2", "5511777770013", "e pro feriado?", "out-question", now))
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.Contains(logs.String(), "perguntou sobre o post") && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if !strings.Contains(logs.String(), "perguntou sobre o post") {
+		t.Error("operator group not told about the unanswered question")
+	}
+}
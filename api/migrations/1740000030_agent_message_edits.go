@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+// Track which WhatsApp messages produced each agent_conversations record so
+// operator edits and deletes can be matched after the debounce window.
+func init() {
+	m.Register(func(app core.App) error {
+		col, err := app.FindCollectionByNameOrId("agent_conversations")
+		if err != nil {
+			return err
+		}
+		col.Fields.Add(
+			&core.JSONField{Name: "wa_message_ids"},
+			&core.DateField{Name: "revoked_at"},
+		)
+		return app.Save(col)
+	}, func(app core.App) error {
+		col, err := app.FindCollectionByNameOrId("agent_conversations")
+		if err != nil {
+			return err
+		}
+		col.Fields.RemoveByName("wa_message_ids")
+		col.Fields.RemoveByName("revoked_at")
+		return app.Save(col)
+	})
+}
@@ -0,0 +1,38 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+// Adds "document" to the messages.type select for PDF/DOCX price lists and
+// menus sent by clients.
+func init() {
+	m.Register(func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("messages")
+		if err != nil {
+			return err
+		}
+
+		typeField := collection.Fields.GetByName("type")
+		if typeField == nil {
+			return nil
+		}
+		typeField.(*core.SelectField).Values = []string{"text", "audio", "image", "video", "document"}
+
+		return app.Save(collection)
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("messages")
+		if err != nil {
+			return nil
+		}
+
+		typeField := collection.Fields.GetByName("type")
+		if typeField == nil {
+			return nil
+		}
+		typeField.(*core.SelectField).Values = []string{"text", "audio", "image", "video"}
+
+		return app.Save(collection)
+	})
+}
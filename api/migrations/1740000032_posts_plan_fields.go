@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("posts")
+		if err != nil {
+			return err
+		}
+
+		collection.Fields.Add(
+			&core.DateField{Name: "planned_for"},
+			&core.TextField{Name: "occasion"},
+		)
+
+		return app.Save(collection)
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("posts")
+		if err != nil {
+			return nil
+		}
+
+		collection.Fields.RemoveByName("planned_for")
+		collection.Fields.RemoveByName("occasion")
+		return app.Save(collection)
+	})
+}
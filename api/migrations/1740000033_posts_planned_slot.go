@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("posts")
+		if err != nil {
+			return err
+		}
+
+		collection.Fields.Add(&core.TextField{Name: "planned_slot"}) // posting window, e.g. "10h-14h"
+		collection.AddIndex("idx_posts_planned_for", false, "planned_for", "")
+
+		return app.Save(collection)
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("posts")
+		if err != nil {
+			return nil
+		}
+
+		collection.RemoveIndex("idx_posts_planned_for")
+		collection.Fields.RemoveByName("planned_slot")
+		return app.Save(collection)
+	})
+}
@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("posts")
+		if err != nil {
+			return err
+		}
+
+		collection.Fields.Add(
+			&core.TextField{Name: "format"},      // "feed", "reel", "story" or "carousel"; empty means feed
+			&core.JSONField{Name: "format_data"}, // reel script, story frames or carousel slides
+		)
+
+		return app.Save(collection)
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("posts")
+		if err != nil {
+			return nil
+		}
+
+		collection.Fields.RemoveByName("format")
+		collection.Fields.RemoveByName("format_data")
+		return app.Save(collection)
+	})
+}
@@ -0,0 +1,38 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		posts, err := app.FindCollectionByNameOrId("posts")
+		if err != nil {
+			return err
+		}
+		posts.Fields.Add(&core.TextField{Name: "original_caption"}) // caption as generated, set on the first edit
+		if err := app.Save(posts); err != nil {
+			return err
+		}
+
+		businesses, err := app.FindCollectionByNameOrId("businesses")
+		if err != nil {
+			return err
+		}
+		businesses.Fields.Add(&core.TextField{Name: "style_memo"}) // rules distilled from rejections and edits
+		return app.Save(businesses)
+	}, func(app core.App) error {
+		if posts, err := app.FindCollectionByNameOrId("posts"); err == nil {
+			posts.Fields.RemoveByName("original_caption")
+			if err := app.Save(posts); err != nil {
+				return err
+			}
+		}
+		if businesses, err := app.FindCollectionByNameOrId("businesses"); err == nil {
+			businesses.Fields.RemoveByName("style_memo")
+			return app.Save(businesses)
+		}
+		return nil
+	})
+}
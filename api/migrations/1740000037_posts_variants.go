@@ -0,0 +1,27 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("posts")
+		if err != nil {
+			return err
+		}
+
+		collection.Fields.Add(&core.JSONField{Name: "variants"}) // caption options generated together; the chosen one is flagged
+
+		return app.Save(collection)
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("posts")
+		if err != nil {
+			return nil
+		}
+
+		collection.Fields.RemoveByName("variants")
+		return app.Save(collection)
+	})
+}
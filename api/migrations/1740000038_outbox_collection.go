@@ -0,0 +1,52 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+// Creates the outbox collection. Every WhatsApp message to a client is queued
+// here and sent by the outbox worker, which applies rate limits, quiet hours
+// and retries.
+func init() {
+	m.Register(func(app core.App) error {
+		businesses, err := app.FindCollectionByNameOrId("businesses")
+		if err != nil {
+			return err
+		}
+
+		collection := core.NewBaseCollection("outbox")
+
+		collection.Fields.Add(
+			&core.RelationField{Name: "business", CollectionId: businesses.Id, MaxSelect: 1},
+			&core.TextField{Name: "phone", Required: true},
+			&core.SelectField{Name: "type", Values: []string{"text", "image", "video"}, Required: true, MaxSelect: 1},
+			&core.TextField{Name: "text"},                                           // message body, or the media caption
+			&core.FileField{Name: "media", MaxSelect: 1, MaxSize: 10 * 1024 * 1024}, // 10MB
+			&core.TextField{Name: "content_type"},
+			&core.SelectField{Name: "status", Values: []string{"pending", "sent", "failed"}, Required: true, MaxSelect: 1},
+			&core.NumberField{Name: "attempts", OnlyInt: true},
+			&core.DateField{Name: "next_attempt_at"},
+			&core.DateField{Name: "sent_at"},
+			&core.TextField{Name: "last_error"},
+			&core.TextField{Name: "wa_message_id"},
+			&core.AutodateField{Name: "created", OnCreate: true, System: true},
+			&core.AutodateField{Name: "updated", OnCreate: true, OnUpdate: true, System: true},
+		)
+
+		// The worker scans pending messages
+		collection.AddIndex("idx_outbox_status", false, "status", "")
+
+		authed := `@request.auth.id != ""`
+		collection.ListRule = &authed
+		collection.ViewRule = &authed
+
+		return app.Save(collection)
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("outbox")
+		if err != nil {
+			return nil
+		}
+		return app.Delete(collection)
+	})
+}
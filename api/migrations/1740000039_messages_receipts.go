@@ -0,0 +1,58 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+// Adds delivery tracking to outgoing messages. WhatsApp receipts move status
+// from sent to delivered to read; post links a caption message to the post it
+// carried, so the operator can see which posts a client never opened.
+func init() {
+	m.Register(func(app core.App) error {
+		posts, err := app.FindCollectionByNameOrId("posts")
+		if err != nil {
+			return err
+		}
+
+		messages, err := app.FindCollectionByNameOrId("messages")
+		if err != nil {
+			return err
+		}
+		messages.Fields.Add(
+			&core.RelationField{Name: "post", CollectionId: posts.Id, MaxSelect: 1},
+			&core.SelectField{Name: "status", Values: []string{"sent", "delivered", "read"}, MaxSelect: 1},
+			&core.DateField{Name: "sent_at"},
+			&core.DateField{Name: "delivered_at"},
+			&core.DateField{Name: "read_at"},
+		)
+		messages.AddIndex("idx_messages_post", false, "post", "post != ''")
+		if err := app.Save(messages); err != nil {
+			return err
+		}
+
+		outbox, err := app.FindCollectionByNameOrId("outbox")
+		if err != nil {
+			return err
+		}
+		outbox.Fields.Add(&core.RelationField{Name: "post", CollectionId: posts.Id, MaxSelect: 1})
+		return app.Save(outbox)
+	}, func(app core.App) error {
+		if outbox, err := app.FindCollectionByNameOrId("outbox"); err == nil {
+			outbox.Fields.RemoveByName("post")
+			if err := app.Save(outbox); err != nil {
+				return err
+			}
+		}
+
+		messages, err := app.FindCollectionByNameOrId("messages")
+		if err != nil {
+			return nil
+		}
+		messages.RemoveIndex("idx_messages_post")
+		for _, name := range []string{"post", "status", "sent_at", "delivered_at", "read_at"} {
+			messages.Fields.RemoveByName(name)
+		}
+		return app.Save(messages)
+	})
+}
@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+// Records how the client answered a post sent over WhatsApp: approved, asked
+// for a change or asked a question, with their words in client_feedback.
+func init() {
+	m.Register(func(app core.App) error {
+		posts, err := app.FindCollectionByNameOrId("posts")
+		if err != nil {
+			return err
+		}
+		posts.Fields.Add(
+			&core.SelectField{Name: "client_reply", Values: []string{"approved", "change_request", "question"}, MaxSelect: 1},
+			&core.TextField{Name: "client_feedback"},
+			&core.DateField{Name: "client_replied_at"},
+		)
+		return app.Save(posts)
+	}, func(app core.App) error {
+		posts, err := app.FindCollectionByNameOrId("posts")
+		if err != nil {
+			return nil
+		}
+		for _, name := range []string{"client_reply", "client_feedback", "client_replied_at"} {
+			posts.Fields.RemoveByName(name)
+		}
+		return app.Save(posts)
+	})
+}
@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+// Adds the opt-in for the client-facing DM assistant. assistant_handoff_at is
+// when the assistant last passed the client to the operators; it stays quiet
+// for a while after that so the two don't talk over each other.
+func init() {
+	m.Register(func(app core.App) error {
+		businesses, err := app.FindCollectionByNameOrId("businesses")
+		if err != nil {
+			return err
+		}
+		businesses.Fields.Add(
+			&core.BoolField{Name: "assistant_enabled"},
+			&core.DateField{Name: "assistant_handoff_at"},
+		)
+		return app.Save(businesses)
+	}, func(app core.App) error {
+		businesses, err := app.FindCollectionByNameOrId("businesses")
+		if err != nil {
+			return nil
+		}
+		businesses.Fields.RemoveByName("assistant_enabled")
+		businesses.Fields.RemoveByName("assistant_handoff_at")
+		return app.Save(businesses)
+	})
+}
@@ -0,0 +1,27 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+// Adds collected_fields to businesses: the raw answers a lead gave during
+// WhatsApp onboarding, keyed by question, so the operator can check what the
+// extraction made of them. onboarding_step tracks which question is open.
+func init() {
+	m.Register(func(app core.App) error {
+		businesses, err := app.FindCollectionByNameOrId("businesses")
+		if err != nil {
+			return err
+		}
+		businesses.Fields.Add(&core.JSONField{Name: "collected_fields"})
+		return app.Save(businesses)
+	}, func(app core.App) error {
+		businesses, err := app.FindCollectionByNameOrId("businesses")
+		if err != nil {
+			return nil
+		}
+		businesses.Fields.RemoveByName("collected_fields")
+		return app.Save(businesses)
+	})
+}
@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+// Adds media_status to messages. Incoming media is saved as soon as it
+// arrives and downloaded, transcribed or described in the background:
+// pending until that finishes, then done, or failed after the last retry.
+// Empty for text and for media processed inline.
+func init() {
+	m.Register(func(app core.App) error {
+		messages, err := app.FindCollectionByNameOrId("messages")
+		if err != nil {
+			return err
+		}
+		messages.Fields.Add(&core.SelectField{Name: "media_status", Values: []string{"pending", "done", "failed"}, MaxSelect: 1})
+		return app.Save(messages)
+	}, func(app core.App) error {
+		messages, err := app.FindCollectionByNameOrId("messages")
+		if err != nil {
+			return nil
+		}
+		messages.Fields.RemoveByName("media_status")
+		return app.Save(messages)
+	})
+}
@@ -0,0 +1,68 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+// Creates media_items, each business's library of photos and videos it sent
+// over WhatsApp. The file stays on the message; the item adds tags taken
+// from the description, quality flags and how often the photo was used.
+// Posts get media_item, the library photo chosen to go with the caption.
+func init() {
+	m.Register(func(app core.App) error {
+		businesses, err := app.FindCollectionByNameOrId("businesses")
+		if err != nil {
+			return err
+		}
+		messages, err := app.FindCollectionByNameOrId("messages")
+		if err != nil {
+			return err
+		}
+
+		collection := core.NewBaseCollection("media_items")
+		collection.Fields.Add(
+			&core.RelationField{Name: "business", CollectionId: businesses.Id, Required: true, MaxSelect: 1, CascadeDelete: true},
+			&core.RelationField{Name: "message", CollectionId: messages.Id, Required: true, MaxSelect: 1, CascadeDelete: true},
+			&core.SelectField{Name: "type", Values: []string{"image", "video"}, Required: true, MaxSelect: 1},
+			&core.TextField{Name: "description"},
+			&core.JSONField{Name: "tags"},  // keywords from the description, accent-free lowercase
+			&core.JSONField{Name: "flags"}, // quality problems, e.g. low_res, screenshot
+			&core.NumberField{Name: "width", OnlyInt: true},
+			&core.NumberField{Name: "height", OnlyInt: true},
+			&core.NumberField{Name: "seconds", OnlyInt: true}, // videos only
+			&core.NumberField{Name: "used_count", OnlyInt: true},
+			&core.DateField{Name: "last_used_at"},
+			&core.AutodateField{Name: "created", OnCreate: true, System: true},
+			&core.AutodateField{Name: "updated", OnCreate: true, OnUpdate: true, System: true},
+		)
+		collection.AddIndex("idx_media_items_business", false, "business", "")
+		collection.AddIndex("idx_media_items_message", true, "message", "")
+
+		authed := `@request.auth.id != ""`
+		collection.ListRule = &authed
+		collection.ViewRule = &authed
+		if err := app.Save(collection); err != nil {
+			return err
+		}
+
+		posts, err := app.FindCollectionByNameOrId("posts")
+		if err != nil {
+			return err
+		}
+		posts.Fields.Add(&core.RelationField{Name: "media_item", CollectionId: collection.Id, MaxSelect: 1})
+		return app.Save(posts)
+	}, func(app core.App) error {
+		if posts, err := app.FindCollectionByNameOrId("posts"); err == nil {
+			posts.Fields.RemoveByName("media_item")
+			if err := app.Save(posts); err != nil {
+				return err
+			}
+		}
+		collection, err := app.FindCollectionByNameOrId("media_items")
+		if err != nil {
+			return nil
+		}
+		return app.Delete(collection)
+	})
+}
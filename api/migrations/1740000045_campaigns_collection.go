@@ -0,0 +1,87 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+// Creates campaigns, a message template sent to a segment of clients once the
+// operator approves it, and campaign_recipients, one row per client with the
+// rendered text and how the send went. Businesses get campaign_opt_out, set
+// when the client answers a campaign with SAIR.
+func init() {
+	m.Register(func(app core.App) error {
+		businesses, err := app.FindCollectionByNameOrId("businesses")
+		if err != nil {
+			return err
+		}
+		outbox, err := app.FindCollectionByNameOrId("outbox")
+		if err != nil {
+			return err
+		}
+
+		authed := `@request.auth.id != ""`
+
+		campaigns := core.NewBaseCollection("campaigns")
+		campaigns.Fields.Add(
+			&core.TextField{Name: "name", Required: true},
+			&core.TextField{Name: "template", Required: true}, // may use {name}, {business} and {next_charge}
+			&core.JSONField{Name: "segment"},                  // types, cities, tiers and invite_statuses; empty matches all
+			&core.SelectField{Name: "status", Values: []string{"draft", "approved", "done", "cancelled"}, Required: true, MaxSelect: 1},
+			&core.DateField{Name: "approved_at"},
+			&core.AutodateField{Name: "created", OnCreate: true, System: true},
+			&core.AutodateField{Name: "updated", OnCreate: true, OnUpdate: true, System: true},
+		)
+		campaigns.ListRule = &authed
+		campaigns.ViewRule = &authed
+		if err := app.Save(campaigns); err != nil {
+			return err
+		}
+
+		recipients := core.NewBaseCollection("campaign_recipients")
+		recipients.Fields.Add(
+			&core.RelationField{Name: "campaign", CollectionId: campaigns.Id, Required: true, MaxSelect: 1, CascadeDelete: true},
+			&core.RelationField{Name: "business", CollectionId: businesses.Id, Required: true, MaxSelect: 1, CascadeDelete: true},
+			&core.TextField{Name: "phone"},
+			&core.TextField{Name: "text"},
+			&core.SelectField{Name: "status", Values: []string{"pending", "queued", "sent", "failed", "opted_out", "skipped", "cancelled"}, Required: true, MaxSelect: 1},
+			&core.RelationField{Name: "outbox", CollectionId: outbox.Id, MaxSelect: 1}, // set once queued
+			&core.TextField{Name: "error"},
+			&core.DateField{Name: "sent_at"},
+			&core.AutodateField{Name: "created", OnCreate: true, System: true},
+			&core.AutodateField{Name: "updated", OnCreate: true, OnUpdate: true, System: true},
+		)
+		recipients.AddIndex("idx_campaign_recipients_campaign", true, "campaign, business", "")
+		// The dispatcher scans pending and queued recipients
+		recipients.AddIndex("idx_campaign_recipients_status", false, "status", "")
+		recipients.ListRule = &authed
+		recipients.ViewRule = &authed
+		if err := app.Save(recipients); err != nil {
+			return err
+		}
+
+		businesses.Fields.Add(
+			&core.BoolField{Name: "campaign_opt_out"},
+			&core.DateField{Name: "campaign_opt_out_at"},
+		)
+		return app.Save(businesses)
+	}, func(app core.App) error {
+		if businesses, err := app.FindCollectionByNameOrId("businesses"); err == nil {
+			businesses.Fields.RemoveByName("campaign_opt_out")
+			businesses.Fields.RemoveByName("campaign_opt_out_at")
+			if err := app.Save(businesses); err != nil {
+				return err
+			}
+		}
+		for _, name := range []string{"campaign_recipients", "campaigns"} {
+			collection, err := app.FindCollectionByNameOrId(name)
+			if err != nil {
+				continue
+			}
+			if err := app.Delete(collection); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
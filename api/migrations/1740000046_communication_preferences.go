@@ -0,0 +1,150 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+// Creates communication_preferences, which message categories a business
+// agreed to receive, and preference_changes, the log of every change with
+// its time and the client message or operator behind it. Outbox messages get
+// the category they belong to so the worker can hold back ones the client
+// stopped after they were queued. Campaign opt-outs move from the businesses
+// fields into preferences.
+func init() {
+	m.Register(func(app core.App) error {
+		businesses, err := app.FindCollectionByNameOrId("businesses")
+		if err != nil {
+			return err
+		}
+		messages, err := app.FindCollectionByNameOrId("messages")
+		if err != nil {
+			return err
+		}
+		users, err := app.FindCollectionByNameOrId("users")
+		if err != nil {
+			return err
+		}
+
+		authed := `@request.auth.id != ""`
+		categories := []string{"marketing", "seasonal", "operational"}
+
+		preferences := core.NewBaseCollection("communication_preferences")
+		preferences.Fields.Add(
+			&core.RelationField{Name: "business", CollectionId: businesses.Id, Required: true, MaxSelect: 1, CascadeDelete: true},
+			&core.BoolField{Name: "marketing"},   // campaigns and invites
+			&core.BoolField{Name: "seasonal"},    // seasonal outreach
+			&core.BoolField{Name: "operational"}, // posts and other operator messages
+			&core.AutodateField{Name: "created", OnCreate: true, System: true},
+			&core.AutodateField{Name: "updated", OnCreate: true, OnUpdate: true, System: true},
+		)
+		preferences.AddIndex("idx_communication_preferences_business", true, "business", "")
+		preferences.ListRule = &authed
+		preferences.ViewRule = &authed
+		if err := app.Save(preferences); err != nil {
+			return err
+		}
+
+		changes := core.NewBaseCollection("preference_changes")
+		changes.Fields.Add(
+			&core.RelationField{Name: "business", CollectionId: businesses.Id, Required: true, MaxSelect: 1, CascadeDelete: true},
+			&core.SelectField{Name: "category", Values: categories, Required: true, MaxSelect: 1},
+			&core.BoolField{Name: "allowed"},
+			&core.SelectField{Name: "source", Values: []string{"client", "operator"}, Required: true, MaxSelect: 1},
+			&core.RelationField{Name: "message", CollectionId: messages.Id, MaxSelect: 1}, // the client's request
+			&core.TextField{Name: "text"},                                           // what the client wrote, or the operator's note
+			&core.RelationField{Name: "user", CollectionId: users.Id, MaxSelect: 1}, // the operator
+			&core.DateField{Name: "changed_at", Required: true},
+			&core.AutodateField{Name: "created", OnCreate: true, System: true},
+		)
+		changes.AddIndex("idx_preference_changes_business", false, "business", "")
+		changes.ListRule = &authed
+		changes.ViewRule = &authed
+		if err := app.Save(changes); err != nil {
+			return err
+		}
+
+		outbox, err := app.FindCollectionByNameOrId("outbox")
+		if err != nil {
+			return err
+		}
+		outbox.Fields.Add(&core.SelectField{Name: "category", Values: categories, MaxSelect: 1}) // empty is operational
+		if err := app.Save(outbox); err != nil {
+			return err
+		}
+
+		optedOut, err := app.FindRecordsByFilter("businesses", "campaign_opt_out = true", "", 0, 0)
+		if err != nil {
+			return err
+		}
+		for _, biz := range optedOut {
+			p := core.NewRecord(preferences)
+			p.Set("business", biz.Id)
+			p.Set("marketing", false)
+			p.Set("seasonal", true)
+			p.Set("operational", true)
+			if err := app.Save(p); err != nil {
+				return err
+			}
+			at := biz.GetDateTime("campaign_opt_out_at").Time()
+			if at.IsZero() {
+				at = time.Now()
+			}
+			c := core.NewRecord(changes)
+			c.Set("business", biz.Id)
+			c.Set("category", "marketing")
+			c.Set("allowed", false)
+			c.Set("source", "client")
+			c.Set("text", "SAIR")
+			c.Set("changed_at", at)
+			if err := app.Save(c); err != nil {
+				return err
+			}
+		}
+
+		businesses.Fields.RemoveByName("campaign_opt_out")
+		businesses.Fields.RemoveByName("campaign_opt_out_at")
+		return app.Save(businesses)
+	}, func(app core.App) error {
+		if businesses, err := app.FindCollectionByNameOrId("businesses"); err == nil {
+			businesses.Fields.Add(
+				&core.BoolField{Name: "campaign_opt_out"},
+				&core.DateField{Name: "campaign_opt_out_at"},
+			)
+			if err := app.Save(businesses); err != nil {
+				return err
+			}
+			if stopped, err := app.FindRecordsByFilter("communication_preferences", "marketing = false", "", 0, 0); err == nil {
+				for _, p := range stopped {
+					biz, err := app.FindRecordById("businesses", p.GetString("business"))
+					if err != nil {
+						continue
+					}
+					biz.Set("campaign_opt_out", true)
+					biz.Set("campaign_opt_out_at", p.GetDateTime("updated"))
+					if err := app.Save(biz); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		if outbox, err := app.FindCollectionByNameOrId("outbox"); err == nil {
+			outbox.Fields.RemoveByName("category")
+			if err := app.Save(outbox); err != nil {
+				return err
+			}
+		}
+		for _, name := range []string{"preference_changes", "communication_preferences"} {
+			collection, err := app.FindCollectionByNameOrId(name)
+			if err != nil {
+				continue
+			}
+			if err := app.Delete(collection); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,44 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+// Creates plan_runs, one row per monthly plan generated in the background,
+// with how many posts are done so the dashboard can show progress. batch_id
+// points at the saved posts once the run is done.
+func init() {
+	m.Register(func(app core.App) error {
+		businesses, err := app.FindCollectionByNameOrId("businesses")
+		if err != nil {
+			return err
+		}
+
+		authed := `@request.auth.id != ""`
+
+		runs := core.NewBaseCollection("plan_runs")
+		runs.Fields.Add(
+			&core.RelationField{Name: "business", CollectionId: businesses.Id, Required: true, MaxSelect: 1, CascadeDelete: true},
+			&core.DateField{Name: "start", Required: true},
+			&core.SelectField{Name: "status", Values: []string{"running", "done", "failed"}, Required: true, MaxSelect: 1},
+			&core.NumberField{Name: "total", OnlyInt: true},
+			&core.NumberField{Name: "done", OnlyInt: true},
+			&core.TextField{Name: "batch_id"},
+			&core.TextField{Name: "error"},
+			&core.AutodateField{Name: "created", OnCreate: true, System: true},
+			&core.AutodateField{Name: "updated", OnCreate: true, OnUpdate: true, System: true},
+		)
+		// Starting a plan looks for a run already going for the business
+		runs.AddIndex("idx_plan_runs_business_status", false, "business, status", "")
+		runs.ListRule = &authed
+		runs.ViewRule = &authed
+		return app.Save(runs)
+	}, func(app core.App) error {
+		runs, err := app.FindCollectionByNameOrId("plan_runs")
+		if err != nil {
+			return nil
+		}
+		return app.Delete(runs)
+	})
+}
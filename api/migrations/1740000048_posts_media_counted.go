@@ -0,0 +1,26 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+// Records which library item a post's use was counted for. A use counts once
+// the post is approved or sent, not when a photo is suggested for a draft.
+func init() {
+	m.Register(func(app core.App) error {
+		posts, err := app.FindCollectionByNameOrId("posts")
+		if err != nil {
+			return err
+		}
+		posts.Fields.Add(&core.TextField{Name: "media_counted"})
+		return app.Save(posts)
+	}, func(app core.App) error {
+		posts, err := app.FindCollectionByNameOrId("posts")
+		if err != nil {
+			return nil
+		}
+		posts.Fields.RemoveByName("media_counted")
+		return app.Save(posts)
+	})
+}
@@ -4,6 +4,7 @@ import type {
 	GeneratedPost,
 	Message,
 	Post,
+	PostDelivery,
 	ProfileSuggestion,
 	ScheduledMessage,
 	Service,
@@ -30,6 +31,11 @@ export async function fetchScheduledMessages(): Promise<ScheduledMessage[]> {
 	return (await pb.send('/api/scheduled-messages', { method: 'GET' })) as ScheduledMessage[];
 }
 
+// Posts delivered to the client but still unread after the given hours.
+export async function fetchUnreadPosts(hours = 24): Promise<PostDelivery[]> {
+	return (await pb.send(`/api/posts:unread?hours=${hours}`, { method: 'GET' })) as PostDelivery[];
+}
+
 export async function fetchSuggestionCounts(): Promise<Record<string, number>> {
 	const res = await pb.collection('profile_suggestions').getList<ProfileSuggestion>(1, 500, {
 		filter: 'dismissed = false',
@@ -57,11 +63,13 @@ export async function sendMessage(
 	caption: string,
 	hashtags: string[] = [],
 	productionNote = '',
+	postId = '',
 ): Promise<void> {
 	await pb.send('/api/messages:send', {
 		method: 'POST',
 		body: JSON.stringify({
 			business_id: businessId,
+			post_id: postId,
 			caption,
 			hashtags: hashtags.join(' '),
 			production_note: productionNote,
@@ -31,7 +31,8 @@ export interface Business {
 	brand_vibe: string;
 	quirks: string;
 	phone: string;
-	onboarding_step: number;
+	onboarding_step: number; // WhatsApp onboarding question in progress; 6 when done
+	collected_fields?: Record<string, string>; // raw onboarding answers by question
 	client_name: string;
 	client_email: string;
 	invite_token: string;
@@ -45,6 +46,9 @@ export interface Business {
 	charge_pending: boolean;
 	terms_accepted_at: string;
 	profile_picture: string;
+	style_memo?: string; // rules learned from rejected and edited posts
+	assistant_enabled?: boolean; // DM assistant answers the client's questions
+	assistant_handoff_at?: string; // last time the assistant passed the client to the team
 }
 
 export interface GeneratedPost {
@@ -57,16 +61,33 @@ export interface Message {
 	id: string;
 	business: string;
 	phone: string;
-	type: 'text' | 'audio' | 'image' | 'video';
+	type: 'text' | 'audio' | 'image' | 'video' | 'document';
 	content: string;
 	media: string;
 	direction: 'incoming' | 'outgoing';
 	wa_timestamp: string;
 	wa_message_id: string;
+	post?: string; // the post whose caption this message carried
+	status?: 'sent' | 'delivered' | 'read'; // outgoing only, updated by receipts
+	sent_at?: string;
+	delivered_at?: string;
+	read_at?: string;
+	media_status?: 'pending' | 'done' | 'failed'; // incoming media processed in the background
 	created: string;
 	collectionId: string;
 }
 
+export interface PostDelivery {
+	post_id: string;
+	business_id: string;
+	business_name?: string;
+	caption: string;
+	status: 'sent' | 'delivered' | 'read';
+	sent_at?: string;
+	delivered_at?: string;
+	read_at?: string;
+}
+
 export interface WAStatus {
 	connected: boolean;
 	qr?: string;
@@ -82,7 +103,68 @@ export interface Post {
 	hook: string;
 	batch_id: string;
 	edited: boolean;
+	original_caption?: string;
+	planned_for?: string;
+	occasion?: string;
+	format?: PostFormat; // empty means feed
+	format_data?: PostFormatData;
+	quality?: QualityReport; // set when the quality gate ran
+	variants?: PostVariant[]; // caption options; the picked one has chosen=true
+	client_reply?: '' | 'approved' | 'change_request' | 'question'; // how the client answered on WhatsApp
+	client_feedback?: string; // requested change or question, in the client's words
+	client_replied_at?: string;
+	media_item?: string; // library photo or video paired with the caption
+	created: string;
+}
+
+// A client's photo or video from the media library (GET /api/businesses/{id}/media).
+export interface MediaItem {
+	id: string;
+	type: 'image' | 'video';
+	description: string;
+	tags: string[];
+	flags: ('low_res' | 'screenshot' | 'short_video' | 'long_video')[];
+	used_count: number;
+	last_used_at?: string;
 	created: string;
+	message: { id: string; collectionId: string; media: string }; // pass to pb.files.getURL
+}
+
+export type PostFormat = 'feed' | 'reel' | 'story' | 'carousel';
+
+export interface PostFormatData {
+	reel?: {
+		hook: string;
+		scenes: { visual: string; onScreenText: string; voiceover: string }[];
+		audioHint: string;
+	};
+	story?: { visual: string; text: string; sticker: string }[];
+	carousel?: { title: string; body: string }[];
+}
+
+export interface QualityCheck {
+	name: string;
+	pass: boolean;
+	reason?: string;
+	error?: string; // judge could not run
+}
+
+export interface QualityReport {
+	passed: boolean;
+	attempts: number;
+	checks: QualityCheck[];
+	judges?: QualityCheck[];
+}
+
+export interface PostVariant {
+	caption: string;
+	hashtags: string[];
+	production_note: string;
+	hook: string;
+	format_data?: PostFormatData;
+	quality?: QualityReport;
+	chosen: boolean;
+	edited_caption?: string; // operator edit kept when another variant was picked
 }
 
 export interface ScheduledMessage {
@@ -91,6 +173,72 @@ export interface ScheduledMessage {
 	text: string;
 }
 
+export type CampaignStatus = 'draft' | 'approved' | 'done' | 'cancelled';
+export type RecipientStatus =
+	| 'pending'
+	| 'queued'
+	| 'sent'
+	| 'failed'
+	| 'opted_out'
+	| 'skipped'
+	| 'cancelled';
+
+// Clients a campaign goes to; empty lists match everyone.
+export interface CampaignSegment {
+	types?: string[];
+	cities?: string[];
+	tiers?: Tier[];
+	invite_statuses?: InviteStatus[];
+}
+
+// A broadcast to a segment of clients (GET /api/campaigns). The template may
+// use {name}, {business} and {next_charge}.
+export interface Campaign {
+	id: string;
+	name: string;
+	template: string;
+	segment: CampaignSegment;
+	status: CampaignStatus;
+	approved_at?: string;
+	created: string;
+	counts: Partial<Record<RecipientStatus, number>>;
+}
+
+// One client's copy of a campaign, from the preview or after approval.
+export interface CampaignRecipient {
+	id?: string;
+	business_id: string;
+	business_name: string;
+	phone: string;
+	text: string;
+	status?: RecipientStatus;
+	skip?: string; // preview only: why the client would not get it
+	error?: string;
+	sent_at?: string;
+}
+
+export type PreferenceCategory = 'marketing' | 'seasonal' | 'operational';
+
+// Which messages a client agreed to receive (GET /api/businesses/{id}/preferences).
+// marketing: campaigns and invites; seasonal: seasonal outreach; operational:
+// posts and everything else the operator sends.
+export interface CommunicationPreferences {
+	marketing: boolean;
+	seasonal: boolean;
+	operational: boolean;
+	history?: PreferenceChange[]; // newest first
+}
+
+export interface PreferenceChange {
+	category: PreferenceCategory;
+	allowed: boolean;
+	source: 'client' | 'operator';
+	message?: string; // the client's WhatsApp message that asked for it
+	text?: string; // what the client wrote, or the operator's note
+	user?: string; // the operator who made it
+	changed_at: string;
+}
+
 export interface ProfileSuggestion {
 	id: string;
 	business: string;